@@ -5,7 +5,10 @@ set -o xtrace
 
 install -o "$(id -u)" -g "$(id -g)" -m 0755 -D /peer-list /opt/percona/peer-list
 install -o "$(id -u)" -g "$(id -g)" -m 0755 -D /xtrabackup-run-backup /opt/percona/xtrabackup-run-backup
+install -o "$(id -u)" -g "$(id -g)" -m 0755 -D /ratelimit /opt/percona/ratelimit
+install -o "$(id -u)" -g "$(id -g)" -m 0755 -D /gcs-proxy /opt/percona/gcs-proxy
+install -o "$(id -u)" -g "$(id -g)" -m 0755 -D /xbstream-encrypt /opt/percona/xbstream-encrypt
 
 mkdir -p /opt/percona/backup/lib/pxc
 install -o "$(id -u)" -g "$(id -g)" -m 0755 -D /backup/lib/pxc/* /opt/percona/backup/lib/pxc/
-install -o "$(id -u)" -g "$(id -g)" -m 0755 -D /backup/recovery-*.sh backup/run_backup.sh backup/backup.sh /opt/percona/backup/
+install -o "$(id -u)" -g "$(id -g)" -m 0755 -D /backup/recovery-*.sh backup/run_backup.sh backup/backup.sh /backup/logical-backup.sh /backup/verify-backup.sh /opt/percona/backup/
@@ -5,6 +5,8 @@ set -o errexit
 LIB_PATH='/opt/percona/backup/lib/pxc'
 # shellcheck source=build/backup/lib/pxc/aws.sh
 . ${LIB_PATH}/aws.sh
+# shellcheck source=build/backup/lib/pxc/gcs.sh
+. ${LIB_PATH}/gcs.sh
 
 SST_INFO_NAME=sst_info
 XBCLOUD_ARGS="--curl-retriable-errors=7 $XBCLOUD_EXTRA_ARGS"
@@ -13,8 +15,30 @@ if [ -n "$VERIFY_TLS" ] && [[ $VERIFY_TLS == "false" ]]; then
 	XBCLOUD_ARGS="--insecure ${XBCLOUD_ARGS}"
 fi
 
+XBCLOUD_PARALLEL=${XBCLOUD_PARALLEL:-$(grep -c processor /proc/cpuinfo)}
+
 S3_BUCKET_PATH=${S3_BUCKET_PATH:-$PXC_SERVICE-$(date +%F-%H-%M)-xtrabackup.stream}
 BACKUP_PATH=${BACKUP_PATH:-$PXC_SERVICE-$(date +%F-%H-%M)-xtrabackup.stream}
+GCS_BUCKET_PATH=${GCS_BUCKET_PATH:-$PXC_SERVICE-$(date +%F-%H-%M)-xtrabackup.stream}
+
+# copies stdin to stdout not faster than UPLOAD_RATE_LIMIT bytes per second
+upload_rate_limit() {
+	if [[ -n ${UPLOAD_RATE_LIMIT} ]]; then
+		/opt/percona/ratelimit "${UPLOAD_RATE_LIMIT}"
+	else
+		cat
+	fi
+}
+
+# encrypts the backup stream if encryption is configured for the storage,
+# the key is expanded only to its presence, so it isn't traced
+encrypt_backup() {
+	if [[ -n ${ENCRYPTION_KEY:+set} || -n ${ENCRYPTION_VAULT_PATH} ]]; then
+		/opt/percona/xbstream-encrypt
+	else
+		cat
+	fi
+}
 
 log() {
 	{ set +x; } 2>/dev/null
@@ -27,6 +51,45 @@ log() {
 	set -x
 }
 
+# prints the name of the synced pod the backup is taken from,
+# the first pod is skipped if the cluster has more than one node
+get_backup_source() {
+	# the node is selected by the operator if the backup source is set in the backup spec
+	if [ -n "${BACKUP_SOURCE_NODE}" ]; then
+		echo "${BACKUP_SOURCE_NODE}"
+		return
+	fi
+
+	CLUSTER_SIZE=$(/opt/percona/peer-list -on-start=/opt/percona/backup/lib/pxc/get-pxc-state.sh -service="$PXC_SERVICE" 2>&1 \
+		| grep wsrep_cluster_size \
+		| sort \
+		| tail -1 \
+		| cut -d : -f 12)
+
+	if [ -z "${CLUSTER_SIZE}" ]; then
+		exit 1
+	fi
+
+	FIRST_NODE=$(/opt/percona/peer-list -on-start=/opt/percona/backup/lib/pxc/get-pxc-state.sh -service="$PXC_SERVICE" 2>&1 \
+		| grep wsrep_ready:ON:wsrep_connected:ON:wsrep_local_state_comment:Synced:wsrep_cluster_status:Primary \
+		| sort -r \
+		| tail -1 \
+		| cut -d : -f 2 \
+		| cut -d . -f 1)
+
+	SKIP_FIRST_POD='|'
+	if ((${CLUSTER_SIZE:-0} > 1)); then
+		SKIP_FIRST_POD="$FIRST_NODE"
+	fi
+	/opt/percona/peer-list -on-start=/opt/percona/backup/lib/pxc/get-pxc-state.sh -service="$PXC_SERVICE" 2>&1 \
+		| grep wsrep_ready:ON:wsrep_connected:ON:wsrep_local_state_comment:Synced:wsrep_cluster_status:Primary \
+		| grep -v "$SKIP_FIRST_POD" \
+		| sort \
+		| tail -1 \
+		| cut -d : -f 2 \
+		| cut -d . -f 1
+}
+
 clean_backup_s3() {
 	s3_add_bucket_dest
 
@@ -133,3 +196,23 @@ clean_backup_azure() {
 		((time *= 2))
 	done
 }
+
+clean_backup_gcs() {
+	local time=15
+
+	for i in {1..5}; do
+		if ((i > 1)); then
+			log 'INFO' "Sleeping ${time}s before retry $i..."
+			sleep "$time"
+		fi
+
+		log 'INFO' "Delete (attempt $i)..."
+		# shellcheck disable=SC2086
+		if xbcloud_gcs delete ${XBCLOUD_ARGS} "$GCS_BUCKET_PATH.$SST_INFO_NAME" \
+			&& xbcloud_gcs delete ${XBCLOUD_ARGS} "$GCS_BUCKET_PATH"; then
+			log 'INFO' "Object deleted successfully on attempt $i. Exiting."
+			break
+		fi
+		((time *= 2))
+	done
+}
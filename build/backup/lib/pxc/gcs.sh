@@ -0,0 +1,81 @@
+#!/bin/bash
+
+set -o errexit
+
+# gcs_xbcloud_args prints xbcloud arguments for Google Cloud Storage.
+# The output contains credentials, so it must not be traced.
+gcs_xbcloud_args() {
+	local args="--storage=google --google-bucket=${GCS_BUCKET}"
+
+	if [ -n "$GCS_PROXY_URL" ]; then
+		args="${args} --google-access-key=${GCS_PROXY_HMAC_KEY} --google-secret-key=${GCS_PROXY_HMAC_KEY}"
+		args="${args} --google-endpoint=${GCS_PROXY_URL}"
+	else
+		args="${args} --google-access-key=${GCS_ACCESS_KEY} --google-secret-key=${GCS_SECRET_KEY}"
+		if [ -n "$GCS_ENDPOINT" ]; then
+			args="${args} --google-endpoint=${GCS_ENDPOINT}"
+		fi
+	fi
+	if [ -n "$GCS_STORAGE_CLASS" ]; then
+		args="${args} --google-storage-class=${GCS_STORAGE_CLASS}"
+	fi
+
+	echo -n "$args"
+}
+
+# gcs_start_proxy starts gcs-proxy and sets GCS_PROXY_URL and GCS_PROXY_PID.
+# xbcloud supports only HMAC keys, so without them its requests go through the proxy,
+# which authorizes them with the service account key from GCS_CREDENTIALS_JSON
+# or with the pod's service account (e.g. GKE workload identity).
+gcs_start_proxy() {
+	local url_file
+	url_file=$(mktemp)
+	rm -f "$url_file"
+
+	/opt/percona/gcs-proxy "$url_file" &
+	GCS_PROXY_PID=$!
+
+	for _ in $(seq 1 30); do
+		if [ -s "$url_file" ]; then
+			GCS_PROXY_URL=$(cat "$url_file")
+			GCS_PROXY_HMAC_KEY=oauth2
+			rm -f "$url_file"
+			return 0
+		fi
+		if ! kill -0 "$GCS_PROXY_PID" 2>/dev/null; then
+			break
+		fi
+		sleep 1
+	done
+
+	echo "gcs-proxy failed to start" >&2
+	kill "$GCS_PROXY_PID" 2>/dev/null || :
+	return 1
+}
+
+# xbcloud_gcs runs xbcloud command against Google Cloud Storage without tracing credentials.
+xbcloud_gcs() {
+	{ set +x; } 2>/dev/null
+	local cmd=$1
+	shift
+
+	local GCS_PROXY_URL='' GCS_PROXY_PID='' GCS_PROXY_HMAC_KEY=''
+	if [ -z "$GCS_ACCESS_KEY" ] || [ -z "$GCS_SECRET_KEY" ]; then
+		gcs_start_proxy || {
+			set -x
+			return 1
+		}
+	fi
+
+	local ret=0
+	# shellcheck disable=SC2046
+	xbcloud "$cmd" $(gcs_xbcloud_args) "$@" || ret=$?
+
+	if [ -n "$GCS_PROXY_PID" ]; then
+		kill "$GCS_PROXY_PID" 2>/dev/null || :
+		wait "$GCS_PROXY_PID" 2>/dev/null || :
+	fi
+
+	set -x
+	return $ret
+}
@@ -0,0 +1,19 @@
+#!/bin/bash
+
+# write_client_defaults writes the credentials into the [client] group of a new option file
+# readable only by the current user and prints its path. The clients get the file in
+# --defaults-file, so the password isn't visible in the process list.
+write_client_defaults() {
+	local user=$1
+	local password=$2
+	local file
+
+	file=$(mktemp)
+	chmod 0600 "${file}"
+	# the values are quoted, so the password can contain the comment characters
+	password=${password//\\/\\\\}
+	password=${password//\"/\\\"}
+	printf '[client]\nuser="%s"\npassword="%s"\n' "${user}" "${password}" >"${file}"
+
+	echo "${file}"
+}
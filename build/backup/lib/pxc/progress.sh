@@ -0,0 +1,67 @@
+#!/bin/bash
+
+# The progress of the restore is stored in the datadir. The operator reads it to report
+# the progress in the status of the restore, and the next pod of the restore job
+# resumes the restore from the last completed phase.
+RESTORE_PROGRESS_DIR=${RESTORE_PROGRESS_DIR:-/datadir/.restore-progress}
+
+# init_progress returns 0 if the progress of the same restore is found and the restore can be resumed.
+# The restore is started over if the files were being moved to the datadir.
+init_progress() {
+	if [[ -n ${RESTORE_ID} && -f "${RESTORE_PROGRESS_DIR}/restore" ]] \
+		&& [[ $(cat "${RESTORE_PROGRESS_DIR}/restore") == "restore=${RESTORE_ID}" ]] \
+		&& [[ $(current_phase) != "copy-back" ]]; then
+		return 0
+	fi
+
+	rm -rf "${RESTORE_PROGRESS_DIR}"
+	mkdir -p "${RESTORE_PROGRESS_DIR}"
+	echo "restore=${RESTORE_ID}" >"${RESTORE_PROGRESS_DIR}/restore"
+	return 1
+}
+
+current_phase() {
+	sed -n 's/^phase=//p' "${RESTORE_PROGRESS_DIR}/phase" 2>/dev/null || :
+}
+
+start_phase() {
+	printf 'phase=%s\nstarted=%s\n' "$1" "$(date +%s)" >"${RESTORE_PROGRESS_DIR}/phase"
+}
+
+complete_phase() {
+	echo "completed=$1" >>"${RESTORE_PROGRESS_DIR}/completed"
+}
+
+is_phase_completed() {
+	grep -qx "completed=$1" "${RESTORE_PROGRESS_DIR}/completed" 2>/dev/null
+}
+
+# start_step records the step of the phase which modifies the downloaded backup,
+# such a step can't be continued if the restore is interrupted
+start_step() {
+	echo "step=$1" >"${RESTORE_PROGRESS_DIR}/step"
+}
+
+complete_step() {
+	complete_phase "$1"
+	rm -f "${RESTORE_PROGRESS_DIR}/step"
+}
+
+interrupted_step() {
+	sed -n 's/^step=//p' "${RESTORE_PROGRESS_DIR}/step" 2>/dev/null || :
+}
+
+# restart_progress forgets the completed phases, so the restore is started over
+restart_progress() {
+	rm -f "${RESTORE_PROGRESS_DIR}/phase" "${RESTORE_PROGRESS_DIR}/completed" "${RESTORE_PROGRESS_DIR}/step"
+}
+
+# progress_counter copies stdin to stdout and counts the downloaded bytes
+# not faster than DOWNLOAD_RATE_LIMIT bytes per second
+progress_counter() {
+	/opt/percona/ratelimit "${DOWNLOAD_RATE_LIMIT:-0}" "${RESTORE_PROGRESS_DIR}/downloaded"
+}
+
+finish_progress() {
+	rm -rf "${RESTORE_PROGRESS_DIR}"
+}
@@ -99,3 +99,38 @@ function vault_store() {
 	set -o xtrace
 	sed -i '/transition-key/d' "$sst_info" >/dev/null
 }
+
+function vault_get_key() {
+	local secret_path=$1
+
+	if [ ! -f "${keyring_vault}" ]; then
+		echo "vault configuration not found" >&2
+		exit 1
+	fi
+
+	local token=$(get_vault_option "token")
+	local vault_addr=$(get_vault_option "vault_url")
+	local mount_point=$(get_vault_option "secret_mount_point")
+	local ca_path=$(get_vault_option "vault_ca")
+	local version=$(get_vault_option "secret_mount_point_version")
+	if [[ ${ca_path} == "null" ]]; then
+		ca_path=""
+	fi
+
+	mount_point=${mount_point%/}
+	secret_path=${secret_path#/}
+
+	# KV version 2 has the secret data nested into the data field
+	local url="${vault_addr%/}/v1/${mount_point#/}/${secret_path}"
+	local query='.data.key'
+	if [[ ${version} == "2" ]]; then
+		url="${vault_addr%/}/v1/${mount_point#/}/data/${secret_path}"
+		query='.data.data.key'
+	fi
+
+	curl --fail --silent --show-error ${ca_path:+--cacert $ca_path} \
+		-H "X-Vault-Request: true" \
+		-H "X-Vault-Token: ${token}" \
+		"${url}" \
+		| jq -r -e "${query}"
+}
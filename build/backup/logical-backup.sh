@@ -0,0 +1,124 @@
+#!/bin/bash
+
+set -o xtrace
+set -o errexit
+set -o pipefail
+
+LIB_PATH='/opt/percona/backup/lib/pxc'
+# shellcheck source=build/backup/lib/pxc/backup.sh
+. ${LIB_PATH}/backup.sh
+# shellcheck source=build/backup/lib/pxc/aws.sh
+. ${LIB_PATH}/aws.sh
+# shellcheck source=build/backup/lib/pxc/mysql.sh
+. ${LIB_PATH}/mysql.sh
+
+# The databases are dumped by mydumper into LOGICAL_DUMP_DIR and the dump is uploaded
+# to the storage as xbstream, so it's downloaded by xbcloud in the same way as xtrabackup backups.
+# The dump is restored by recovery-logical.sh with myloader.
+
+LOGICAL_DUMP_DIR=${LOGICAL_DUMP_DIR:-/dump}
+LOGICAL_THREADS=${LOGICAL_THREADS:-$(grep -c processor /proc/cpuinfo)}
+
+if ! command -v mydumper >/dev/null; then
+	log 'ERROR' 'mydumper is not found in the image, set backup.logicalImage to an image with mydumper'
+	exit 1
+fi
+
+NODE_NAME=$(get_backup_source)
+if [ -z "$NODE_NAME" ]; then
+	/opt/percona/peer-list -on-start=/opt/percona/backup/lib/pxc/get-pxc-state.sh -service="$PXC_SERVICE"
+	log 'ERROR' 'Cannot find node for backup'
+	log 'ERROR' 'Backup was finished unsuccessful'
+	exit 1
+fi
+
+# the system databases are not dumped, they can't be restored into another major version
+REGEX='^(?!(mysql|sys|performance_schema|information_schema)\.)'
+if [ -n "${LOGICAL_DATABASES}" ]; then
+	REGEX="^($(echo "${LOGICAL_DATABASES}" | tr ' ' '|'))\."
+fi
+
+# InnoDB tables are dumped in consistent snapshot transactions and the global read lock
+# is held only until the dump threads start them, so the writes to the cluster aren't
+# blocked for the whole dump. The locking can be changed in MYDUMPER_EXTRA_ARGS.
+# The option is renamed to --trx-tables in mydumper 0.16.
+LOCK_ARGS=(--trx-consistency-only)
+if mydumper --help 2>&1 | grep -q -- '--trx-tables'; then
+	LOCK_ARGS=(--trx-tables)
+fi
+if [[ ${MYDUMPER_EXTRA_ARGS} =~ --(trx-|lock-all-tables|no-locks|less-locking|sync-thread-lock-mode) ]]; then
+	LOCK_ARGS=()
+fi
+
+rm -rf "${LOGICAL_DUMP_DIR:?}"/*
+mkdir -p "${LOGICAL_DUMP_DIR}"
+
+{ set +x; } 2>/dev/null
+DEFAULTS_FILE=$(write_client_defaults xtrabackup "${PXC_PASS}")
+set -x
+trap 'rm -f "${DEFAULTS_FILE}"' EXIT
+
+log 'INFO' "Dumping databases from ${NODE_NAME}"
+# shellcheck disable=SC2086
+mydumper \
+	--defaults-file="${DEFAULTS_FILE}" \
+	--host="${NODE_NAME}.${PXC_SERVICE}" \
+	--port=3306 \
+	--outputdir="${LOGICAL_DUMP_DIR}" \
+	--threads="${LOGICAL_THREADS}" \
+	--regex="${REGEX}" \
+	--compress \
+	--triggers \
+	--events \
+	--routines \
+	--verbose=3 \
+	"${LOCK_ARGS[@]}" \
+	$MYDUMPER_EXTRA_ARGS
+
+cd "${LOGICAL_DUMP_DIR}"
+if [ ! -f metadata ]; then
+	log 'ERROR' 'Dump is incomplete, metadata file is not found'
+	exit 1
+fi
+
+# dump files are uploaded as a single xbstream
+dump_stream() {
+	# shellcheck disable=SC2035
+	xbstream -c * | upload_rate_limit
+}
+
+if [ -n "$S3_BUCKET" ]; then
+	clean_backup_s3
+	# shellcheck disable=SC2086
+	dump_stream \
+		| xbcloud put --storage=s3 \
+			--md5 \
+			--parallel="${XBCLOUD_PARALLEL}" \
+			$XBCLOUD_ARGS \
+			--s3-bucket="$S3_BUCKET" \
+			"$S3_BUCKET_PATH"
+elif [ -n "$AZURE_CONTAINER_NAME" ]; then
+	clean_backup_azure
+	# shellcheck disable=SC2086
+	dump_stream \
+		| xbcloud put --storage=azure \
+			--parallel="${XBCLOUD_PARALLEL}" \
+			$XBCLOUD_ARGS \
+			"$BACKUP_PATH"
+elif [ -n "$GCS_BUCKET" ]; then
+	clean_backup_gcs
+	# shellcheck disable=SC2086
+	dump_stream \
+		| xbcloud_gcs put \
+			--md5 \
+			--parallel="${XBCLOUD_PARALLEL}" \
+			$XBCLOUD_ARGS \
+			"$GCS_BUCKET_PATH"
+else
+	log 'ERROR' 'Logical backups are supported only for s3, azure and gcs storages'
+	exit 1
+fi
+
+rm -rf "${LOGICAL_DUMP_DIR:?}"/*
+
+log 'INFO' 'Backup was finished successfully'
@@ -10,6 +10,10 @@ LIB_PATH='/opt/percona/backup/lib/pxc'
 . ${LIB_PATH}/vault.sh
 # shellcheck source=build/backup/lib/pxc/aws.sh
 . ${LIB_PATH}/aws.sh
+# shellcheck source=build/backup/lib/pxc/gcs.sh
+. ${LIB_PATH}/gcs.sh
+# shellcheck source=build/backup/lib/pxc/progress.sh
+. ${LIB_PATH}/progress.sh
 
 # temporary fix for PXB-2784
 XBCLOUD_ARGS="--curl-retriable-errors=7 $XBCLOUD_EXTRA_ARGS"
@@ -18,12 +22,18 @@ if [ -n "$VERIFY_TLS" ] && [[ $VERIFY_TLS == "false" ]]; then
 	XBCLOUD_ARGS="--insecure ${XBCLOUD_ARGS}"
 fi
 
+XBCLOUD_PARALLEL=${XBCLOUD_PARALLEL:-$(grep -c processor /proc/cpuinfo)}
+
+XBCLOUD_CMD=xbcloud
+
 if [ -n "$S3_BUCKET_URL" ]; then
 	{ set +x; } 2>/dev/null
 	s3_add_bucket_dest
 	set -x
 	# shellcheck disable=SC2086
 	aws $AWS_S3_NO_VERIFY_SSL s3 ls "${S3_BUCKET_URL}"
+elif [ -n "${GCS_BUCKET}" ]; then
+	XBCLOUD_CMD=xbcloud_gcs
 elif [ -n "${BACKUP_PATH}" ]; then
 	XBCLOUD_ARGS="${XBCLOUD_ARGS} --storage=azure"
 fi
@@ -32,27 +42,85 @@ if [ -n "${AZURE_CONTAINER_NAME}" ]; then
 	XBCLOUD_ARGS="${XBCLOUD_ARGS} --azure-container-name=${AZURE_CONTAINER_NAME}"
 fi
 
-rm -rf /datadir/*
-tmp=$(mktemp --directory /datadir/pxc_sst_XXXX)
+# backups and binlogs are encrypted on the client side if encryption is configured for the storage
+if [[ -n ${ENCRYPTION_KEY} || -n ${ENCRYPTION_VAULT_PATH} ]]; then
+	{ set +x; } 2>/dev/null
+	ENCRYPTION_KEY_FILE=$(mktemp /tmp/backup-encryption-key.XXXX)
+	if [[ -n ${ENCRYPTION_VAULT_PATH} ]]; then
+		ENCRYPTION_KEY=$(vault_get_key "${ENCRYPTION_VAULT_PATH}")
+	fi
+	printf '%s' "${ENCRYPTION_KEY}" >"${ENCRYPTION_KEY_FILE}"
+	unset ENCRYPTION_KEY
+	set -x
+
+	XBSTREAM_EXTRA_ARGS="$XBSTREAM_EXTRA_ARGS --decrypt=${ENCRYPTION_ALGORITHM:-AES256} --encrypt-key-file=${ENCRYPTION_KEY_FILE}"
+fi
+
+# the backup is downloaded into the known directory, so the next pod of the job can resume the restore
+tmp=/datadir/pxc_sst_restore
+if ! init_progress; then
+	rm -rf /datadir/*
+elif [[ -n $(interrupted_step) ]]; then
+	# xtrabackup --prepare modifies the downloaded backup and the interrupted prepare
+	# can't be continued, so the backup is downloaded and prepared from the start
+	echo "$(interrupted_step) was interrupted, restarting the restore"
+	restart_progress
+fi
+mkdir -p "${tmp}"
 
 destination() {
 	if [ -n "${S3_BUCKET_URL}" ]; then
 		echo -n "s3://${S3_BUCKET_URL}"
+	elif [ -n "${GCS_BUCKET}" ]; then
+		echo -n "${GCS_BUCKET_PATH}"
 	elif [ -n "${BACKUP_PATH}" ]; then
 		echo -n "${BACKUP_PATH}"
 	fi
 }
 
-# shellcheck disable=SC2086
-xbcloud get --parallel="$(grep -c processor /proc/cpuinfo)" ${XBCLOUD_ARGS} "$(destination).sst_info" | xbstream -x -C "${tmp}" --parallel="$(grep -c processor /proc/cpuinfo)" $XBSTREAM_EXTRA_ARGS
+# copies stdin to stdout not faster than DOWNLOAD_RATE_LIMIT bytes per second
+download_rate_limit() {
+	if [[ -n ${RESTORE_ID} ]]; then
+		progress_counter
+	elif [[ -n ${DOWNLOAD_RATE_LIMIT} ]]; then
+		/opt/percona/ratelimit "${DOWNLOAD_RATE_LIMIT}"
+	else
+		cat
+	fi
+}
+
+get_backup() {
+	local backup=$1
+	local target_dir=$2
+
+	# shellcheck disable=SC2086
+	$XBCLOUD_CMD get --parallel="${XBCLOUD_PARALLEL}" ${XBCLOUD_ARGS} "${backup}" \
+		| download_rate_limit \
+		| xbstream -x -C "${target_dir}" --parallel="$(grep -c processor /proc/cpuinfo)" $XBSTREAM_EXTRA_ARGS
+}
+
+# BACKUP_BASE_CHAIN is set for incremental backups and contains the full backup
+# and the incremental backups which should be applied before the restored one
+FULL_BACKUP=$(destination)
+INCREMENTAL_BACKUPS=()
+if [ -n "${BACKUP_BASE_CHAIN}" ]; then
+	read -ra BASE_CHAIN <<<"${BACKUP_BASE_CHAIN}"
+	FULL_BACKUP=${BASE_CHAIN[0]}
+	INCREMENTAL_BACKUPS=("${BASE_CHAIN[@]:1}" "$(destination)")
+fi
 
 XTRABACKUP_VERSION=$(get_xtrabackup_version)
 if check_for_version "$XTRABACKUP_VERSION" '8.0.0'; then
 	XBSTREAM_EXTRA_ARGS="$XBSTREAM_EXTRA_ARGS --decompress"
 fi
 
-# shellcheck disable=SC2086
-xbcloud get --parallel="$(grep -c processor /proc/cpuinfo)" ${XBCLOUD_ARGS} "$(destination)" | xbstream -x -C "${tmp}" --parallel="$(grep -c processor /proc/cpuinfo)" $XBSTREAM_EXTRA_ARGS
+if ! is_phase_completed download; then
+	start_phase download
+	rm -rf "${tmp:?}"/* "${RESTORE_PROGRESS_DIR}/downloaded"
+	get_backup "${FULL_BACKUP}.sst_info" "${tmp}"
+	get_backup "${FULL_BACKUP}" "${tmp}"
+	complete_phase download
+fi
 
 set +o xtrace
 if [[ -f "${tmp}/sst_info" ]]; then
@@ -116,8 +184,10 @@ fi
 
 if ! check_for_version "$XTRABACKUP_VERSION" '8.0.0' \
 	&& ! check_for_version "$XTRABACKUP_VERSION" '8.4.0'; then
-	# shellcheck disable=SC2086
-	innobackupex ${XB_USE_MEMORY+--use-memory=$XB_USE_MEMORY} --parallel="$(grep -c processor /proc/cpuinfo)" $REMAINING_XB_ARGS --decompress "$tmp"
+	if ! is_phase_completed prepare; then
+		# shellcheck disable=SC2086
+		innobackupex ${XB_USE_MEMORY+--use-memory=$XB_USE_MEMORY} --parallel="$(grep -c processor /proc/cpuinfo)" $REMAINING_XB_ARGS --decompress "$tmp"
+	fi
 	XB_EXTRA_ARGS="$XB_EXTRA_ARGS --binlog-info=ON"
 fi
 
@@ -128,14 +198,74 @@ if [[ ${XTRABACKUP_ENABLED} == "true" ]]; then
 	DEFAULTS_FILE=""
 fi
 
-echo "+ xtrabackup $DEFAULTS_FILE ${XB_USE_MEMORY+--use-memory=$XB_USE_MEMORY} --prepare \
-	$REMAINING_XB_ARGS --rollback-prepared-trx  --xtrabackup-plugin-dir=/usr/lib64/xtrabackup/plugin \
+prepare() {
+	echo "+ xtrabackup $DEFAULTS_FILE ${XB_USE_MEMORY+--use-memory=$XB_USE_MEMORY} --prepare \
+	$REMAINING_XB_ARGS $*  --xtrabackup-plugin-dir=/usr/lib64/xtrabackup/plugin \
 	--target-dir=$tmp ${PXB_VAULT_PREPARE_ARGS}"
 
-# shellcheck disable=SC2086
-xtrabackup $DEFAULTS_FILE ${XB_USE_MEMORY+--use-memory=$XB_USE_MEMORY} --prepare \
-	$REMAINING_XB_ARGS $transition_option --rollback-prepared-trx \
-	--xtrabackup-plugin-dir=/usr/lib64/xtrabackup/plugin --target-dir="$tmp" ${PXB_VAULT_PREPARE_ARGS}
+	# shellcheck disable=SC2086
+	xtrabackup $DEFAULTS_FILE ${XB_USE_MEMORY+--use-memory=$XB_USE_MEMORY} --prepare \
+		$REMAINING_XB_ARGS $transition_option "$@" \
+		--xtrabackup-plugin-dir=/usr/lib64/xtrabackup/plugin --target-dir="$tmp" ${PXB_VAULT_PREPARE_ARGS}
+}
+
+# tables of the partial restore are exported from the prepared backup
+# and imported into the running cluster by the operator
+PREPARE_ARGS=(--rollback-prepared-trx)
+if [[ ${PARTIAL_RESTORE} == "true" ]]; then
+	PREPARE_ARGS+=(--export)
+fi
+
+# the incremental backups are applied one by one,
+# so the resumed restore continues from the next incremental backup.
+# If the restore is interrupted while a backup is being prepared,
+# it's started over from the download of the full backup.
+if ! is_phase_completed prepare; then
+	start_phase prepare
+	if ((${#INCREMENTAL_BACKUPS[@]} > 0)); then
+		if ! is_phase_completed prepare-base; then
+			start_step prepare-base
+			prepare --apply-log-only
+			complete_step prepare-base
+		fi
+
+		last=$((${#INCREMENTAL_BACKUPS[@]} - 1))
+		for i in "${!INCREMENTAL_BACKUPS[@]}"; do
+			if is_phase_completed "prepare-incremental-$i"; then
+				continue
+			fi
+			incremental_dir=/datadir/pxc_incr_restore
+			rm -rf "${incremental_dir}"
+			mkdir -p "${incremental_dir}"
+			get_backup "${INCREMENTAL_BACKUPS[$i]}" "${incremental_dir}"
+
+			start_step "prepare-incremental-$i"
+			if ((i < last)); then
+				prepare --apply-log-only --incremental-dir="${incremental_dir}"
+			else
+				prepare "${PREPARE_ARGS[@]}" --incremental-dir="${incremental_dir}"
+			fi
+			rm -rf "${incremental_dir}"
+			complete_step "prepare-incremental-$i"
+		done
+	else
+		start_step prepare-full
+		prepare "${PREPARE_ARGS[@]}"
+		complete_step prepare-full
+	fi
+	complete_phase prepare
+fi
+
+if [[ ${PARTIAL_RESTORE} == "true" ]]; then
+	mv "$tmp" /datadir/backup
+	if [ -n "${ENCRYPTION_KEY_FILE}" ]; then
+		rm -f "${ENCRYPTION_KEY_FILE}"
+	fi
+	finish_progress
+	exit 0
+fi
+
+start_phase copy-back
 
 echo "+ xtrabackup $DEFAULTS_FILE --defaults-group=mysqld --datadir=/datadir --move-back \
 	$REMAINING_XB_ARGS --force-non-empty-directories $master_key_options \
@@ -147,3 +277,7 @@ xtrabackup $DEFAULTS_FILE $DEFAULTS_GROUP --datadir=/datadir --move-back $REMAIN
 	${PXB_VAULT_MOVEBACK_ARGS} --xtrabackup-plugin-dir=/usr/lib64/xtrabackup/plugin --target-dir="$tmp"
 
 rm -rf "$tmp"
+if [ -n "${ENCRYPTION_KEY_FILE}" ]; then
+	rm -f "${ENCRYPTION_KEY_FILE}"
+fi
+finish_progress
@@ -0,0 +1,119 @@
+#!/bin/bash
+
+set -o errexit
+set -o xtrace
+set -o pipefail
+
+LIB_PATH='/opt/percona/backup/lib/pxc'
+# shellcheck source=build/backup/lib/pxc/aws.sh
+. ${LIB_PATH}/aws.sh
+# shellcheck source=build/backup/lib/pxc/gcs.sh
+. ${LIB_PATH}/gcs.sh
+# shellcheck source=build/backup/lib/pxc/mysql.sh
+. ${LIB_PATH}/mysql.sh
+
+# The logical backup is downloaded into LOGICAL_DUMP_DIR and loaded by myloader into the running cluster.
+# The databases and tables selected by LOGICAL_RESTORE_DATABASES and LOGICAL_RESTORE_TABLES are loaded,
+# all the dumped databases are loaded if nothing is selected.
+
+LOGICAL_DUMP_DIR=${LOGICAL_DUMP_DIR:-/dump}
+LOGICAL_THREADS=${LOGICAL_THREADS:-$(grep -c processor /proc/cpuinfo)}
+
+if ! command -v myloader >/dev/null; then
+	echo 'myloader is not found in the image, set backup.logicalImage to an image with myloader'
+	exit 1
+fi
+
+# temporary fix for PXB-2784
+XBCLOUD_ARGS="--curl-retriable-errors=7 $XBCLOUD_EXTRA_ARGS"
+
+if [ -n "$VERIFY_TLS" ] && [[ $VERIFY_TLS == "false" ]]; then
+	XBCLOUD_ARGS="--insecure ${XBCLOUD_ARGS}"
+fi
+
+XBCLOUD_PARALLEL=${XBCLOUD_PARALLEL:-$(grep -c processor /proc/cpuinfo)}
+
+XBCLOUD_CMD=xbcloud
+BACKUP=""
+
+if [ -n "$S3_BUCKET_URL" ]; then
+	{ set +x; } 2>/dev/null
+	s3_add_bucket_dest
+	set -x
+	BACKUP="s3://${S3_BUCKET_URL}"
+elif [ -n "${GCS_BUCKET}" ]; then
+	XBCLOUD_CMD=xbcloud_gcs
+	BACKUP="${GCS_BUCKET_PATH}"
+elif [ -n "${BACKUP_PATH}" ]; then
+	XBCLOUD_ARGS="${XBCLOUD_ARGS} --storage=azure"
+	BACKUP="${BACKUP_PATH}"
+fi
+
+if [ -n "${AZURE_CONTAINER_NAME}" ]; then
+	XBCLOUD_ARGS="${XBCLOUD_ARGS} --azure-container-name=${AZURE_CONTAINER_NAME}"
+fi
+
+# copies stdin to stdout not faster than DOWNLOAD_RATE_LIMIT bytes per second
+download_rate_limit() {
+	if [[ -n ${DOWNLOAD_RATE_LIMIT} ]]; then
+		/opt/percona/ratelimit "${DOWNLOAD_RATE_LIMIT}"
+	else
+		cat
+	fi
+}
+
+rm -rf "${LOGICAL_DUMP_DIR:?}"/*
+mkdir -p "${LOGICAL_DUMP_DIR}"
+
+# shellcheck disable=SC2086
+$XBCLOUD_CMD get --parallel="${XBCLOUD_PARALLEL}" ${XBCLOUD_ARGS} "${BACKUP}" \
+	| download_rate_limit \
+	| xbstream -x -C "${LOGICAL_DUMP_DIR}" --parallel="$(grep -c processor /proc/cpuinfo)"
+
+if [ ! -f "${LOGICAL_DUMP_DIR}/metadata" ]; then
+	echo 'Backup is not a logical backup, metadata file is not found'
+	exit 1
+fi
+
+LOADER_ARGS=()
+filter=()
+for database in ${LOGICAL_RESTORE_DATABASES}; do
+	filter+=("${database}\\..*")
+done
+for table in ${LOGICAL_RESTORE_TABLES}; do
+	filter+=("${table%%.*}\\.${table#*.}")
+done
+if ((${#filter[@]} > 0)); then
+	LOADER_ARGS+=("--regex=^($(
+		IFS='|'
+		echo "${filter[*]}"
+	))$")
+fi
+
+# the tables of a single database are loaded into the target database
+if [ -n "${LOGICAL_RESTORE_TARGET_DATABASE}" ]; then
+	source_db=${LOGICAL_RESTORE_DATABASES%% *}
+	if [ -z "${source_db}" ]; then
+		source_db=${LOGICAL_RESTORE_TABLES%%.*}
+	fi
+	LOADER_ARGS+=("--source-db=${source_db}" "--database=${LOGICAL_RESTORE_TARGET_DATABASE}")
+fi
+
+{ set +x; } 2>/dev/null
+DEFAULTS_FILE=$(write_client_defaults "${PXC_USER}" "${PXC_PASS}")
+set -x
+trap 'rm -f "${DEFAULTS_FILE}"' EXIT
+
+# the backup is loaded through a single node to avoid certification conflicts
+# shellcheck disable=SC2086
+myloader \
+	--defaults-file="${DEFAULTS_FILE}" \
+	--host="${PXC_SERVICE}-0.${PXC_SERVICE}" \
+	--port=3306 \
+	--directory="${LOGICAL_DUMP_DIR}" \
+	--threads="${LOGICAL_THREADS}" \
+	--verbose=3 \
+	"${LOADER_ARGS[@]}" \
+	$MYLOADER_EXTRA_ARGS
+
+rm -rf "${LOGICAL_DUMP_DIR:?}"/*
@@ -0,0 +1,81 @@
+#!/bin/bash
+
+set -o errexit
+set -o xtrace
+
+# The backup is prepared with --export by the restore init container into /datadir/backup.
+# The script exports the tables selected by PARTIAL_RESTORE_DATABASES and PARTIAL_RESTORE_TABLES
+# into /datadir/export/<database>/<table> and waits until the operator imports them.
+
+BACKUP_DIR=/datadir/backup
+EXPORT_DIR=/datadir/export
+SOCKET=/tmp/partial-restore.sock
+PARTIAL_RESTORE_TIMEOUT=${PARTIAL_RESTORE_TIMEOUT:-86400}
+
+if [[ -f ${EXPORT_DIR}/.ready ]]; then
+	echo "tables are already exported"
+else
+	rm -rf "${EXPORT_DIR}"
+	mkdir -p "${EXPORT_DIR}"
+
+	mysqld --defaults-file="${BACKUP_DIR}/backup-my.cnf" --datadir="${BACKUP_DIR}" --socket="${SOCKET}" \
+		--skip-grant-tables --skip-networking --skip-log-bin --wsrep-provider=none \
+		--log-error="${EXPORT_DIR}/mysqld.log" &
+	mysqld_pid=$!
+
+	for _ in $(seq 300); do
+		if mysqladmin --socket="${SOCKET}" ping >/dev/null 2>&1; then
+			break
+		fi
+		if ! kill -0 "${mysqld_pid}" 2>/dev/null; then
+			cat "${EXPORT_DIR}/mysqld.log"
+			exit 1
+		fi
+		sleep 1
+	done
+
+	mysql_query() {
+		mysql --socket="${SOCKET}" -NBr -e "$1"
+	}
+
+	tables=()
+	for database in ${PARTIAL_RESTORE_DATABASES}; do
+		while read -r table; do
+			tables+=("${database}.${table}")
+		done < <(mysql_query "SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA='${database}' AND TABLE_TYPE='BASE TABLE' AND ENGINE='InnoDB'")
+	done
+	for table in ${PARTIAL_RESTORE_TABLES}; do
+		tables+=("${table}")
+	done
+
+	for table in "${tables[@]}"; do
+		database=${table%%.*}
+		name=${table#*.}
+		mkdir -p "${EXPORT_DIR}/${database}/${name}"
+		mysql_query "SHOW CREATE TABLE \`${database}\`.\`${name}\`" | cut -f2- >"${EXPORT_DIR}/${database}/${name}/create.sql"
+	done
+
+	mysqladmin --socket="${SOCKET}" shutdown
+	wait "${mysqld_pid}" || :
+
+	for table in "${tables[@]}"; do
+		database=${table%%.*}
+		name=${table#*.}
+		find "${BACKUP_DIR}/${database}" -maxdepth 1 \( -name "${name}.ibd" -o -name "${name}.cfg" -o -name "${name}#p#*" \) \
+			-exec cp {} "${EXPORT_DIR}/${database}/${name}/" \;
+		echo "${table}" >>"${EXPORT_DIR}/tables"
+	done
+
+	rm -rf "${BACKUP_DIR}"
+	touch "${EXPORT_DIR}/.ready"
+fi
+
+for _ in $(seq "${PARTIAL_RESTORE_TIMEOUT}"); do
+	if [[ -f ${EXPORT_DIR}/.done ]]; then
+		exit 0
+	fi
+	sleep 1
+done
+
+echo "tables are not imported in ${PARTIAL_RESTORE_TIMEOUT} seconds"
+exit 1
@@ -8,6 +8,8 @@ LIB_PATH='/opt/percona/backup/lib/pxc'
 . ${LIB_PATH}/check-version.sh
 # shellcheck source=build/backup/lib/pxc/vault.sh
 . ${LIB_PATH}/vault.sh
+# shellcheck source=build/backup/lib/pxc/progress.sh
+. ${LIB_PATH}/progress.sh
 
 SOCAT_OPTS="TCP:${RESTORE_SRC_SERVICE}:3307,retry=30"
 function check_ssl() {
@@ -38,17 +40,35 @@ function check_ssl() {
 
 check_ssl
 ping -c1 "$RESTORE_SRC_SERVICE" || :
-rm -rf /datadir/*
-tmp=$(mktemp --directory /datadir/pxc_sst_XXXX)
+# the backup is downloaded into the known directory, so the next pod of the job can resume the restore
+tmp=/datadir/pxc_sst_restore
+if ! init_progress; then
+	rm -rf /datadir/*
+fi
+mkdir -p "${tmp}"
 
-socat -u "$SOCAT_OPTS" stdio >"$tmp"/sst_info
+# counts the downloaded bytes if the progress of the restore is reported
+download_progress() {
+	if [[ -n ${RESTORE_ID} ]]; then
+		progress_counter
+	else
+		cat
+	fi
+}
 
 XTRABACKUP_VERSION=$(get_xtrabackup_version)
 if check_for_version "$XTRABACKUP_VERSION" '8.0.0'; then
 	XBSTREAM_EXTRA_ARGS="$XBSTREAM_EXTRA_ARGS --decompress"
 fi
-# shellcheck disable=SC2086
-socat -u "$SOCAT_OPTS" stdio | xbstream -x -C "$tmp" --parallel="$(grep -c processor /proc/cpuinfo)" $XBSTREAM_EXTRA_ARGS
+
+if ! is_phase_completed download; then
+	start_phase download
+	rm -rf "${tmp:?}"/* "${RESTORE_PROGRESS_DIR}/downloaded"
+	socat -u "$SOCAT_OPTS" stdio >"$tmp"/sst_info
+	# shellcheck disable=SC2086
+	socat -u "$SOCAT_OPTS" stdio | download_progress | xbstream -x -C "$tmp" --parallel="$(grep -c processor /proc/cpuinfo)" $XBSTREAM_EXTRA_ARGS
+	complete_phase download
+fi
 
 PXB_VAULT_PREPARE_ARGS=""
 PXB_VAULT_MOVEBACK_ARGS=""
@@ -98,20 +118,27 @@ else
 fi
 
 if ! check_for_version "$XTRABACKUP_VERSION" '8.0.0' \
-	&& ! check_for_version "$XTRABACKUP_VERSION" '8.4.0'; then
+	&& ! check_for_version "$XTRABACKUP_VERSION" '8.4.0' \
+	&& ! is_phase_completed prepare; then
 	# shellcheck disable=SC2086
 	innobackupex $DEFAULTS_FILE ${XB_USE_MEMORY+--use-memory=$XB_USE_MEMORY} --parallel="$(grep -c processor /proc/cpuinfo)" $REMAINING_XB_ARGS --decompress "$tmp"
 	XB_EXTRA_ARGS="$XB_EXTRA_ARGS --binlog-info=ON"
 fi
 
-echo "+ xtrabackup $DEFAULTS_FILE ${XB_USE_MEMORY+--use-memory=$XB_USE_MEMORY} \
+if ! is_phase_completed prepare; then
+	start_phase prepare
+	echo "+ xtrabackup $DEFAULTS_FILE ${XB_USE_MEMORY+--use-memory=$XB_USE_MEMORY} \
 	--prepare $REMAINING_XB_ARGS --xtrabackup-plugin-dir=/usr/lib64/xtrabackup/plugin \
 	--target-dir=$tmp ${PXB_VAULT_PREPARE_ARG}"
 
-# shellcheck disable=SC2086
-xtrabackup $DEFAULTS_FILE ${XB_USE_MEMORY+--use-memory=$XB_USE_MEMORY} \
-	--prepare $REMAINING_XB_ARGS $transition_option --rollback-prepared-trx \
-	--xtrabackup-plugin-dir=/usr/lib64/xtrabackup/plugin --target-dir="$tmp" ${PXB_VAULT_PREPARE_ARGS}
+	# shellcheck disable=SC2086
+	xtrabackup $DEFAULTS_FILE ${XB_USE_MEMORY+--use-memory=$XB_USE_MEMORY} \
+		--prepare $REMAINING_XB_ARGS $transition_option --rollback-prepared-trx \
+		--xtrabackup-plugin-dir=/usr/lib64/xtrabackup/plugin --target-dir="$tmp" ${PXB_VAULT_PREPARE_ARGS}
+	complete_phase prepare
+fi
+
+start_phase copy-back
 
 echo "+ xtrabackup $DEFAULTS_FILE --defaults-group=mysqld --datadir=/datadir --move-back \
 	$REMAINING_XB_ARGS --force-non-empty-directories $master_key_options \
@@ -123,3 +150,4 @@ xtrabackup $DEFAULTS_FILE --defaults-group=mysqld --datadir=/datadir --move-back
 	${PXB_VAULT_MOVEBACK_ARGS} --xtrabackup-plugin-dir=/usr/lib64/xtrabackup/plugin --target-dir="$tmp"
 
 rm -rf "$tmp"
+finish_progress
@@ -141,7 +141,7 @@ backup_s3() {
 	xbstream -C /tmp -c ${SST_INFO_NAME} $XBSTREAM_EXTRA_ARGS \
 		| xbcloud put --storage=s3 \
 			--md5 \
-			--parallel="$(grep -c processor /proc/cpuinfo)" \
+			--parallel="${XBCLOUD_PARALLEL}" \
 			$XBCLOUD_ARGS \
 			--s3-bucket="$S3_BUCKET" \
 			"$S3_BUCKET_PATH.$SST_INFO_NAME" 2>&1 \
@@ -160,9 +160,11 @@ backup_s3() {
 	if ((SST_FAILED == 0)); then
 		# shellcheck disable=SC2086
 		socat -u "$SOCAT_OPTS" stdio \
+			| encrypt_backup \
+			| upload_rate_limit \
 			| xbcloud put --storage=s3 \
 				--md5 \
-				--parallel="$(grep -c processor /proc/cpuinfo)" \
+				--parallel="${XBCLOUD_PARALLEL}" \
 				$XBCLOUD_ARGS \
 				--s3-bucket="$S3_BUCKET" \
 				"$S3_BUCKET_PATH" 2>&1 \
@@ -210,7 +212,7 @@ backup_azure() {
 	# shellcheck disable=SC2086
 	xbstream -C /tmp -c ${SST_INFO_NAME} $XBSTREAM_EXTRA_ARGS \
 		| xbcloud put --storage=azure \
-			--parallel="$(grep -c processor /proc/cpuinfo)" \
+			--parallel="${XBCLOUD_PARALLEL}" \
 			$XBCLOUD_ARGS \
 			"$BACKUP_PATH.$SST_INFO_NAME" 2>&1 \
 		| (grep -v "error: http request failed: Couldn't resolve host name" || exit 1)
@@ -228,8 +230,10 @@ backup_azure() {
 	if ((SST_FAILED == 0)); then
 		# shellcheck disable=SC2086
 		socat -u "$SOCAT_OPTS" stdio \
+			| encrypt_backup \
+			| upload_rate_limit \
 			| xbcloud put --storage=azure \
-				--parallel="$(grep -c processor /proc/cpuinfo)" \
+				--parallel="${XBCLOUD_PARALLEL}" \
 				$XBCLOUD_ARGS \
 				"$BACKUP_PATH" 2>&1 \
 		| (grep -v "error: http request failed: Couldn't resolve host name" || exit 1) &
@@ -239,6 +243,63 @@ backup_azure() {
 	log 'INFO' "Backup is uploaded to azure successfully."
 }
 
+backup_gcs() {
+	log 'INFO' "Backup to gs://$GCS_BUCKET/$GCS_BUCKET_PATH"
+
+	local socat_status
+	# shellcheck disable=SC2086
+	socat -u "$SOCAT_OPTS" stdio | xbstream -x -C /tmp $XBSTREAM_EXTRA_ARGS &
+	wait $!
+	socat_status=$?
+	log 'INFO' 'Socat was started'
+
+	FIRST_RECEIVED=1
+	if [[ ${socat_status} -ne 0 ]]; then
+		log 'ERROR' 'Socat(1) failed'
+		log 'ERROR' 'Backup was finished unsuccessfully'
+		exit 1
+	fi
+	vault_store /tmp/${SST_INFO_NAME}
+
+	# this xbcloud command will fail with backup is incomplete
+	# it's expected since we only upload sst_info
+	set +o pipefail
+	# shellcheck disable=SC2086
+	xbstream -C /tmp -c ${SST_INFO_NAME} $XBSTREAM_EXTRA_ARGS \
+		| xbcloud_gcs put \
+			--md5 \
+			--parallel="${XBCLOUD_PARALLEL}" \
+			$XBCLOUD_ARGS \
+			"$GCS_BUCKET_PATH.$SST_INFO_NAME" 2>&1 \
+		| (grep -v "error: http request failed: Couldn't resolve host name" || exit 1)
+	set -o pipefail
+
+	log 'INFO' "${GCS_BUCKET_PATH}.${SST_INFO_NAME} is uploaded to gcs successfully."
+
+	MYSQL_VERSION=$(parse_ini 'mysql-version' /tmp/${SST_INFO_NAME})
+	# if PXC 5.7
+	if check_for_version "$MYSQL_VERSION" '5.7.0' && ! check_for_version "$MYSQL_VERSION" '8.0.0'; then
+		# ignore SIGTERM from garbd, it has no idea that we still have work to do.
+		trap '' 15
+	fi
+
+	if ((SST_FAILED == 0)); then
+		# shellcheck disable=SC2086
+		socat -u "$SOCAT_OPTS" stdio \
+			| encrypt_backup \
+			| upload_rate_limit \
+			| xbcloud_gcs put \
+				--md5 \
+				--parallel="${XBCLOUD_PARALLEL}" \
+				$XBCLOUD_ARGS \
+				"$GCS_BUCKET_PATH" 2>&1 \
+			| (grep -v "error: http request failed: Couldn't resolve host name" || exit 1) &
+		wait $!
+	fi
+
+	log 'INFO' "Backup is uploaded to gcs successfully."
+}
+
 check_ssl
 
 trap handle_sigterm 15
@@ -247,6 +308,8 @@ if [ -n "$S3_BUCKET" ]; then
 	backup_s3
 elif [ -n "$AZURE_CONTAINER_NAME" ]; then
 	backup_azure
+elif [ -n "$GCS_BUCKET" ]; then
+	backup_gcs
 else
 	backup_volume
 fi
@@ -0,0 +1,66 @@
+#!/bin/bash
+
+set -o errexit
+set -o xtrace
+
+DATADIR=/var/lib/mysql
+SOCKET=/tmp/mysqld-verify.sock
+MYSQL_VERSION=$(mysqld -V | awk '{print $3}' | awk -F'.' '{print $1"."$2}')
+
+MYSQLD_ARGS=()
+vault_secret="/etc/mysql/vault-keyring-secret/keyring_vault.conf"
+if [ -f "${vault_secret}" ]; then
+	if [[ $MYSQL_VERSION =~ ^(5\.7|8\.0)$ ]]; then
+		MYSQLD_ARGS+=(--early-plugin-load=keyring_vault.so --keyring-vault-config="${vault_secret}")
+	fi
+
+	if [[ $MYSQL_VERSION == '8.4' ]]; then
+		echo -n '{ "components": "file://component_keyring_vault" }' >"${DATADIR}/mysqld.my"
+		cp "${vault_secret}" "${DATADIR}/component_keyring_vault.cnf"
+	fi
+fi
+
+mysql_exec() {
+	mysql --socket="${SOCKET}" --batch --skip-column-names "$@"
+}
+
+mysqld --datadir="${DATADIR}" --socket="${SOCKET}" --wsrep-provider=none \
+	--skip-grant-tables --skip-networking --skip-log-bin "${MYSQLD_ARGS[@]}" &
+pid=$!
+
+set +o xtrace
+for _ in $(seq "${VERIFY_STARTUP_TIMEOUT:-600}"); do
+	if mysqladmin --socket="${SOCKET}" ping >/dev/null 2>&1; then
+		break
+	fi
+	if ! kill -0 "${pid}" 2>/dev/null; then
+		echo "mysqld exited before accepting connections"
+		exit 1
+	fi
+	sleep 1
+done
+set -o xtrace
+
+mysqladmin --socket="${SOCKET}" ping
+
+if ((${VERIFY_CHECK_TABLES_LIMIT:-0} > 0)); then
+	mapfile -t tables < <(mysql_exec -e "SELECT CONCAT('\`', TABLE_SCHEMA, '\`.\`', TABLE_NAME, '\`') FROM information_schema.TABLES
+		WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA NOT IN ('mysql', 'sys', 'performance_schema', 'information_schema')
+		ORDER BY RAND() LIMIT ${VERIFY_CHECK_TABLES_LIMIT}")
+
+	for table in "${tables[@]}"; do
+		# CHECK TABLE returns Table, Op, Msg_type and Msg_text columns
+		errors=$(mysql_exec -e "CHECK TABLE ${table}" | awk -F'\t' '$3 == "error" || ($3 == "status" && $4 != "OK")')
+		if [ -n "${errors}" ]; then
+			echo "CHECK TABLE ${table} failed: ${errors}"
+			exit 1
+		fi
+	done
+fi
+
+if [ -n "${VERIFY_QUERIES}" ]; then
+	echo "${VERIFY_QUERIES}" | mysql_exec --table
+fi
+
+mysqladmin --socket="${SOCKET}" shutdown
+wait "${pid}"
@@ -14,6 +14,7 @@ function main() {
 
 	NODE_LIST=()
 	NODE_LIST_REPL=()
+	NODE_LIST_READERS=()
 	NODE_LIST_MYSQLX=()
 	NODE_LIST_ADMIN=()
 	NODE_LIST_BACKUP=()
@@ -29,6 +30,14 @@ function main() {
 		send_proxy='send-proxy-v2'
 	fi
 
+	# the writer node elected by the operator, the first node is the writer if it isn't set.
+	# The operator also puts the other nodes into maintenance through the runtime API,
+	# so the backup servers don't take over the writes on the health checks of a single HAProxy pod.
+	primary_node=''
+	if [ -f /etc/haproxy/primary/primary ]; then
+		primary_node=$(cat /etc/haproxy/primary/primary)
+	fi
+
 	while read pxc_host; do
 		if [ -z "$pxc_host" ]; then
 			log 'Could not find PEERS ...'
@@ -38,7 +47,7 @@ function main() {
 		node_name=$(echo "$pxc_host" | cut -d . -f -1)
 		node_id=$(echo $node_name | awk -F'-' '{print $NF}')
 		NODE_LIST_REPL+=("server $node_name $pxc_host:3306 $send_proxy $SERVER_OPTIONS")
-		if [ "x$node_id" == 'x0' ]; then
+		if [[ -n $primary_node && $node_name == "$primary_node" ]] || [[ -z $primary_node && "x$node_id" == 'x0' ]]; then
 			firs_node_replica="$pxc_host"
 			main_node="$pxc_host"
 			firs_node="server $node_name $pxc_host:3306 $send_proxy $SERVER_OPTIONS on-marked-up shutdown-backup-sessions"
@@ -46,12 +55,26 @@ function main() {
 			firs_node_mysqlx="server $node_name $pxc_host:33060 $SERVER_OPTIONS on-marked-up shutdown-backup-sessions"
 			continue
 		fi
+		NODE_LIST_READERS+=("server $node_name $pxc_host:3306 $send_proxy $SERVER_OPTIONS")
 		NODE_LIST_BACKUP+=("galera-nodes/$node_name" "galera-admin-nodes/$node_name")
 		NODE_LIST+=("server $node_name $pxc_host:3306 $send_proxy $SERVER_OPTIONS backup")
 		NODE_LIST_ADMIN+=("server $node_name $pxc_host:33062 $SERVER_OPTIONS backup")
 		NODE_LIST_MYSQLX+=("server $node_name $pxc_host:33060 $send_proxy $SERVER_OPTIONS backup")
 	done
 
+	# the async replicas of the cluster are added only to the replicas backend
+	NODE_LIST_ASYNC=()
+	async_id=0
+	for async_host in ${REPLICAS_ASYNC_HOSTS}; do
+		async_port=3306
+		if [[ $async_host == *:* ]]; then
+			async_port=${async_host##*:}
+			async_host=${async_host%:*}
+		fi
+		NODE_LIST_ASYNC+=("server async-replica-$async_id $async_host:$async_port $SERVER_OPTIONS")
+		async_id=$((async_id + 1))
+	done
+
 	if [ -n "$firs_node" ]; then
 		if [[ "${#NODE_LIST[@]}" -ne 0 ]]; then
 			NODE_LIST=("$firs_node" "$(printf '%s\n' "${NODE_LIST[@]}" | sort --version-sort -r | uniq)")
@@ -81,6 +104,16 @@ function main() {
 
 	log "number of available nodes are ${#NODE_LIST_REPL[@]}"
 	echo "${#NODE_LIST_REPL[@]}" >$path_to_haproxy_cfg/AVAILABLE_NODES
+
+	# the settings of the replicas backend are read by haproxy_check_pxc.sh
+	for setting in REPLICAS_MAX_RECV_QUEUE REPLICAS_EXCLUDE_DONORS REPLICAS_MAX_SECONDS_BEHIND_SOURCE; do
+		if [ -n "${!setting}" ]; then
+			echo "${!setting}" >"$path_to_haproxy_cfg/$setting"
+		else
+			rm -f "$path_to_haproxy_cfg/$setting"
+		fi
+	done
+
 	(
 		IFS=$'\n'
 		echo "${NODE_LIST[*]}"
@@ -117,7 +150,7 @@ function main() {
 		if [ -n "$firs_node_replica" ]; then
 			(
 				IFS=$'\n'
-				echo "${NODE_LIST_REPL[*]:1}"
+				echo "${NODE_LIST_READERS[*]}"
 			) >>"$path_to_haproxy_cfg/haproxy.cfg"
 		else
 			NODE_LIST_REPL=("$(printf "%s\n" "${NODE_LIST_REPL[@]}" | sort -r | tail -n +2)")
@@ -128,6 +161,13 @@ function main() {
 		fi
 	fi
 
+	if [[ "${#NODE_LIST_ASYNC[@]}" -ne 0 ]]; then
+		(
+			IFS=$'\n'
+			echo "${NODE_LIST_ASYNC[*]}"
+		) >>"$path_to_haproxy_cfg/haproxy.cfg"
+	fi
+
 	cat <<-EOF >>"$path_to_haproxy_cfg/haproxy.cfg"
 		    backend galera-mysqlx-nodes
 		      mode tcp
@@ -31,6 +31,18 @@ if [ -f "$path_to_haproxy_cfg/AVAILABLE_NODES" ]; then
 	AVAILABLE_NODES=$(/bin/cat $path_to_haproxy_cfg/AVAILABLE_NODES)
 fi
 
+# the settings of the replicas backend written by haproxy_add_pxc_nodes.sh
+if [ -f "$path_to_haproxy_cfg/REPLICAS_MAX_RECV_QUEUE" ]; then
+	REPLICAS_MAX_RECV_QUEUE=$(/bin/cat $path_to_haproxy_cfg/REPLICAS_MAX_RECV_QUEUE)
+fi
+if [ -f "$path_to_haproxy_cfg/REPLICAS_EXCLUDE_DONORS" ]; then
+	REPLICAS_EXCLUDE_DONORS=$(/bin/cat $path_to_haproxy_cfg/REPLICAS_EXCLUDE_DONORS)
+fi
+REPLICAS_MAX_SECONDS_BEHIND_SOURCE=60
+if [ -f "$path_to_haproxy_cfg/REPLICAS_MAX_SECONDS_BEHIND_SOURCE" ]; then
+	REPLICAS_MAX_SECONDS_BEHIND_SOURCE=$(/bin/cat $path_to_haproxy_cfg/REPLICAS_MAX_SECONDS_BEHIND_SOURCE)
+fi
+
 log() {
 	local address=$1
 	local port=$2
@@ -43,14 +55,43 @@ log() {
 	fi
 }
 
+# the async replicas are checked by the replication lag, they aren't nodes of the cluster
+if [[ ${HAPROXY_SERVER_NAME} == async-replica-* ]]; then
+	SECONDS_BEHIND_SOURCE=($(MYSQL_PWD="${MONITOR_PASSWORD}" /usr/bin/timeout $TIMEOUT /usr/bin/mysql -nE -u$MONITOR_USER -h $PXC_SERVER_IP -P $4 \
+		-e "SHOW REPLICA STATUS;" \
+		| /usr/bin/awk '$1 == "Seconds_Behind_Source:" {print $2}'))
+
+	status_log="Seconds_Behind_Source of async replica $PXC_SERVER_IP in backend $HAPROXY_PROXY_NAME is ${SECONDS_BEHIND_SOURCE[*]:-not found}"
+	replica_ok=1
+	if [[ ${#SECONDS_BEHIND_SOURCE[@]} -eq 0 ]]; then
+		replica_ok=0
+	fi
+	for lag in "${SECONDS_BEHIND_SOURCE[@]}"; do
+		# the lag is NULL if the replication is stopped
+		if [[ ! $lag =~ ^[0-9]+$ || $lag -gt $REPLICAS_MAX_SECONDS_BEHIND_SOURCE ]]; then
+			replica_ok=0
+		fi
+	done
+
+	if [[ $replica_ok -eq 1 ]]; then
+		log "$PXC_SERVER_IP" "$4" "$status_log" "$VERBOSE"
+		log "$PXC_SERVER_IP" "$4" "Async replica $PXC_SERVER_IP for backend $HAPROXY_PROXY_NAME is ok" "$VERBOSE"
+		exit 0
+	fi
+	log "$PXC_SERVER_IP" "$4" "$status_log" 1
+	log "$PXC_SERVER_IP" "$4" "Async replica $PXC_SERVER_IP for backend $HAPROXY_PROXY_NAME is not ok" 1
+	exit 1
+fi
+
 PXC_NODE_STATUS=($(MYSQL_PWD="${MONITOR_PASSWORD}" $MYSQL_CMDLINE -h $PXC_SERVER_IP -P $PXC_SERVER_PORT \
 	-e "SHOW STATUS LIKE 'wsrep_local_state'; \
         SHOW VARIABLES LIKE 'pxc_maint_mode'; \
         SHOW GLOBAL STATUS LIKE 'wsrep_cluster_status'; \
         SHOW GLOBAL VARIABLES LIKE 'wsrep_reject_queries'; \
-        SHOW GLOBAL VARIABLES LIKE 'wsrep_sst_donor_rejects_queries';" \
-	| /usr/bin/grep -A 1 -E 'wsrep_local_state$|pxc_maint_mode$|wsrep_cluster_status$|wsrep_reject_queries$|wsrep_sst_donor_rejects_queries$' \
-	| /usr/bin/sed -n -e '2p' -e '5p' -e '8p' -e '11p' -e '14p' \
+        SHOW GLOBAL VARIABLES LIKE 'wsrep_sst_donor_rejects_queries'; \
+        SHOW GLOBAL STATUS LIKE 'wsrep_local_recv_queue';" \
+	| /usr/bin/grep -A 1 -E 'wsrep_local_state$|pxc_maint_mode$|wsrep_cluster_status$|wsrep_reject_queries$|wsrep_sst_donor_rejects_queries$|wsrep_local_recv_queue$' \
+	| /usr/bin/sed -n -e '2p' -e '5p' -e '8p' -e '11p' -e '14p' -e '17p' \
 	| /usr/bin/tr '\n' ' '))
 
 # ${PXC_NODE_STATUS[0]} - wsrep_local_state
@@ -58,12 +99,25 @@ PXC_NODE_STATUS=($(MYSQL_PWD="${MONITOR_PASSWORD}" $MYSQL_CMDLINE -h $PXC_SERVER
 # ${PXC_NODE_STATUS[2]} - wsrep_cluster_status
 # ${PXC_NODE_STATUS[3]} - wsrep_reject_queries
 # ${PXC_NODE_STATUS[4]} - wsrep_sst_donor_rejects_queries
+# ${PXC_NODE_STATUS[5]} - wsrep_local_recv_queue
 status_log="The following values are used for PXC node $PXC_SERVER_IP in backend $HAPROXY_PROXY_NAME: "
-status_log+="wsrep_local_state is ${PXC_NODE_STATUS[0]}; pxc_maint_mod is ${PXC_NODE_STATUS[1]}; wsrep_cluster_status is ${PXC_NODE_STATUS[2]}; wsrep_reject_queries is ${PXC_NODE_STATUS[3]}; wsrep_sst_donor_rejects_queries is ${PXC_NODE_STATUS[4]}; $AVAILABLE_NODES nodes are available"
+status_log+="wsrep_local_state is ${PXC_NODE_STATUS[0]}; pxc_maint_mod is ${PXC_NODE_STATUS[1]}; wsrep_cluster_status is ${PXC_NODE_STATUS[2]}; wsrep_reject_queries is ${PXC_NODE_STATUS[3]}; wsrep_sst_donor_rejects_queries is ${PXC_NODE_STATUS[4]}; wsrep_local_recv_queue is ${PXC_NODE_STATUS[5]}; $AVAILABLE_NODES nodes are available"
+
+# the replicas backend skips the donors and the nodes which are behind the cluster
+REPLICA_IS_OK=1
+if [[ ${HAPROXY_PROXY_NAME} == 'galera-replica-nodes' ]]; then
+	if [[ ${REPLICAS_EXCLUDE_DONORS} == 'true' && ${PXC_NODE_STATUS[0]} -ne 4 ]]; then
+		REPLICA_IS_OK=0
+	fi
+	if [[ -n ${REPLICAS_MAX_RECV_QUEUE} && ${PXC_NODE_STATUS[5]} -gt ${REPLICAS_MAX_RECV_QUEUE} ]]; then
+		REPLICA_IS_OK=0
+	fi
+fi
 
 if [[ ${PXC_NODE_STATUS[2]} == 'Primary' &&  ( ${PXC_NODE_STATUS[0]} -eq 4 || \
     ${PXC_NODE_STATUS[0]} -eq 2 && ( "${AVAILABLE_NODES}" -le 1 || "${DONOR_IS_OK}" -eq 1 ) ) \
-    && ${PXC_NODE_STATUS[1]} == 'DISABLED' && ${PXC_NODE_STATUS[3]} == 'NONE' && ${PXC_NODE_STATUS[4]} == 'OFF' ]];
+    && ${PXC_NODE_STATUS[1]} == 'DISABLED' && ${PXC_NODE_STATUS[3]} == 'NONE' && ${PXC_NODE_STATUS[4]} == 'OFF' \
+    && ${REPLICA_IS_OK} -eq 1 ]];
 then
     log "$PXC_SERVER_IP" "$PXC_SERVER_PORT" "$status_log" "$VERBOSE"
     log "$PXC_SERVER_IP" "$PXC_SERVER_PORT" "PXC node $PXC_SERVER_IP for backend $HAPROXY_PROXY_NAME is ok" "$VERBOSE"
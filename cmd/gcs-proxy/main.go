@@ -0,0 +1,41 @@
+// A small utility program which allows xbcloud to use Google Cloud Storage without HMAC keys.
+// It listens on a random local port, writes its URL to the file passed as the argument
+// and forwards the requests to GCS authorized with an access token of the service account key
+// in GCS_CREDENTIALS_JSON or, if it's not set, of the pod's service account (e.g. GKE workload identity).
+// xbcloud should be started with the proxy URL as --google-endpoint.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatalf("Usage: %s <url file>", os.Args[0])
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	url, err := storage.StartGCSProxy(ctx, []byte(os.Getenv("GCS_CREDENTIALS_JSON")), os.Getenv("GCS_ENDPOINT"), os.Getenv("VERIFY_TLS") != "false")
+	if err != nil {
+		log.Fatalf("Start proxy: %v", err)
+	}
+
+	// the file is renamed, so the script never reads a partially written URL
+	tmp := os.Args[1] + ".tmp"
+	if err := os.WriteFile(tmp, []byte(url), 0o600); err != nil {
+		log.Fatalf("Write url: %v", err)
+	}
+	if err := os.Rename(tmp, os.Args[1]); err != nil {
+		log.Fatalf("Write url: %v", err)
+	}
+
+	<-ctx.Done()
+}
@@ -46,6 +46,7 @@ var (
 	namespace = flag.String("ns", "", "The namespace this pod is running in. If unspecified, the POD_NAMESPACE env var is used.")
 	domain    = flag.String("domain", "", "The Cluster Domain which is used by the Cluster, if not set tries to determine it from /etc/resolv.conf file.")
 	protocol  = flag.String("protocol", "", "The protocol used for SRV lookups. The default value is empty string. Acceptable values are also tcp and udp.")
+	watchFile = flag.String("watch-file", "", "File which is watched for changes, the on-change script is run with the current peers when its content is changed.")
 )
 
 func lookup(svcName, protocol string) (sets.String, error) {
@@ -70,6 +71,21 @@ func lookup(svcName, protocol string) (sets.String, error) {
 	return endpoints, nil
 }
 
+// readWatchFile returns the content of the watched file, a missing file is read as empty.
+func readWatchFile(path string) string {
+	if path == "" {
+		return ""
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Failed to read %s: %v", path, err)
+		}
+		return ""
+	}
+	return string(content)
+}
+
 func shellOut(sendStdin, script string) {
 	log.Printf("execing: %v with stdin: %v", script, sendStdin)
 	// TODO: Switch to sending stdin from go
@@ -160,7 +176,9 @@ func main() {
 	}
 	proto := normalizedProtocol
 
+	watched := readWatchFile(*watchFile)
 	for peers := sets.NewString(); script != ""; time.Sleep(pollPeriod) {
+		content := readWatchFile(*watchFile)
 		newPeers, err = lookup(*svc, proto)
 		if err != nil {
 			log.Printf("%v", err)
@@ -178,11 +196,17 @@ func main() {
 				log.Printf("Peer list updated\nwas %v\nnow %v", peers.List(), newPeers.List())
 				shellOut(strings.Join(peerList, "\n"), script)
 				peers = newPeers
+				watched = content
 				lastChangeTime = time.Now()
 				isFirstUpdate = false
 			} else {
 				log.Printf("Ignoring peer list update, last change was %v ago", time.Since(lastChangeTime))
 			}
+		} else if content != watched && peers.Len() > 0 && *onChange != "" {
+			// the changes of the watched file aren't delayed, they are made by the operator
+			log.Printf("%s is updated\nwas %q\nnow %q", *watchFile, watched, content)
+			shellOut(strings.Join(peers.List(), "\n"), *onChange)
+			watched = content
 		}
 		script = *onChange
 	}
@@ -0,0 +1,254 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/timeline"
+)
+
+// mysqlbinlogTimeFormat is the format of the times printed and accepted by mysqlbinlog.
+const mysqlbinlogTimeFormat = "2006-01-02 15:04:05"
+
+// uploadedGTIDSets caches the GTID sets of the uploaded binlogs. The binlogs are never
+// changed after the upload, so the sets are only read from the storage once.
+var uploadedGTIDSets sync.Map
+
+// ListBinlogs returns the binlogs uploaded to the storage with their GTID sets
+// ordered by the time of their first event.
+func (c *Collector) ListBinlogs(ctx context.Context) ([]timeline.Binlog, error) {
+	objs, err := c.storage.ListObjects(ctx, timeline.BinlogPrefix)
+	if err != nil {
+		return nil, errors.Wrapf(err, "list objects with prefix %s", timeline.BinlogPrefix)
+	}
+
+	var binlogs []timeline.Binlog
+	for _, obj := range objs {
+		if strings.HasSuffix(obj, gtidPostfix) {
+			continue
+		}
+		t, err := timeline.ParseBinlogName(obj)
+		if err != nil {
+			continue
+		}
+		set, err := c.uploadedGTIDSet(ctx, obj)
+		if err != nil {
+			return nil, err
+		}
+		binlogs = append(binlogs, timeline.Binlog{Name: obj, FirstEventTime: t, GTIDSet: set})
+	}
+
+	sort.SliceStable(binlogs, func(i, j int) bool {
+		return binlogs[i].FirstEventTime.Before(binlogs[j].FirstEventTime)
+	})
+
+	return binlogs, nil
+}
+
+func (c *Collector) uploadedGTIDSet(ctx context.Context, binlog string) (string, error) {
+	if set, ok := uploadedGTIDSets.Load(binlog); ok {
+		return set.(string), nil
+	}
+
+	obj, err := c.storage.GetObject(ctx, binlog+gtidPostfix)
+	if err != nil {
+		return "", errors.Wrapf(err, "get GTID set of %s", binlog)
+	}
+	defer obj.Close()
+
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		return "", errors.Wrapf(err, "read GTID set of %s", binlog)
+	}
+	set := strings.TrimSpace(string(data))
+	uploadedGTIDSets.Store(binlog, set)
+
+	return set, nil
+}
+
+// Timeline returns the periods of time the cluster can be restored to from the uploaded binlogs.
+// The windows end at the latest event uploaded by the collector and at the last event before the gaps.
+func (c *Collector) Timeline(ctx context.Context) (*timeline.Timeline, []timeline.Binlog, error) {
+	binlogs, err := c.ListBinlogs(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "list binlogs")
+	}
+
+	latest, err := readLatestTimestamp()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "read timeline file")
+	}
+
+	t := timeline.New(binlogs, latest)
+
+	// the last event time of the binlogs isn't stored, the binlogs before the gaps
+	// are decoded to not report the missing transactions as restorable
+	for i, gap := range t.Gaps {
+		last, err := c.lastEventTime(ctx, gap.After)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "get last event time of %s", gap.After)
+		}
+		if last.IsZero() || last.After(gap.End) {
+			continue
+		}
+		t.Gaps[i].Start = last
+		t.Windows[i].End = last
+		for j := range binlogs {
+			if binlogs[j].Name == gap.After {
+				binlogs[j].LastEventTime = last
+			}
+		}
+	}
+
+	gapSet, err := os.ReadFile(naming.GapDetected)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, errors.Wrapf(err, "read %s", naming.GapDetected)
+	}
+	t.GapDetected = strings.TrimSpace(string(gapSet))
+
+	return t, binlogs, nil
+}
+
+// readLatestTimestamp returns the time of the latest uploaded event from the timeline file.
+// The zero time is returned if nothing has been uploaded since the collector was started.
+func readLatestTimestamp() (time.Time, error) {
+	data, err := os.ReadFile(naming.TimelinePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) < 2 {
+		return time.Time{}, nil
+	}
+	ts, err := strconv.ParseInt(strings.TrimSpace(lines[len(lines)-1]), 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "parse latest timestamp %s", lines[len(lines)-1])
+	}
+
+	return time.Unix(ts, 0), nil
+}
+
+var eventHeaderRegexp = regexp.MustCompile(`^#(\d{6}\s+\d{1,2}:\d{2}:\d{2})\s+server id`)
+
+// lastEventTime returns the time of the last event in the uploaded binlog.
+func (c *Collector) lastEventTime(ctx context.Context, binlog string) (time.Time, error) {
+	pr, pw := io.Pipe()
+	resCh := make(chan time.Time, 1)
+	go func() {
+		var last time.Time
+		s := bufio.NewScanner(pr)
+		s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for s.Scan() {
+			m := eventHeaderRegexp.FindStringSubmatch(s.Text())
+			if m == nil {
+				continue
+			}
+			t, err := time.ParseInLocation("060102 15:04:05", strings.Join(strings.Fields(m[1]), " "), time.Local)
+			if err == nil {
+				last = t
+			}
+		}
+		// drain the output of mysqlbinlog if the line is too long
+		_, _ = io.Copy(io.Discard, pr)
+		resCh <- last
+	}()
+
+	err := c.decodeBinlog(ctx, binlog, nil, pw)
+	pw.Close()
+	last := <-resCh
+
+	return last, err
+}
+
+// DecodeEvents writes the events of the uploaded binlogs between the times
+// decoded by mysqlbinlog with the row events printed as pseudo SQL.
+func (c *Collector) DecodeEvents(ctx context.Context, from, to time.Time, w io.Writer) error {
+	binlogs, err := c.ListBinlogs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "list binlogs")
+	}
+
+	args := []string{
+		"--base64-output=DECODE-ROWS",
+		"--verbose",
+		"--start-datetime=" + from.In(time.Local).Format(mysqlbinlogTimeFormat),
+		"--stop-datetime=" + to.In(time.Local).Format(mysqlbinlogTimeFormat),
+	}
+
+	for i, b := range binlogs {
+		if b.FirstEventTime.After(to) {
+			break
+		}
+		if i < len(binlogs)-1 && binlogs[i+1].FirstEventTime.Before(from) {
+			continue
+		}
+		if err := c.decodeBinlog(ctx, b.Name, args, w); err != nil {
+			return errors.Wrapf(err, "decode %s", b.Name)
+		}
+	}
+
+	return nil
+}
+
+// decodeBinlog downloads the binlog from the storage and writes the output of mysqlbinlog.
+func (c *Collector) decodeBinlog(ctx context.Context, binlog string, args []string, w io.Writer) error {
+	obj, err := c.storage.GetObject(ctx, binlog)
+	if err != nil {
+		return errors.Wrap(err, "get object")
+	}
+	defer obj.Close()
+
+	cmd := exec.CommandContext(ctx, "mysqlbinlog", append(args, "-")...)
+	cmd.Stdin = obj
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+
+	var xbcrypt *exec.Cmd
+	if c.encryption.Enabled() {
+		keyFile, err := c.encryption.WriteKeyFile(ctx, os.TempDir())
+		if err != nil {
+			return errors.Wrap(err, "write encryption key")
+		}
+		defer os.Remove(keyFile) //nolint:errcheck
+
+		xbcrypt = c.encryption.XbcryptCmd(ctx, keyFile, true)
+		xbcrypt.Stdin = obj
+		xbcrypt.Stderr = os.Stderr
+		cmd.Stdin, err = xbcrypt.StdoutPipe()
+		if err != nil {
+			return errors.Wrap(err, "xbcrypt stdout pipe")
+		}
+		if err := xbcrypt.Start(); err != nil {
+			return errors.Wrap(err, "start xbcrypt")
+		}
+	}
+
+	log.Printf("Running %s", cmd.String())
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, "run mysqlbinlog")
+	}
+	if xbcrypt != nil {
+		if err := xbcrypt.Wait(); err != nil {
+			return errors.Wrap(err, "decrypt")
+		}
+	}
+
+	return nil
+}
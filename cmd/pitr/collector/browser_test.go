@@ -0,0 +1,45 @@
+package collector
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage/mock"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/timeline"
+)
+
+func TestListBinlogs(t *testing.T) {
+	ctx := context.Background()
+
+	s := mock.NewStorage(t)
+	s.On("ListObjects", ctx, "binlog_").Return([]string{
+		"binlog_1704103200_000012_0a1b",
+		"binlog_1704103200_000012_0a1b-gtid-set",
+		"binlog_1704096000_000011_9f8e",
+		"binlog_1704096000_000011_9f8e-gtid-set",
+		"binlog_unexpected",
+	}, nil)
+	s.On("GetObject", ctx, "binlog_1704096000_000011_9f8e-gtid-set").Return(io.NopCloser(strings.NewReader("uuid:1-100\n")), nil).Once()
+	s.On("GetObject", ctx, "binlog_1704103200_000012_0a1b-gtid-set").Return(io.NopCloser(strings.NewReader("uuid:101-200")), nil).Once()
+
+	c := &Collector{storage: s}
+
+	expected := []timeline.Binlog{
+		{Name: "binlog_1704096000_000011_9f8e", FirstEventTime: time.Unix(1704096000, 0), GTIDSet: "uuid:1-100"},
+		{Name: "binlog_1704103200_000012_0a1b", FirstEventTime: time.Unix(1704103200, 0), GTIDSet: "uuid:101-200"},
+	}
+
+	binlogs, err := c.ListBinlogs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, binlogs)
+
+	// the GTID sets are read from the storage once
+	binlogs, err = c.ListBinlogs(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, binlogs)
+}
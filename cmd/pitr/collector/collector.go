@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/md5"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -13,6 +14,7 @@ import (
 	"os/exec"
 	"path"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -23,7 +25,10 @@ import (
 
 	"github.com/percona/percona-xtradb-cluster-operator/cmd/pitr/pxc"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/encryption"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/throttling"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/timeline"
 )
 
 const collectorPasswordPath = "/etc/mysql/mysql-users-secret/xtrabackup"
@@ -59,6 +64,12 @@ var (
 			Help: "Timestamp of the last successful binlog upload",
 		},
 	)
+	pxcBinlogCollectorFailoverTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pxc_binlog_collector_failover_total",
+			Help: "Total number of failovers to another PXC host",
+		},
+	)
 	pxcBinlogCollectorGapDetected = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Name: "pxc_binlog_collector_gap_detected_total",
@@ -73,6 +84,7 @@ func init() {
 	prometheus.MustRegister(pxcBinlogCollectorLastProcessingTime)
 	prometheus.MustRegister(pxcBinlogCollectorLastUploadTime)
 	prometheus.MustRegister(pxcBinlogCollectorGapDetected)
+	prometheus.MustRegister(pxcBinlogCollectorFailoverTotal)
 	prometheus.MustRegister(pxcBinlogCollectorUploadedTotal)
 }
 
@@ -85,6 +97,14 @@ type Collector struct {
 	pxcPass         string      // password for connection to PXC
 	gtidCacheKey    string      // filename of gtid cache json
 	sourceID        string
+	encryption      encryption.Config // the key is resolved, see encryption.Config.WithKey
+	uploadRateLimit int64             // bytes per second
+	parallelUploads int               // number of binlogs uploaded concurrently
+	host            string            // host the binlogs were collected from in the last run
+	// lastListedBinlog is the last binlog listed in the last run, the binlogs
+	// are flushed after the listing, so it's the last binlog which can be collected
+	lastListedBinlog string
+	stream           streamConfig
 }
 
 type Config struct {
@@ -92,13 +112,28 @@ type Config struct {
 	PXCUser            string `env:"PXC_USER,required"`
 	PXCPass            string `env:"PXC_PASS,required"`
 	StorageType        string `env:"STORAGE_TYPE,required"`
+	StorageName        string `env:"STORAGE_NAME"`
 	BackupStorageS3    BackupS3
 	BackupStorageAzure BackupAzure
+	BackupStorageGCS   BackupGCS
 	BufferSize         int64   `env:"BUFFER_SIZE"`
 	CollectSpanSec     float64 `env:"COLLECT_SPAN_SEC" envDefault:"60"`
 	VerifyTLS          bool    `env:"VERIFY_TLS" envDefault:"true"`
 	TimeoutSeconds     float64 `env:"TIMEOUT_SECONDS" envDefault:"60"`
 	GTIDCacheKey       string  `env:"GTID_CACHE_KEY,required"`
+	ParallelUploads    int     `env:"PARALLEL_UPLOADS" envDefault:"1"`
+	Encryption         encryption.Config
+	Throttling         throttling.Config
+	Streaming          StreamingConfig
+}
+
+// StreamingConfig configures the streaming of binlog events from the cluster.
+type StreamingConfig struct {
+	Enabled bool `env:"STREAMING_ENABLED"`
+	// FlushSec is the interval of the upload of the streamed events.
+	FlushSec float64 `env:"STREAMING_FLUSH_SEC" envDefault:"10"`
+	// ServerID is the server id used by the collector to connect to the cluster as a replica.
+	ServerID uint32 `env:"STREAMING_SERVER_ID" envDefault:"4294967000"`
 }
 
 type BackupS3 struct {
@@ -121,6 +156,12 @@ type BackupAzure struct {
 	Concurrency   int    `env:"AZURE_CONCURRENCY"`
 }
 
+type BackupGCS struct {
+	Endpoint        string `env:"GCS_ENDPOINT"`
+	BucketURL       string `env:"GCS_BUCKET_URL,required"`
+	CredentialsJSON string `env:"GCS_CREDENTIALS_JSON"`
+}
+
 const (
 	lastSetFilePrefix string = "last-binlog-set-" // filename prefix for object where the last binlog set will be stored
 	gtidPostfix       string = "-gtid-set"        // filename postfix for files with GTID set
@@ -160,10 +201,26 @@ func New(ctx context.Context, c Config) (*Collector, error) {
 		if err != nil {
 			return nil, errors.Wrap(err, "new azure storage")
 		}
+	case "gcs":
+		bucket, prefix, _ := strings.Cut(c.BackupStorageGCS.BucketURL, "/")
+		if prefix != "" {
+			prefix += "/"
+		}
+		prefix = path.Clean(prefix) + "/"
+		s, err = storage.NewGCS(ctx, []byte(c.BackupStorageGCS.CredentialsJSON), c.BackupStorageGCS.Endpoint, bucket, prefix, c.VerifyTLS)
+		if err != nil {
+			return nil, errors.Wrap(err, "new gcs storage")
+		}
 	default:
 		return nil, errors.New("unknown STORAGE_TYPE")
 	}
 
+	storageName := c.StorageName
+	if storageName == "" {
+		storageName = c.StorageType
+	}
+	s = &instrumentedStorage{Storage: s, name: storageName}
+
 	file, err := os.Open(collectorPasswordPath)
 	if err != nil {
 		return nil, errors.Wrap(err, "open file")
@@ -173,12 +230,28 @@ func New(ctx context.Context, c Config) (*Collector, error) {
 		return nil, errors.Wrap(err, "read password")
 	}
 
+	enc := c.Encryption
+	if enc.Enabled() {
+		enc, err = enc.WithKey(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "get encryption key")
+		}
+	}
+
 	return &Collector{
-		storage:        s,
-		pxcUser:        c.PXCUser,
-		pxcPass:        string(pxcPass),
-		pxcServiceName: c.PXCServiceName,
-		gtidCacheKey:   c.GTIDCacheKey,
+		storage:         s,
+		pxcUser:         c.PXCUser,
+		pxcPass:         string(pxcPass),
+		pxcServiceName:  c.PXCServiceName,
+		gtidCacheKey:    c.GTIDCacheKey,
+		encryption:      enc,
+		uploadRateLimit: c.Throttling.UploadRateLimit,
+		parallelUploads: c.ParallelUploads,
+		stream: streamConfig{
+			flushInterval: time.Duration(c.Streaming.FlushSec * float64(time.Second)),
+			serverID:      c.Streaming.ServerID,
+			dir:           path.Join(os.TempDir(), "binlog-stream"),
+		},
 	}, nil
 }
 
@@ -254,11 +327,48 @@ func (c *Collector) CreateCollectorFunctions(ctx context.Context) error {
 	return nil
 }
 
+// Run collects the binlogs from the PXC host with the oldest binlog. If the collection
+// from the host fails, e.g. the host disappears in the middle of the upload, the collection
+// continues from the next host. The upload is resumed from the last uploaded GTID set,
+// so the binlogs of the next host are uploaded without gaps.
 func (c *Collector) Run(ctx context.Context) error {
-	err := c.newDB(ctx)
+	hosts, err := pxc.GetPXCHosts(ctx, c.pxcServiceName, c.pxcUser, c.pxcPass)
 	if err != nil {
 		pxcBinlogCollectorBackupFailure.Inc()
-		return errors.Wrap(err, "new db connection")
+		return errors.Wrap(err, "get hosts")
+	}
+
+	var errs []error
+	for i, host := range hosts {
+		if i > 0 {
+			log.Printf("failing over to %s", host)
+			pxcBinlogCollectorFailoverTotal.Inc()
+		}
+
+		err := c.collectFrom(ctx, host)
+		if err == nil {
+			pxcBinlogCollectorBackupSuccess.Inc()
+			return nil
+		}
+		log.Printf("ERROR: collect binlogs from %s: %v", host, err)
+		errs = append(errs, errors.Wrapf(err, "host %s", host))
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	pxcBinlogCollectorBackupFailure.Inc()
+	return errors.Wrap(stderrors.Join(errs...), "collect binlog files")
+}
+
+func (c *Collector) collectFrom(ctx context.Context, host string) error {
+	log.Println("reading binlogs from pxc with hostname=", host)
+
+	var err error
+	c.db, err = pxc.NewPXC(host, c.pxcUser, c.pxcPass)
+	if err != nil {
+		return errors.Wrapf(err, "new manager with host %s", host)
 	}
 	defer c.close()
 
@@ -270,14 +380,12 @@ func (c *Collector) Run(ctx context.Context) error {
 		return errors.Wrap(err, "get cluster state uuid")
 	}
 
-	err = c.CollectBinLogs(ctx)
-	if err != nil {
-		pxcBinlogCollectorBackupFailure.Inc()
-		return errors.Wrap(err, "collect binlog files")
-	}
+	c.host = host
+	updateStatus(func(s *timeline.CollectorStatus) {
+		s.Host = host
+	})
 
-	pxcBinlogCollectorBackupSuccess.Inc()
-	return nil
+	return c.CollectBinLogs(ctx)
 }
 
 func (c *Collector) lastGTIDSet(ctx context.Context, suffix string) (pxc.GTIDSet, error) {
@@ -296,22 +404,6 @@ func (c *Collector) lastGTIDSet(ctx context.Context, suffix string) (pxc.GTIDSet
 	return pxc.NewGTIDSet(string(lastSet)), nil
 }
 
-func (c *Collector) newDB(ctx context.Context) error {
-	host, err := pxc.GetPXCOldestBinlogHost(ctx, c.pxcServiceName, c.pxcUser, c.pxcPass)
-	if err != nil {
-		return errors.Wrap(err, "get host")
-	}
-
-	log.Println("reading binlogs from pxc with hostname=", host)
-
-	c.db, err = pxc.NewPXC(host, c.pxcUser, c.pxcPass)
-	if err != nil {
-		return errors.Wrapf(err, "new manager with host %s", host)
-	}
-
-	return nil
-}
-
 func (c *Collector) close() error {
 	return c.db.Close()
 }
@@ -516,6 +608,15 @@ func (c *Collector) CollectBinLogs(ctx context.Context) error {
 	if err != nil {
 		return errors.Wrap(err, "get binlog list")
 	}
+	if len(binlogList) > 0 {
+		c.lastListedBinlog = binlogList[len(binlogList)-1].Name
+
+		sourceTs, err := c.db.GetBinLogLastTimestamp(ctx, c.lastListedBinlog)
+		if err != nil {
+			return errors.Wrap(err, "get last timestamp of source")
+		}
+		setSourceEventTime(sourceTs)
+	}
 
 	err = c.addGTIDSets(ctx, cache, binlogList)
 	if err != nil {
@@ -633,10 +734,9 @@ func (c *Collector) CollectBinLogs(ctx context.Context) error {
 		}
 	}
 
-	for _, binlog := range binlogList {
-		err = c.manageBinlog(ctx, binlog)
-		if err != nil {
-			return errors.Wrap(err, "manage binlog")
+	err = c.uploadBinlogs(ctx, binlogList, func(binlog pxc.Binlog) error {
+		if err := c.commitBinlog(ctx, binlog); err != nil {
+			return errors.Wrap(err, "commit binlog")
 		}
 
 		pxcBinlogCollectorUploadedTotal.Inc()
@@ -650,6 +750,12 @@ func (c *Collector) CollectBinLogs(ctx context.Context) error {
 		if err := updateTimelineFile(lastTs); err != nil {
 			return errors.Wrap(err, "update timeline file")
 		}
+		setUploadedEventTime(lastTs)
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "manage binlog")
 	}
 
 	pxcBinlogCollectorLastProcessingTime.SetToCurrentTime()
@@ -676,25 +782,77 @@ func extractIncrementalNumber(binlogName string) (string, error) {
 	return parts[len(parts)-1], nil
 }
 
+// uploadBinlogs uploads the binlogs with up to parallelUploads binlogs uploaded concurrently.
+// The uploaded binlogs are committed in their order, so the collection continues
+// after the last committed binlog if one of the uploads fails.
+func (c *Collector) uploadBinlogs(ctx context.Context, binlogs []pxc.Binlog, commit func(pxc.Binlog) error) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan error, len(binlogs))
+	for i := range results {
+		results[i] = make(chan error, 1)
+	}
+
+	queue := make(chan int)
+	go func() {
+		defer close(queue)
+		for i := range binlogs {
+			select {
+			case queue <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	workers := c.parallelUploads
+	if workers < 1 {
+		workers = 1
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				results[i] <- c.manageBinlog(ctx, binlogs[i])
+			}
+		}()
+	}
+
+	for i, binlog := range binlogs {
+		select {
+		case err := <-results[i]:
+			if err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if err := commit(binlog); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// manageBinlog uploads the binlog with its GTID set to the storage.
 func (c *Collector) manageBinlog(ctx context.Context, binlog pxc.Binlog) (err error) {
 	binlogTmstmp, err := c.db.GetBinLogFirstTimestamp(ctx, binlog.Name)
 	if err != nil {
 		return errors.Wrapf(err, "get first timestamp for %s", binlog.Name)
 	}
 
-	incrementalNum, err := extractIncrementalNumber(binlog.Name) // extracts e.g. "000011"
+	binlogName, err := storageBinlogName(binlog.Name, binlogTmstmp, binlog.GTIDSet)
 	if err != nil {
-		return errors.Wrapf(err, "extract incremental number from %s", binlog.Name)
+		return err
 	}
 
-	// Construct internal storage filename with timestamp, incremental number, and GTID md5 hash
-	binlogName := fmt.Sprintf("binlog_%s_%s_%x", binlogTmstmp, incrementalNum, md5.Sum([]byte(binlog.GTIDSet.Raw())))
-
-	var setBuffer bytes.Buffer
-	// no error handling because WriteString() always returns nil error
-	// nolint:errcheck
-	setBuffer.WriteString(binlog.GTIDSet.Raw())
-
 	tmpDir := os.TempDir() + "/"
 
 	err = os.Remove(tmpDir + binlog.Name)
@@ -748,9 +906,7 @@ func (c *Collector) manageBinlog(ctx context.Context, binlog pxc.Binlog) (err er
 
 	go readBinlog(ctx, file, pw, errBuf, binlog.Name)
 
-	// Use constructed binlogName for storage keys
-	err = c.storage.PutObject(ctx, binlogName, pr, -1)
-	if err != nil {
+	if err := c.putBinlog(ctx, binlogName, pr); err != nil {
 		return errors.Wrapf(err, "put %s object", binlog.Name)
 	}
 
@@ -761,27 +917,86 @@ func (c *Collector) manageBinlog(ctx context.Context, binlog pxc.Binlog) (err er
 		return errors.Wrap(err, "wait mysqlbinlog command error:"+errBuf.String())
 	}
 
-	err = c.storage.PutObject(ctx, binlogName+gtidPostfix, &setBuffer, int64(setBuffer.Len()))
+	err = c.storage.PutObject(ctx, binlogName+gtidPostfix, strings.NewReader(binlog.GTIDSet.Raw()), int64(len(binlog.GTIDSet.Raw())))
 	if err != nil {
 		return errors.Wrap(err, "put gtid-set object")
 	}
-	for _, gtidSet := range binlog.GTIDSet.List() {
-		// no error handling because WriteString() always returns nil error
-		// nolint:errcheck
-		setBuffer.WriteString(binlog.GTIDSet.Raw())
 
+	return nil
+}
+
+// storageBinlogName returns the name of the binlog in the storage
+// with the first event timestamp, incremental number and GTID md5 hash.
+func storageBinlogName(binlog, firstTs string, set pxc.GTIDSet) (string, error) {
+	incrementalNum, err := extractIncrementalNumber(binlog) // extracts e.g. "000011"
+	if err != nil {
+		return "", errors.Wrapf(err, "extract incremental number from %s", binlog)
+	}
+
+	return fmt.Sprintf("binlog_%s_%s_%x", firstTs, incrementalNum, md5.Sum([]byte(set.Raw()))), nil
+}
+
+// putBinlog uploads the binlog data to the storage. The data is encrypted if the encryption is enabled.
+func (c *Collector) putBinlog(ctx context.Context, name string, data io.Reader) error {
+	var xbcrypt *exec.Cmd
+	if c.encryption.Enabled() {
+		keyFile, err := c.encryption.WriteKeyFile(ctx, os.TempDir())
+		if err != nil {
+			return errors.Wrap(err, "write encryption key")
+		}
+		defer os.Remove(keyFile) //nolint:errcheck
+
+		xbcrypt = c.encryption.XbcryptCmd(ctx, keyFile, false)
+		xbcrypt.Stdin = data
+		xbcrypt.Stderr = os.Stderr
+		data, err = xbcrypt.StdoutPipe()
+		if err != nil {
+			return errors.Wrap(err, "xbcrypt stdout pipe")
+		}
+		if err := xbcrypt.Start(); err != nil {
+			return errors.Wrap(err, "start xbcrypt")
+		}
+	}
+
+	err := c.storage.PutObject(ctx, name, throttling.NewReader(ctx, data, c.uploadRateLimit), -1)
+	if err != nil {
+		if xbcrypt != nil {
+			_ = xbcrypt.Process.Kill()
+			_ = xbcrypt.Wait()
+		}
+		return err
+	}
+
+	if xbcrypt != nil {
+		// the object isn't valid without the gtid-set object, it will be uploaded again
+		if err := xbcrypt.Wait(); err != nil {
+			return errors.Wrap(err, "encrypt")
+		}
+	}
+
+	return nil
+}
+
+// commitBinlog stores the GTID set of the uploaded binlog as the last uploaded set.
+// The next collection starts after the last uploaded set.
+func (c *Collector) commitBinlog(ctx context.Context, binlog pxc.Binlog) error {
+	for _, gtidSet := range binlog.GTIDSet.List() {
 		lastSetName := lastSetFilePrefix + strings.Split(gtidSet, ":")[0]
 
 		// remove any newline characters from the last set name
 		lastSetName = strings.ReplaceAll(lastSetName, "\n", "")
 		lastSetName = strings.ReplaceAll(lastSetName, "\r", "")
 
-		err = c.storage.PutObject(ctx, lastSetName, &setBuffer, int64(setBuffer.Len()))
+		err := c.storage.PutObject(ctx, lastSetName, strings.NewReader(binlog.GTIDSet.Raw()), int64(len(binlog.GTIDSet.Raw())))
 		if err != nil {
 			return errors.Wrap(err, "put last-set object")
 		}
 	}
 	c.lastUploadedSet = binlog.GTIDSet
+	updateStatus(func(s *timeline.CollectorStatus) {
+		s.LastUploadedGTIDSet = binlog.GTIDSet.Raw()
+		s.LastUploadTime = time.Now()
+	})
 
 	return nil
 }
@@ -0,0 +1,115 @@
+package collector
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/timeline"
+)
+
+var (
+	statusMu sync.Mutex
+	status   timeline.CollectorStatus
+)
+
+var (
+	pxcBinlogCollectorLag = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "pxc_binlog_collector_lag_seconds",
+			Help: "Seconds between the newest event seen on the source host and the newest uploaded event",
+		},
+		func() float64 {
+			s := CurrentStatus()
+			return s.Lag().Seconds()
+		},
+	)
+	pxcBinlogCollectorStorageErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pxc_binlog_collector_storage_errors_total",
+			Help: "Total number of failed storage operations",
+		},
+		[]string{"storage", "operation"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(pxcBinlogCollectorLag)
+	prometheus.MustRegister(pxcBinlogCollectorStorageErrors)
+}
+
+// CurrentStatus returns the state of the binlog collection.
+func CurrentStatus() timeline.CollectorStatus {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	return status
+}
+
+func updateStatus(f func(s *timeline.CollectorStatus)) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	f(&status)
+}
+
+// setUploadedEventTime sets the time of the newest uploaded event from the unix timestamp.
+func setUploadedEventTime(ts string) {
+	t, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return
+	}
+	updateStatus(func(s *timeline.CollectorStatus) {
+		s.LastUploadedEventTime = time.Unix(t, 0)
+	})
+}
+
+// setSourceEventTime sets the time of the newest event seen on the source host from the unix timestamp.
+func setSourceEventTime(ts string) {
+	t, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return
+	}
+	updateStatus(func(s *timeline.CollectorStatus) {
+		s.SourceEventTime = time.Unix(t, 0)
+	})
+}
+
+// instrumentedStorage counts the failed operations of the storage by the storage name.
+type instrumentedStorage struct {
+	storage.Storage
+	name string
+}
+
+func (s *instrumentedStorage) observe(operation string, err error) {
+	if err != nil && err != storage.ErrObjectNotFound {
+		pxcBinlogCollectorStorageErrors.WithLabelValues(s.name, operation).Inc()
+	}
+}
+
+func (s *instrumentedStorage) GetObject(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	r, err := s.Storage.GetObject(ctx, objectName)
+	s.observe("get", err)
+	return r, err
+}
+
+func (s *instrumentedStorage) PutObject(ctx context.Context, name string, data io.Reader, size int64) error {
+	err := s.Storage.PutObject(ctx, name, data, size)
+	s.observe("put", err)
+	return err
+}
+
+func (s *instrumentedStorage) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	objs, err := s.Storage.ListObjects(ctx, prefix)
+	s.observe("list", err)
+	return objs, err
+}
+
+func (s *instrumentedStorage) DeleteObject(ctx context.Context, objectName string) error {
+	err := s.Storage.DeleteObject(ctx, objectName)
+	s.observe("delete", err)
+	return err
+}
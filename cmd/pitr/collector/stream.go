@@ -0,0 +1,457 @@
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/percona/percona-xtradb-cluster-operator/cmd/pitr/pxc"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+)
+
+type streamConfig struct {
+	flushInterval time.Duration
+	serverID      uint32
+	dir           string // directory for the streamed binlogs
+}
+
+// Stream collects the binlogs continuously. The binlogs which aren't uploaded yet are collected
+// by Run and the events of the next binlogs are streamed from the same host with the binlog
+// replication protocol and uploaded every flush interval. If the streaming stops,
+// e.g. the host disappears, the binlogs are collected by Run from the next host
+// starting from the last uploaded GTID set, and the streaming is restarted.
+func (c *Collector) Stream(ctx context.Context, timeout time.Duration) error {
+	for {
+		runCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := c.Run(runCtx)
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		err = c.streamFrom(ctx, c.host)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Printf("ERROR: stream binlogs from %s: %v", c.host, err)
+	}
+}
+
+// streamFrom streams the binlogs after the last collected binlog from the host
+// until the streaming or the upload of the streamed events fails.
+func (c *Collector) streamFrom(ctx context.Context, host string) error {
+	db, err := pxc.NewPXC(host, c.pxcUser, c.pxcPass)
+	if err != nil {
+		return errors.Wrapf(err, "new manager with host %s", host)
+	}
+	names, err := db.GetBinLogNamesList(ctx)
+	db.Close()
+	if err != nil {
+		return errors.Wrap(err, "get binlog list")
+	}
+	start := nextBinlog(names, c.lastListedBinlog)
+	if start == "" {
+		return errors.Errorf("no binlogs after %s", c.lastListedBinlog)
+	}
+
+	if err := os.RemoveAll(c.stream.dir); err != nil {
+		return errors.Wrapf(err, "remove %s", c.stream.dir)
+	}
+	if err := os.MkdirAll(c.stream.dir, 0o755); err != nil {
+		return errors.Wrapf(err, "create %s", c.stream.dir)
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errBuf := &bytes.Buffer{}
+	cmd := exec.CommandContext(streamCtx, "mysqlbinlog", "-R", "--raw", "--stop-never",
+		"--connection-server-id="+strconv.FormatUint(uint64(c.stream.serverID), 10),
+		"-P", "33062", "-h"+host, "-u"+c.pxcUser, "--result-file="+c.stream.dir+"/", start)
+	cmd.Env = append(cmd.Env, "MYSQL_PWD="+c.pxcPass)
+	cmd.Stderr = errBuf
+
+	if err := cmd.Start(); err != nil {
+		return errors.Wrap(err, "run mysqlbinlog command")
+	}
+	log.Printf("streaming binlogs from %s starting with %s", host, start)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	ticker := time.NewTicker(c.stream.flushInterval)
+	defer ticker.Stop()
+
+	binlogs := make(map[string]*streamedBinlog)
+	for {
+		select {
+		case <-ctx.Done():
+			<-done
+			return ctx.Err()
+		case err := <-done:
+			// upload the events received before the streaming stopped
+			if ferr := c.flushStream(ctx, binlogs); ferr != nil {
+				log.Printf("ERROR: upload streamed binlogs: %v", ferr)
+			}
+			return errors.Errorf("mysqlbinlog stopped: %v: %s", err, errBuf.String())
+		case <-ticker.C:
+			if err := c.flushStream(ctx, binlogs); err != nil {
+				cancel()
+				<-done
+				return errors.Wrap(err, "upload streamed binlogs")
+			}
+		}
+	}
+}
+
+// nextBinlog returns the first binlog after the last collected binlog.
+func nextBinlog(names []string, last string) string {
+	sort.Strings(names)
+	for _, name := range names {
+		if name > last {
+			return name
+		}
+	}
+	return ""
+}
+
+// flushStream uploads the complete transactions of the streamed binlogs which aren't uploaded yet.
+// The binlogs rotated by the server are removed after they are uploaded completely.
+func (c *Collector) flushStream(ctx context.Context, binlogs map[string]*streamedBinlog) error {
+	entries, err := os.ReadDir(c.stream.dir)
+	if err != nil {
+		return errors.Wrapf(err, "read %s", c.stream.dir)
+	}
+
+	for i, entry := range entries {
+		b, ok := binlogs[entry.Name()]
+		if !ok {
+			b = newStreamedBinlog(entry.Name())
+			binlogs[entry.Name()] = b
+		}
+
+		file := path.Join(c.stream.dir, entry.Name())
+		size, err := b.parse(file)
+		if err != nil {
+			return errors.Wrapf(err, "parse %s", file)
+		}
+		if b.seenTs > 0 {
+			setSourceEventTime(strconv.FormatInt(b.seenTs, 10))
+		}
+
+		if set := b.gtids.String(); set != "" && set != b.uploadedSet {
+			if err := c.uploadStreamedBinlog(ctx, file, b); err != nil {
+				return errors.Wrapf(err, "upload %s", b.name)
+			}
+		}
+
+		// the server rotated the binlog, so the streamed file is complete
+		if i < len(entries)-1 && b.committed == size {
+			if err := os.Remove(file); err != nil {
+				return errors.Wrapf(err, "remove %s", file)
+			}
+			delete(binlogs, entry.Name())
+		}
+	}
+
+	return nil
+}
+
+func (c *Collector) uploadStreamedBinlog(ctx context.Context, file string, b *streamedBinlog) error {
+	set := pxc.NewGTIDSet(b.gtids.String())
+	name, err := storageBinlogName(b.name, strconv.FormatInt(b.firstTs, 10), set)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return errors.Wrapf(err, "open %s", file)
+	}
+	defer f.Close()
+
+	if err := c.putBinlog(ctx, name, io.NewSectionReader(f, 0, b.committed)); err != nil {
+		return errors.Wrapf(err, "put %s object", name)
+	}
+	err = c.storage.PutObject(ctx, name+gtidPostfix, strings.NewReader(set.Raw()), int64(len(set.Raw())))
+	if err != nil {
+		return errors.Wrap(err, "put gtid-set object")
+	}
+	if err := c.commitBinlog(ctx, pxc.Binlog{Name: b.name, GTIDSet: set}); err != nil {
+		return errors.Wrap(err, "commit binlog")
+	}
+
+	pxcBinlogCollectorUploadedTotal.Inc()
+	pxcBinlogCollectorLastUploadTime.SetToCurrentTime()
+
+	if exists, err := fileExists(naming.TimelinePath); !exists && err == nil {
+		if err := createTimelineFile(strconv.FormatInt(b.firstTs, 10)); err != nil {
+			return errors.Wrap(err, "create timeline file")
+		}
+	}
+	if err := updateTimelineFile(strconv.FormatInt(b.lastTs, 10)); err != nil {
+		return errors.Wrap(err, "update timeline file")
+	}
+	setUploadedEventTime(strconv.FormatInt(b.lastTs, 10))
+
+	// the previous upload of the binlog contains a part of the transactions of this upload
+	if b.uploaded != "" && b.uploaded != name {
+		for _, obj := range []string{b.uploaded, b.uploaded + gtidPostfix} {
+			if err := c.storage.DeleteObject(ctx, obj); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+				return errors.Wrapf(err, "delete %s", obj)
+			}
+		}
+	}
+	b.uploaded = name
+	b.uploadedSet = set.Raw()
+
+	log.Printf("uploaded %d bytes of streamed binlog %s to storage with name %s", b.committed, b.name, name)
+
+	return nil
+}
+
+const (
+	binlogMagic     = "\xfebin"
+	eventHeaderSize = 19
+
+	queryEvent       = 2
+	xidEvent         = 16
+	gtidEvent        = 33
+	anonymousGTID    = 34
+	xaPrepareEvent   = 38
+	queryPostHdrSize = 13
+)
+
+// streamedBinlog is the binlog written by mysqlbinlog with the events streamed from the server.
+type streamedBinlog struct {
+	name string // name of the binlog on the server
+	// committed is the size of the binlog up to the end of the last complete transaction
+	committed   int64
+	gtids       gtidSet
+	firstTs     int64  // timestamp of the first transaction
+	lastTs      int64  // timestamp of the last transaction
+	seenTs      int64  // timestamp of the last streamed event
+	uploaded    string // name of the last uploaded object
+	uploadedSet string
+}
+
+func newStreamedBinlog(name string) *streamedBinlog {
+	return &streamedBinlog{name: name, gtids: make(gtidSet)}
+}
+
+// parse reads the events written since the last complete transaction
+// and returns the size of the file. The events of the incomplete transaction
+// at the end of the file are parsed again by the next call.
+func (b *streamedBinlog) parse(file string) (int64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := stat.Size()
+
+	if b.committed == 0 {
+		if size < int64(len(binlogMagic)) {
+			return size, nil
+		}
+		magic := make([]byte, len(binlogMagic))
+		if _, err := io.ReadFull(f, magic); err != nil {
+			return 0, err
+		}
+		if string(magic) != binlogMagic {
+			return 0, errors.New("not a binlog")
+		}
+		b.committed = int64(len(binlogMagic))
+	}
+
+	if _, err := f.Seek(b.committed, io.SeekStart); err != nil {
+		return 0, err
+	}
+	r := bufio.NewReader(f)
+
+	var (
+		offset  = b.committed
+		inTrx   bool
+		begun   bool
+		trxGTID *gtid
+		trxTs   int64
+		header  = make([]byte, eventHeaderSize)
+	)
+	for size-offset >= eventHeaderSize {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return 0, err
+		}
+		ts := int64(binary.LittleEndian.Uint32(header[0:4]))
+		typ := header[4]
+		length := int64(binary.LittleEndian.Uint32(header[9:13]))
+		if length < eventHeaderSize {
+			return 0, errors.Errorf("invalid event length %d at %d", length, offset)
+		}
+		if offset+length > size {
+			break
+		}
+		if ts > b.seenTs {
+			b.seenTs = ts
+		}
+
+		var body []byte
+		if typ == gtidEvent || typ == queryEvent {
+			body = make([]byte, length-eventHeaderSize)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return 0, err
+			}
+		} else if _, err := r.Discard(int(length - eventHeaderSize)); err != nil {
+			return 0, err
+		}
+		offset += length
+
+		commit := false
+		switch {
+		case typ == gtidEvent || typ == anonymousGTID:
+			inTrx, begun = true, false
+			trxGTID = parseGTIDEvent(typ, body)
+			trxTs = ts
+		case !inTrx:
+			// events outside of the transactions, e.g. the format description
+			b.committed = offset
+		case typ == xidEvent || typ == xaPrepareEvent:
+			commit = true
+		case typ == queryEvent:
+			switch q := queryStatement(body); {
+			case hasPrefix(q, "BEGIN"), hasPrefix(q, "XA START"):
+				begun = true
+			case hasPrefix(q, "COMMIT"), hasPrefix(q, "ROLLBACK"):
+				commit = true
+			case !begun:
+				// DDL is the only statement of the transaction
+				commit = true
+			}
+		}
+
+		if commit {
+			if trxGTID != nil {
+				b.gtids.add(trxGTID.sid, trxGTID.gno)
+			}
+			if b.firstTs == 0 {
+				b.firstTs = trxTs
+			}
+			b.lastTs = ts
+			b.committed = offset
+			inTrx = false
+			trxGTID = nil
+		}
+	}
+
+	return size, nil
+}
+
+type gtid struct {
+	sid string
+	gno int64
+}
+
+// parseGTIDEvent returns the GTID of the GTID event: 1 byte of flags, 16 bytes of the SID and 8 bytes of the GNO.
+func parseGTIDEvent(typ byte, body []byte) *gtid {
+	if typ != gtidEvent || len(body) < 25 {
+		return nil
+	}
+	sid := hex.EncodeToString(body[1:17])
+	return &gtid{
+		sid: sid[0:8] + "-" + sid[8:12] + "-" + sid[12:16] + "-" + sid[16:20] + "-" + sid[20:32],
+		gno: int64(binary.LittleEndian.Uint64(body[17:25])),
+	}
+}
+
+// queryStatement returns the statement of the query event. The statement follows
+// the post header, status variables and the zero terminated database name.
+func queryStatement(body []byte) []byte {
+	if len(body) < queryPostHdrSize {
+		return nil
+	}
+	dbLen := int(body[8])
+	statusLen := int(binary.LittleEndian.Uint16(body[11:13]))
+	start := queryPostHdrSize + statusLen + dbLen + 1
+	if start > len(body) {
+		return nil
+	}
+	return body[start:]
+}
+
+func hasPrefix(q []byte, prefix string) bool {
+	return len(q) >= len(prefix) && strings.EqualFold(string(q[:len(prefix)]), prefix)
+}
+
+type gnoInterval struct {
+	start, end int64
+}
+
+// gtidSet is the set of the transactions of the streamed binlog by the source UUID.
+type gtidSet map[string][]gnoInterval
+
+func (s gtidSet) add(sid string, gno int64) {
+	intervals := s[sid]
+	if n := len(intervals); n > 0 && intervals[n-1].end+1 == gno {
+		intervals[n-1].end = gno
+		return
+	}
+	s[sid] = append(intervals, gnoInterval{gno, gno})
+}
+
+// String returns the set in the format of MySQL.
+func (s gtidSet) String() string {
+	sids := make([]string, 0, len(s))
+	for sid := range s {
+		sids = append(sids, sid)
+	}
+	sort.Strings(sids)
+
+	items := make([]string, 0, len(sids))
+	for _, sid := range sids {
+		intervals := append([]gnoInterval(nil), s[sid]...)
+		sort.Slice(intervals, func(i, j int) bool { return intervals[i].start < intervals[j].start })
+
+		var merged []gnoInterval
+		for _, i := range intervals {
+			if n := len(merged); n > 0 && i.start <= merged[n-1].end+1 {
+				if i.end > merged[n-1].end {
+					merged[n-1].end = i.end
+				}
+				continue
+			}
+			merged = append(merged, i)
+		}
+
+		item := sid
+		for _, i := range merged {
+			if i.start == i.end {
+				item += ":" + strconv.FormatInt(i.start, 10)
+				continue
+			}
+			item += ":" + strconv.FormatInt(i.start, 10) + "-" + strconv.FormatInt(i.end, 10)
+		}
+		items = append(items, item)
+	}
+
+	return strings.Join(items, ",")
+}
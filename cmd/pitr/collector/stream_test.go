@@ -0,0 +1,111 @@
+package collector
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSID = "3e11fa47-71ca-11e1-9e33-c80aa9429562"
+
+func testEvent(ts uint32, typ byte, body []byte) []byte {
+	header := make([]byte, eventHeaderSize)
+	binary.LittleEndian.PutUint32(header[0:4], ts)
+	header[4] = typ
+	binary.LittleEndian.PutUint32(header[5:9], 1)
+	binary.LittleEndian.PutUint32(header[9:13], uint32(eventHeaderSize+len(body)))
+	return append(header, body...)
+}
+
+func testGTIDEvent(ts uint32, gno uint64) []byte {
+	sid, _ := hex.DecodeString("3e11fa4771ca11e19e33c80aa9429562")
+	body := append([]byte{1}, sid...)
+	body = binary.LittleEndian.AppendUint64(body, gno)
+	return testEvent(ts, gtidEvent, append(body, make([]byte, 16)...))
+}
+
+func testQueryEvent(ts uint32, db, query string) []byte {
+	body := make([]byte, queryPostHdrSize)
+	body[8] = byte(len(db))
+	binary.LittleEndian.PutUint16(body[11:13], 2)
+	body = append(body, 0, 0)
+	body = append(body, db...)
+	body = append(body, 0)
+	return testEvent(ts, queryEvent, append(body, query...))
+}
+
+func TestStreamedBinlogParse(t *testing.T) {
+	var data []byte
+	data = append(data, binlogMagic...)
+	data = append(data, testEvent(100, 15, make([]byte, 80))...) // format description
+	data = append(data, testEvent(100, 35, make([]byte, 8))...)  // previous gtids
+
+	// row based transaction
+	data = append(data, testGTIDEvent(101, 5)...)
+	data = append(data, testQueryEvent(101, "db", "BEGIN")...)
+	data = append(data, testEvent(101, 19, make([]byte, 30))...)
+	data = append(data, testEvent(101, 30, make([]byte, 50))...)
+	data = append(data, testEvent(101, xidEvent, make([]byte, 8))...)
+	// DDL
+	data = append(data, testGTIDEvent(102, 6)...)
+	data = append(data, testQueryEvent(102, "db", "CREATE TABLE t (id int)")...)
+	// statement based transaction
+	data = append(data, testGTIDEvent(103, 8)...)
+	data = append(data, testQueryEvent(103, "db", "BEGIN")...)
+	data = append(data, testQueryEvent(103, "db", "INSERT INTO t VALUES (1)")...)
+	data = append(data, testQueryEvent(103, "db", "COMMIT")...)
+	committed := int64(len(data))
+
+	// incomplete transaction
+	data = append(data, testGTIDEvent(104, 9)...)
+	data = append(data, testQueryEvent(104, "db", "BEGIN")...)
+	partial := testEvent(104, 30, make([]byte, 50))
+	data = append(data, partial[:30]...)
+
+	file := filepath.Join(t.TempDir(), "binlog.000012")
+	require.NoError(t, os.WriteFile(file, data, 0o644))
+
+	b := newStreamedBinlog("binlog.000012")
+	size, err := b.parse(file)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), size)
+	assert.Equal(t, committed, b.committed)
+	assert.Equal(t, testSID+":5-6:8", b.gtids.String())
+	assert.Equal(t, int64(101), b.firstTs)
+	assert.Equal(t, int64(103), b.lastTs)
+
+	// the rest of the transaction is received
+	data = append(data, partial[30:]...)
+	data = append(data, testEvent(105, xidEvent, make([]byte, 8))...)
+	require.NoError(t, os.WriteFile(file, data, 0o644))
+
+	size, err = b.parse(file)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), size)
+	assert.Equal(t, size, b.committed)
+	assert.Equal(t, testSID+":5-6:8-9", b.gtids.String())
+	assert.Equal(t, int64(101), b.firstTs)
+	assert.Equal(t, int64(105), b.lastTs)
+}
+
+func TestGTIDSetString(t *testing.T) {
+	s := make(gtidSet)
+	for _, gno := range []int64{3, 4, 1, 2, 7, 10, 11} {
+		s.add("b", gno)
+	}
+	s.add("a", 1)
+	assert.Equal(t, "a:1,b:1-4:7:10-11", s.String())
+	assert.Equal(t, "", make(gtidSet).String())
+}
+
+func TestNextBinlog(t *testing.T) {
+	names := []string{"binlog.000013", "binlog.000011", "binlog.000012"}
+	assert.Equal(t, "binlog.000012", nextBinlog(names, "binlog.000011"))
+	assert.Equal(t, "", nextBinlog(names, "binlog.000013"))
+	assert.Equal(t, "binlog.000011", nextBinlog(names, ""))
+}
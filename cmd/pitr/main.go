@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/percona/percona-xtradb-cluster-operator/cmd/pitr/collector"
 	"github.com/percona/percona-xtradb-cluster-operator/cmd/pitr/recoverer"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/timeline"
 
 	"github.com/caarlos0/env"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -31,6 +33,10 @@ func main() {
 		http.Handle("/metrics", promhttp.Handler())
 		http.HandleFunc("/health", healthHandler)
 		http.HandleFunc("/invalidate-cache/", cacheInvalidationHandler)
+		http.HandleFunc("/binlogs", binlogsHandler)
+		http.HandleFunc("/timeline", timelineHandler)
+		http.HandleFunc("/events", eventsHandler)
+		http.HandleFunc("/status", statusHandler)
 		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
 			log.Printf("ERROR: HTTP server error: %v", err)
 		}
@@ -115,6 +121,156 @@ func cacheInvalidationHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// binlogsHandler lists the uploaded binlogs with their GTID sets and the times of their first and last events.
+func binlogsHandler(w http.ResponseWriter, r *http.Request) {
+	c, ok := browserCollector(w, r)
+	if !ok {
+		return
+	}
+
+	_, binlogs, err := c.Timeline(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("ERROR: get binlogs:", err)
+		return
+	}
+	if binlogs == nil {
+		binlogs = []timeline.Binlog{}
+	}
+
+	writeJSON(w, binlogs)
+}
+
+// timelineHandler reports the periods of time the cluster can be restored to and the gaps between them.
+// If the date is passed, the response also tells if the cluster can be restored to the date.
+func timelineHandler(w http.ResponseWriter, r *http.Request) {
+	c, ok := browserCollector(w, r)
+	if !ok {
+		return
+	}
+
+	var date time.Time
+	if v := r.URL.Query().Get("date"); v != "" {
+		var err error
+		date, err = parseTime(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	t, _, err := c.Timeline(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("ERROR: get timeline:", err)
+		return
+	}
+
+	if date.IsZero() {
+		writeJSON(w, t)
+		return
+	}
+
+	writeJSON(w, struct {
+		*timeline.Timeline
+		Date       time.Time `json:"date"`
+		Restorable bool      `json:"restorable"`
+	}{t, date, t.Contains(date)})
+}
+
+// eventsHandler streams the events of the uploaded binlogs between the from and to times decoded by mysqlbinlog.
+func eventsHandler(w http.ResponseWriter, r *http.Request) {
+	c, ok := browserCollector(w, r)
+	if !ok {
+		return
+	}
+
+	from, err := parseTime(r.URL.Query().Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "from: "+err.Error())
+		return
+	}
+	to, err := parseTime(r.URL.Query().Get("to"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "to: "+err.Error())
+		return
+	}
+	if to.Before(from) {
+		writeError(w, http.StatusBadRequest, "to is before from")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := c.DecodeEvents(r.Context(), from, to, w); err != nil {
+		// the status is already sent with the events
+		log.Println("ERROR: decode events:", err)
+	}
+}
+
+// statusHandler reports the host the binlogs are collected from and the last uploaded binlog.
+func statusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "only GET method is allowed")
+		return
+	}
+
+	writeJSON(w, collector.CurrentStatus())
+}
+
+// browserCollector returns the collector to read the uploaded binlogs.
+// Only GET requests are allowed by the binlog browser.
+func browserCollector(w http.ResponseWriter, r *http.Request) (*collector.Collector, bool) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "only GET method is allowed")
+		return nil, false
+	}
+
+	config, err := getCollectorConfig()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("ERROR: get collector config:", err)
+		return nil, false
+	}
+
+	c, err := collector.New(r.Context(), config)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("ERROR: get new collector:", err)
+		return nil, false
+	}
+
+	return c, true
+}
+
+// parseTime parses the time in RFC 3339 or in the format of the date recovery in UTC.
+func parseTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, errors.New("time is required")
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02 15:04:05", v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %s, use RFC 3339 or YYYY-MM-DD hh:mm:ss", v)
+	}
+	return t, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println("ERROR: writing response:", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.WriteHeader(code)
+	if _, err := w.Write([]byte(msg)); err != nil {
+		log.Println("ERROR: writing response:", err)
+	}
+}
+
 func runCollector(ctx context.Context) {
 	config, err := getCollectorConfig()
 	if err != nil {
@@ -130,6 +286,14 @@ func runCollector(ctx context.Context) {
 		log.Fatalln("ERROR: init collector:", err)
 	}
 
+	if config.Streaming.Enabled {
+		log.Println("running binlog collector in streaming mode")
+		if err := c.Stream(ctx, time.Duration(config.TimeoutSeconds)*time.Second); err != nil {
+			log.Fatalln("ERROR:", err)
+		}
+		return
+	}
+
 	log.Println("running binlog collector")
 	for {
 		timeout, cancel := context.WithTimeout(ctx, time.Duration(config.TimeoutSeconds)*time.Second)
@@ -178,6 +342,10 @@ func getCollectorConfig() (collector.Config, error) {
 		if err := env.Parse(&cfg.BackupStorageAzure); err != nil {
 			return cfg, err
 		}
+	case "gcs":
+		if err := env.Parse(&cfg.BackupStorageGCS); err != nil {
+			return cfg, err
+		}
 	default:
 		return cfg, errors.New("unknown STORAGE_TYPE")
 	}
@@ -205,6 +373,13 @@ func getRecovererConfig() (recoverer.Config, error) {
 		if err := env.Parse(&cfg.BinlogStorageAzure); err != nil {
 			return cfg, err
 		}
+	case "gcs":
+		if err := env.Parse(&cfg.BackupStorageGCS); err != nil {
+			return cfg, err
+		}
+		if err := env.Parse(&cfg.BinlogStorageGCS); err != nil {
+			return cfg, err
+		}
 	default:
 		return cfg, errors.New("unknown STORAGE_TYPE")
 	}
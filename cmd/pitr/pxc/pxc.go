@@ -273,6 +273,16 @@ func (p *PXC) GetBinLogLastTimestamp(ctx context.Context, binlog string) (string
 	return timestamp, nil
 }
 
+// GetGTIDExecuted returns the set of the transactions executed by the server.
+func (p *PXC) GetGTIDExecuted(ctx context.Context) (string, error) {
+	var set string
+	if err := p.db.QueryRowContext(ctx, "SELECT @@GLOBAL.gtid_executed").Scan(&set); err != nil {
+		return "", errors.Wrap(err, "scan gtid_executed")
+	}
+
+	return strings.ReplaceAll(set, "\n", ""), nil
+}
+
 func (p *PXC) SubtractGTIDSet(ctx context.Context, set, subSet string) (string, error) {
 	var result string
 	row := p.db.QueryRowContext(ctx, "SELECT GTID_SUBTRACT(?,?)", set, subSet)
@@ -318,13 +328,27 @@ func GetPXCFirstHost(ctx context.Context, pxcServiceName string) (string, error)
 }
 
 func GetPXCOldestBinlogHost(ctx context.Context, pxcServiceName, user, pass string) (string, error) {
+	hosts, err := GetPXCHosts(ctx, pxcServiceName, user, pass)
+	if err != nil {
+		return "", err
+	}
+
+	return hosts[0], nil
+}
+
+// GetPXCHosts returns the synced PXC hosts ordered by the time of their oldest binlog.
+// The hosts which binlogs can't be read are skipped.
+func GetPXCHosts(ctx context.Context, pxcServiceName, user, pass string) ([]string, error) {
 	nodes, err := GetNodesByServiceName(ctx, pxcServiceName)
 	if err != nil {
-		return "", errors.Wrap(err, "get nodes by service name")
+		return nil, errors.Wrap(err, "get nodes by service name")
 	}
 
-	var oldestHost string
-	var oldestTS int64
+	type host struct {
+		name       string
+		binlogTime int64
+	}
+	var hosts []host
 	for _, node := range nodes {
 		if strings.Contains(node, "wsrep_ready:ON:wsrep_connected:ON:wsrep_local_state_comment:Synced:wsrep_cluster_status:Primary") {
 			nodeArr := strings.Split(node, ":")
@@ -333,19 +357,24 @@ func GetPXCOldestBinlogHost(ctx context.Context, pxcServiceName, user, pass stri
 				log.Printf("ERROR: get binlog time: %v", err)
 				continue
 			}
-			if len(oldestHost) == 0 || oldestTS > 0 && binlogTime < oldestTS {
-				oldestHost = nodeArr[0]
-				oldestTS = binlogTime
-			}
-
+			hosts = append(hosts, host{name: nodeArr[0], binlogTime: binlogTime})
 		}
 	}
 
-	if len(oldestHost) == 0 {
-		return "", errors.New("can't find host")
+	if len(hosts) == 0 {
+		return nil, errors.New("can't find host")
+	}
+
+	sort.SliceStable(hosts, func(i, j int) bool {
+		return hosts[i].binlogTime < hosts[j].binlogTime
+	})
+
+	names := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		names = append(names, h.name)
 	}
 
-	return oldestHost, nil
+	return names, nil
 }
 
 func getBinlogTime(ctx context.Context, host, user, pass string) (int64, error) {
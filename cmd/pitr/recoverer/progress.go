@@ -0,0 +1,39 @@
+package recoverer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"time"
+)
+
+// progress reports the progress of the recovery to the operator. The operator
+// reads the file from the pod of the job and reports it in the status of the restore.
+type progress struct {
+	file    string
+	started time.Time
+	total   int
+}
+
+func newProgress(dir string, total int) *progress {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Printf("create progress directory: %v", err)
+	}
+	return &progress{file: path.Join(dir, "progress"), started: time.Now(), total: total}
+}
+
+// write replaces the progress file with the number of applied binlogs and the GTID set of the applied binlog.
+func (p *progress) write(applied int, gtidSet string) {
+	data := fmt.Sprintf("phase=pitr\nstarted=%d\nbinlogs_applied=%d\nbinlogs_total=%d\ngtid=%s\n",
+		p.started.Unix(), applied, p.total, gtidSet)
+
+	tmp := p.file + ".tmp"
+	if err := os.WriteFile(tmp, []byte(data), 0o644); err != nil {
+		log.Printf("write progress: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, p.file); err != nil {
+		log.Printf("write progress: %v", err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -17,7 +18,9 @@ import (
 
 	"github.com/percona/percona-xtradb-cluster-operator/cmd/pitr/pxc"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/encryption"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/throttling"
 
 	"github.com/pkg/errors"
 )
@@ -31,12 +34,18 @@ type Recoverer struct {
 	recoverType    RecoverType
 	pxcServiceName string
 	binlogs        []string
+	binlogSets     map[string]string // GTID sets of the binlogs
 	gtidSet        string
 	startGTID      string
 	recoverFlag    string
 	recoverEndTime time.Time
 	gtid           string
 	verifyTLS      bool
+	encryption     encryption.Config // binlogs encryption, the key is resolved
+	downloadRate   int64             // bytes per second
+	binlogFile     string
+	binlogPosition int64
+	dryRun         bool
 }
 
 type Config struct {
@@ -45,13 +54,21 @@ type Config struct {
 	PXCPass            string `env:"PXC_PASS,required"`
 	BackupStorageS3    BackupS3
 	BackupStorageAzure BackupAzure
+	BackupStorageGCS   BackupGCS
 	RecoverTime        string `env:"PITR_DATE"`
 	RecoverType        string `env:"PITR_RECOVERY_TYPE,required"`
 	GTID               string `env:"PITR_GTID"`
+	BinlogFile         string `env:"PITR_BINLOG_FILE"`
+	BinlogPosition     int64  `env:"PITR_BINLOG_POSITION"`
+	DryRun             bool   `env:"PITR_DRY_RUN"`
 	VerifyTLS          bool   `env:"VERIFY_TLS" envDefault:"true"`
 	StorageType        string `env:"STORAGE_TYPE,required"`
 	BinlogStorageS3    BinlogS3
 	BinlogStorageAzure BinlogAzure
+	BinlogStorageGCS   BinlogGCS
+	BackupEncryption   encryption.Config
+	BinlogEncryption   BinlogEncryption
+	Throttling         throttling.Config
 }
 
 func (c Config) storages(ctx context.Context) (storage.Storage, storage.Storage, error) {
@@ -93,6 +110,17 @@ func (c Config) storages(ctx context.Context) (storage.Storage, storage.Storage,
 		if err != nil {
 			return nil, nil, errors.Wrap(err, "new azure storage")
 		}
+	case "gcs":
+		var err error
+		bucket, prefix := getContainerAndPrefix(c.BinlogStorageGCS.BucketURL)
+		binlogStorage, err = storage.NewGCS(ctx, []byte(c.BinlogStorageGCS.CredentialsJSON), c.BinlogStorageGCS.Endpoint, bucket, prefix, c.VerifyTLS)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "new gcs storage")
+		}
+		defaultStorage, err = storage.NewGCS(ctx, []byte(c.BackupStorageGCS.CredentialsJSON), c.BackupStorageGCS.Endpoint, c.BackupStorageGCS.Bucket, strings.TrimSuffix(c.BackupStorageGCS.BackupDest, "/")+"/", c.VerifyTLS)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "new gcs storage")
+		}
 	default:
 		return nil, nil, errors.New("unknown STORAGE_TYPE")
 	}
@@ -140,6 +168,33 @@ type BinlogAzure struct {
 	Concurrency   int    `env:"BINLOG_AZURE_CONCURRENCY"`
 }
 
+type BackupGCS struct {
+	Endpoint        string `env:"GCS_ENDPOINT"`
+	Bucket          string `env:"GCS_BUCKET,required"`
+	BackupDest      string `env:"GCS_BUCKET_PATH,required"`
+	CredentialsJSON string `env:"GCS_CREDENTIALS_JSON"`
+}
+
+type BinlogEncryption struct {
+	Algorithm string `env:"BINLOG_ENCRYPTION_ALGORITHM"`
+	Key       string `env:"BINLOG_ENCRYPTION_KEY"`
+	VaultPath string `env:"BINLOG_ENCRYPTION_VAULT_PATH"`
+}
+
+func (e BinlogEncryption) Config() encryption.Config {
+	return encryption.Config{
+		Algorithm: e.Algorithm,
+		Key:       e.Key,
+		VaultPath: e.VaultPath,
+	}
+}
+
+type BinlogGCS struct {
+	Endpoint        string `env:"BINLOG_GCS_ENDPOINT"`
+	BucketURL       string `env:"BINLOG_GCS_BUCKET_URL,required"`
+	CredentialsJSON string `env:"BINLOG_GCS_CREDENTIALS_JSON"`
+}
+
 func (c *Config) Verify() {
 	if len(c.BackupStorageS3.Endpoint) == 0 {
 		c.BackupStorageS3.Endpoint = "s3.amazonaws.com"
@@ -161,7 +216,25 @@ func New(ctx context.Context, c Config) (*Recoverer, error) {
 		return nil, errors.Wrap(err, "new binlog storage manager")
 	}
 
-	startGTID, err := getStartGTIDSet(ctx, storage)
+	var xbstreamArgs []string
+	if c.BackupEncryption.Enabled() {
+		keyFile, err := c.BackupEncryption.WriteKeyFile(ctx, os.TempDir())
+		if err != nil {
+			return nil, errors.Wrap(err, "write backup encryption key")
+		}
+		defer os.Remove(keyFile) //nolint:errcheck
+		xbstreamArgs = c.BackupEncryption.XbstreamArgs(keyFile)
+	}
+
+	binlogEncryption := c.BinlogEncryption.Config()
+	if binlogEncryption.Enabled() {
+		binlogEncryption, err = binlogEncryption.WithKey(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "get binlog encryption key")
+		}
+	}
+
+	startGTID, err := getStartGTIDSet(ctx, storage, xbstreamArgs)
 	if err != nil {
 		return nil, errors.Wrap(err, "get start GTID")
 	}
@@ -203,6 +276,11 @@ func New(ctx context.Context, c Config) (*Recoverer, error) {
 		startGTID:      startGTID,
 		gtid:           c.GTID,
 		verifyTLS:      c.VerifyTLS,
+		encryption:     binlogEncryption,
+		downloadRate:   c.Throttling.DownloadRateLimit,
+		binlogFile:     c.BinlogFile,
+		binlogPosition: c.BinlogPosition,
+		dryRun:         c.DryRun,
 	}, nil
 }
 
@@ -245,6 +323,7 @@ const (
 	Date        RecoverType = "date"        // recover to exact date
 	Transaction RecoverType = "transaction" // recover to needed trunsaction
 	Skip        RecoverType = "skip"        // skip transactions
+	Position    RecoverType = "position"    // recover to the position in the binlog
 )
 
 func (r *Recoverer) Run(ctx context.Context) error {
@@ -280,12 +359,28 @@ func (r *Recoverer) Run(ctx context.Context) error {
 		r.recoverEndTime = endTime
 
 		log.Printf("recovery type: %s, target time: %s", Date, r.recoverEndTime)
+	case Position:
+		idx := slices.IndexFunc(r.binlogs, func(binlog string) bool {
+			return isBinlog(binlog, r.binlogFile)
+		})
+		if idx == -1 {
+			return errors.Errorf("binlog %s is not found after the backup", r.binlogFile)
+		}
+		r.binlogs = r.binlogs[:idx+1]
+		log.Printf("recovery type: %s, binlog: %s, position: %d", Position, r.binlogs[idx], r.binlogPosition)
 	case Latest:
 		log.Printf("recovery type: %s", Latest)
 	default:
 		return errors.New("wrong recover type")
 	}
 
+	if r.dryRun {
+		if err := r.listTransactions(ctx, os.Stdout); err != nil {
+			return errors.Wrap(err, "list transactions")
+		}
+		return nil
+	}
+
 	err = r.recover(ctx)
 	if err != nil {
 		return errors.Wrap(err, "recover")
@@ -329,39 +424,43 @@ func (r *Recoverer) recover(ctx context.Context) (err error) {
 		return errors.Wrap(err, "start mysql")
 	}
 
+	// the transactions applied by the previous pod of the job are skipped
+	executed, err := r.db.GetGTIDExecuted(ctx)
+	if err != nil {
+		return errors.Wrap(err, "get executed gtid set")
+	}
+
+	progress := newProgress(naming.PITRRestoreProgressDir, len(r.binlogs))
 	for i, binlog := range r.binlogs {
 		remaining := len(r.binlogs) - i
 		log.Printf("working with %s, %d out of %d remaining\n", binlog, remaining, len(r.binlogs))
-		if r.recoverType == Date {
-			binlogArr := strings.Split(binlog, "_")
-			if len(binlogArr) < 2 {
-				return errors.New("get timestamp from binlog name")
-			}
-			binlogTime, err := strconv.ParseInt(binlogArr[1], 10, 64)
+		apply, err := r.isBeforeRecoverTime(binlog)
+		if err != nil {
+			return err
+		}
+		if !apply {
+			break
+		}
+
+		set := r.binlogSets[binlog]
+		if set != "" && executed != "" {
+			applied, err := r.db.GTIDSubset(ctx, set, executed)
 			if err != nil {
-				return errors.Wrap(err, "get binlog time")
+				return errors.Wrapf(err, "check if %s is applied", binlog)
 			}
-			if binlogTime > r.recoverEndTime.Unix() {
-				log.Printf("Stopping at %s because it's after the recovery time (%d > %d)", binlog, binlogTime, r.recoverEndTime.Unix())
-				break
+			if applied {
+				log.Printf("skipping %s, the transactions are already applied", binlog)
+				progress.write(i+1, set)
+				continue
 			}
 		}
 
-		binlogObj, err := r.storage.GetObject(ctx, binlog)
-		if err != nil {
-			return errors.Wrap(err, "get obj")
-		}
-
-		cmd := exec.CommandContext(ctx, "sh", "-c", "mysqlbinlog --disable-log-bin "+r.recoverFlag+" -")
-		log.Printf("Running %s", cmd.String())
-		cmd.Stdin = binlogObj
-		cmd.Stdout = binlogStdout
-		cmd.Stderr = os.Stderr
-		err = cmd.Run()
-		if err != nil {
-			return errors.Wrapf(err, "run mysqlbinlog")
+		progress.write(i, set)
+		if err := r.mysqlbinlog(ctx, binlog, "--disable-log-bin "+r.binlogFlags(i), binlogStdout); err != nil {
+			return err
 		}
 	}
+	progress.write(len(r.binlogs), "")
 
 	if err := binlogStdout.Close(); err != nil {
 		return errors.Wrap(err, "close binlog stdout")
@@ -378,9 +477,89 @@ func (r *Recoverer) recover(ctx context.Context) (err error) {
 	return nil
 }
 
+// isBeforeRecoverTime returns false if the binlog starts after the recovery time of the date recovery.
+func (r *Recoverer) isBeforeRecoverTime(binlog string) (bool, error) {
+	if r.recoverType != Date {
+		return true, nil
+	}
+
+	binlogArr := strings.Split(binlog, "_")
+	if len(binlogArr) < 2 {
+		return false, errors.New("get timestamp from binlog name")
+	}
+	binlogTime, err := strconv.ParseInt(binlogArr[1], 10, 64)
+	if err != nil {
+		return false, errors.Wrap(err, "get binlog time")
+	}
+	if binlogTime > r.recoverEndTime.Unix() {
+		log.Printf("Stopping at %s because it's after the recovery time (%d > %d)", binlog, binlogTime, r.recoverEndTime.Unix())
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// binlogFlags returns the mysqlbinlog flags for the i-th binlog of the recovery.
+func (r *Recoverer) binlogFlags(i int) string {
+	flags := r.recoverFlag
+	if r.recoverType == Position && i == len(r.binlogs)-1 {
+		flags += " --stop-position=" + strconv.FormatInt(r.binlogPosition, 10)
+	}
+	return flags
+}
+
+// mysqlbinlog downloads the binlog from the storage, decodes it with mysqlbinlog
+// with the given flags and writes the output to w.
+func (r *Recoverer) mysqlbinlog(ctx context.Context, binlog, flags string, w io.Writer) error {
+	binlogObj, err := r.storage.GetObject(ctx, binlog)
+	if err != nil {
+		return errors.Wrap(err, "get obj")
+	}
+	defer binlogObj.Close()
+	binlogData := throttling.NewReader(ctx, binlogObj, r.downloadRate)
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", "mysqlbinlog "+flags+" -")
+	log.Printf("Running %s", cmd.String())
+	cmd.Stdin = binlogData
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+
+	var xbcrypt *exec.Cmd
+	if r.encryption.Enabled() {
+		keyFile, err := r.encryption.WriteKeyFile(ctx, os.TempDir())
+		if err != nil {
+			return errors.Wrap(err, "write binlog encryption key")
+		}
+		defer os.Remove(keyFile) //nolint:errcheck
+
+		xbcrypt = r.encryption.XbcryptCmd(ctx, keyFile, true)
+		xbcrypt.Stdin = binlogData
+		xbcrypt.Stderr = os.Stderr
+		cmd.Stdin, err = xbcrypt.StdoutPipe()
+		if err != nil {
+			return errors.Wrap(err, "xbcrypt stdout pipe")
+		}
+		if err := xbcrypt.Start(); err != nil {
+			return errors.Wrap(err, "start xbcrypt")
+		}
+	}
+
+	err = cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "run mysqlbinlog")
+	}
+	if xbcrypt != nil {
+		if err := xbcrypt.Wait(); err != nil {
+			return errors.Wrapf(err, "decrypt %s", binlog)
+		}
+	}
+
+	return nil
+}
+
 type testContextKey struct{}
 
-func getDecompressedContent(ctx context.Context, infoObj io.Reader, filename string) ([]byte, error) {
+func getDecompressedContent(ctx context.Context, infoObj io.Reader, filename string, xbstreamArgs []string) ([]byte, error) {
 	// this is done to support unit tests
 	if val, ok := ctx.Value(testContextKey{}).(bool); ok && val {
 		return io.ReadAll(infoObj)
@@ -388,7 +567,7 @@ func getDecompressedContent(ctx context.Context, infoObj io.Reader, filename str
 
 	tmpDir := os.TempDir()
 
-	cmd := exec.CommandContext(ctx, "xbstream", "-x", "--decompress")
+	cmd := exec.CommandContext(ctx, "xbstream", append([]string{"-x", "--decompress"}, xbstreamArgs...)...)
 	cmd.Dir = tmpDir
 	cmd.Stdin = infoObj
 	var outb, errb bytes.Buffer
@@ -417,6 +596,7 @@ func (r *Recoverer) setBinlogs(ctx context.Context) error {
 	}
 	reverse(list)
 	binlogs := []string{}
+	r.binlogSets = make(map[string]string)
 	sourceID := strings.Split(r.startGTID, ":")[0]
 	log.Println("current gtid set is", r.startGTID)
 	for _, binlog := range list {
@@ -453,6 +633,7 @@ func (r *Recoverer) setBinlogs(ctx context.Context) error {
 		}
 
 		binlogs = append(binlogs, binlog)
+		r.binlogSets[binlog] = strings.TrimSpace(binlogGTIDSet)
 		subResult, err := r.db.SubtractGTIDSet(ctx, r.startGTID, binlogGTIDSet)
 		log.Println("Checking sub result", " binlog gtid ", binlogGTIDSet, " sub result ", subResult)
 		if err != nil {
@@ -504,13 +685,15 @@ func reverse(list []string) {
 	}
 }
 
-func getStartGTIDSet(ctx context.Context, s storage.Storage) (string, error) {
-	currGTID, err := getGTID(ctx, s)
+// getStartGTIDSet returns GTID set of the backup.
+// xbstreamArgs are passed to xbstream to decrypt the backup files.
+func getStartGTIDSet(ctx context.Context, s storage.Storage, xbstreamArgs []string) (string, error) {
+	currGTID, err := getGTID(ctx, s, xbstreamArgs)
 	if err != nil {
 		return "", errors.Wrapf(err, "get gtid")
 	}
 
-	xbInfoContent, err := getXtrabackupInfo(ctx, s)
+	xbInfoContent, err := getXtrabackupInfo(ctx, s, xbstreamArgs)
 	if err != nil {
 		return "", errors.Wrapf(err, "get xtrabackup info")
 	}
@@ -522,7 +705,7 @@ func getStartGTIDSet(ctx context.Context, s storage.Storage) (string, error) {
 	return fmt.Sprintf("%s:%s", currGTID, set), nil
 }
 
-func getXtrabackupInfo(ctx context.Context, s storage.Storage) ([]byte, error) {
+func getXtrabackupInfo(ctx context.Context, s storage.Storage, xbstreamArgs []string) ([]byte, error) {
 	xbInfo, err := s.ListObjects(ctx, "xtrabackup_info")
 	if err != nil {
 		return nil, errors.Wrapf(err, "list xtrabackup_info objects")
@@ -535,21 +718,21 @@ func getXtrabackupInfo(ctx context.Context, s storage.Storage) ([]byte, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "get xtrabackup_info object")
 	}
-	xbInfoContent, err := getDecompressedContent(ctx, xbInfoObj, "xtrabackup_info")
+	xbInfoContent, err := getDecompressedContent(ctx, xbInfoObj, "xtrabackup_info", xbstreamArgs)
 	if err != nil {
 		return nil, errors.Wrapf(err, "get decompressed content for xtrabackup_info")
 	}
 	return xbInfoContent, nil
 }
 
-func getGTID(ctx context.Context, s storage.Storage) (string, error) {
+func getGTID(ctx context.Context, s storage.Storage, xbstreamArgs []string) (string, error) {
 	sstInfo, err := s.ListObjects(ctx, ".sst_info/sst_info")
 	if err != nil {
 		return "", errors.Wrapf(err, "list sst_info objects objects")
 	}
 	if len(sstInfo) > 0 {
 		sort.Strings(sstInfo)
-		return getGTIDFromSSTInfo(ctx, sstInfo[0], s)
+		return getGTIDFromSSTInfo(ctx, sstInfo[0], s, xbstreamArgs)
 	}
 
 	xbBinlogInfo, err := s.ListObjects(ctx, "xtrabackup_binlog_info")
@@ -558,7 +741,7 @@ func getGTID(ctx context.Context, s storage.Storage) (string, error) {
 	}
 	if len(xbBinlogInfo) > 0 {
 		sort.Strings(xbBinlogInfo)
-		return getGTIDFromXtrabackupBinlogInfo(ctx, xbBinlogInfo[0], s)
+		return getGTIDFromXtrabackupBinlogInfo(ctx, xbBinlogInfo[0], s, xbstreamArgs)
 	}
 	return "", errors.New("no sst_info or xtrabackup_binlog_info objects found")
 }
@@ -566,12 +749,13 @@ func getGTID(ctx context.Context, s storage.Storage) (string, error) {
 func getGTIDFromSSTInfo(
 	ctx context.Context,
 	sstInfoFile string,
-	s storage.Storage) (string, error) {
+	s storage.Storage,
+	xbstreamArgs []string) (string, error) {
 	sstInfoObj, err := s.GetObject(ctx, sstInfoFile)
 	if err != nil {
 		return "", errors.Wrapf(err, "get sst_info object")
 	}
-	sstContent, err := getDecompressedContent(ctx, sstInfoObj, "sst_info")
+	sstContent, err := getDecompressedContent(ctx, sstInfoObj, "sst_info", xbstreamArgs)
 	if err != nil {
 		return "", errors.Wrapf(err, "get decompressed content for sst_info")
 	}
@@ -598,13 +782,13 @@ func parseGTIDFromSSTInfoContent(content []byte) (string, error) {
 	return string(newOut[:e]), nil
 }
 
-func getGTIDFromXtrabackupBinlogInfo(ctx context.Context, xbBinlogInfoFile string, s storage.Storage) (string, error) {
+func getGTIDFromXtrabackupBinlogInfo(ctx context.Context, xbBinlogInfoFile string, s storage.Storage, xbstreamArgs []string) (string, error) {
 	xbBinlogInfoObj, err := s.GetObject(ctx, xbBinlogInfoFile)
 	if err != nil {
 		return "", errors.Wrapf(err, "get xtrabackup_binlog_info object")
 	}
 
-	xbBinlogInfoContent, err := getDecompressedContent(ctx, xbBinlogInfoObj, "xtrabackup_binlog_info")
+	xbBinlogInfoContent, err := getDecompressedContent(ctx, xbBinlogInfoObj, "xtrabackup_binlog_info", xbstreamArgs)
 	if err != nil {
 		return "", errors.Wrapf(err, "get decompressed content for xtrabackup_binlog_info")
 	}
@@ -169,7 +169,7 @@ func TestGetStartGTID(t *testing.T) {
 			mockStorage := mock.NewStorage(t)
 			tc.mockFn(mockStorage)
 
-			got, err := getStartGTIDSet(ctx, mockStorage)
+			got, err := getStartGTIDSet(ctx, mockStorage, nil)
 			if (err != nil) != tc.wantErr {
 				t.Errorf("getStartGTIDSet() error = %v, wantErr %v", err, tc.wantErr)
 			}
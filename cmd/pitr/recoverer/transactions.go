@@ -0,0 +1,175 @@
+package recoverer
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxStatementLength is the length of the statement printed for the transaction.
+const maxStatementLength = 100
+
+// listTransactions prints the transactions which would be applied by the recovery.
+// The position of the transaction can be used as binlogPosition of the position recovery
+// to stop before the transaction. The gtid can be used for the transaction and skip recoveries.
+func (r *Recoverer) listTransactions(ctx context.Context, w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "TIME\tGTID\tBINLOG\tPOSITION\tSTATEMENT"); err != nil {
+		return err
+	}
+
+	for i, binlog := range r.binlogs {
+		apply, err := r.isBeforeRecoverTime(binlog)
+		if err != nil {
+			return err
+		}
+		if !apply {
+			break
+		}
+
+		pr, pw := io.Pipe()
+		errCh := make(chan error, 1)
+		go func() {
+			err := printTransactions(pr, w, binlog)
+			// drain the output of mysqlbinlog if the parsing failed
+			_, _ = io.Copy(io.Discard, pr)
+			errCh <- err
+		}()
+
+		err = r.mysqlbinlog(ctx, binlog, "--verbose --base64-output=DECODE-ROWS "+r.binlogFlags(i), pw)
+		pw.Close()
+		if perr := <-errCh; err == nil {
+			err = perr
+		}
+		if err != nil {
+			return errors.Wrapf(err, "list transactions of %s", binlog)
+		}
+	}
+
+	return nil
+}
+
+var eventHeaderRegexp = regexp.MustCompile(`^#(\d{6}\s+\d{1,2}:\d{2}:\d{2})\s+server id`)
+
+type transaction struct {
+	gtid      string
+	time      string
+	position  int64
+	statement string
+}
+
+// printTransactions parses the output of mysqlbinlog --verbose and prints
+// a line with the time, gtid, position and the first statement of each transaction.
+func printTransactions(r io.Reader, w io.Writer, binlog string) error {
+	var (
+		position  int64
+		eventTime string
+		trx       *transaction
+	)
+
+	flush := func() error {
+		if trx == nil {
+			return nil
+		}
+		_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", trx.time, trx.gtid, binlog, trx.position, trx.statement)
+		trx = nil
+		return err
+	}
+
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return errors.Wrap(err, "read mysqlbinlog output")
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(line, "# at "):
+			position, _ = strconv.ParseInt(strings.TrimPrefix(line, "# at "), 10, 64)
+		case eventHeaderRegexp.MatchString(line):
+			eventTime = parseEventTime(eventHeaderRegexp.FindStringSubmatch(line)[1])
+		case strings.HasPrefix(line, "SET @@SESSION.GTID_NEXT="):
+			_, gtid, _ := strings.Cut(line, "'")
+			gtid, _, _ = strings.Cut(gtid, "'")
+			if gtid == "AUTOMATIC" {
+				break
+			}
+			if err := flush(); err != nil {
+				return err
+			}
+			trx = &transaction{gtid: gtid, time: eventTime, position: position}
+		case trx != nil && trx.statement == "":
+			trx.statement = statementSummary(line)
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return flush()
+}
+
+// statementSummary returns the statement of the line or an empty string
+// if the line doesn't contain the statement of the transaction.
+func statementSummary(line string) string {
+	switch {
+	case strings.HasPrefix(line, "### INSERT"),
+		strings.HasPrefix(line, "### UPDATE"),
+		strings.HasPrefix(line, "### DELETE"):
+		line = strings.TrimPrefix(line, "### ")
+	case line == "",
+		strings.HasPrefix(line, "#"),
+		strings.HasPrefix(line, "/*"),
+		strings.HasPrefix(line, "SET "),
+		strings.HasPrefix(line, "use "),
+		strings.HasPrefix(line, "BEGIN"),
+		strings.HasPrefix(line, "COMMIT"),
+		strings.HasPrefix(line, "ROLLBACK"),
+		strings.HasPrefix(line, "DELIMITER"),
+		strings.HasPrefix(line, "BINLOG"),
+		strings.HasPrefix(line, "'"):
+		return ""
+	}
+
+	line = strings.TrimSuffix(line, "/*!*/;")
+	if len(line) > maxStatementLength {
+		line = line[:maxStatementLength] + "..."
+	}
+	return line
+}
+
+// parseEventTime converts the time of the binlog event header (YYMMDD HH:MM:SS)
+// to the format of the date recovery.
+func parseEventTime(s string) string {
+	t, err := time.Parse("060102 15:04:05", strings.Join(strings.Fields(s), " "))
+	if err != nil {
+		return s
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// isBinlog checks if the binlog in the storage is the binlog file.
+// The file is either the name of the binlog in the storage or the name
+// of the binlog on the server, which is matched by its sequence number.
+// The names of the binlogs in the storage are binlog_<timestamp>_<number>_<gtid set hash>.
+func isBinlog(binlog, file string) bool {
+	if path.Base(binlog) == file {
+		return true
+	}
+
+	parts := strings.Split(path.Base(binlog), "_")
+	if len(parts) != 4 {
+		return false
+	}
+	ext := path.Ext(file)
+	return ext != "" && strings.TrimPrefix(ext, ".") == parts[2]
+}
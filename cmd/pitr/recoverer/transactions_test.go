@@ -0,0 +1,99 @@
+package recoverer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mysqlbinlogOutput = `# The proper term is pseudo_replica_mode, but we use this compatibility alias
+/*!50530 SET @@SESSION.PSEUDO_SLAVE_MODE=1*/;
+# at 4
+#231115 10:00:00 server id 1  end_log_pos 126 CRC32 0x1b9bd4a5 	Start: binlog v 4, server v 8.0.35-27.1 created 231115 10:00:00
+# at 157
+#231115 10:00:01 server id 1  end_log_pos 236 CRC32 0x9d2e7cf2 	GTID	last_committed=0	sequence_number=1	rbr_only=yes	original_committed_timestamp=1700042401000000
+/*!50718 SET TRANSACTION ISOLATION LEVEL READ COMMITTED*//*!*/;
+SET @@SESSION.GTID_NEXT= '3e11fa47-71ca-11e1-9e33-c80aa9429562:23'/*!*/;
+# at 236
+#231115 10:00:01 server id 1  end_log_pos 311 CRC32 0x8e0c3c6b 	Query	thread_id=8	exec_time=0	error_code=0
+SET TIMESTAMP=1700042401/*!*/;
+BEGIN
+/*!*/;
+# at 311
+#231115 10:00:01 server id 1  end_log_pos 366 CRC32 0x5d2a0e55 	Table_map: ` + "`shop`.`orders`" + ` mapped to number 90
+# at 366
+#231115 10:00:01 server id 1  end_log_pos 410 CRC32 0x7aa0b7d1 	Write_rows: table id 90 flags: STMT_END_F
+### INSERT INTO ` + "`shop`.`orders`" + `
+### SET
+###   @1=1
+# at 410
+#231115 10:00:01 server id 1  end_log_pos 441 CRC32 0x2d5e1cb5 	Xid = 12
+COMMIT/*!*/;
+# at 441
+#231115 10:05:00 server id 1  end_log_pos 518 CRC32 0x7b0f1c1e 	GTID	last_committed=1	sequence_number=2	rbr_only=no	original_committed_timestamp=1700042700000000
+SET @@SESSION.GTID_NEXT= '3e11fa47-71ca-11e1-9e33-c80aa9429562:24'/*!*/;
+# at 518
+#231115 10:05:00 server id 1  end_log_pos 640 CRC32 0x3a7b2c4d 	Query	thread_id=8	exec_time=0	error_code=0	Xid = 15
+use ` + "`shop`" + `/*!*/;
+SET TIMESTAMP=1700042700/*!*/;
+DROP TABLE ` + "`orders`" + ` /* generated by server */
+/*!*/;
+SET @@SESSION.GTID_NEXT= 'AUTOMATIC' /* added by mysqlbinlog */ /*!*/;
+DELIMITER ;
+# End of log file
+`
+
+func TestPrintTransactions(t *testing.T) {
+	var out bytes.Buffer
+	err := printTransactions(strings.NewReader(mysqlbinlogOutput), &out, "binlog_1700042400_000011_abc")
+	require.NoError(t, err)
+
+	expected := "2023-11-15 10:00:01\t3e11fa47-71ca-11e1-9e33-c80aa9429562:23\tbinlog_1700042400_000011_abc\t157\tINSERT INTO `shop`.`orders`\n" +
+		"2023-11-15 10:05:00\t3e11fa47-71ca-11e1-9e33-c80aa9429562:24\tbinlog_1700042400_000011_abc\t441\tDROP TABLE `orders` /* generated by server */\n"
+	assert.Equal(t, expected, out.String())
+}
+
+func TestStatementSummary(t *testing.T) {
+	assert.Equal(t, "", statementSummary("BEGIN"))
+	assert.Equal(t, "", statementSummary("SET TIMESTAMP=1700042700/*!*/;"))
+	assert.Equal(t, "", statementSummary("### SET"))
+	assert.Equal(t, "UPDATE `shop`.`orders`", statementSummary("### UPDATE `shop`.`orders`"))
+	assert.Equal(t, strings.Repeat("a", maxStatementLength)+"...", statementSummary(strings.Repeat("a", maxStatementLength+1)))
+}
+
+func TestIsBinlog(t *testing.T) {
+	tests := []struct {
+		binlog   string
+		file     string
+		expected bool
+	}{
+		{"binlog_1700042400_000011_abc", "binlog_1700042400_000011_abc", true},
+		{"pitr/binlog_1700042400_000011_abc", "binlog_1700042400_000011_abc", true},
+		{"binlog_1700042400_000011_abc", "binlog.000011", true},
+		{"binlog_1700042400_000011_abc", "mysql-bin.000011", true},
+		{"binlog_1700042400_000011_abc", "binlog.000012", false},
+		{"binlog_1700042400_abc", "binlog.000011", false},
+		{"binlog_1700042400_000011_abc", "000011", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, isBinlog(tt.binlog, tt.file), "%s %s", tt.binlog, tt.file)
+	}
+}
+
+func TestBinlogFlags(t *testing.T) {
+	r := &Recoverer{
+		recoverType:    Position,
+		binlogs:        []string{"binlog_1_000010_a", "binlog_2_000011_b"},
+		binlogPosition: 441,
+	}
+	assert.Equal(t, "", r.binlogFlags(0))
+	assert.Equal(t, " --stop-position=441", r.binlogFlags(1))
+
+	r.recoverType = Skip
+	r.recoverFlag = "--exclude-gtids=uuid:24"
+	assert.Equal(t, "--exclude-gtids=uuid:24", r.binlogFlags(1))
+}
@@ -0,0 +1,97 @@
+// A small utility program to copy stdin to stdout with a limited speed.
+// It's used by backup and restore scripts to limit the network bandwidth of xbcloud.
+// If the progress file is passed, the number of copied bytes is written to the file
+// as downloaded=<bytes> every second. The bytes are added to the number in the existing file,
+// so the file accumulates the size of all backups downloaded by the restore. The restore
+// script removes the file when the download of the backups is started over.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/throttling"
+)
+
+func main() {
+	if len(os.Args) != 2 && len(os.Args) != 3 {
+		log.Fatalf("Usage: %s <bytes per second> [<progress file>]", os.Args[0])
+	}
+	limit, err := strconv.ParseInt(os.Args[1], 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid limit %s: %v", os.Args[1], err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var w io.Writer = os.Stdout
+	var c *progressCounter
+	if len(os.Args) == 3 {
+		c = newProgressCounter(os.Args[2])
+		go c.run(ctx)
+		w = io.MultiWriter(os.Stdout, c)
+	}
+
+	_, err = io.Copy(w, throttling.NewReader(ctx, os.Stdin, limit))
+	// log.Fatalf doesn't run the deferred functions, so the last progress is written explicitly
+	if c != nil {
+		c.write()
+	}
+	if err != nil {
+		log.Fatalf("Copy: %v", err)
+	}
+}
+
+type progressCounter struct {
+	file  string
+	bytes atomic.Int64
+}
+
+func newProgressCounter(file string) *progressCounter {
+	c := &progressCounter{file: file}
+	if data, err := os.ReadFile(file); err == nil {
+		n, _ := strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(string(data)), "downloaded="), 10, 64)
+		c.bytes.Store(n)
+	}
+	return c
+}
+
+func (c *progressCounter) Write(p []byte) (int, error) {
+	c.bytes.Add(int64(len(p)))
+	return len(p), nil
+}
+
+func (c *progressCounter) run(ctx context.Context) {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			c.write()
+		}
+	}
+}
+
+// write replaces the progress file, so the readers never see a partially written file.
+func (c *progressCounter) write() {
+	tmp := c.file + ".tmp"
+	if err := os.WriteFile(tmp, []byte(fmt.Sprintf("downloaded=%d\n", c.bytes.Load())), 0o644); err != nil {
+		log.Printf("Write progress: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, c.file); err != nil {
+		log.Printf("Write progress: %v", err)
+	}
+}
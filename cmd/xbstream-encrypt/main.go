@@ -0,0 +1,50 @@
+// A small utility program to encrypt the xbstream archive from stdin to stdout.
+// It's used by the backup job to encrypt the SST stream received from a donor node the same way
+// xtrabackup --encrypt does it, so the backup is restored by xbstream -x --decrypt.
+// The encryption is configured by ENCRYPTION_ALGORITHM, ENCRYPTION_KEY and ENCRYPTION_VAULT_PATH.
+package main
+
+import (
+	"bufio"
+	"context"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/caarlos0/env"
+
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/encryption"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatalf("Encrypt stream: %v", err)
+	}
+}
+
+func run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg := encryption.Config{}
+	if err := env.Parse(&cfg); err != nil {
+		return err
+	}
+
+	keyFile, err := cfg.WriteKeyFile(ctx, "")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(keyFile) //nolint:errcheck
+
+	out := bufio.NewWriterSize(os.Stdout, 4<<20)
+	err = encryption.EncryptXbstream(ctx, bufio.NewReaderSize(os.Stdin, 4<<20), out, func(ctx context.Context) *exec.Cmd {
+		return cfg.XbcryptCmd(ctx, keyFile, false)
+	})
+	if err != nil {
+		return err
+	}
+	return out.Flush()
+}
@@ -8,8 +8,12 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
+	"github.com/caarlos0/env"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/encryption"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/throttling"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/xtrabackup/api"
 	xbscapi "github.com/percona/percona-xtradb-cluster-operator/pkg/xtrabackup/api"
 	xbscserver "github.com/percona/percona-xtradb-cluster-operator/pkg/xtrabackup/server"
@@ -104,6 +108,22 @@ func getRequestObject() *xbscapi.CreateBackupRequest {
 	req.BackupConfig.VerifyTls = os.Getenv("VERIFY_TLS") == "true"
 	req.BackupConfig.ContainerOptions = containerOptions
 
+	if lsn := os.Getenv("INCREMENTAL_LSN"); lsn != "" {
+		incrementalLSN, err := strconv.ParseUint(lsn, 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid INCREMENTAL_LSN: %v", err)
+		}
+		req.BackupConfig.IncrementalLsn = incrementalLSN
+	}
+
+	if err := setEncryptionConfig(req); err != nil {
+		log.Fatalf("Failed to get encryption config: %v", err)
+	}
+
+	if err := setThrottlingConfig(req); err != nil {
+		log.Fatalf("Failed to get throttling config: %v", err)
+	}
+
 	storageType := os.Getenv("STORAGE_TYPE")
 	switch storageType {
 	case "s3":
@@ -112,6 +132,9 @@ func getRequestObject() *xbscapi.CreateBackupRequest {
 	case "azure":
 		req.BackupConfig.Type = xbscapi.BackupStorageType_AZURE
 		setAzureConfig(req)
+	case "gcs":
+		req.BackupConfig.Type = xbscapi.BackupStorageType_GCS
+		setGCSConfig(req)
 	default:
 		log.Fatalf("Invalid storage type: %s", storageType)
 	}
@@ -147,6 +170,53 @@ func setAzureConfig(req *xbscapi.CreateBackupRequest) {
 	}
 }
 
+func setGCSConfig(req *xbscapi.CreateBackupRequest) {
+	req.BackupConfig.Gcs = &xbscapi.GCSConfig{
+		Bucket:       os.Getenv("GCS_BUCKET"),
+		EndpointUrl:  os.Getenv("GCS_ENDPOINT"),
+		StorageClass: os.Getenv("GCS_STORAGE_CLASS"),
+		AccessKey:    os.Getenv("GCS_ACCESS_KEY"),
+		SecretKey:    os.Getenv("GCS_SECRET_KEY"),
+	}
+}
+
+func setEncryptionConfig(req *xbscapi.CreateBackupRequest) error {
+	cfg := encryption.Config{}
+	if err := env.Parse(&cfg); err != nil {
+		return err
+	}
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	key, err := cfg.GetKey(context.Background())
+	if err != nil {
+		return err
+	}
+	req.BackupConfig.Encryption = &xbscapi.EncryptionConfig{
+		Algorithm: cfg.GetAlgorithm(),
+		Key:       key,
+	}
+	return nil
+}
+
+func setThrottlingConfig(req *xbscapi.CreateBackupRequest) error {
+	cfg := throttling.Config{}
+	if err := env.Parse(&cfg); err != nil {
+		return err
+	}
+	if cfg == (throttling.Config{}) {
+		return nil
+	}
+
+	req.BackupConfig.Throttling = &xbscapi.ThrottlingConfig{
+		Parallel:        uint32(max(cfg.Parallel, 0)),
+		Throttle:        uint32(max(cfg.Throttle, 0)),
+		UploadRateLimit: cfg.UploadRateLimit,
+	}
+	return nil
+}
+
 func sanitizeRequest(req *xbscapi.CreateBackupRequest) (string, error) {
 	// Create a deep copy to avoid modifying the original request
 	reqBytes, err := json.Marshal(req)
@@ -169,6 +239,13 @@ func sanitizeRequest(req *xbscapi.CreateBackupRequest) (string, error) {
 		if reqCopy.BackupConfig.Azure != nil {
 			reqCopy.BackupConfig.Azure.AccessKey = "********"
 		}
+		if reqCopy.BackupConfig.Gcs != nil {
+			reqCopy.BackupConfig.Gcs.AccessKey = "********"
+			reqCopy.BackupConfig.Gcs.SecretKey = "********"
+		}
+		if reqCopy.BackupConfig.Encryption != nil {
+			reqCopy.BackupConfig.Encryption.Key = "********"
+		}
 	}
 
 	js, err := json.Marshal(&reqCopy)
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
@@ -48,8 +49,22 @@ type PerconaXtraDBClusterBackup struct {
 }
 
 type PXCBackupSpec struct {
-	PXCCluster               string                  `json:"pxcCluster"`
-	StorageName              string                  `json:"storageName,omitempty"`
+	PXCCluster  string `json:"pxcCluster"`
+	StorageName string `json:"storageName,omitempty"`
+	// Type is the type of the backup. Incremental backups contain only the changes
+	// made since the base backup and require the xtrabackup sidecar.
+	// Logical backups are dumps of the databases which can be restored
+	// into a cluster of another major version.
+	// +kubebuilder:validation:Enum={full,incremental,logical}
+	Type PXCBackupType `json:"type,omitempty"`
+	// Logical configures the dump of the logical backup.
+	Logical *PXCLogicalBackupOptions `json:"logical,omitempty"`
+	// Source selects the node the backup is taken from.
+	// By default the backup is taken from a synced node chosen by the backup job.
+	Source *PXCBackupSource `json:"source,omitempty"`
+	// BaseBackupName is the name of the backup the incremental backup is based on.
+	// If it's not specified, the latest succeeded backup on the same storage is used.
+	BaseBackupName           string                  `json:"baseBackupName,omitempty"`
 	ContainerOptions         *BackupContainerOptions `json:"containerOptions,omitempty"`
 	ActiveDeadlineSeconds    *int64                  `json:"activeDeadlineSeconds,omitempty"`
 	StartingDeadlineSeconds  *int64                  `json:"startingDeadlineSeconds,omitempty"`
@@ -58,6 +73,52 @@ type PXCBackupSpec struct {
 	// Once this threshold is reached, the backup will be marked as failed.
 	// When unspecified, uses the value from the parent cluster's .spec.backup.runningDeadlineSeconds (which defaults to 5m).
 	RunningDeadlineSeconds *int64 `json:"runningDeadlineSeconds,omitempty"`
+	// Verification configures the verification of the backup after it succeeds.
+	Verification *PXCBackupVerification `json:"verification,omitempty"`
+	// CopyTo is the list of storages the backup is copied to after it succeeds.
+	// Each copy is a separate PerconaXtraDBClusterBackup object which can be used for restores.
+	CopyTo []BackupCopySpec `json:"copyTo,omitempty"`
+	// CopyFrom is the name of the backup this backup is a copy of. It's set by the operator.
+	CopyFrom string `json:"copyFrom,omitempty"`
+}
+
+// BackupCopySpec configures a copy of the backup in another storage.
+type BackupCopySpec struct {
+	// +kubebuilder:validation:Required
+	StorageName string `json:"storageName"`
+	// DeleteFromStorage deletes the copy from the storage when its backup object is deleted.
+	DeleteFromStorage bool `json:"deleteFromStorage,omitempty"`
+}
+
+// IsCopy returns true if the backup is a copy of another backup.
+func (s *PXCBackupSpec) IsCopy() bool {
+	return s.CopyFrom != ""
+}
+
+// PXCBackupVerification configures a job which restores the backup into a scratch
+// volume, starts mysqld against the restored data and runs the checks.
+type PXCBackupVerification struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// CheckTablesLimit is the number of randomly chosen tables to run CHECK TABLE on.
+	// Set it to 0 to skip table checks.
+	// +kubebuilder:default=10
+	CheckTablesLimit *int32 `json:"checkTablesLimit,omitempty"`
+	// Queries are SQL statements executed against the restored data.
+	// The verification fails if any of them fails.
+	Queries               []string                    `json:"queries,omitempty"`
+	Resources             corev1.ResourceRequirements `json:"resources,omitempty"`
+	ActiveDeadlineSeconds *int64                      `json:"activeDeadlineSeconds,omitempty"`
+}
+
+func (v *PXCBackupVerification) IsEnabled() bool {
+	return v != nil && v.Enabled
+}
+
+func (v *PXCBackupVerification) GetCheckTablesLimit() int32 {
+	if v == nil || v.CheckTablesLimit == nil {
+		return 10
+	}
+	return *v.CheckTablesLimit
 }
 
 type PXCBackupStatus struct {
@@ -69,6 +130,7 @@ type PXCBackupStatus struct {
 	StorageName           string                            `json:"storageName,omitempty"`
 	S3                    *BackupStorageS3Spec              `json:"s3,omitempty"`
 	Azure                 *BackupStorageAzureSpec           `json:"azure,omitempty"`
+	GCS                   *BackupStorageGCSSpec             `json:"gcs,omitempty"`
 	PVC                   *corev1.PersistentVolumeClaimSpec `json:"pvc,omitempty"`
 	StorageType           BackupStorageType                 `json:"storage_type"`
 	Image                 string                            `json:"image,omitempty"`
@@ -78,6 +140,114 @@ type PXCBackupStatus struct {
 	Conditions            []metav1.Condition                `json:"conditions,omitempty"`
 	VerifyTLS             *bool                             `json:"verifyTLS,omitempty"`
 	LatestRestorableTime  *metav1.Time                      `json:"latestRestorableTime,omitempty"`
+	Type                  PXCBackupType                     `json:"type,omitempty"`
+	BaseBackupName        string                            `json:"baseBackupName,omitempty"`
+	// BaseChain contains destinations of the full backup and the incremental backups
+	// which should be applied, in order, before this incremental backup.
+	BaseChain []PXCBackupDestination `json:"baseChain,omitempty"`
+	LSN       *PXCBackupLSN          `json:"lsn,omitempty"`
+	// Encryption is copied from the storage when the backup is started
+	// and is used to decrypt the backup.
+	Encryption *BackupEncryptionSpec `json:"encryption,omitempty"`
+	// GTID is the GTID set of the last change included in the backup.
+	GTID string `json:"gtid,omitempty"`
+	// PXCVersion is the version of the cluster when the backup was taken.
+	PXCVersion string `json:"pxcVersion,omitempty"`
+	// Size is the total size of the backup objects in the storage.
+	Size *resource.Quantity `json:"size,omitempty"`
+	// Copies contains the copies of the backup in other storages.
+	Copies []PXCBackupCopyStatus `json:"copies,omitempty"`
+	// Source is the node the backup is taken from if the source is selected in the spec.
+	Source *PXCBackupSourceStatus `json:"source,omitempty"`
+}
+
+type PXCBackupSourceStatus struct {
+	// Cluster is the name of the cluster the backup is taken from.
+	// It differs from the cluster of the backup if the backup is taken from a replica cluster.
+	Cluster string `json:"cluster"`
+	Pod     string `json:"pod"`
+	// Desynced is true while the node is desynced and removed from the routing by the operator.
+	Desynced bool `json:"desynced,omitempty"`
+}
+
+type PXCBackupCopyStatus struct {
+	StorageName string `json:"storageName"`
+	// BackupName is the name of the backup object of the copy.
+	BackupName string         `json:"backupName"`
+	State      PXCBackupState `json:"state,omitempty"`
+}
+
+type PXCBackupType string
+
+const (
+	PXCBackupTypeFull        PXCBackupType = "full"
+	PXCBackupTypeIncremental PXCBackupType = "incremental"
+	PXCBackupTypeLogical     PXCBackupType = "logical"
+)
+
+// PXCLogicalBackupOptions configures the dump of the logical backup.
+type PXCLogicalBackupOptions struct {
+	// Threads is the number of threads dumping the tables in parallel.
+	// By default the number of CPUs of the backup pod is used.
+	// +kubebuilder:validation:Minimum=1
+	Threads int32 `json:"threads,omitempty"`
+	// Databases are dumped into the backup. By default all databases
+	// except the system ones are dumped.
+	// +kubebuilder:validation:items:Pattern=`^[0-9a-zA-Z$_]+$`
+	Databases []string `json:"databases,omitempty"`
+}
+
+type PXCBackupSourcePolicy string
+
+const (
+	PXCBackupSourcePod         PXCBackupSourcePolicy = "pod"
+	PXCBackupSourceLeastLoaded PXCBackupSourcePolicy = "leastLoaded"
+	PXCBackupSourceReplica     PXCBackupSourcePolicy = "replica"
+)
+
+// PXCBackupSource selects the node the backup is taken from.
+// +kubebuilder:validation:XValidation:rule="self.policy != 'pod' || has(self.pod)",message="'pod' is required for the 'pod' policy"
+// +kubebuilder:validation:XValidation:rule="self.policy != 'replica' || has(self.replicaCluster)",message="'replicaCluster' is required for the 'replica' policy"
+type PXCBackupSource struct {
+	// Policy is the way the node is selected:
+	// pod uses the pod set in the pod field,
+	// leastLoaded uses the ready node with the shortest wsrep_local_recv_queue,
+	// replica uses the least loaded node of the async replica cluster.
+	// +kubebuilder:validation:Enum={pod,leastLoaded,replica}
+	Policy PXCBackupSourcePolicy `json:"policy"`
+	// Pod is the name of the PXC pod the backup is taken from.
+	Pod string `json:"pod,omitempty"`
+	// ReplicaCluster is the name of the cluster in the same namespace
+	// which replicates from the cluster of the backup.
+	ReplicaCluster string `json:"replicaCluster,omitempty"`
+	// Desync sets pxc_maint_mode of the node to MAINTENANCE for the duration of the backup,
+	// so HAProxy and ProxySQL don't route the traffic to it. Unless the backup is streamed
+	// by Galera SST, which desyncs the donor itself, the node is also desynced with wsrep_desync,
+	// so it doesn't cause the flow control in the cluster.
+	// The node is used as is if it's the only ready node of the cluster.
+	Desync bool `json:"desync,omitempty"`
+}
+
+// PXCBackupLSN is the range of InnoDB log sequence numbers covered by the backup.
+type PXCBackupLSN struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to,omitempty"`
+}
+
+func (s *PXCBackupSpec) IsIncremental() bool {
+	return s.Type == PXCBackupTypeIncremental
+}
+
+func (status *PXCBackupStatus) IsIncremental() bool {
+	return status.Type == PXCBackupTypeIncremental
+}
+
+func (s *PXCBackupSpec) IsLogical() bool {
+	return s.Type == PXCBackupTypeLogical
+}
+
+func (status *PXCBackupStatus) IsLogical() bool {
+	return status.Type == PXCBackupTypeLogical
 }
 
 type PXCBackupDestination string
@@ -101,6 +271,10 @@ func (dest *PXCBackupDestination) SetAzureDestination(container, backupName stri
 	dest.set(AzureBlobStoragePrefix + container + "/" + backupName)
 }
 
+func (dest *PXCBackupDestination) SetGCSDestination(bucket, backupName string) {
+	dest.set(GCSBlobStoragePrefix + bucket + "/" + backupName)
+}
+
 func (dest *PXCBackupDestination) String() string {
 	if dest == nil {
 		return ""
@@ -109,7 +283,7 @@ func (dest *PXCBackupDestination) String() string {
 }
 
 func (dest *PXCBackupDestination) StorageTypePrefix() string {
-	for _, p := range []string{AwsBlobStoragePrefix, AzureBlobStoragePrefix, PVCStoragePrefix} {
+	for _, p := range []string{AwsBlobStoragePrefix, AzureBlobStoragePrefix, GCSBlobStoragePrefix, PVCStoragePrefix} {
 		if strings.HasPrefix(dest.String(), p) {
 			return p
 		}
@@ -163,6 +337,8 @@ func (status *PXCBackupStatus) GetStorageType(cluster *PerconaXtraDBCluster) Bac
 		return BackupStorageS3
 	case status.Azure != nil:
 		return BackupStorageAzure
+	case status.GCS != nil:
+		return BackupStorageGCS
 	case status.PVC != nil:
 		return BackupStorageFilesystem
 	}
@@ -172,6 +348,7 @@ func (status *PXCBackupStatus) GetStorageType(cluster *PerconaXtraDBCluster) Bac
 
 const (
 	BackupConditionPITRReady = "PITRReady"
+	BackupConditionVerified  = "Verified"
 )
 
 type PXCBackupState string
@@ -2,19 +2,59 @@ package v1
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // PerconaXtraDBClusterRestoreSpec defines the desired state of PerconaXtraDBClusterRestore
 type PerconaXtraDBClusterRestoreSpec struct {
-	PXCCluster       string                      `json:"pxcCluster"`
-	BackupName       string                      `json:"backupName"`
+	PXCCluster string `json:"pxcCluster"`
+	BackupName string `json:"backupName"`
+	// BackupNamespace is the namespace of the backup referenced by BackupName.
+	// The cluster which the backup belongs to should allow restores to the namespace
+	// of the restore in spec.backup.allowedRestoreNamespaces. Backups of the clusters
+	// which use the Vault keyring can't be restored in another namespace.
+	BackupNamespace  string                      `json:"backupNamespace,omitempty"`
 	ContainerOptions *BackupContainerOptions     `json:"containerOptions,omitempty"`
 	BackupSource     *PXCBackupStatus            `json:"backupSource,omitempty"`
 	PITR             *PITR                       `json:"pitr,omitempty"`
 	Resources        corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Databases are restored from the backup with all their tables
+	// into the running cluster without stopping it.
+	Databases []string `json:"databases,omitempty"`
+	// Tables are restored from the backup into the running cluster without stopping it.
+	// The tables are specified as database.table.
+	Tables []string `json:"tables,omitempty"`
+	// TargetDatabase is the database the restored tables are imported into.
+	// By default the tables are imported into their original databases.
+	TargetDatabase string `json:"targetDatabase,omitempty"`
+	// NewCluster is the cluster created for the restore. The backup of pxcCluster
+	// is restored into the new cluster and pxcCluster isn't changed.
+	NewCluster *RestoreNewClusterSpec `json:"newCluster,omitempty"`
+}
+
+type RestoreNewClusterSpec struct {
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Template is the spec of the new cluster. By default the spec of pxcCluster
+	// is cloned without scheduled backups, point-in-time recovery, replication
+	// channels and the user and TLS secrets. The users secret of pxcCluster
+	// is copied into <name>-secrets, the restored data has the same users.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	Template *runtime.RawExtension `json:"template,omitempty"`
+	// Overrides is a JSON merge patch applied to the spec of the new cluster.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	Overrides *runtime.RawExtension `json:"overrides,omitempty"`
 }
 
 // PerconaXtraDBClusterRestoreStatus defines the observed state of PerconaXtraDBClusterRestore
@@ -27,13 +67,89 @@ type PerconaXtraDBClusterRestoreStatus struct {
 	HAProxySize   int32        `json:"haproxySize,omitempty"`
 	ProxySQLSize  int32        `json:"proxysqlSize,omitempty"`
 	Unsafe        UnsafeFlags  `json:"unsafeFlags,omitempty"`
+	// ImportedTables are the tables imported into the cluster by the partial restore.
+	ImportedTables []string `json:"importedTables,omitempty"`
+	// ImportingTable is the table which is being imported by the partial restore.
+	// It's dropped and imported again if the import is interrupted.
+	ImportingTable string `json:"importingTable,omitempty"`
+	// Progress is the progress of the restore reported by the restore jobs.
+	Progress *RestoreProgress `json:"progress,omitempty"`
+	// FailedState is the state the restore failed in. The restore is resumed
+	// from the state if it's annotated with percona.com/resume-restore.
+	FailedState RestoreState `json:"failedState,omitempty"`
+}
+
+type RestorePhase string
+
+const (
+	RestorePhaseDownload RestorePhase = "download"
+	RestorePhasePrepare  RestorePhase = "prepare"
+	RestorePhaseCopyBack RestorePhase = "copy-back"
+	RestorePhaseSST      RestorePhase = "sst"
+	RestorePhasePITR     RestorePhase = "pitr"
+)
+
+type RestoreProgress struct {
+	Phase RestorePhase `json:"phase,omitempty"`
+	// PhaseStarted is the time the current phase was started.
+	PhaseStarted    *metav1.Time   `json:"phaseStarted,omitempty"`
+	CompletedPhases []RestorePhase `json:"completedPhases,omitempty"`
+	// BytesDownloaded is the size of the backup downloaded by the restore job.
+	BytesDownloaded int64 `json:"bytesDownloaded,omitempty"`
+	// BytesTotal is the size of the backup. It's unknown for incremental backups.
+	BytesTotal     int64 `json:"bytesTotal,omitempty"`
+	BinlogsApplied int   `json:"binlogsApplied,omitempty"`
+	BinlogsTotal   int   `json:"binlogsTotal,omitempty"`
+	// CurrentGTID is the GTID set of the binlog applied by the point-in-time recovery.
+	CurrentGTID string `json:"currentGTID,omitempty"`
+	// ETA is the estimated time of the end of the current phase.
+	ETA         *metav1.Time `json:"eta,omitempty"`
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// IsPhaseCompleted checks if the phase of the restore is completed.
+func (p *RestoreProgress) IsPhaseCompleted(phase RestorePhase) bool {
+	if p == nil {
+		return false
+	}
+	for _, c := range p.CompletedPhases {
+		if c == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// CompletePhase marks the phase as completed.
+func (p *RestoreProgress) CompletePhase(phase RestorePhase) {
+	if p.Phase == phase {
+		p.Phase = ""
+		p.PhaseStarted = nil
+		p.ETA = nil
+	}
+	if !p.IsPhaseCompleted(phase) {
+		p.CompletedPhases = append(p.CompletedPhases, phase)
+	}
 }
 
 type PITR struct {
 	BackupSource *PXCBackupStatus `json:"backupSource"`
-	Type         string           `json:"type"`
-	Date         string           `json:"date"`
-	GTID         string           `json:"gtid"`
+	// Type of the recovery:
+	// latest - apply all available binlogs,
+	// date - stop before the first transaction after the date,
+	// transaction - stop before the transaction with the gtid,
+	// skip - skip the transactions in the gtid set and apply all the others,
+	// position - stop before the event at binlogPosition of binlogFile.
+	Type string `json:"type"`
+	Date string `json:"date"`
+	GTID string `json:"gtid"`
+	// BinlogFile is the binlog of the position recovery. It's either the name
+	// of the binlog in the storage or the name of the binlog on the server, e.g. binlog.000011.
+	BinlogFile     string `json:"binlogFile,omitempty"`
+	BinlogPosition int64  `json:"binlogPosition,omitempty"`
+	// DryRun lists the transactions which would be applied by the recovery
+	// in the logs of the PITR job without restoring the backup.
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -73,18 +189,46 @@ const (
 	RestoreStartCluster   RestoreState = "Starting Cluster"
 	RestorePITR           RestoreState = "Point-in-time recovering"
 	RestorePrepareCluster RestoreState = "Preparing Cluster"
+	RestoreExportTables   RestoreState = "Exporting Tables"
+	RestoreImportTables   RestoreState = "Importing Tables"
+	RestoreLoadLogical    RestoreState = "Loading Logical Backup"
 	RestoreFailed         RestoreState = "Failed"
 	RestoreSucceeded      RestoreState = "Succeeded"
 )
 
 const AnnotationUnsafePITR = "percona.com/unsafe-pitr"
 
+// AnnotationResumeRestore resumes the failed restore from the state it failed in.
+const AnnotationResumeRestore = "percona.com/resume-restore"
+
+// IsResumable checks if the restore can be resumed from the state. The restore jobs
+// continue from the last completed phase of the job.
+func (s RestoreState) IsResumable() bool {
+	switch s {
+	case RestoreStopCluster, RestoreRestore, RestorePITR, RestorePrepareCluster, RestoreStartCluster:
+		return true
+	}
+	return false
+}
+
+const PITRTypePosition = "position"
+
+// IsDryRun returns true if the restore only lists the transactions of the point-in-time recovery.
+func (cr *PerconaXtraDBClusterRestore) IsDryRun() bool {
+	return cr.Spec.PITR != nil && cr.Spec.PITR.DryRun
+}
+
+// IsCrossNamespace returns true if the backup is in another namespace than the restore.
+func (cr *PerconaXtraDBClusterRestore) IsCrossNamespace() bool {
+	return cr.Spec.BackupNamespace != "" && cr.Spec.BackupNamespace != cr.Namespace
+}
+
 func (cr *PerconaXtraDBClusterRestore) CheckNsetDefaults() error {
 	if cr.Spec.PXCCluster == "" {
 		return errors.New("pxcCluster can't be empty")
 	}
-	if cr.Spec.PITR != nil && cr.Spec.PITR.BackupSource != nil && cr.Spec.PITR.BackupSource.StorageName == "" && cr.Spec.PITR.BackupSource.S3 == nil && cr.Spec.PITR.BackupSource.Azure == nil {
-		return errors.New("PITR.BackupSource.StorageName, PITR.BackupSource.S3 and PITR.BackupSource.Azure can't be empty simultaneously")
+	if cr.Spec.PITR != nil && cr.Spec.PITR.BackupSource != nil && cr.Spec.PITR.BackupSource.StorageName == "" && cr.Spec.PITR.BackupSource.S3 == nil && cr.Spec.PITR.BackupSource.Azure == nil && cr.Spec.PITR.BackupSource.GCS == nil {
+		return errors.New("PITR.BackupSource.StorageName, PITR.BackupSource.S3, PITR.BackupSource.Azure and PITR.BackupSource.GCS can't be empty simultaneously")
 	}
 	if cr.Spec.BackupName == "" && cr.Spec.BackupSource == nil {
 		return errors.New("backupName and BackupSource can't be empty simultaneously")
@@ -92,6 +236,86 @@ func (cr *PerconaXtraDBClusterRestore) CheckNsetDefaults() error {
 	if len(cr.Spec.BackupName) > 0 && cr.Spec.BackupSource != nil {
 		return errors.New("backupName and BackupSource can't be specified simultaneously")
 	}
+	if cr.Spec.BackupNamespace != "" && cr.Spec.BackupName == "" {
+		return errors.New("backupNamespace can be specified only with backupName")
+	}
+	if err := cr.checkPartial(); err != nil {
+		return err
+	}
+	if cr.Spec.PITR != nil && cr.Spec.PITR.Type == PITRTypePosition {
+		if cr.Spec.PITR.BinlogFile == "" || cr.Spec.PITR.BinlogPosition <= 0 {
+			return errors.New("binlogFile and binlogPosition are required for the position recovery")
+		}
+	}
+	if nc := cr.Spec.NewCluster; nc != nil {
+		if nc.Name == "" {
+			return errors.New("newCluster.name can't be empty")
+		}
+		if nc.Name == cr.Spec.PXCCluster {
+			return errors.New("newCluster.name should differ from pxcCluster")
+		}
+		if cr.IsPartial() {
+			return errors.New("databases and tables can't be restored into a new cluster")
+		}
+		if cr.IsDryRun() {
+			return errors.New("dry run of point-in-time recovery can't be used with newCluster")
+		}
+	}
+
+	return nil
+}
+
+// TargetCluster returns the name of the cluster the backup is restored into.
+func (cr *PerconaXtraDBClusterRestore) TargetCluster() string {
+	if cr.Spec.NewCluster != nil && cr.Spec.NewCluster.Name != "" {
+		return cr.Spec.NewCluster.Name
+	}
+	return cr.Spec.PXCCluster
+}
+
+// IsPartial returns true if only the selected databases and tables are restored.
+func (cr *PerconaXtraDBClusterRestore) IsPartial() bool {
+	return len(cr.Spec.Databases) > 0 || len(cr.Spec.Tables) > 0
+}
+
+// identifierRegexp matches the names which don't need to be quoted
+// and are stored in the file system without encoding.
+var identifierRegexp = regexp.MustCompile(`^[0-9a-zA-Z$_]+$`)
+
+func (cr *PerconaXtraDBClusterRestore) checkPartial() error {
+	if !cr.IsPartial() {
+		if cr.Spec.TargetDatabase != "" {
+			return errors.New("targetDatabase can be specified only with databases or tables")
+		}
+		return nil
+	}
+	if cr.Spec.PITR != nil {
+		return errors.New("point-in-time recovery is not supported for the restore of databases and tables")
+	}
+
+	databases := make(map[string]struct{})
+	for _, db := range cr.Spec.Databases {
+		if !identifierRegexp.MatchString(db) {
+			return fmt.Errorf("invalid database name %q", db)
+		}
+		databases[db] = struct{}{}
+	}
+	for _, t := range cr.Spec.Tables {
+		db, table, ok := strings.Cut(t, ".")
+		if !ok || !identifierRegexp.MatchString(db) || !identifierRegexp.MatchString(table) {
+			return fmt.Errorf("invalid table %q, expected database.table", t)
+		}
+		databases[db] = struct{}{}
+	}
+
+	if cr.Spec.TargetDatabase != "" {
+		if !identifierRegexp.MatchString(cr.Spec.TargetDatabase) {
+			return fmt.Errorf("invalid target database name %q", cr.Spec.TargetDatabase)
+		}
+		if len(databases) > 1 {
+			return errors.New("targetDatabase can be specified only if tables of a single database are restored")
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,195 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// PerconaXtraDBClusterRestoreDrillSpec defines the desired state of PerconaXtraDBClusterRestoreDrill
+type PerconaXtraDBClusterRestoreDrillSpec struct {
+	// PXCCluster is the cluster the backups of which are restored by the drills.
+	PXCCluster string `json:"pxcCluster"`
+	// Schedule is the cron schedule of the drills.
+	Schedule string `json:"schedule"`
+	// Suspend stops scheduling new drills. The running drill is finished.
+	Suspend bool `json:"suspend,omitempty"`
+	// StorageName limits the drills to the backups in the storage.
+	// By default the latest succeeded backup of pxcCluster is restored.
+	StorageName string `json:"storageName,omitempty"`
+	// PITR recovers the restored backup to a random point in time
+	// from the binlogs uploaded after the backup.
+	PITR *RestoreDrillPITR `json:"pitr,omitempty"`
+	// Overrides is a JSON merge patch applied to the spec of the drill cluster.
+	// The drill cluster is cloned from pxcCluster the same way as newCluster of the restore.
+	// The overrides can't set secretsName, the users secret of the drill cluster is deleted with the cluster.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	Overrides *runtime.RawExtension `json:"overrides,omitempty"`
+	// Assertions are the SQL queries checked on the restored cluster.
+	Assertions []RestoreDrillAssertion `json:"assertions,omitempty"`
+	// Timeout is the maximum duration of the drill. The drill is failed
+	// and the drill cluster is deleted after the timeout. Defaults to 6h.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// HistoryLimit is the number of the finished drills kept in the status. Defaults to 12.
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+}
+
+type RestoreDrillPITR struct {
+	Enabled bool `json:"enabled"`
+	// Window limits the random point in time to the recent binlogs.
+	// By default any point after the backup can be chosen.
+	Window *metav1.Duration `json:"window,omitempty"`
+}
+
+type RestoreDrillAssertion struct {
+	Name string `json:"name"`
+	// Query is the SQL query executed on the restored cluster by the root user.
+	// The first column of the first row is compared with expected.
+	Query    string `json:"query"`
+	Expected string `json:"expected"`
+}
+
+// PerconaXtraDBClusterRestoreDrillStatus defines the observed state of PerconaXtraDBClusterRestoreDrill
+type PerconaXtraDBClusterRestoreDrillStatus struct {
+	LastScheduleTime   *metav1.Time `json:"lastScheduleTime,omitempty"`
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// Current is the drill in progress.
+	Current *RestoreDrillRun `json:"current,omitempty"`
+	// History are the finished drills, the newest first.
+	History []RestoreDrillRun `json:"history,omitempty"`
+}
+
+type RestoreDrillState string
+
+const (
+	RestoreDrillRunning   RestoreDrillState = "Running"
+	RestoreDrillSucceeded RestoreDrillState = "Succeeded"
+	RestoreDrillFailed    RestoreDrillState = "Failed"
+)
+
+type RestoreDrillRun struct {
+	// Restore is the restore created by the drill.
+	Restore string `json:"restore,omitempty"`
+	// Cluster is the ephemeral cluster the backup is restored into.
+	Cluster string `json:"cluster,omitempty"`
+	Backup  string `json:"backup,omitempty"`
+	// PITRDate is the point in time the backup is recovered to.
+	PITRDate    string            `json:"pitrDate,omitempty"`
+	State       RestoreDrillState `json:"state"`
+	StartedAt   metav1.Time       `json:"startedAt"`
+	CompletedAt *metav1.Time      `json:"completedAt,omitempty"`
+	// RTO is the time from the start of the drill until the restored cluster is ready.
+	RTO        *metav1.Duration              `json:"rto,omitempty"`
+	Assertions []RestoreDrillAssertionResult `json:"assertions,omitempty"`
+	Message    string                        `json:"message,omitempty"`
+}
+
+type RestoreDrillAssertionResult struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Actual string `json:"actual,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PerconaXtraDBClusterRestoreDrill is the Schema for the perconaxtradbclusterrestoredrills API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName="pxc-drill";"pxc-drills"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.pxcCluster",description="Cluster name"
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule",description="Cron schedule"
+// +kubebuilder:printcolumn:name="Last Schedule",type="date",JSONPath=".status.lastScheduleTime",description="Last schedule time"
+// +kubebuilder:printcolumn:name="Last Successful",type="date",JSONPath=".status.lastSuccessfulTime",description="Last successful drill time"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type PerconaXtraDBClusterRestoreDrill struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PerconaXtraDBClusterRestoreDrillSpec   `json:"spec,omitempty"`
+	Status PerconaXtraDBClusterRestoreDrillStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PerconaXtraDBClusterRestoreDrillList contains a list of PerconaXtraDBClusterRestoreDrill
+type PerconaXtraDBClusterRestoreDrillList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PerconaXtraDBClusterRestoreDrill `json:"items"`
+}
+
+const (
+	defaultRestoreDrillTimeout      = 6 * time.Hour
+	defaultRestoreDrillHistoryLimit = 12
+)
+
+func (cr *PerconaXtraDBClusterRestoreDrill) CheckNSetDefaults() error {
+	if cr.Spec.PXCCluster == "" {
+		return errors.New("pxcCluster can't be empty")
+	}
+	if _, err := cron.ParseStandard(cr.Spec.Schedule); err != nil {
+		return fmt.Errorf("invalid schedule %q: %v", cr.Spec.Schedule, err)
+	}
+	names := make(map[string]struct{}, len(cr.Spec.Assertions))
+	for _, a := range cr.Spec.Assertions {
+		if a.Name == "" || a.Query == "" {
+			return errors.New("name and query of the assertions can't be empty")
+		}
+		if _, ok := names[a.Name]; ok {
+			return fmt.Errorf("duplicate assertion %q", a.Name)
+		}
+		names[a.Name] = struct{}{}
+	}
+
+	if cr.Spec.Timeout == nil {
+		cr.Spec.Timeout = &metav1.Duration{Duration: defaultRestoreDrillTimeout}
+	}
+	if cr.Spec.HistoryLimit == nil {
+		limit := int32(defaultRestoreDrillHistoryLimit)
+		cr.Spec.HistoryLimit = &limit
+	}
+
+	return nil
+}
+
+// NextScheduleTime returns the time of the next drill after the last scheduled one.
+func (cr *PerconaXtraDBClusterRestoreDrill) NextScheduleTime() (time.Time, error) {
+	sched, err := cron.ParseStandard(cr.Spec.Schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	last := cr.CreationTimestamp.Time
+	if cr.Status.LastScheduleTime != nil {
+		last = cr.Status.LastScheduleTime.Time
+	}
+	return sched.Next(last), nil
+}
+
+// FinishRun moves the current drill to the history
+// and removes the drills exceeding the history limit.
+func (cr *PerconaXtraDBClusterRestoreDrill) FinishRun() {
+	run := cr.Status.Current
+	if run == nil {
+		return
+	}
+	cr.Status.Current = nil
+	if run.State == RestoreDrillSucceeded {
+		cr.Status.LastSuccessfulTime = run.CompletedAt
+	}
+
+	cr.Status.History = append([]RestoreDrillRun{*run}, cr.Status.History...)
+	limit := defaultRestoreDrillHistoryLimit
+	if cr.Spec.HistoryLimit != nil {
+		limit = int(*cr.Spec.HistoryLimit)
+	}
+	if len(cr.Status.History) > limit {
+		cr.Status.History = cr.Status.History[:limit]
+	}
+}
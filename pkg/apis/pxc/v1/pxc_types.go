@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	cmapi "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
@@ -132,9 +133,20 @@ type PXCSpec struct {
 	// +kubebuilder:validation:Enum={jemalloc,tcmalloc}
 	MySQLAllocator string `json:"mysqlAllocator,omitempty"`
 
+	// ConnectionDraining drains the client connections of the pods restarted by the SmartUpdate strategy.
+	ConnectionDraining *ConnectionDrainingSpec `json:"connectionDraining,omitempty"`
+
 	*PodSpec `json:",inline"`
 }
 
+type ConnectionDrainingSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// TimeoutSeconds is the time the client connections have to finish before the pod is restarted.
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=0
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
 // ServiceExpose defines the configuration options for exposing a k8s Service.
 // +kubebuilder:validation:XValidation:rule="!(has(self.loadBalancerClass)) || self.type == 'LoadBalancer'",message="'loadBalancerClass' can only be set when service type is 'LoadBalancer'"
 type ServiceExpose struct {
@@ -237,6 +249,12 @@ type BackupSpec struct {
 	// Once this threshold is reached, the backup will be marked as failed. Default is 300 seconds (20m).
 	// +kubebuilder:default:=1200
 	RunningDeadlineSeconds *int64 `json:"runningDeadlineSeconds,omitempty"`
+	// AllowedRestoreNamespaces is the list of namespaces where the backups of the cluster
+	// can be restored from. Use "*" to allow all namespaces.
+	AllowedRestoreNamespaces []string `json:"allowedRestoreNamespaces,omitempty"`
+	// LogicalImage is the image of the logical backup and restore jobs.
+	// The image must have mydumper and myloader in addition to the tools of the backup image.
+	LogicalImage string `json:"logicalImage,omitempty"`
 }
 
 func (b *BackupSpec) GetAllowParallel() bool {
@@ -246,12 +264,87 @@ func (b *BackupSpec) GetAllowParallel() bool {
 	return *b.AllowParallel
 }
 
+// IsRestoreAllowedTo returns true if the backups of the cluster can be restored in the namespace.
+func (b *BackupSpec) IsRestoreAllowedTo(namespace string) bool {
+	if b == nil {
+		return false
+	}
+	for _, ns := range b.AllowedRestoreNamespaces {
+		if ns == "*" || ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
 type PITRSpec struct {
 	Enabled            bool                        `json:"enabled"`
 	StorageName        string                      `json:"storageName"`
 	Resources          corev1.ResourceRequirements `json:"resources,omitempty"`
 	TimeBetweenUploads float64                     `json:"timeBetweenUploads,omitempty"`
 	TimeoutSeconds     float64                     `json:"timeoutSeconds,omitempty"`
+	// Pruning configures the deletion of binlogs which aren't needed to restore the existing backups.
+	Pruning *PITRPruningSpec `json:"pruning,omitempty"`
+	// ParallelUploads is the number of binlogs uploaded concurrently by the collector.
+	// +kubebuilder:validation:Minimum=1
+	ParallelUploads int32 `json:"parallelUploads,omitempty"`
+	// Streaming configures the streaming of binlog events from the cluster.
+	Streaming *PITRStreamingSpec `json:"streaming,omitempty"`
+	// RPOThreshold is the maximum age of the newest uploaded binlog event while the cluster
+	// has newer transactions. The PITRDegraded condition is set if the threshold is exceeded.
+	RPOThreshold *metav1.Duration `json:"rpoThreshold,omitempty"`
+}
+
+// PITRStreamingSpec configures the binlog collector to stream the binlog events
+// from the cluster with the binlog replication protocol instead of collecting
+// the binlogs every timeBetweenUploads. The streamed events are uploaded every flush interval.
+type PITRStreamingSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// FlushIntervalSeconds is the interval of the upload of the streamed events.
+	// +kubebuilder:default=10
+	FlushIntervalSeconds int32 `json:"flushIntervalSeconds,omitempty"`
+}
+
+func (s *PITRStreamingSpec) IsEnabled() bool {
+	return s != nil && s.Enabled
+}
+
+func (s *PITRStreamingSpec) GetFlushInterval() time.Duration {
+	if s == nil || s.FlushIntervalSeconds <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(s.FlushIntervalSeconds) * time.Second
+}
+
+// PITRPruningSpec configures the pruning of binlogs in the PITR storage.
+// Binlogs written before the oldest succeeded backup of the cluster minus
+// the safety margin are deleted. Nothing is deleted if the cluster has no backups.
+type PITRPruningSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// SafetyMargin is the period of binlogs retained before the oldest backup.
+	// +kubebuilder:default="24h"
+	SafetyMargin *metav1.Duration `json:"safetyMargin,omitempty"`
+	// IntervalSeconds is the minimal interval between pruning runs.
+	// +kubebuilder:default=3600
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+}
+
+func (p *PITRPruningSpec) IsEnabled() bool {
+	return p != nil && p.Enabled
+}
+
+func (p *PITRPruningSpec) GetSafetyMargin() time.Duration {
+	if p == nil || p.SafetyMargin == nil {
+		return 24 * time.Hour
+	}
+	return p.SafetyMargin.Duration
+}
+
+func (p *PITRPruningSpec) GetInterval() time.Duration {
+	if p == nil || p.IntervalSeconds <= 0 {
+		return time.Hour
+	}
+	return time.Duration(p.IntervalSeconds) * time.Second
 }
 
 type PXCScheduledBackupSchedule struct {
@@ -265,45 +358,131 @@ type PXCScheduledBackupSchedule struct {
 	Retention *PXCScheduledBackupRetention `json:"retention,omitempty"`
 	// +kubebuilder:validation:Required
 	StorageName string `json:"storageName,omitempty"`
+	// +kubebuilder:validation:Enum={full,incremental,logical}
+	Type         PXCBackupType            `json:"type,omitempty"`
+	Logical      *PXCLogicalBackupOptions `json:"logical,omitempty"`
+	Source       *PXCBackupSource         `json:"source,omitempty"`
+	Verification *PXCBackupVerification   `json:"verification,omitempty"`
+	// Copies configures copies of the scheduled backups in other storages.
+	Copies []PXCScheduledBackupCopy `json:"copies,omitempty"`
+}
+
+// PXCScheduledBackupCopy configures copies of the scheduled backups in the storage.
+// The retention of the copies is applied independently of the retention of the backups.
+type PXCScheduledBackupCopy struct {
+	// +kubebuilder:validation:Required
+	StorageName string `json:"storageName"`
+	// +optional
+	Retention *PXCScheduledBackupRetention `json:"retention,omitempty"`
+}
+
+func (c PXCScheduledBackupCopy) GetRetention() PXCScheduledBackupRetention {
+	if c.Retention != nil {
+		return *c.Retention
+	}
+	return PXCScheduledBackupRetention{}
 }
 
 type PXCScheduledBackupRetentionType string
 
 const (
-	pxcScheduledBackupRetentionCount PXCScheduledBackupRetentionType = "count"
+	PXCScheduledBackupRetentionCount PXCScheduledBackupRetentionType = "count"
+	PXCScheduledBackupRetentionGFS   PXCScheduledBackupRetentionType = "gfs"
+	PXCScheduledBackupRetentionAge   PXCScheduledBackupRetentionType = "age"
 )
 
 // PXCScheduledBackupRetention defines how backups are retained.
+// The base backups of retained incremental backups are always retained.
 type PXCScheduledBackupRetention struct {
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=count
+	// +kubebuilder:validation:Enum={count,gfs,age}
 	Type PXCScheduledBackupRetentionType `json:"type,omitempty"`
 
 	// +kubebuilder:validation:Minimum=0
 	Count int `json:"count,omitempty"`
 
+	// GFS configures how many daily, weekly and monthly backups are retained with the gfs retention type.
+	// +optional
+	GFS *PXCScheduledBackupGFSRetention `json:"gfs,omitempty"`
+
+	// MaxAge is the age after which backups are deleted with the age retention type.
+	// The latest backup is always retained.
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+
+	// MaxTotalSize limits the total size of the backups in the storage. The budget is shared
+	// by the backups of all schedules, copies and manual backups of the cluster in the storage,
+	// but only the oldest backups of the schedule exceeding the limit are deleted.
+	// The latest backup is always retained. Backups of unknown size are not taken into account.
+	// +optional
+	MaxTotalSize *resource.Quantity `json:"maxTotalSize,omitempty"`
+
+	// DryRun disables the deletion of backups. Backups which would be deleted
+	// are listed in the status of the cluster.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
 	// When set to true (the default), backups will be deleted from storage.
 	// +kubebuilder:validation:Required
 	// +kubebuilder:default=true
 	DeleteFromStorage bool `json:"deleteFromStorage"`
 }
 
+// PXCScheduledBackupGFSRetention defines the grandfather-father-son retention.
+// The latest backup of each of the last N days, weeks and months is retained.
+type PXCScheduledBackupGFSRetention struct {
+	// +kubebuilder:validation:Minimum=0
+	Daily int `json:"daily,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	Weekly int `json:"weekly,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	Monthly int `json:"monthly,omitempty"`
+}
+
 // GetRetention resolves the retention configuration of the PXCScheduledBackupSchedule spec.
 func (s PXCScheduledBackupSchedule) GetRetention() PXCScheduledBackupRetention {
 	if s.Retention != nil {
 		return *s.Retention
 	}
 	return PXCScheduledBackupRetention{
-		Type:  pxcScheduledBackupRetentionCount,
+		Type:  PXCScheduledBackupRetentionCount,
 		Count: s.Keep,
 		// with the legacy configuration, we always deleted old backups through the finalizers
 		DeleteFromStorage: true,
 	}
 }
 
-// IsValidCountRetention checks if the retention is of type count and the count has a non-zero value.
-func (s PXCScheduledBackupRetention) IsValidCountRetention() bool {
-	return s.Type == pxcScheduledBackupRetentionCount && s.Count > 0
+func (s PXCScheduledBackupRetention) validate() error {
+	switch s.Type {
+	case PXCScheduledBackupRetentionGFS:
+		if s.GFS == nil {
+			return errors.New("gfs retention: gfs should be specified")
+		}
+	case PXCScheduledBackupRetentionAge:
+		if s.MaxAge == nil {
+			return errors.New("age retention: maxAge should be specified")
+		}
+	}
+	return nil
+}
+
+// IsEnabled checks if the retention deletes any backups.
+func (s PXCScheduledBackupRetention) IsEnabled() bool {
+	switch s.Type {
+	case PXCScheduledBackupRetentionCount:
+		if s.Count > 0 {
+			return true
+		}
+	case PXCScheduledBackupRetentionGFS:
+		if s.GFS != nil {
+			return true
+		}
+	case PXCScheduledBackupRetentionAge:
+		if s.MaxAge != nil && s.MaxAge.Duration > 0 {
+			return true
+		}
+	}
+	return s.MaxTotalSize != nil && !s.MaxTotalSize.IsZero()
 }
 
 type AppState string
@@ -332,6 +511,107 @@ type PerconaXtraDBClusterStatus struct {
 	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
 	Size               int32              `json:"size"`
 	Ready              int32              `json:"ready"`
+	// BackupRetention lists the backups which would be deleted by the retention of the schedules in dry-run mode.
+	BackupRetention []BackupRetentionStatus `json:"backupRetention,omitempty"`
+	PITR            *PITRStatus             `json:"pitr,omitempty"`
+	// HAProxyPrimary is the PXC pod elected as the writer node for HAProxy
+	// if the primary is selected by the operator.
+	HAProxyPrimary string `json:"haproxyPrimary,omitempty"`
+	// Switchover is the state of the last writer switchover requested by the percona.com/switchover-to annotation.
+	Switchover *SwitchoverStatus `json:"switchover,omitempty"`
+	// ProxySQLConfig is the state of the query rules and users kept in ProxySQL by the operator.
+	ProxySQLConfig *ProxySQLConfigStatus `json:"proxysqlConfig,omitempty"`
+}
+
+// ProxySQLConfigStatus describes the query rules and users the operator keeps in ProxySQL.
+type ProxySQLConfigStatus struct {
+	// QueryRules are the IDs of the query rules owned by the operator.
+	QueryRules []int `json:"queryRules,omitempty"`
+	// Users are the users with the options set by the operator.
+	Users []string `json:"users,omitempty"`
+	// Synced is true if every ProxySQL instance has the configuration of the cluster spec.
+	Synced bool `json:"synced"`
+	// Hash is the hash of the query rules and users of the spec synced to every instance.
+	Hash string `json:"hash,omitempty"`
+	// Drifted are the ProxySQL pods whose configuration was changed outside of the operator
+	// and was reverted during the last sync.
+	Drifted []string `json:"drifted,omitempty"`
+	// LastSyncTime is the time the configuration was last written to the instances.
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	Message      string       `json:"message,omitempty"`
+}
+
+// SwitchoverStatus describes the planned move of the writer node to the target pod.
+type SwitchoverStatus struct {
+	Target      string          `json:"target,omitempty"`
+	Previous    string          `json:"previous,omitempty"`
+	State       SwitchoverState `json:"state,omitempty"`
+	Message     string          `json:"message,omitempty"`
+	StartedAt   *metav1.Time    `json:"startedAt,omitempty"`
+	CompletedAt *metav1.Time    `json:"completedAt,omitempty"`
+}
+
+type SwitchoverState string
+
+const (
+	// SwitchoverPending means the operator waits for the target to catch up with the cluster.
+	SwitchoverPending SwitchoverState = "Pending"
+	// SwitchoverSwitching means the writer is flipped and the operator waits for the proxies to route to the target
+	// and for the sessions of the previous writer in HAProxy to finish.
+	SwitchoverSwitching SwitchoverState = "Switching"
+	SwitchoverSucceeded SwitchoverState = "Succeeded"
+	SwitchoverFailed    SwitchoverState = "Failed"
+)
+
+// AnnotationSwitchoverTo requests the move of the writer node to the pxc pod set in the value.
+// The annotation is removed when the switchover is finished.
+const AnnotationSwitchoverTo = "percona.com/switchover-to"
+
+// PITRStatus describes the binlogs in the PITR storage.
+type PITRStatus struct {
+	// StorageUsage is the total size of the binlogs in the storage.
+	StorageUsage *resource.Quantity `json:"storageUsage,omitempty"`
+	Binlogs      int                `json:"binlogs,omitempty"`
+	// OldestBinlogTime is the time of the first event of the oldest binlog in the storage.
+	OldestBinlogTime *metav1.Time `json:"oldestBinlogTime,omitempty"`
+	LastPruneTime    *metav1.Time `json:"lastPruneTime,omitempty"`
+	PrunedBinlogs    int          `json:"prunedBinlogs,omitempty"`
+	// RestorableWindows are the periods of time the cluster can be restored to from the binlogs.
+	// The windows are separated by the gaps in the binlogs.
+	RestorableWindows []PITRRestorableWindow `json:"restorableWindows,omitempty"`
+	// GapDetected is the GTID set the binlog collector couldn't find in the binlogs of the cluster.
+	GapDetected string `json:"gapDetected,omitempty"`
+	// SourceHost is the PXC host the binlog collector collects the binlogs from.
+	SourceHost string `json:"sourceHost,omitempty"`
+	// LastUploadedEventTime is the time of the newest event uploaded by the binlog collector.
+	LastUploadedEventTime *metav1.Time `json:"lastUploadedEventTime,omitempty"`
+	// RPO is the age of the newest uploaded event if the source host has transactions
+	// which aren't uploaded yet. It's the data lost if the cluster is restored now.
+	RPO *metav1.Duration `json:"rpo,omitempty"`
+}
+
+type PITRRestorableWindow struct {
+	Start metav1.Time `json:"start"`
+	End   metav1.Time `json:"end"`
+}
+
+// IsRestorable checks if the cluster can be restored to the time from the binlogs.
+func (s *PITRStatus) IsRestorable(t time.Time) bool {
+	if s == nil {
+		return false
+	}
+	for _, w := range s.RestorableWindows {
+		if !t.Before(w.Start.Time) && !t.After(w.End.Time) {
+			return true
+		}
+	}
+	return false
+}
+
+type BackupRetentionStatus struct {
+	Schedule        string   `json:"schedule"`
+	StorageName     string   `json:"storageName"`
+	PendingDeletion []string `json:"pendingDeletion,omitempty"`
 }
 
 // TODO: add replication status(error,active and etc)
@@ -347,7 +627,8 @@ type ReplicationChannelStatus struct {
 type ConditionStatus string
 
 const (
-	ConditionTrue ConditionStatus = "True"
+	ConditionTrue  ConditionStatus = "True"
+	ConditionFalse ConditionStatus = "False"
 )
 
 type ClusterCondition struct {
@@ -513,6 +794,44 @@ func (cr *PerconaXtraDBCluster) Validate() error {
 					return errors.Wrap(err, "Backup: validate volume spec")
 				}
 			}
+			if strg.Type == BackupStorageGCS && strg.GCS == nil {
+				return errors.Errorf("backup storage %s: gcs should be specified", sch.StorageName)
+			}
+			if sch.Retention != nil {
+				if err := sch.Retention.validate(); err != nil {
+					return errors.Wrapf(err, "backup schedule %s", sch.Name)
+				}
+			}
+			for _, cp := range sch.Copies {
+				if cp.Retention != nil {
+					if err := cp.Retention.validate(); err != nil {
+						return errors.Wrapf(err, "backup schedule %s: copy to storage %s", sch.Name, cp.StorageName)
+					}
+				}
+				cpStrg, ok := cr.Spec.Backup.Storages[cp.StorageName]
+				if !ok {
+					return errors.Errorf("backup schedule %s: copy storage %s doesn't exist", sch.Name, cp.StorageName)
+				}
+				if cp.StorageName == sch.StorageName {
+					return errors.Errorf("backup schedule %s: copy storage %s is the storage of the schedule", sch.Name, cp.StorageName)
+				}
+				if strg.Type == BackupStorageFilesystem || cpStrg.Type == BackupStorageFilesystem {
+					return errors.Errorf("backup schedule %s: copies are not supported for filesystem storage", sch.Name)
+				}
+			}
+		}
+		for name, strg := range c.Backup.Storages {
+			if strg == nil {
+				continue
+			}
+			if strg.Encryption != nil {
+				if err := strg.Encryption.validate(strg.Type); err != nil {
+					return errors.Wrapf(err, "backup storage %s", name)
+				}
+			}
+			if strg.Catalog.IsEnabled() && strg.Type == BackupStorageFilesystem {
+				return errors.Errorf("backup storage %s: catalog is not supported for filesystem storage", name)
+			}
 		}
 	}
 
@@ -636,6 +955,57 @@ type ProxySQLSpec struct {
 	Expose ServiceExpose `json:"expose,omitempty"`
 
 	Scheduler ProxySQLSchedulerSpec `json:"scheduler"`
+
+	// QueryRules are kept by the operator in mysql_query_rules of every ProxySQL instance.
+	// The operator owns the rules with the listed IDs, other rules aren't changed.
+	// The rules route, cache and mirror the queries to the hostgroups configured by the operator.
+	// Custom hostgroups and the global query cache and mirroring variables aren't managed,
+	// the variables can be set in the ProxySQL configuration.
+	// +listType=map
+	// +listMapKey=ruleID
+	QueryRules []ProxySQLQueryRule `json:"queryRules,omitempty"`
+	// Users are the settings the operator keeps in mysql_users of every ProxySQL instance.
+	// The users are synced to ProxySQL from PXC, the operator only sets their options.
+	// +listType=map
+	// +listMapKey=username
+	Users []ProxySQLUser `json:"users,omitempty"`
+}
+
+// ProxySQLQueryRule is a row of mysql_query_rules.
+// The writer hostgroup is 11 and the reader hostgroup is 10.
+type ProxySQLQueryRule struct {
+	// +kubebuilder:validation:Minimum=1
+	RuleID int `json:"ruleID"`
+	// +kubebuilder:default=true
+	Active             *bool  `json:"active,omitempty"`
+	Username           string `json:"username,omitempty"`
+	SchemaName         string `json:"schemaName,omitempty"`
+	FlagIn             int    `json:"flagIn,omitempty"`
+	MatchDigest        string `json:"matchDigest,omitempty"`
+	MatchPattern       string `json:"matchPattern,omitempty"`
+	NegateMatchPattern bool   `json:"negateMatchPattern,omitempty"`
+	FlagOut            *int   `json:"flagOut,omitempty"`
+	// DestinationHostgroup is the hostgroup the matched queries are routed to.
+	DestinationHostgroup *int `json:"destinationHostgroup,omitempty"`
+	// CacheTTL is the time in milliseconds the results of the matched queries are cached for.
+	CacheTTL *int `json:"cacheTTL,omitempty"`
+	// MirrorHostgroup is the hostgroup the matched queries are mirrored to.
+	MirrorHostgroup *int `json:"mirrorHostgroup,omitempty"`
+	// +kubebuilder:validation:Enum={0,1,2}
+	Multiplex *int   `json:"multiplex,omitempty"`
+	Apply     bool   `json:"apply,omitempty"`
+	Comment   string `json:"comment,omitempty"`
+}
+
+// ProxySQLUser is the options of the user in mysql_users.
+type ProxySQLUser struct {
+	Username string `json:"username"`
+	// DefaultHostgroup is the hostgroup the queries which match no rule are routed to.
+	DefaultHostgroup      *int  `json:"defaultHostgroup,omitempty"`
+	TransactionPersistent *bool `json:"transactionPersistent,omitempty"`
+	FastForward           *bool `json:"fastForward,omitempty"`
+	MaxConnections        *int  `json:"maxConnections,omitempty"`
+	Active                *bool `json:"active,omitempty"`
 }
 
 type ProxySQLSchedulerSpec struct {
@@ -676,6 +1046,16 @@ type HAProxySpec struct {
 	ExposePrimary  ServiceExpose           `json:"exposePrimary,omitempty"`
 	ExposeReplicas *ReplicasServiceExpose  `json:"exposeReplicas,omitempty"`
 	HealthCheck    *HAProxyHealthCheckSpec `json:"healthCheck,omitempty"`
+	// PrimarySelection is the way the writer node is selected.
+	// With haproxy every HAProxy pod selects the writer by its own health checks.
+	// With operator the operator elects a single writer node and all HAProxy pods use it,
+	// the other nodes are put into maintenance in the writer backends of every HAProxy pod.
+	// HAProxy pods don't fail over by themselves then: a new writer is elected by the operator
+	// on the next reconcile, so the failover depends on the running operator.
+	// +kubebuilder:validation:Enum={haproxy,operator}
+	PrimarySelection HAProxyPrimarySelection `json:"primarySelection,omitempty"`
+	// ReplicasSelection is the policy of the health checks of the nodes behind the replicas service.
+	ReplicasSelection *HAProxyReplicasSelectionSpec `json:"replicasSelection,omitempty"`
 
 	// Deprecated: Use ExposeReplica.Enabled instead
 	ReplicasServiceEnabled *bool `json:"replicasServiceEnabled,omitempty"`
@@ -683,6 +1063,13 @@ type HAProxySpec struct {
 	ReplicasLoadBalancerSourceRanges []string `json:"replicasLoadBalancerSourceRanges,omitempty"`
 }
 
+type HAProxyPrimarySelection string
+
+const (
+	HAProxyPrimarySelectionHAProxy  HAProxyPrimarySelection = "haproxy"
+	HAProxyPrimarySelectionOperator HAProxyPrimarySelection = "operator"
+)
+
 type HAProxyHealthCheckSpec struct {
 	// Interval in milliseconds between health checks (default: 10000)
 	// +kubebuilder:validation:Minimum=1000
@@ -698,6 +1085,32 @@ type HAProxyHealthCheckSpec struct {
 	Rise *int32 `json:"rise,omitempty"`
 }
 
+type HAProxyReplicasSelectionSpec struct {
+	// MaxRecvQueue excludes the nodes with a longer wsrep_local_recv_queue from the replicas service.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRecvQueue *int32 `json:"maxRecvQueue,omitempty"`
+	// ExcludeDonors excludes the donor and desynced nodes from the replicas service
+	// even if the donors are allowed by OK_IF_DONOR.
+	// +optional
+	ExcludeDonors bool `json:"excludeDonors,omitempty"`
+	// AsyncReplicas are the async replicas of the cluster added to the replicas service.
+	// +optional
+	AsyncReplicas *HAProxyAsyncReplicasSpec `json:"asyncReplicas,omitempty"`
+}
+
+type HAProxyAsyncReplicasSpec struct {
+	// Hosts are the host:port addresses of the replicas, the default port is 3306.
+	// The replicas are checked with the monitor user replicated from the cluster.
+	// +kubebuilder:validation:MinItems=1
+	Hosts []string `json:"hosts"`
+	// MaxSecondsBehindSource excludes the replicas with a longer Seconds_Behind_Source from the replicas service.
+	// The replicas with stopped replication are always excluded.
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=0
+	MaxSecondsBehindSource int32 `json:"maxSecondsBehindSource,omitempty"`
+}
+
 type ReplicasServiceExpose struct {
 	ServiceExpose `json:",inline"`
 	OnlyReaders   bool `json:"onlyReaders,omitempty"`
@@ -769,6 +1182,7 @@ type BackupStorageSpec struct {
 	Type                      BackupStorageType                 `json:"type"`
 	S3                        *BackupStorageS3Spec              `json:"s3,omitempty"`
 	Azure                     *BackupStorageAzureSpec           `json:"azure,omitempty"`
+	GCS                       *BackupStorageGCSSpec             `json:"gcs,omitempty"`
 	Volume                    *VolumeSpec                       `json:"volume,omitempty"`
 	NodeSelector              map[string]string                 `json:"nodeSelector,omitempty"`
 	Resources                 corev1.ResourceRequirements       `json:"resources,omitempty"`
@@ -784,6 +1198,9 @@ type BackupStorageSpec struct {
 	RuntimeClassName          *string                           `json:"runtimeClassName,omitempty"`
 	VerifyTLS                 *bool                             `json:"verifyTLS,omitempty"`
 	ContainerOptions          *BackupContainerOptions           `json:"containerOptions,omitempty"`
+	Encryption                *BackupEncryptionSpec             `json:"encryption,omitempty"`
+	Catalog                   *BackupCatalogSpec                `json:"catalog,omitempty"`
+	Throttling                *BackupThrottlingSpec             `json:"throttling,omitempty"`
 }
 
 type BackupContainerOptions struct {
@@ -846,6 +1263,7 @@ const (
 	BackupStorageFilesystem BackupStorageType = "filesystem"
 	BackupStorageS3         BackupStorageType = "s3"
 	BackupStorageAzure      BackupStorageType = "azure"
+	BackupStorageGCS        BackupStorageType = "gcs"
 )
 
 type BackupStorageS3Spec struct {
@@ -938,6 +1356,7 @@ type BackupStorageAzureSpec struct {
 const (
 	AzureBlobStoragePrefix string = "azure://"
 	AwsBlobStoragePrefix   string = "s3://"
+	GCSBlobStoragePrefix   string = "gs://"
 	PVCStoragePrefix       string = "pvc/"
 )
 
@@ -954,6 +1373,120 @@ func (b *BackupStorageAzureSpec) ContainerAndPrefix() (string, string) {
 	return container, prefix
 }
 
+type BackupStorageGCSSpec struct {
+	Bucket string `json:"bucket"`
+	// CredentialsSecret is the name of the secret with GCS credentials.
+	// GCS_CREDENTIALS_JSON key should contain a service account key, it is
+	// used by the operator and the binlog collector. If it's not set,
+	// the credentials are taken from the metadata server (e.g. GKE workload identity).
+	// GCS_ACCESS_KEY and GCS_SECRET_KEY keys can contain HMAC keys used by xbcloud.
+	// Without them xbcloud requests go through a local proxy, which authorizes
+	// them with the same credentials as the operator. Backups in the sidecar use
+	// the service account of the PXC pods in that case.
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+	EndpointURL       string `json:"endpointUrl,omitempty"`
+	StorageClass      string `json:"storageClass,omitempty"`
+}
+
+// BucketAndPrefix returns bucket name and backup prefix from Bucket.
+// BackupStorageGCSSpec.Bucket can contain backup path in format `<bucket-name>/<backup-prefix>`.
+func (b *BackupStorageGCSSpec) BucketAndPrefix() (string, string) {
+	bucket, prefix, _ := strings.Cut(b.Bucket, "/")
+
+	if prefix != "" {
+		prefix = strings.TrimSuffix(prefix, "/")
+		prefix += "/"
+	}
+
+	return bucket, prefix
+}
+
+type BackupEncryptionAlgorithm string
+
+const (
+	BackupEncryptionAES128 BackupEncryptionAlgorithm = "AES128"
+	BackupEncryptionAES192 BackupEncryptionAlgorithm = "AES192"
+	BackupEncryptionAES256 BackupEncryptionAlgorithm = "AES256"
+)
+
+// BackupEncryptionSpec configures client-side encryption of backups and binlogs
+// uploaded to the storage. The key is taken either from KeySecret or from Vault.
+// Backups taken by the xtrabackup sidecar are encrypted by xtrabackup --encrypt,
+// the default backup job encrypts the files of the received SST stream the same way.
+type BackupEncryptionSpec struct {
+	// +kubebuilder:validation:Enum={AES128,AES192,AES256}
+	// +kubebuilder:default=AES256
+	Algorithm BackupEncryptionAlgorithm `json:"algorithm,omitempty"`
+	// KeySecret is the secret key with the encryption key.
+	// The key length should be 16, 24 or 32 bytes for AES128, AES192 and AES256 respectively.
+	KeySecret *corev1.SecretKeySelector `json:"keySecret,omitempty"`
+	// VaultPath is the path of the Vault secret with the encryption key,
+	// relative to the secret_mount_point of the cluster Vault configuration.
+	// The key is read from the "key" field of the secret.
+	VaultPath string `json:"vaultPath,omitempty"`
+}
+
+func (e *BackupEncryptionSpec) IsEnabled() bool {
+	return e != nil && (e.KeySecret != nil || e.VaultPath != "")
+}
+
+func (e *BackupEncryptionSpec) validate(storageType BackupStorageType) error {
+	if e.KeySecret != nil && e.VaultPath != "" {
+		return errors.New("encryption: only one of keySecret and vaultPath can be specified")
+	}
+	if !e.IsEnabled() {
+		return errors.New("encryption: keySecret or vaultPath should be specified")
+	}
+	if storageType == BackupStorageFilesystem {
+		return errors.New("encryption is not supported for filesystem storage")
+	}
+	return nil
+}
+
+func (e *BackupEncryptionSpec) GetAlgorithm() BackupEncryptionAlgorithm {
+	if e.Algorithm == "" {
+		return BackupEncryptionAES256
+	}
+	return e.Algorithm
+}
+
+// BackupThrottlingSpec limits the resources used by backups, restores and binlog uploads of the storage.
+type BackupThrottlingSpec struct {
+	// UploadRateLimit is the maximal upload speed of backups and binlogs in bytes per second.
+	UploadRateLimit *resource.Quantity `json:"uploadRateLimit,omitempty"`
+	// DownloadRateLimit is the maximal download speed of restored backups and binlogs in bytes per second.
+	DownloadRateLimit *resource.Quantity `json:"downloadRateLimit,omitempty"`
+	// Parallel is the number of parallel xbcloud transfers.
+	// +kubebuilder:validation:Minimum=1
+	Parallel int32 `json:"parallel,omitempty"`
+	// ThrottleIOPS is the number of read and write IO operations per second
+	// of xtrabackup (xtrabackup --throttle). It's supported only for backups taken by the xtrabackup sidecar.
+	// +kubebuilder:validation:Minimum=1
+	ThrottleIOPS int32 `json:"throttleIOPS,omitempty"`
+}
+
+// BackupCatalogSpec configures the import of the backups found in the storage.
+// The operator periodically scans the storage and creates PerconaXtraDBClusterBackup
+// objects for the complete backups which don't have them. Imported backups can be
+// used for restores, deleting them doesn't delete the data from the storage.
+type BackupCatalogSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// ScanIntervalSeconds is the minimal interval between storage scans.
+	// +kubebuilder:default=600
+	ScanIntervalSeconds int32 `json:"scanIntervalSeconds,omitempty"`
+}
+
+func (c *BackupCatalogSpec) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+func (c *BackupCatalogSpec) GetScanInterval() time.Duration {
+	if c == nil || c.ScanIntervalSeconds <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(c.ScanIntervalSeconds) * time.Second
+}
+
 type VolumeSpec struct {
 	// EmptyDir to use as data volume for mysql. EmptyDir represents a temporary
 	// directory that shares a pod's lifetime.
@@ -1891,6 +2424,12 @@ func (cr *PerconaXtraDBCluster) HAProxyReplicasServiceEnabled() bool {
 	return cr.Spec.HAProxy.ExposeReplicas.ServiceExpose.Enabled
 }
 
+// HAProxyPrimarySelectedByOperator returns true if the writer node of HAProxy is elected by the operator.
+func (cr *PerconaXtraDBCluster) HAProxyPrimarySelectedByOperator() bool {
+	return cr.HAProxyEnabled() && cr.CompareVersionWith("1.20.0") >= 0 &&
+		cr.Spec.HAProxy.PrimarySelection == HAProxyPrimarySelectionOperator
+}
+
 func (cr *PerconaXtraDBCluster) ProxySQLEnabled() bool {
 	return cr.Spec.ProxySQL != nil && cr.Spec.ProxySQL.Enabled
 }
@@ -93,13 +93,13 @@ func TestGetRetention(t *testing.T) {
 			input: PXCScheduledBackupSchedule{
 				Keep: 3,
 				Retention: &PXCScheduledBackupRetention{
-					Type:              pxcScheduledBackupRetentionCount,
+					Type:              PXCScheduledBackupRetentionCount,
 					Count:             4,
 					DeleteFromStorage: false,
 				},
 			},
 			expected: &PXCScheduledBackupRetention{
-				Type:              pxcScheduledBackupRetentionCount,
+				Type:              PXCScheduledBackupRetentionCount,
 				Count:             4,
 				DeleteFromStorage: false,
 			},
@@ -551,3 +551,137 @@ func TestValidateExtraPVCs(t *testing.T) {
 		})
 	}
 }
+
+func TestRestoreCheckPartial(t *testing.T) {
+	tests := map[string]struct {
+		spec        PerconaXtraDBClusterRestoreSpec
+		expectedErr string
+	}{
+		"full restore": {
+			spec: PerconaXtraDBClusterRestoreSpec{},
+		},
+		"databases and tables": {
+			spec: PerconaXtraDBClusterRestoreSpec{
+				Databases: []string{"app", "app_2"},
+				Tables:    []string{"shop.orders"},
+			},
+		},
+		"target database": {
+			spec: PerconaXtraDBClusterRestoreSpec{
+				Tables:         []string{"shop.orders", "shop.items"},
+				TargetDatabase: "shop_restored",
+			},
+		},
+		"target database without tables": {
+			spec: PerconaXtraDBClusterRestoreSpec{
+				TargetDatabase: "shop_restored",
+			},
+			expectedErr: "targetDatabase can be specified only with databases or tables",
+		},
+		"target database for multiple databases": {
+			spec: PerconaXtraDBClusterRestoreSpec{
+				Databases:      []string{"app"},
+				Tables:         []string{"shop.orders"},
+				TargetDatabase: "restored",
+			},
+			expectedErr: "targetDatabase can be specified only if tables of a single database are restored",
+		},
+		"invalid target database": {
+			spec: PerconaXtraDBClusterRestoreSpec{
+				Databases:      []string{"app"},
+				TargetDatabase: "app-restored",
+			},
+			expectedErr: `invalid target database name "app-restored"`,
+		},
+		"invalid database": {
+			spec: PerconaXtraDBClusterRestoreSpec{
+				Databases: []string{"app`; DROP"},
+			},
+			expectedErr: "invalid database name \"app`; DROP\"",
+		},
+		"table without database": {
+			spec: PerconaXtraDBClusterRestoreSpec{
+				Tables: []string{"orders"},
+			},
+			expectedErr: `invalid table "orders", expected database.table`,
+		},
+		"pitr": {
+			spec: PerconaXtraDBClusterRestoreSpec{
+				Databases: []string{"app"},
+				PITR:      &PITR{Type: "latest"},
+			},
+			expectedErr: "point-in-time recovery is not supported for the restore of databases and tables",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := &PerconaXtraDBClusterRestore{Spec: tt.spec}
+			cr.Spec.PXCCluster = "cluster1"
+			cr.Spec.BackupName = "backup1"
+
+			err := cr.CheckNsetDefaults()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestRestoreNewCluster(t *testing.T) {
+	newRestore := func(nc *RestoreNewClusterSpec) *PerconaXtraDBClusterRestore {
+		return &PerconaXtraDBClusterRestore{
+			Spec: PerconaXtraDBClusterRestoreSpec{
+				PXCCluster: "cluster1",
+				BackupName: "backup1",
+				NewCluster: nc,
+			},
+		}
+	}
+
+	cr := newRestore(nil)
+	assert.NoError(t, cr.CheckNsetDefaults())
+	assert.Equal(t, "cluster1", cr.TargetCluster())
+
+	cr = newRestore(&RestoreNewClusterSpec{Name: "cluster2"})
+	assert.NoError(t, cr.CheckNsetDefaults())
+	assert.Equal(t, "cluster2", cr.TargetCluster())
+
+	cr = newRestore(&RestoreNewClusterSpec{})
+	assert.EqualError(t, cr.CheckNsetDefaults(), "newCluster.name can't be empty")
+
+	cr = newRestore(&RestoreNewClusterSpec{Name: "cluster1"})
+	assert.EqualError(t, cr.CheckNsetDefaults(), "newCluster.name should differ from pxcCluster")
+
+	cr = newRestore(&RestoreNewClusterSpec{Name: "cluster2"})
+	cr.Spec.Tables = []string{"db.t1"}
+	assert.EqualError(t, cr.CheckNsetDefaults(), "databases and tables can't be restored into a new cluster")
+}
+
+func TestRestorePITRPosition(t *testing.T) {
+	cr := &PerconaXtraDBClusterRestore{
+		Spec: PerconaXtraDBClusterRestoreSpec{
+			PXCCluster: "cluster1",
+			BackupName: "backup1",
+			PITR: &PITR{
+				Type: PITRTypePosition,
+			},
+		},
+	}
+	assert.EqualError(t, cr.CheckNsetDefaults(), "binlogFile and binlogPosition are required for the position recovery")
+
+	cr.Spec.PITR.BinlogFile = "binlog.000011"
+	assert.EqualError(t, cr.CheckNsetDefaults(), "binlogFile and binlogPosition are required for the position recovery")
+
+	cr.Spec.PITR.BinlogPosition = 441
+	assert.NoError(t, cr.CheckNsetDefaults())
+	assert.False(t, cr.IsDryRun())
+
+	cr.Spec.PITR.DryRun = true
+	assert.True(t, cr.IsDryRun())
+
+	cr.Spec.NewCluster = &RestoreNewClusterSpec{Name: "cluster2"}
+	assert.EqualError(t, cr.CheckNsetDefaults(), "dry run of point-in-time recovery can't be used with newCluster")
+}
@@ -26,5 +26,7 @@ func init() {
 		&PerconaXtraDBClusterBackupList{},
 		&PerconaXtraDBClusterRestore{},
 		&PerconaXtraDBClusterRestoreList{},
+		&PerconaXtraDBClusterRestoreDrill{},
+		&PerconaXtraDBClusterRestoreDrillList{},
 	)
 }
@@ -28,6 +28,21 @@ func (in *AppStatus) DeepCopy() *AppStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupCatalogSpec) DeepCopyInto(out *BackupCatalogSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupCatalogSpec.
+func (in *BackupCatalogSpec) DeepCopy() *BackupCatalogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupCatalogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupContainerArgs) DeepCopyInto(out *BackupContainerArgs) {
 	*out = *in
@@ -81,6 +96,61 @@ func (in *BackupContainerOptions) DeepCopy() *BackupContainerOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupCopySpec) DeepCopyInto(out *BackupCopySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupCopySpec.
+func (in *BackupCopySpec) DeepCopy() *BackupCopySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupCopySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupEncryptionSpec) DeepCopyInto(out *BackupEncryptionSpec) {
+	*out = *in
+	if in.KeySecret != nil {
+		in, out := &in.KeySecret, &out.KeySecret
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupEncryptionSpec.
+func (in *BackupEncryptionSpec) DeepCopy() *BackupEncryptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupEncryptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupRetentionStatus) DeepCopyInto(out *BackupRetentionStatus) {
+	*out = *in
+	if in.PendingDeletion != nil {
+		in, out := &in.PendingDeletion, &out.PendingDeletion
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRetentionStatus.
+func (in *BackupRetentionStatus) DeepCopy() *BackupRetentionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupRetentionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupSpec) DeepCopyInto(out *BackupSpec) {
 	*out = *in
@@ -155,6 +225,11 @@ func (in *BackupSpec) DeepCopyInto(out *BackupSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.AllowedRestoreNamespaces != nil {
+		in, out := &in.AllowedRestoreNamespaces, &out.AllowedRestoreNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupSpec.
@@ -182,6 +257,21 @@ func (in *BackupStorageAzureSpec) DeepCopy() *BackupStorageAzureSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupStorageGCSSpec) DeepCopyInto(out *BackupStorageGCSSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStorageGCSSpec.
+func (in *BackupStorageGCSSpec) DeepCopy() *BackupStorageGCSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupStorageGCSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupStorageS3Spec) DeepCopyInto(out *BackupStorageS3Spec) {
 	*out = *in
@@ -215,6 +305,11 @@ func (in *BackupStorageSpec) DeepCopyInto(out *BackupStorageSpec) {
 		*out = new(BackupStorageAzureSpec)
 		**out = **in
 	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(BackupStorageGCSSpec)
+		**out = **in
+	}
 	if in.Volume != nil {
 		in, out := &in.Volume, &out.Volume
 		*out = new(VolumeSpec)
@@ -286,6 +381,21 @@ func (in *BackupStorageSpec) DeepCopyInto(out *BackupStorageSpec) {
 		*out = new(BackupContainerOptions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(BackupEncryptionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Catalog != nil {
+		in, out := &in.Catalog, &out.Catalog
+		*out = new(BackupCatalogSpec)
+		**out = **in
+	}
+	if in.Throttling != nil {
+		in, out := &in.Throttling, &out.Throttling
+		*out = new(BackupThrottlingSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStorageSpec.
@@ -298,6 +408,31 @@ func (in *BackupStorageSpec) DeepCopy() *BackupStorageSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupThrottlingSpec) DeepCopyInto(out *BackupThrottlingSpec) {
+	*out = *in
+	if in.UploadRateLimit != nil {
+		in, out := &in.UploadRateLimit, &out.UploadRateLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.DownloadRateLimit != nil {
+		in, out := &in.DownloadRateLimit, &out.DownloadRateLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupThrottlingSpec.
+func (in *BackupThrottlingSpec) DeepCopy() *BackupThrottlingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupThrottlingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
 	*out = *in
@@ -329,6 +464,21 @@ func (in *ComponentStatus) DeepCopy() *ComponentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionDrainingSpec) DeepCopyInto(out *ConnectionDrainingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDrainingSpec.
+func (in *ConnectionDrainingSpec) DeepCopy() *ConnectionDrainingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionDrainingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExtraPVC) DeepCopyInto(out *ExtraPVC) {
 	*out = *in
@@ -344,6 +494,26 @@ func (in *ExtraPVC) DeepCopy() *ExtraPVC {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HAProxyAsyncReplicasSpec) DeepCopyInto(out *HAProxyAsyncReplicasSpec) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxyAsyncReplicasSpec.
+func (in *HAProxyAsyncReplicasSpec) DeepCopy() *HAProxyAsyncReplicasSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HAProxyAsyncReplicasSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HAProxyHealthCheckSpec) DeepCopyInto(out *HAProxyHealthCheckSpec) {
 	*out = *in
@@ -374,6 +544,31 @@ func (in *HAProxyHealthCheckSpec) DeepCopy() *HAProxyHealthCheckSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HAProxyReplicasSelectionSpec) DeepCopyInto(out *HAProxyReplicasSelectionSpec) {
+	*out = *in
+	if in.MaxRecvQueue != nil {
+		in, out := &in.MaxRecvQueue, &out.MaxRecvQueue
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AsyncReplicas != nil {
+		in, out := &in.AsyncReplicas, &out.AsyncReplicas
+		*out = new(HAProxyAsyncReplicasSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAProxyReplicasSelectionSpec.
+func (in *HAProxyReplicasSelectionSpec) DeepCopy() *HAProxyReplicasSelectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HAProxyReplicasSelectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HAProxySpec) DeepCopyInto(out *HAProxySpec) {
 	*out = *in
@@ -389,6 +584,11 @@ func (in *HAProxySpec) DeepCopyInto(out *HAProxySpec) {
 		*out = new(HAProxyHealthCheckSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReplicasSelection != nil {
+		in, out := &in.ReplicasSelection, &out.ReplicasSelection
+		*out = new(HAProxyReplicasSelectionSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ReplicasServiceEnabled != nil {
 		in, out := &in.ReplicasServiceEnabled, &out.ReplicasServiceEnabled
 		*out = new(bool)
@@ -482,10 +682,62 @@ func (in *PITR) DeepCopy() *PITR {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PITRPruningSpec) DeepCopyInto(out *PITRPruningSpec) {
+	*out = *in
+	if in.SafetyMargin != nil {
+		in, out := &in.SafetyMargin, &out.SafetyMargin
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PITRPruningSpec.
+func (in *PITRPruningSpec) DeepCopy() *PITRPruningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PITRPruningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PITRRestorableWindow) DeepCopyInto(out *PITRRestorableWindow) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PITRRestorableWindow.
+func (in *PITRRestorableWindow) DeepCopy() *PITRRestorableWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(PITRRestorableWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PITRSpec) DeepCopyInto(out *PITRSpec) {
 	*out = *in
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Pruning != nil {
+		in, out := &in.Pruning, &out.Pruning
+		*out = new(PITRPruningSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Streaming != nil {
+		in, out := &in.Streaming, &out.Streaming
+		*out = new(PITRStreamingSpec)
+		**out = **in
+	}
+	if in.RPOThreshold != nil {
+		in, out := &in.RPOThreshold, &out.RPOThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PITRSpec.
@@ -498,6 +750,65 @@ func (in *PITRSpec) DeepCopy() *PITRSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PITRStatus) DeepCopyInto(out *PITRStatus) {
+	*out = *in
+	if in.StorageUsage != nil {
+		in, out := &in.StorageUsage, &out.StorageUsage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.OldestBinlogTime != nil {
+		in, out := &in.OldestBinlogTime, &out.OldestBinlogTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastPruneTime != nil {
+		in, out := &in.LastPruneTime, &out.LastPruneTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RestorableWindows != nil {
+		in, out := &in.RestorableWindows, &out.RestorableWindows
+		*out = make([]PITRRestorableWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUploadedEventTime != nil {
+		in, out := &in.LastUploadedEventTime, &out.LastUploadedEventTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RPO != nil {
+		in, out := &in.RPO, &out.RPO
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PITRStatus.
+func (in *PITRStatus) DeepCopy() *PITRStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PITRStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PITRStreamingSpec) DeepCopyInto(out *PITRStreamingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PITRStreamingSpec.
+func (in *PITRStreamingSpec) DeepCopy() *PITRStreamingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PITRStreamingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PMMSpec) DeepCopyInto(out *PMMSpec) {
 	*out = *in
@@ -534,9 +845,79 @@ func (in *PMMSpec) DeepCopy() *PMMSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PXCBackupCopyStatus) DeepCopyInto(out *PXCBackupCopyStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PXCBackupCopyStatus.
+func (in *PXCBackupCopyStatus) DeepCopy() *PXCBackupCopyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PXCBackupCopyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PXCBackupLSN) DeepCopyInto(out *PXCBackupLSN) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PXCBackupLSN.
+func (in *PXCBackupLSN) DeepCopy() *PXCBackupLSN {
+	if in == nil {
+		return nil
+	}
+	out := new(PXCBackupLSN)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PXCBackupSource) DeepCopyInto(out *PXCBackupSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PXCBackupSource.
+func (in *PXCBackupSource) DeepCopy() *PXCBackupSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PXCBackupSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PXCBackupSourceStatus) DeepCopyInto(out *PXCBackupSourceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PXCBackupSourceStatus.
+func (in *PXCBackupSourceStatus) DeepCopy() *PXCBackupSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PXCBackupSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PXCBackupSpec) DeepCopyInto(out *PXCBackupSpec) {
 	*out = *in
+	if in.Logical != nil {
+		in, out := &in.Logical, &out.Logical
+		*out = new(PXCLogicalBackupOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(PXCBackupSource)
+		**out = **in
+	}
 	if in.ContainerOptions != nil {
 		in, out := &in.ContainerOptions, &out.ContainerOptions
 		*out = new(BackupContainerOptions)
@@ -562,6 +943,16 @@ func (in *PXCBackupSpec) DeepCopyInto(out *PXCBackupSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(PXCBackupVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CopyTo != nil {
+		in, out := &in.CopyTo, &out.CopyTo
+		*out = make([]BackupCopySpec, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PXCBackupSpec.
@@ -595,6 +986,11 @@ func (in *PXCBackupStatus) DeepCopyInto(out *PXCBackupStatus) {
 		*out = new(BackupStorageAzureSpec)
 		**out = **in
 	}
+	if in.GCS != nil {
+		in, out := &in.GCS, &out.GCS
+		*out = new(BackupStorageGCSSpec)
+		**out = **in
+	}
 	if in.PVC != nil {
 		in, out := &in.PVC, &out.PVC
 		*out = new(corev1.PersistentVolumeClaimSpec)
@@ -616,6 +1012,36 @@ func (in *PXCBackupStatus) DeepCopyInto(out *PXCBackupStatus) {
 		in, out := &in.LatestRestorableTime, &out.LatestRestorableTime
 		*out = (*in).DeepCopy()
 	}
+	if in.BaseChain != nil {
+		in, out := &in.BaseChain, &out.BaseChain
+		*out = make([]PXCBackupDestination, len(*in))
+		copy(*out, *in)
+	}
+	if in.LSN != nil {
+		in, out := &in.LSN, &out.LSN
+		*out = new(PXCBackupLSN)
+		**out = **in
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(BackupEncryptionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Copies != nil {
+		in, out := &in.Copies, &out.Copies
+		*out = make([]PXCBackupCopyStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(PXCBackupSourceStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PXCBackupStatus.
@@ -628,9 +1054,110 @@ func (in *PXCBackupStatus) DeepCopy() *PXCBackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PXCBackupVerification) DeepCopyInto(out *PXCBackupVerification) {
+	*out = *in
+	if in.CheckTablesLimit != nil {
+		in, out := &in.CheckTablesLimit, &out.CheckTablesLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PXCBackupVerification.
+func (in *PXCBackupVerification) DeepCopy() *PXCBackupVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(PXCBackupVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PXCLogicalBackupOptions) DeepCopyInto(out *PXCLogicalBackupOptions) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PXCLogicalBackupOptions.
+func (in *PXCLogicalBackupOptions) DeepCopy() *PXCLogicalBackupOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(PXCLogicalBackupOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PXCScheduledBackupCopy) DeepCopyInto(out *PXCScheduledBackupCopy) {
+	*out = *in
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(PXCScheduledBackupRetention)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PXCScheduledBackupCopy.
+func (in *PXCScheduledBackupCopy) DeepCopy() *PXCScheduledBackupCopy {
+	if in == nil {
+		return nil
+	}
+	out := new(PXCScheduledBackupCopy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PXCScheduledBackupGFSRetention) DeepCopyInto(out *PXCScheduledBackupGFSRetention) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PXCScheduledBackupGFSRetention.
+func (in *PXCScheduledBackupGFSRetention) DeepCopy() *PXCScheduledBackupGFSRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(PXCScheduledBackupGFSRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PXCScheduledBackupRetention) DeepCopyInto(out *PXCScheduledBackupRetention) {
 	*out = *in
+	if in.GFS != nil {
+		in, out := &in.GFS, &out.GFS
+		*out = new(PXCScheduledBackupGFSRetention)
+		**out = **in
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxTotalSize != nil {
+		in, out := &in.MaxTotalSize, &out.MaxTotalSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PXCScheduledBackupRetention.
@@ -649,8 +1176,30 @@ func (in *PXCScheduledBackupSchedule) DeepCopyInto(out *PXCScheduledBackupSchedu
 	if in.Retention != nil {
 		in, out := &in.Retention, &out.Retention
 		*out = new(PXCScheduledBackupRetention)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logical != nil {
+		in, out := &in.Logical, &out.Logical
+		*out = new(PXCLogicalBackupOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(PXCBackupSource)
 		**out = **in
 	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(PXCBackupVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Copies != nil {
+		in, out := &in.Copies, &out.Copies
+		*out = make([]PXCScheduledBackupCopy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PXCScheduledBackupSchedule.
@@ -679,6 +1228,11 @@ func (in *PXCSpec) DeepCopyInto(out *PXCSpec) {
 		}
 	}
 	in.Expose.DeepCopyInto(&out.Expose)
+	if in.ConnectionDraining != nil {
+		in, out := &in.ConnectionDraining, &out.ConnectionDraining
+		*out = new(ConnectionDrainingSpec)
+		**out = **in
+	}
 	if in.PodSpec != nil {
 		in, out := &in.PodSpec, &out.PodSpec
 		*out = new(PodSpec)
@@ -826,36 +1380,170 @@ func (in *PerconaXtraDBClusterList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
-	return nil
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PerconaXtraDBClusterRestore) DeepCopyInto(out *PerconaXtraDBClusterRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerconaXtraDBClusterRestore.
+func (in *PerconaXtraDBClusterRestore) DeepCopy() *PerconaXtraDBClusterRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(PerconaXtraDBClusterRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PerconaXtraDBClusterRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PerconaXtraDBClusterRestoreDrill) DeepCopyInto(out *PerconaXtraDBClusterRestoreDrill) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerconaXtraDBClusterRestoreDrill.
+func (in *PerconaXtraDBClusterRestoreDrill) DeepCopy() *PerconaXtraDBClusterRestoreDrill {
+	if in == nil {
+		return nil
+	}
+	out := new(PerconaXtraDBClusterRestoreDrill)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PerconaXtraDBClusterRestoreDrill) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PerconaXtraDBClusterRestoreDrillList) DeepCopyInto(out *PerconaXtraDBClusterRestoreDrillList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PerconaXtraDBClusterRestoreDrill, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerconaXtraDBClusterRestoreDrillList.
+func (in *PerconaXtraDBClusterRestoreDrillList) DeepCopy() *PerconaXtraDBClusterRestoreDrillList {
+	if in == nil {
+		return nil
+	}
+	out := new(PerconaXtraDBClusterRestoreDrillList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PerconaXtraDBClusterRestoreDrillList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PerconaXtraDBClusterRestoreDrillSpec) DeepCopyInto(out *PerconaXtraDBClusterRestoreDrillSpec) {
+	*out = *in
+	if in.PITR != nil {
+		in, out := &in.PITR, &out.PITR
+		*out = new(RestoreDrillPITR)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Assertions != nil {
+		in, out := &in.Assertions, &out.Assertions
+		*out = make([]RestoreDrillAssertion, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.HistoryLimit != nil {
+		in, out := &in.HistoryLimit, &out.HistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerconaXtraDBClusterRestoreDrillSpec.
+func (in *PerconaXtraDBClusterRestoreDrillSpec) DeepCopy() *PerconaXtraDBClusterRestoreDrillSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PerconaXtraDBClusterRestoreDrillSpec)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PerconaXtraDBClusterRestore) DeepCopyInto(out *PerconaXtraDBClusterRestore) {
+func (in *PerconaXtraDBClusterRestoreDrillStatus) DeepCopyInto(out *PerconaXtraDBClusterRestoreDrillStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Current != nil {
+		in, out := &in.Current, &out.Current
+		*out = new(RestoreDrillRun)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]RestoreDrillRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerconaXtraDBClusterRestore.
-func (in *PerconaXtraDBClusterRestore) DeepCopy() *PerconaXtraDBClusterRestore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerconaXtraDBClusterRestoreDrillStatus.
+func (in *PerconaXtraDBClusterRestoreDrillStatus) DeepCopy() *PerconaXtraDBClusterRestoreDrillStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PerconaXtraDBClusterRestore)
+	out := new(PerconaXtraDBClusterRestoreDrillStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PerconaXtraDBClusterRestore) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PerconaXtraDBClusterRestoreList) DeepCopyInto(out *PerconaXtraDBClusterRestoreList) {
 	*out = *in
@@ -907,6 +1595,21 @@ func (in *PerconaXtraDBClusterRestoreSpec) DeepCopyInto(out *PerconaXtraDBCluste
 		(*in).DeepCopyInto(*out)
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NewCluster != nil {
+		in, out := &in.NewCluster, &out.NewCluster
+		*out = new(RestoreNewClusterSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerconaXtraDBClusterRestoreSpec.
@@ -931,6 +1634,16 @@ func (in *PerconaXtraDBClusterRestoreStatus) DeepCopyInto(out *PerconaXtraDBClus
 		*out = (*in).DeepCopy()
 	}
 	out.Unsafe = in.Unsafe
+	if in.ImportedTables != nil {
+		in, out := &in.ImportedTables, &out.ImportedTables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(RestoreProgress)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerconaXtraDBClusterRestoreStatus.
@@ -1049,6 +1762,28 @@ func (in *PerconaXtraDBClusterStatus) DeepCopyInto(out *PerconaXtraDBClusterStat
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.BackupRetention != nil {
+		in, out := &in.BackupRetention, &out.BackupRetention
+		*out = make([]BackupRetentionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PITR != nil {
+		in, out := &in.PITR, &out.PITR
+		*out = new(PITRStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Switchover != nil {
+		in, out := &in.Switchover, &out.Switchover
+		*out = new(SwitchoverStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProxySQLConfig != nil {
+		in, out := &in.ProxySQLConfig, &out.ProxySQLConfig
+		*out = new(ProxySQLConfigStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerconaXtraDBClusterStatus.
@@ -1242,6 +1977,85 @@ func (in *PodSpec) DeepCopy() *PodSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxySQLConfigStatus) DeepCopyInto(out *ProxySQLConfigStatus) {
+	*out = *in
+	if in.QueryRules != nil {
+		in, out := &in.QueryRules, &out.QueryRules
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drifted != nil {
+		in, out := &in.Drifted, &out.Drifted
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySQLConfigStatus.
+func (in *ProxySQLConfigStatus) DeepCopy() *ProxySQLConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxySQLConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxySQLQueryRule) DeepCopyInto(out *ProxySQLQueryRule) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FlagOut != nil {
+		in, out := &in.FlagOut, &out.FlagOut
+		*out = new(int)
+		**out = **in
+	}
+	if in.DestinationHostgroup != nil {
+		in, out := &in.DestinationHostgroup, &out.DestinationHostgroup
+		*out = new(int)
+		**out = **in
+	}
+	if in.CacheTTL != nil {
+		in, out := &in.CacheTTL, &out.CacheTTL
+		*out = new(int)
+		**out = **in
+	}
+	if in.MirrorHostgroup != nil {
+		in, out := &in.MirrorHostgroup, &out.MirrorHostgroup
+		*out = new(int)
+		**out = **in
+	}
+	if in.Multiplex != nil {
+		in, out := &in.Multiplex, &out.Multiplex
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySQLQueryRule.
+func (in *ProxySQLQueryRule) DeepCopy() *ProxySQLQueryRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxySQLQueryRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProxySQLSchedulerSpec) DeepCopyInto(out *ProxySQLSchedulerSpec) {
 	*out = *in
@@ -1263,6 +2077,20 @@ func (in *ProxySQLSpec) DeepCopyInto(out *ProxySQLSpec) {
 	in.PodSpec.DeepCopyInto(&out.PodSpec)
 	in.Expose.DeepCopyInto(&out.Expose)
 	out.Scheduler = in.Scheduler
+	if in.QueryRules != nil {
+		in, out := &in.QueryRules, &out.QueryRules
+		*out = make([]ProxySQLQueryRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]ProxySQLUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySQLSpec.
@@ -1275,6 +2103,46 @@ func (in *ProxySQLSpec) DeepCopy() *ProxySQLSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxySQLUser) DeepCopyInto(out *ProxySQLUser) {
+	*out = *in
+	if in.DefaultHostgroup != nil {
+		in, out := &in.DefaultHostgroup, &out.DefaultHostgroup
+		*out = new(int)
+		**out = **in
+	}
+	if in.TransactionPersistent != nil {
+		in, out := &in.TransactionPersistent, &out.TransactionPersistent
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FastForward != nil {
+		in, out := &in.FastForward, &out.FastForward
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxConnections != nil {
+		in, out := &in.MaxConnections, &out.MaxConnections
+		*out = new(int)
+		**out = **in
+	}
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySQLUser.
+func (in *ProxySQLUser) DeepCopy() *ProxySQLUser {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxySQLUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicasServiceExpose) DeepCopyInto(out *ReplicasServiceExpose) {
 	*out = *in
@@ -1382,6 +2250,157 @@ func (in *ReplicationStatus) DeepCopy() *ReplicationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreDrillAssertion) DeepCopyInto(out *RestoreDrillAssertion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreDrillAssertion.
+func (in *RestoreDrillAssertion) DeepCopy() *RestoreDrillAssertion {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreDrillAssertion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreDrillAssertionResult) DeepCopyInto(out *RestoreDrillAssertionResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreDrillAssertionResult.
+func (in *RestoreDrillAssertionResult) DeepCopy() *RestoreDrillAssertionResult {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreDrillAssertionResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreDrillPITR) DeepCopyInto(out *RestoreDrillPITR) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreDrillPITR.
+func (in *RestoreDrillPITR) DeepCopy() *RestoreDrillPITR {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreDrillPITR)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreDrillRun) DeepCopyInto(out *RestoreDrillRun) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.RTO != nil {
+		in, out := &in.RTO, &out.RTO
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Assertions != nil {
+		in, out := &in.Assertions, &out.Assertions
+		*out = make([]RestoreDrillAssertionResult, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreDrillRun.
+func (in *RestoreDrillRun) DeepCopy() *RestoreDrillRun {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreDrillRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreNewClusterSpec) DeepCopyInto(out *RestoreNewClusterSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreNewClusterSpec.
+func (in *RestoreNewClusterSpec) DeepCopy() *RestoreNewClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreNewClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreProgress) DeepCopyInto(out *RestoreProgress) {
+	*out = *in
+	if in.PhaseStarted != nil {
+		in, out := &in.PhaseStarted, &out.PhaseStarted
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedPhases != nil {
+		in, out := &in.CompletedPhases, &out.CompletedPhases
+		*out = make([]RestorePhase, len(*in))
+		copy(*out, *in)
+	}
+	if in.ETA != nil {
+		in, out := &in.ETA, &out.ETA
+		*out = (*in).DeepCopy()
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreProgress.
+func (in *RestoreProgress) DeepCopy() *RestoreProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
 	*out = *in
@@ -1436,6 +2455,29 @@ func (in *ServiceExpose) DeepCopy() *ServiceExpose {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverStatus) DeepCopyInto(out *SwitchoverStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverStatus.
+func (in *SwitchoverStatus) DeepCopy() *SwitchoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSSpec) DeepCopyInto(out *TLSSpec) {
 	*out = *in
@@ -1,15 +1,18 @@
 package pxc
 
 import (
-	"container/heap"
 	"context"
 	"crypto/sha1"
 	"encoding/hex"
+	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/robfig/cron/v3"
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -20,6 +23,7 @@ import (
 	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app/binlogcollector"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup"
 )
 
 type BackupScheduleJob struct {
@@ -43,6 +47,11 @@ func (r *ReconcilePerconaXtraDBCluster) reconcileBackups(ctx context.Context, cr
 			if err := r.reconcileBinlogCollector(ctx, cr); err != nil {
 				return errors.Wrap(err, "reconcile binlog collector")
 			}
+			r.reconcilePITRStorage(ctx, cr)
+			if err := r.reconcilePITRTimeline(ctx, cr); err != nil {
+				log.Error(err, "failed to reconcile PITR timeline")
+			}
+			r.reconcilePITRRPO(ctx, cr)
 		}
 
 		if !cr.Spec.Backup.PITR.Enabled || cr.Spec.Pause || restoreRunning {
@@ -52,6 +61,8 @@ func (r *ReconcilePerconaXtraDBCluster) reconcileBackups(ctx context.Context, cr
 			}
 		}
 
+		r.reconcileBackupCatalog(ctx, cr)
+
 		for i, bcp := range cr.Spec.Backup.Schedule {
 			bcp.Name = backupNamePrefix + "-" + bcp.Name
 			backups[bcp.Name] = bcp
@@ -84,27 +95,35 @@ func (r *ReconcilePerconaXtraDBCluster) reconcileBackups(ctx context.Context, cr
 		}
 	}
 
+	if cr.Spec.Backup == nil || !cr.Spec.Backup.PITR.Enabled {
+		r.clearPITRStatus(cr)
+	}
+
+	var retentionStatus []api.BackupRetentionStatus
+	applyRetention := func(schedule, storageName, copyStorage string, retention api.PXCScheduledBackupRetention) {
+		st, err := r.applyBackupRetention(ctx, cr, schedule, storageName, copyStorage, retention)
+		if err != nil {
+			log.Error(err, "failed to apply backup retention", "name", schedule, "storage", storageName)
+			// the last result of the storage is kept until the retention is applied again
+			st = findBackupRetentionStatus(cr.Status.BackupRetention, schedule, storageName)
+		}
+		if st != nil {
+			retentionStatus = append(retentionStatus, *st)
+		}
+	}
 	r.crons.backupJobs.Range(func(k, v interface{}) bool {
 		item := v.(BackupScheduleJob)
 		if !strings.HasPrefix(item.Name, backupNamePrefix) {
 			return true
 		}
 		if spec, ok := backups[item.Name]; ok {
-			if spec.GetRetention().IsValidCountRetention() {
-				oldjobs, err := r.oldScheduledBackups(ctx, cr, item.Name, spec.GetRetention().Count)
-				if err != nil {
-					log.Error(err, "failed to list old backups", "name", item.Name)
-					return true
-				}
-
-				for _, todel := range oldjobs {
-					log.Info("deleting outdated backup", "backup", todel.Name)
-					err = r.client.Delete(ctx, &todel)
-					if err != nil {
-						log.Error(err, "failed to delete old backup", "name", todel.Name)
-					}
+			if spec.GetRetention().IsEnabled() {
+				applyRetention(item.Name, spec.StorageName, "", spec.GetRetention())
+			}
+			for _, cp := range spec.Copies {
+				if cp.GetRetention().IsEnabled() {
+					applyRetention(item.Name, cp.StorageName, cp.StorageName, cp.GetRetention())
 				}
-
 			}
 		} else {
 			log.Info("deleting outdated backup job", "name", item.Name)
@@ -114,6 +133,26 @@ func (r *ReconcilePerconaXtraDBCluster) reconcileBackups(ctx context.Context, cr
 		return true
 	})
 
+	sort.Slice(retentionStatus, func(i, j int) bool {
+		if retentionStatus[i].Schedule != retentionStatus[j].Schedule {
+			return retentionStatus[i].Schedule < retentionStatus[j].Schedule
+		}
+		return retentionStatus[i].StorageName < retentionStatus[j].StorageName
+	})
+	if !equality.Semantic.DeepEqual(cr.Status.BackupRetention, retentionStatus) {
+		cr.Status.BackupRetention = retentionStatus
+	}
+
+	return nil
+}
+
+// findBackupRetentionStatus returns the result of the retention of the schedule in the storage.
+func findBackupRetentionStatus(statuses []api.BackupRetentionStatus, schedule, storageName string) *api.BackupRetentionStatus {
+	for i := range statuses {
+		if statuses[i].Schedule == schedule && statuses[i].StorageName == storageName {
+			return &statuses[i]
+		}
+	}
 	return nil
 }
 
@@ -133,17 +172,71 @@ func shouldRecreateBackupJob(expected api.PXCScheduledBackupSchedule, existing B
 		}
 	}
 
+	if !reflect.DeepEqual(backupCopies(existing.PXCScheduledBackupSchedule), backupCopies(expected)) {
+		return true
+	}
+
 	return false
 }
 
+// backupCopies returns the copies of the backup created by the schedule.
+func backupCopies(sch api.PXCScheduledBackupSchedule) []api.BackupCopySpec {
+	if len(sch.Copies) == 0 {
+		return nil
+	}
+
+	copies := make([]api.BackupCopySpec, 0, len(sch.Copies))
+	for _, cp := range sch.Copies {
+		copies = append(copies, api.BackupCopySpec{
+			StorageName:       cp.StorageName,
+			DeleteFromStorage: cp.GetRetention().DeleteFromStorage,
+		})
+	}
+	return copies
+}
+
 func backupJobClusterPrefix(clusterName string) string {
 	h := sha1.New()
 	h.Write([]byte(clusterName))
 	return hex.EncodeToString(h.Sum(nil))[:5]
 }
 
-// oldScheduledBackups returns list of the most old pxc-bakups that execeed `keep` limit
-func (r *ReconcilePerconaXtraDBCluster) oldScheduledBackups(ctx context.Context, cr *api.PerconaXtraDBCluster, ancestor string, keep int) ([]api.PerconaXtraDBClusterBackup, error) {
+// applyBackupRetention deletes the scheduled backups which are not retained by the retention.
+// If the retention is in dry-run mode, backups aren't deleted and the returned status lists them.
+func (r *ReconcilePerconaXtraDBCluster) applyBackupRetention(ctx context.Context, cr *api.PerconaXtraDBCluster, ancestor, storageName, copyStorage string, retention api.PXCScheduledBackupRetention) (*api.BackupRetentionStatus, error) {
+	log := logf.FromContext(ctx)
+
+	oldjobs, err := r.oldScheduledBackups(ctx, cr, ancestor, storageName, copyStorage, retention)
+	if err != nil {
+		return nil, errors.Wrap(err, "list old backups")
+	}
+
+	if retention.DryRun {
+		st := &api.BackupRetentionStatus{
+			Schedule:    ancestor,
+			StorageName: storageName,
+		}
+		for _, bcp := range oldjobs {
+			st.PendingDeletion = append(st.PendingDeletion, bcp.Name)
+		}
+		return st, nil
+	}
+
+	for _, todel := range oldjobs {
+		log.Info("deleting outdated backup", "backup", todel.Name)
+		err = r.client.Delete(ctx, &todel)
+		if err != nil {
+			log.Error(err, "failed to delete old backup", "name", todel.Name)
+		}
+	}
+
+	return nil, nil
+}
+
+// oldScheduledBackups returns list of the pxc-backups that aren't retained by the retention.
+// If copyStorage is set, only copies of the backups in this storage are taken into account,
+// otherwise copies are ignored.
+func (r *ReconcilePerconaXtraDBCluster) oldScheduledBackups(ctx context.Context, cr *api.PerconaXtraDBCluster, ancestor, storageName, copyStorage string, retention api.PXCScheduledBackupRetention) ([]api.PerconaXtraDBClusterBackup, error) {
 	bcpList := api.PerconaXtraDBClusterBackupList{}
 	err := r.client.List(ctx,
 		&bcpList,
@@ -159,31 +252,53 @@ func (r *ReconcilePerconaXtraDBCluster) oldScheduledBackups(ctx context.Context,
 		return []api.PerconaXtraDBClusterBackup{}, err
 	}
 
-	// fast path
-	if len(bcpList.Items) <= keep {
-		return []api.PerconaXtraDBClusterBackup{}, nil
+	items := make([]api.PerconaXtraDBClusterBackup, 0, len(bcpList.Items))
+	for _, bcp := range bcpList.Items {
+		if bcp.Spec.IsCopy() != (copyStorage != "") {
+			continue
+		}
+		if copyStorage != "" && bcp.Spec.StorageName != copyStorage {
+			continue
+		}
+		items = append(items, bcp)
 	}
 
-	// just build an ordered by creationTimestamp min-heap from items and return top "len(items) - keep" items
-	h := &minHeap{}
-	heap.Init(h)
-	for _, bcp := range bcpList.Items {
-		if bcp.Status.State == api.BackupSucceeded {
-			heap.Push(h, bcp)
+	var others []api.PerconaXtraDBClusterBackup
+	if retention.MaxTotalSize != nil && !retention.MaxTotalSize.IsZero() {
+		others, err = r.otherStorageBackups(ctx, cr, storageName, items)
+		if err != nil {
+			return []api.PerconaXtraDBClusterBackup{}, errors.Wrap(err, "list backups in storage")
 		}
 	}
 
-	if h.Len() <= keep {
-		return []api.PerconaXtraDBClusterBackup{}, nil
+	return backup.BackupsToDelete(retention, items, others, time.Now()), nil
+}
+
+// otherStorageBackups returns the backups of the cluster in the storage except the given ones,
+// e.g. backups of other schedules, copies and manual backups.
+func (r *ReconcilePerconaXtraDBCluster) otherStorageBackups(ctx context.Context, cr *api.PerconaXtraDBCluster, storageName string, except []api.PerconaXtraDBClusterBackup) ([]api.PerconaXtraDBClusterBackup, error) {
+	bcpList := api.PerconaXtraDBClusterBackupList{}
+	if err := r.client.List(ctx, &bcpList, client.InNamespace(cr.Namespace)); err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[string]struct{}, len(except))
+	for _, bcp := range except {
+		excluded[bcp.Name] = struct{}{}
 	}
 
-	ret := make([]api.PerconaXtraDBClusterBackup, 0, h.Len()-keep)
-	for i := h.Len() - keep; i > 0; i-- {
-		o := heap.Pop(h).(api.PerconaXtraDBClusterBackup)
-		ret = append(ret, o)
+	var others []api.PerconaXtraDBClusterBackup
+	for _, bcp := range bcpList.Items {
+		if bcp.Spec.PXCCluster != cr.Name || bcp.Spec.StorageName != storageName {
+			continue
+		}
+		if _, ok := excluded[bcp.Name]; ok {
+			continue
+		}
+		others = append(others, bcp)
 	}
 
-	return ret, nil
+	return others, nil
 }
 
 func (r *ReconcilePerconaXtraDBCluster) createBackupJob(ctx context.Context, cr *api.PerconaXtraDBCluster, backupJob api.PXCScheduledBackupSchedule, storageType api.BackupStorageType) func() {
@@ -216,7 +331,12 @@ func (r *ReconcilePerconaXtraDBCluster) createBackupJob(ctx context.Context, cr
 			Spec: api.PXCBackupSpec{
 				PXCCluster:              cr.Name,
 				StorageName:             backupJob.StorageName,
+				Type:                    backupJob.Type,
+				Logical:                 backupJob.Logical,
+				Source:                  backupJob.Source,
+				Verification:            backupJob.Verification,
 				StartingDeadlineSeconds: cr.Spec.Backup.StartingDeadlineSeconds,
+				CopyTo:                  backupCopies(backupJob),
 			},
 		}
 		err = r.client.Create(ctx, bcp)
@@ -228,7 +348,7 @@ func (r *ReconcilePerconaXtraDBCluster) createBackupJob(ctx context.Context, cr
 
 func backupFinalizers(cr *api.PerconaXtraDBCluster, backupJob api.PXCScheduledBackupSchedule, storageType api.BackupStorageType) []string {
 	switch storageType {
-	case api.BackupStorageS3, api.BackupStorageAzure, api.BackupStorageFilesystem:
+	case api.BackupStorageS3, api.BackupStorageAzure, api.BackupStorageGCS, api.BackupStorageFilesystem:
 		if cr.CompareVersionWith("1.18.0") >= 0 && !backupJob.GetRetention().DeleteFromStorage {
 			return []string{}
 		}
@@ -246,29 +366,6 @@ func (r *ReconcilePerconaXtraDBCluster) deleteBackupJob(name string) {
 	r.crons.crons.Remove(job.(BackupScheduleJob).JobID)
 }
 
-// A minHeap is a min-heap of backup jobs.
-type minHeap []api.PerconaXtraDBClusterBackup
-
-func (h minHeap) Len() int { return len(h) }
-
-func (h minHeap) Less(i, j int) bool {
-	return h[i].CreationTimestamp.Before(&h[j].CreationTimestamp)
-}
-
-func (h minHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
-
-func (h *minHeap) Push(x interface{}) {
-	*h = append(*h, x.(api.PerconaXtraDBClusterBackup))
-}
-
-func (h *minHeap) Pop() interface{} {
-	old := *h
-	n := len(old)
-	x := old[n-1]
-	*h = old[0 : n-1]
-	return x
-}
-
 func (r *ReconcilePerconaXtraDBCluster) deletePITR(ctx context.Context, cr *api.PerconaXtraDBCluster) error {
 	collectorDeployment := appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
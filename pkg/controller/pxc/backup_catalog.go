@@ -0,0 +1,162 @@
+package pxc
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+)
+
+// reconcileBackupCatalog imports the backups found in the storages with enabled catalog.
+// Each storage is scanned not more often than its scan interval.
+func (r *ReconcilePerconaXtraDBCluster) reconcileBackupCatalog(ctx context.Context, cr *api.PerconaXtraDBCluster) {
+	log := logf.FromContext(ctx)
+
+	for name, stg := range cr.Spec.Backup.Storages {
+		if stg == nil || !stg.Catalog.IsEnabled() || stg.Type == api.BackupStorageFilesystem {
+			continue
+		}
+
+		key := cr.Namespace + "/" + cr.Name + "/" + name
+		if last, ok := r.backupCatalogScans.Load(key); ok && time.Since(last.(time.Time)) < stg.Catalog.GetScanInterval() {
+			continue
+		}
+
+		if err := r.importStorageBackups(ctx, cr, name, stg); err != nil {
+			log.Error(err, "failed to import backups from storage", "storage", name)
+		}
+		r.backupCatalogScans.Store(key, time.Now())
+	}
+}
+
+func (r *ReconcilePerconaXtraDBCluster) importStorageBackups(ctx context.Context, cr *api.PerconaXtraDBCluster, storageName string, stg *api.BackupStorageSpec) error {
+	log := logf.FromContext(ctx).WithValues("storage", storageName)
+
+	opts, err := storage.GetOptions(ctx, r.client, cr, storageName)
+	if err != nil {
+		return errors.Wrap(err, "get storage options")
+	}
+	cli, err := r.newStorageClientFunc(ctx, opts)
+	if err != nil {
+		return errors.Wrap(err, "new storage client")
+	}
+
+	found, err := backup.ScanStorage(ctx, cli, stg.Type, !stg.Encryption.IsEnabled())
+	if err != nil {
+		return errors.Wrap(err, "scan storage")
+	}
+
+	bcpList := api.PerconaXtraDBClusterBackupList{}
+	if err := r.client.List(ctx, &bcpList, &client.ListOptions{Namespace: cr.Namespace}); err != nil {
+		return errors.Wrap(err, "get backup objects")
+	}
+	known := make(map[api.PXCBackupDestination]struct{}, len(bcpList.Items))
+	for _, bcp := range bcpList.Items {
+		known[bcp.Status.Destination] = struct{}{}
+	}
+
+	for _, b := range found {
+		dest, err := backup.CatalogDestination(stg, b.Name)
+		if err != nil {
+			return errors.Wrap(err, "get backup destination")
+		}
+		if _, ok := known[dest]; ok {
+			continue
+		}
+		if b.Type == api.PXCBackupTypeIncremental && len(b.BaseChain) == 0 {
+			log.Info("skipping incremental backup without base backups", "backup", b.Name)
+			continue
+		}
+
+		status := api.PXCBackupStatus{
+			State:                 api.BackupSucceeded,
+			Destination:           dest,
+			StorageName:           storageName,
+			S3:                    stg.S3,
+			Azure:                 stg.Azure,
+			GCS:                   stg.GCS,
+			StorageType:           stg.Type,
+			Image:                 cr.Spec.Backup.Image,
+			SSLSecretName:         cr.Spec.PXC.SSLSecretName,
+			SSLInternalSecretName: cr.Spec.PXC.SSLInternalSecretName,
+			VaultSecretName:       cr.Spec.PXC.VaultSecretName,
+			VerifyTLS:             stg.VerifyTLS,
+			Encryption:            stg.Encryption,
+			Type:                  b.Type,
+			LSN:                   b.LSN,
+			GTID:                  b.GTID,
+		}
+		if !b.CompletedAt.IsZero() {
+			status.CompletedAt = &metav1.Time{Time: b.CompletedAt}
+		}
+		for _, base := range b.BaseChain {
+			baseDest, err := backup.CatalogDestination(stg, base)
+			if err != nil {
+				return errors.Wrap(err, "get base backup destination")
+			}
+			status.BaseChain = append(status.BaseChain, baseDest)
+		}
+
+		bcp, err := r.createImportedBackup(ctx, cr, storageName, b)
+		if err != nil {
+			return errors.Wrapf(err, "create backup object for %s", b.Name)
+		}
+		if bcp == nil {
+			log.Info("backup object already exists", "backup", b.Name, "name", naming.ImportedBackupName(storageName, b.Name))
+			continue
+		}
+
+		bcp.Status = status
+		if err := r.client.Status().Update(ctx, bcp); err != nil {
+			return errors.Wrapf(err, "update status of backup %s", bcp.Name)
+		}
+
+		log.Info("backup imported from storage", "backup", b.Name, "name", bcp.Name, "destination", dest)
+	}
+
+	return nil
+}
+
+// createImportedBackup creates the backup object for the backup found in the storage.
+// It returns nil if the object with the same name already exists and isn't waiting for the import.
+func (r *ReconcilePerconaXtraDBCluster) createImportedBackup(ctx context.Context, cr *api.PerconaXtraDBCluster, storageName string, b backup.CatalogBackup) (*api.PerconaXtraDBClusterBackup, error) {
+	bcp := &api.PerconaXtraDBClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.ImportedBackupName(storageName, b.Name),
+			Namespace: cr.Namespace,
+			Labels:    naming.LabelsImportedBackup(cr, storageName),
+		},
+		Spec: api.PXCBackupSpec{
+			PXCCluster:  cr.Name,
+			StorageName: storageName,
+			Type:        b.Type,
+		},
+	}
+
+	err := r.client.Create(ctx, bcp)
+	if err == nil {
+		return bcp, nil
+	}
+	if !k8serrors.IsAlreadyExists(err) {
+		return nil, err
+	}
+
+	// the object could be created during the previous scan which failed to update its status
+	if err := r.client.Get(ctx, client.ObjectKeyFromObject(bcp), bcp); err != nil {
+		return nil, errors.Wrap(err, "get backup object")
+	}
+	if bcp.Labels[naming.LabelPerconaBackupType] != naming.BackupTypeImported || bcp.Status.State != api.BackupNew {
+		return nil, nil
+	}
+
+	return bcp, nil
+}
@@ -1,10 +1,22 @@
 package pxc
 
 import (
+	"context"
 	"testing"
+	"time"
 
-	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/apis"
+	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
 )
 
 func TestShouldRecreateJob(t *testing.T) {
@@ -94,6 +106,47 @@ func TestShouldRecreateJob(t *testing.T) {
 			},
 			expected: true,
 		},
+		"copies changed": {
+			job: BackupScheduleJob{
+				PXCScheduledBackupSchedule: pxcv1.PXCScheduledBackupSchedule{
+					StorageName: "test-storage",
+					Schedule:    "10 4 * * *",
+				},
+			},
+			schedule: pxcv1.PXCScheduledBackupSchedule{
+				StorageName: "test-storage",
+				Schedule:    "10 4 * * *",
+				Copies: []pxcv1.PXCScheduledBackupCopy{
+					{StorageName: "test-storage-dr"},
+				},
+			},
+			expected: true,
+		},
+		"copy retention count changed": {
+			job: BackupScheduleJob{
+				PXCScheduledBackupSchedule: pxcv1.PXCScheduledBackupSchedule{
+					StorageName: "test-storage",
+					Schedule:    "10 4 * * *",
+					Copies: []pxcv1.PXCScheduledBackupCopy{
+						{
+							StorageName: "test-storage-dr",
+							Retention:   &pxcv1.PXCScheduledBackupRetention{Count: 3, DeleteFromStorage: true},
+						},
+					},
+				},
+			},
+			schedule: pxcv1.PXCScheduledBackupSchedule{
+				StorageName: "test-storage",
+				Schedule:    "10 4 * * *",
+				Copies: []pxcv1.PXCScheduledBackupCopy{
+					{
+						StorageName: "test-storage-dr",
+						Retention:   &pxcv1.PXCScheduledBackupRetention{Count: 7, DeleteFromStorage: true},
+					},
+				},
+			},
+			expected: false,
+		},
 	}
 
 	for name, tt := range tests {
@@ -103,3 +156,76 @@ func TestShouldRecreateJob(t *testing.T) {
 		})
 	}
 }
+
+func TestOldScheduledBackupsSizeLimit(t *testing.T) {
+	ctx := context.Background()
+
+	cr, err := readDefaultCR("cluster1", "test")
+	require.NoError(t, err)
+
+	now := time.Now()
+	newBackup := func(name, storage string, ago time.Duration, labels map[string]string) *pxcv1.PerconaXtraDBClusterBackup {
+		return &pxcv1.PerconaXtraDBClusterBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         cr.Namespace,
+				Labels:            labels,
+				CreationTimestamp: metav1.NewTime(now.Add(-ago - time.Minute)),
+			},
+			Spec: pxcv1.PXCBackupSpec{
+				PXCCluster:  cr.Name,
+				StorageName: storage,
+			},
+			Status: pxcv1.PXCBackupStatus{
+				State:       pxcv1.BackupSucceeded,
+				CompletedAt: &metav1.Time{Time: now.Add(-ago)},
+				Size:        resource.NewQuantity(100, resource.BinarySI),
+			},
+		}
+	}
+
+	daily := naming.LabelsScheduledBackup(cr, "daily")
+	weekly := naming.LabelsScheduledBackup(cr, "weekly")
+	objs := []client.Object{
+		newBackup("daily-1", "s3-us-west", time.Hour, daily),
+		newBackup("daily-2", "s3-us-west", 25*time.Hour, daily),
+		newBackup("daily-3", "s3-us-west", 49*time.Hour, daily),
+		newBackup("weekly-1", "s3-us-west", 2*time.Hour, weekly),
+		newBackup("manual", "s3-us-west", 3*time.Hour, nil),
+		newBackup("other-storage", "azure-blob", 4*time.Hour, nil),
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, apis.AddToScheme(scheme))
+	r := &ReconcilePerconaXtraDBCluster{
+		client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		scheme: scheme,
+	}
+
+	retention := pxcv1.PXCScheduledBackupRetention{
+		Type:         pxcv1.PXCScheduledBackupRetentionCount,
+		MaxTotalSize: resource.NewQuantity(300, resource.BinarySI),
+	}
+
+	// daily-1, weekly-1 and manual fill the budget of the storage
+	toDelete, err := r.oldScheduledBackups(ctx, cr, "daily", "s3-us-west", "", retention)
+	require.NoError(t, err)
+	var names []string
+	for _, bcp := range toDelete {
+		names = append(names, bcp.Name)
+	}
+	assert.Equal(t, []string{"daily-3", "daily-2"}, names)
+}
+
+func TestFindBackupRetentionStatus(t *testing.T) {
+	statuses := []pxcv1.BackupRetentionStatus{
+		{Schedule: "daily", StorageName: "s3-us-west", PendingDeletion: []string{"daily-1"}},
+		{Schedule: "daily", StorageName: "azure-blob", PendingDeletion: []string{"daily-copy-1"}},
+	}
+
+	st := findBackupRetentionStatus(statuses, "daily", "azure-blob")
+	require.NotNil(t, st)
+	assert.Equal(t, []string{"daily-copy-1"}, st.PendingDeletion)
+
+	assert.Nil(t, findBackupRetentionStatus(statuses, "weekly", "s3-us-west"))
+}
@@ -0,0 +1,181 @@
+package pxc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/k8s"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/queries"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/users"
+)
+
+// haproxyBackends are the backends of haproxy.cfg with the pxc pods as the servers.
+var haproxyBackends = []string{"galera-nodes", "galera-admin-nodes", haproxyReplicaBackend, "galera-mysqlx-nodes"}
+
+const haproxyReplicaBackend = "galera-replica-nodes"
+
+// drainingExcludedUsers are the users of the operator and of the proxies,
+// their connections don't prevent the restart of the pod.
+var drainingExcludedUsers = []string{users.Operator, users.Monitor, users.Xtrabackup, users.Replication, users.ProxyAdmin, users.PMMServer}
+
+func connectionDrainingEnabled(cr *api.PerconaXtraDBCluster) bool {
+	return cr.CompareVersionWith("1.20.0") >= 0 &&
+		cr.Spec.PXC.ConnectionDraining != nil &&
+		cr.Spec.PXC.ConnectionDraining.Enabled
+}
+
+// drainPod stops routing the new client connections to the pxc pod and waits
+// until the active client connections of the pod are finished.
+// HAProxy servers of the pod are switched to the drain state, see drainHAProxyServer, ProxySQL switches the pod
+// to OFFLINE_SOFT when pxc_maint_mode of the pod is MAINTENANCE.
+// The pod is restarted anyway if the connections aren't finished in time or the pod can't be drained.
+func (r *ReconcilePerconaXtraDBCluster) drainPod(ctx context.Context, cr *api.PerconaXtraDBCluster, pod *corev1.Pod) {
+	log := logf.FromContext(ctx)
+
+	if !k8s.IsPodReady(*pod) {
+		return
+	}
+
+	if cr.HAProxyEnabled() {
+		if err := r.drainHAProxyServer(ctx, cr, pod.Name); err != nil {
+			log.Error(err, "failed to drain pod in HAProxy", "pod", pod.Name)
+			return
+		}
+	}
+	if cr.ProxySQLEnabled() {
+		if err := r.setMaintMode(ctx, cr, pod.Name, "MAINTENANCE"); err != nil {
+			log.Error(err, "failed to drain pod in ProxySQL", "pod", pod.Name)
+			return
+		}
+	}
+
+	timeout := time.Duration(cr.Spec.PXC.ConnectionDraining.TimeoutSeconds) * time.Second
+	log.Info("waiting for client connections to finish", "pod", pod.Name, "timeout", timeout)
+
+	err := retry(time.Second, timeout, func() (bool, error) {
+		connections, err := r.activeClientConnections(ctx, cr, pod.Name)
+		if err != nil {
+			return false, err
+		}
+		return connections == 0, nil
+	})
+	if err != nil {
+		log.Info("pod is restarted with active client connections", "pod", pod.Name, "reason", err.Error())
+		return
+	}
+
+	log.Info("client connections are finished", "pod", pod.Name)
+}
+
+// undrainPod routes the client connections to the pxc pod again.
+// The restarted pod starts with pxc_maint_mode DISABLED, it's set again for the pods which weren't restarted.
+// If the writer is elected by the operator, the writer backends are left to reconcileHAProxyPrimary.
+func (r *ReconcilePerconaXtraDBCluster) undrainPod(ctx context.Context, cr *api.PerconaXtraDBCluster, pod *corev1.Pod) error {
+	if cr.HAProxyEnabled() {
+		if err := r.setHAProxyServerState(ctx, cr, haproxyDrainedBackends(cr), pod.Name, haproxyServerStateReady); err != nil {
+			return errors.Wrap(err, "set HAProxy server state")
+		}
+	}
+	if cr.ProxySQLEnabled() {
+		if err := r.setMaintMode(ctx, cr, pod.Name, "DISABLED"); err != nil {
+			return errors.Wrap(err, "set pxc_maint_mode")
+		}
+	}
+
+	return nil
+}
+
+// drainHAProxyServer switches the servers of the pod to the drain state in every HAProxy pod.
+// If the writer is elected by the operator, the writer backends are owned by the election:
+// the other pods are already in maintenance there, and if the pod is the writer,
+// another writer is elected and the pod is drained as the previous writer.
+func (r *ReconcilePerconaXtraDBCluster) drainHAProxyServer(ctx context.Context, cr *api.PerconaXtraDBCluster, pod string) error {
+	if err := r.setHAProxyServerState(ctx, cr, haproxyDrainedBackends(cr), pod, haproxyServerStateDrain); err != nil {
+		return err
+	}
+	if !cr.HAProxyPrimarySelectedByOperator() || cr.Status.HAProxyPrimary != pod {
+		return nil
+	}
+
+	podList := new(corev1.PodList)
+	if err := r.client.List(ctx, podList, client.InNamespace(cr.Namespace), client.MatchingLabels(naming.LabelsPXC(cr))); err != nil {
+		return errors.Wrap(err, "list pxc pods")
+	}
+	candidates := slices.DeleteFunc(podList.Items, func(p corev1.Pod) bool {
+		return p.Name == pod
+	})
+
+	primary, err := electHAProxyPrimary("", candidates, func(pod string) error {
+		return r.checkPrimaryCandidate(ctx, cr, pod)
+	})
+	if err != nil {
+		return errors.Wrap(err, "elect another HAProxy primary")
+	}
+	logf.FromContext(ctx).Info("HAProxy primary is moved from the drained pod", "previous", pod, "primary", primary)
+
+	if err := r.setHAProxyPrimary(ctx, cr, primary); err != nil {
+		return err
+	}
+
+	return r.applyHAProxyPrimary(ctx, cr, primary, pod)
+}
+
+// haproxyDrainedBackends returns the backends where the servers are drained and undrained by their state.
+// If the writer is elected by the operator, the writer backends are changed only by the election.
+func haproxyDrainedBackends(cr *api.PerconaXtraDBCluster) []string {
+	if cr.HAProxyPrimarySelectedByOperator() {
+		return []string{haproxyReplicaBackend}
+	}
+	return haproxyBackends
+}
+
+// setHAProxyServerState sets the state of the server in the backends of every HAProxy pod,
+// the pods don't share the runtime state. The state is reset when HAProxy reloads the configuration.
+func (r *ReconcilePerconaXtraDBCluster) setHAProxyServerState(ctx context.Context, cr *api.PerconaXtraDBCluster, backends []string, server, state string) error {
+	pods, err := r.runningHAProxyPods(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	for _, pod := range pods {
+		var outb, errb bytes.Buffer
+		err := r.clientcmd.Exec(&pod, "pxc-monit", haproxyServerStateCmd(backends, server, state), nil, &outb, &errb, false)
+		if err != nil {
+			return errors.Wrapf(err, "set server state in %s: %s", pod.Name, errb.String())
+		}
+	}
+
+	return nil
+}
+
+// haproxyServerStateCmd returns the command which sets the state of the server in the backends
+// through the runtime API of HAProxy.
+func haproxyServerStateCmd(backends []string, server, state string) []string {
+	commands := make([]string, 0, len(backends))
+	for _, backend := range backends {
+		commands = append(commands, fmt.Sprintf("set server %s/%s state %s", backend, server, state))
+	}
+
+	return haproxyRuntimeCmd(commands...)
+}
+
+func (r *ReconcilePerconaXtraDBCluster) activeClientConnections(ctx context.Context, cr *api.PerconaXtraDBCluster, pod string) (int, error) {
+	db, err := queries.New(r.client, cr.Namespace, internalSecretsPrefix+cr.Name, users.Operator,
+		pod+"."+cr.Name+"-pxc."+cr.Namespace, 33062, cr.Spec.PXC.ReadinessProbes.TimeoutSeconds)
+	if err != nil {
+		return 0, errors.Wrap(err, "connect")
+	}
+	defer db.Close()
+
+	return db.ActiveClientConnections(ctx, drainingExcludedUsers)
+}
@@ -0,0 +1,46 @@
+package pxc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+)
+
+func TestConnectionDrainingEnabled(t *testing.T) {
+	cr, err := readDefaultCR("cluster1", "test")
+	require.NoError(t, err)
+
+	assert.False(t, connectionDrainingEnabled(cr))
+
+	cr.Spec.PXC.ConnectionDraining = &api.ConnectionDrainingSpec{Enabled: true, TimeoutSeconds: 60}
+	assert.True(t, connectionDrainingEnabled(cr))
+
+	cr.Spec.CRVersion = "1.19.0"
+	assert.False(t, connectionDrainingEnabled(cr))
+}
+
+func TestHAProxyServerStateCmd(t *testing.T) {
+	assert.Equal(t, []string{
+		"/bin/bash", "-c",
+		"echo 'set server galera-nodes/cluster1-pxc-1 state drain; " +
+			"set server galera-admin-nodes/cluster1-pxc-1 state drain; " +
+			"set server galera-replica-nodes/cluster1-pxc-1 state drain; " +
+			"set server galera-mysqlx-nodes/cluster1-pxc-1 state drain' | socat stdio /etc/haproxy/pxc/haproxy.sock",
+	}, haproxyServerStateCmd(haproxyBackends, "cluster1-pxc-1", haproxyServerStateDrain))
+}
+
+func TestHAProxyDrainedBackends(t *testing.T) {
+	cr := &api.PerconaXtraDBCluster{
+		Spec: api.PerconaXtraDBClusterSpec{
+			CRVersion: "1.20.0",
+			HAProxy:   &api.HAProxySpec{PodSpec: api.PodSpec{Enabled: true}},
+		},
+	}
+	assert.Equal(t, haproxyBackends, haproxyDrainedBackends(cr))
+
+	cr.Spec.HAProxy.PrimarySelection = api.HAProxyPrimarySelectionOperator
+	assert.Equal(t, []string{"galera-replica-nodes"}, haproxyDrainedBackends(cr))
+}
@@ -81,6 +81,12 @@ func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 		clientcmd:     cli,
 		lockers:       newLockStore(),
 		recorder:      mgr.GetEventRecorderFor(naming.OperatorController),
+
+		newStorageClientFunc: storage.NewClient,
+		backupCatalogScans:   new(sync.Map),
+		pitrStorageChecks:    new(sync.Map),
+		pitrTimelineChecks:   new(sync.Map),
+		pitrRPOChecks:        new(sync.Map),
 	}, nil
 }
 
@@ -150,6 +156,12 @@ type ReconcilePerconaXtraDBCluster struct {
 	serverVersion  *version.ServerVersion
 	lockers        lockStore
 	recorder       record.EventRecorder
+
+	newStorageClientFunc storage.NewClientFunc
+	backupCatalogScans   *sync.Map
+	pitrStorageChecks    *sync.Map
+	pitrTimelineChecks   *sync.Map
+	pitrRPOChecks        *sync.Map
 }
 
 type lockStore struct {
@@ -444,6 +456,12 @@ func (r *ReconcilePerconaXtraDBCluster) Reconcile(ctx context.Context, request r
 		}
 	}
 
+	r.reconcileProxySQLConfig(ctx, o)
+
+	if err := r.reconcileSwitchover(ctx, o); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "reconcile switchover")
+	}
+
 	if o.CompareVersionWith("1.9.0") >= 0 {
 		err = r.reconcileReplication(ctx, o, userReconcileResult.updateReplicationPassword)
 		if err != nil {
@@ -497,6 +515,10 @@ func (r *ReconcilePerconaXtraDBCluster) reconcileHAProxy(ctx context.Context, cr
 			return errors.Wrap(err, "delete HAProxy stateful set")
 		}
 
+		if err := r.reconcileHAProxyPrimary(ctx, cr); err != nil {
+			return errors.Wrap(err, "reconcile HAProxy primary")
+		}
+
 		return nil
 	}
 
@@ -509,6 +531,10 @@ func (r *ReconcilePerconaXtraDBCluster) reconcileHAProxy(ctx context.Context, cr
 	}
 	sts := statefulset.NewHAProxy(cr)
 
+	if err := r.reconcileHAProxyPrimary(ctx, cr); err != nil {
+		return errors.Wrap(err, "reconcile HAProxy primary")
+	}
+
 	if err := r.updatePod(ctx, sts, &cr.Spec.HAProxy.PodSpec, cr, templateAnnotations, true); err != nil {
 		return errors.Wrap(err, "HAProxy upgrade error")
 	}
@@ -0,0 +1,420 @@
+package pxc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/k8s"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app/config"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/queries"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/users"
+)
+
+// reconcileHAProxyPrimary elects the writer node for all HAProxy pods and publishes it
+// in the status and in the config map mounted to the HAProxy pods.
+// HAProxy pods regenerate their configuration when the config map is changed,
+// but the config map is applied with a delay, so the writer is also set through the runtime API
+// of every HAProxy pod on each reconcile until all pods use the same writer.
+func (r *ReconcilePerconaXtraDBCluster) reconcileHAProxyPrimary(ctx context.Context, cr *api.PerconaXtraDBCluster) error {
+	log := logf.FromContext(ctx)
+
+	if !cr.HAProxyPrimarySelectedByOperator() {
+		cr.Status.HAProxyPrimary = ""
+		return errors.Wrap(deleteConfigMapIfExists(ctx, r.client, cr, naming.HAProxyPrimaryConfigMapName(cr)), "delete config map")
+	}
+
+	podList := new(corev1.PodList)
+	if err := r.client.List(ctx, podList, client.InNamespace(cr.Namespace), client.MatchingLabels(naming.LabelsPXC(cr))); err != nil {
+		return errors.Wrap(err, "list pxc pods")
+	}
+
+	primary, err := electHAProxyPrimary(cr.Status.HAProxyPrimary, podList.Items, func(pod string) error {
+		return r.checkPrimaryCandidate(ctx, cr, pod)
+	})
+	if err != nil {
+		// the current primary is kept, the writer is moved only when another node is elected
+		log.Info("Failed to elect HAProxy primary", "current", cr.Status.HAProxyPrimary, "reason", err.Error())
+		return nil
+	}
+
+	if primary != cr.Status.HAProxyPrimary {
+		log.Info("HAProxy primary is elected", "previous", cr.Status.HAProxyPrimary, "primary", primary)
+	}
+
+	if err := r.setHAProxyPrimary(ctx, cr, primary); err != nil {
+		return err
+	}
+
+	// the previous writer keeps its sessions until the switchover is finished
+	draining := ""
+	if s := cr.Status.Switchover; s != nil && s.State == api.SwitchoverSwitching && s.Target == primary {
+		draining = s.Previous
+	}
+	if err := r.applyHAProxyPrimary(ctx, cr, primary, draining); err != nil {
+		log.Info("HAProxy pods don't use the same writer yet", "primary", primary, "reason", err.Error())
+	}
+
+	return nil
+}
+
+// setHAProxyPrimary saves the writer node in the status and in the config map mounted to the HAProxy pods.
+func (r *ReconcilePerconaXtraDBCluster) setHAProxyPrimary(ctx context.Context, cr *api.PerconaXtraDBCluster, primary string) error {
+	cr.Status.HAProxyPrimary = primary
+
+	configMap := config.NewConfigMap(cr, naming.HAProxyPrimaryConfigMapName(cr), "primary", primary)
+	if err := k8s.SetControllerReference(cr, configMap, r.scheme); err != nil {
+		return errors.Wrap(err, "set controller ref")
+	}
+	if _, err := createOrUpdateConfigmap(ctx, r.client, configMap); err != nil {
+		return errors.Wrap(err, "create or update config map")
+	}
+
+	return nil
+}
+
+// checkPrimaryCandidate returns an error if the node can't be the writer node.
+// The checks are the same as the checks of haproxy_check_pxc.sh.
+func (r *ReconcilePerconaXtraDBCluster) checkPrimaryCandidate(ctx context.Context, cr *api.PerconaXtraDBCluster, pod string) error {
+	db, err := queries.New(r.client, cr.Namespace, internalSecretsPrefix+cr.Name, users.Operator,
+		pod+"."+cr.Name+"-pxc."+cr.Namespace, 33062, cr.Spec.PXC.ReadinessProbes.TimeoutSeconds)
+	if err != nil {
+		return errors.Wrap(err, "connect")
+	}
+	defer db.Close()
+
+	state, err := db.WsrepLocalStateComment()
+	if err != nil {
+		return errors.Wrap(err, "get wsrep_local_state_comment")
+	}
+	if state != "Synced" {
+		return errors.Errorf("node is %s", state)
+	}
+
+	maintMode, err := db.ReadVariable("pxc_maint_mode")
+	if err != nil {
+		return errors.Wrap(err, "get pxc_maint_mode")
+	}
+	if maintMode != "DISABLED" {
+		return errors.Errorf("pxc_maint_mode is %s", maintMode)
+	}
+
+	return nil
+}
+
+// electHAProxyPrimary returns the writer node. The current primary is kept while it's healthy,
+// so the writes aren't moved back when the failed node is back. Otherwise the nodes are checked
+// in the order HAProxy uses the backup nodes: the first node and then the nodes in reverse order.
+func electHAProxyPrimary(current string, pods []corev1.Pod, check func(pod string) error) (string, error) {
+	var candidates []string
+	for _, pod := range pods {
+		if k8s.IsPodReady(pod) {
+			candidates = append(candidates, pod.Name)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", errors.New("no ready pxc pods")
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		oi, oj := podOrdinal(candidates[i]), podOrdinal(candidates[j])
+		if oi == 0 || oj == 0 {
+			return oi == 0
+		}
+		return oi > oj
+	})
+	for i, pod := range candidates {
+		if pod == current {
+			candidates = append([]string{pod}, append(candidates[:i:i], candidates[i+1:]...)...)
+			break
+		}
+	}
+
+	var errs []string
+	for _, pod := range candidates {
+		if err := check(pod); err != nil {
+			errs = append(errs, pod+": "+err.Error())
+			continue
+		}
+		return pod, nil
+	}
+
+	return "", errors.Errorf("no healthy pxc pods: %s", strings.Join(errs, "; "))
+}
+
+func podOrdinal(pod string) int {
+	ordinal, err := strconv.Atoi(pod[strings.LastIndex(pod, "-")+1:])
+	if err != nil {
+		return -1
+	}
+	return ordinal
+}
+
+const (
+	haproxyServerStateDrain = "drain"
+	haproxyServerStateReady = "ready"
+	haproxyServerStateMaint = "maint"
+)
+
+const (
+	haproxySrvAdminMaint = 0x01 | 0x02 | 0x04 | 0x20 | 0x40
+	haproxySrvAdminDrain = 0x08 | 0x10
+)
+
+// haproxyWriterBackends are the backends of haproxy.cfg which route the connections to the writer node.
+var haproxyWriterBackends = []string{"galera-nodes", "galera-admin-nodes", "galera-mysqlx-nodes"}
+
+// haproxyServer is a server of the writer backends reported by "show servers state" of the runtime API.
+type haproxyServer struct {
+	backend    string
+	name       string
+	adminState int
+}
+
+func (s haproxyServer) state() string {
+	switch {
+	case s.adminState&haproxySrvAdminMaint != 0:
+		return haproxyServerStateMaint
+	case s.adminState&haproxySrvAdminDrain != 0:
+		return haproxyServerStateDrain
+	default:
+		return haproxyServerStateReady
+	}
+}
+
+// applyHAProxyPrimary makes the primary the only writer in every HAProxy pod through the runtime API.
+// The other pxc pods are put into maintenance in the writer backends, otherwise an HAProxy pod
+// would fail over to its backup servers by its own health checks. So HAProxy doesn't fail over
+// by itself: if the primary fails, the writes are refused until the next reconcile elects
+// another primary, and the writer isn't changed while the operator isn't running.
+// The draining pod doesn't get new connections, but its sessions aren't shut down.
+// The runtime state is reset when HAProxy reloads the configuration, so it's checked on each reconcile.
+func (r *ReconcilePerconaXtraDBCluster) applyHAProxyPrimary(ctx context.Context, cr *api.PerconaXtraDBCluster, primary, draining string) error {
+	pods, err := r.runningHAProxyPods(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for i := range pods {
+		pod := &pods[i]
+
+		servers, err := r.haproxyServers(pod)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		commands, err := haproxyPrimaryCommands(servers, primary, draining)
+		if err != nil {
+			errs = append(errs, pod.Name+": "+err.Error())
+			continue
+		}
+		if len(commands) == 0 {
+			continue
+		}
+
+		if _, err := r.haproxyRuntime(pod, commands...); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		logf.FromContext(ctx).Info("HAProxy writer is set", "pod", pod.Name, "primary", primary)
+
+		// the servers are changed by the runtime API only if the commands are valid for the current configuration
+		servers, err = r.haproxyServers(pod)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if commands, _ := haproxyPrimaryCommands(servers, primary, draining); len(commands) > 0 {
+			errs = append(errs, fmt.Sprintf("%s doesn't use %s as the only writer", pod.Name, primary))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// checkHAProxyWriter returns an error if an HAProxy pod doesn't route the new connections only to the primary.
+func (r *ReconcilePerconaXtraDBCluster) checkHAProxyWriter(ctx context.Context, cr *api.PerconaXtraDBCluster, primary, draining string) error {
+	pods, err := r.runningHAProxyPods(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	for i := range pods {
+		servers, err := r.haproxyServers(&pods[i])
+		if err != nil {
+			return err
+		}
+
+		commands, err := haproxyPrimaryCommands(servers, primary, draining)
+		if err != nil {
+			return errors.Wrap(err, pods[i].Name)
+		}
+		if len(commands) > 0 {
+			return errors.Errorf("%s doesn't use %s as the only writer", pods[i].Name, primary)
+		}
+	}
+
+	return nil
+}
+
+// haproxySessions returns the number of the sessions of the server in the writer backends of all HAProxy pods.
+func (r *ReconcilePerconaXtraDBCluster) haproxySessions(ctx context.Context, cr *api.PerconaXtraDBCluster, server string) (int, error) {
+	pods, err := r.runningHAProxyPods(ctx, cr)
+	if err != nil {
+		return 0, err
+	}
+
+	sessions := 0
+	for i := range pods {
+		out, err := r.haproxyRuntime(&pods[i], "show stat")
+		if err != nil {
+			return 0, err
+		}
+
+		n, err := parseHAProxySessions(out, server)
+		if err != nil {
+			return 0, errors.Wrap(err, pods[i].Name)
+		}
+		sessions += n
+	}
+
+	return sessions, nil
+}
+
+// parseHAProxySessions returns the current sessions of the server in the writer backends
+// from the CSV output of "show stat".
+func parseHAProxySessions(out, server string) (int, error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "# ") {
+		return 0, errors.New("no header in show stat output")
+	}
+
+	header := strings.Split(strings.TrimPrefix(lines[0], "# "), ",")
+	pxname, svname, scur := slices.Index(header, "pxname"), slices.Index(header, "svname"), slices.Index(header, "scur")
+	if pxname < 0 || svname < 0 || scur < 0 {
+		return 0, errors.New("unexpected header in show stat output")
+	}
+
+	sessions := 0
+	for _, line := range lines[1:] {
+		fields := strings.Split(line, ",")
+		if len(fields) <= max(pxname, svname, scur) {
+			continue
+		}
+		if fields[svname] != server || !slices.Contains(haproxyWriterBackends, fields[pxname]) {
+			continue
+		}
+
+		n, err := strconv.Atoi(fields[scur])
+		if err != nil {
+			return 0, errors.Wrapf(err, "parse sessions of %s/%s", fields[pxname], server)
+		}
+		sessions += n
+	}
+
+	return sessions, nil
+}
+
+// haproxyPrimaryCommands returns the runtime API commands which make the primary the only writer
+// and drain the draining server, the servers already in the required state aren't changed.
+func haproxyPrimaryCommands(servers []haproxyServer, primary, draining string) ([]string, error) {
+	found := false
+	var commands []string
+	for _, s := range servers {
+		state := haproxyServerStateMaint
+		switch s.name {
+		case primary:
+			found = true
+			state = haproxyServerStateReady
+		case draining:
+			state = haproxyServerStateDrain
+		}
+		if s.state() != state {
+			commands = append(commands, fmt.Sprintf("set server %s/%s state %s", s.backend, s.name, state))
+		}
+	}
+	if !found {
+		return nil, errors.Errorf("server %s is not configured", primary)
+	}
+
+	return commands, nil
+}
+
+// haproxyServers returns the servers of the writer backends of the HAProxy pod.
+func (r *ReconcilePerconaXtraDBCluster) haproxyServers(pod *corev1.Pod) ([]haproxyServer, error) {
+	out, err := r.haproxyRuntime(pod, "show servers state")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseHAProxyServers(out)
+}
+
+// parseHAProxyServers parses the output of "show servers state". The first line is the version
+// of the format and the second one is the header: be_id be_name srv_id srv_name srv_addr srv_op_state srv_admin_state ...
+func parseHAProxyServers(out string) ([]haproxyServer, error) {
+	var servers []haproxyServer
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 7 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if !slices.Contains(haproxyWriterBackends, fields[1]) {
+			continue
+		}
+
+		adminState, err := strconv.Atoi(fields[6])
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse admin state of %s/%s", fields[1], fields[3])
+		}
+		servers = append(servers, haproxyServer{backend: fields[1], name: fields[3], adminState: adminState})
+	}
+
+	return servers, nil
+}
+
+func (r *ReconcilePerconaXtraDBCluster) haproxyRuntime(pod *corev1.Pod, commands ...string) (string, error) {
+	var outb, errb bytes.Buffer
+	if err := r.clientcmd.Exec(pod, "pxc-monit", haproxyRuntimeCmd(commands...), nil, &outb, &errb, false); err != nil {
+		return "", errors.Wrapf(err, "run %q in %s: %s", strings.Join(commands, "; "), pod.Name, errb.String())
+	}
+
+	return outb.String(), nil
+}
+
+func (r *ReconcilePerconaXtraDBCluster) runningHAProxyPods(ctx context.Context, cr *api.PerconaXtraDBCluster) ([]corev1.Pod, error) {
+	podList := new(corev1.PodList)
+	if err := r.client.List(ctx, podList, client.InNamespace(cr.Namespace), client.MatchingLabels(naming.LabelsHAProxy(cr))); err != nil {
+		return nil, errors.Wrap(err, "list HAProxy pods")
+	}
+
+	pods := make([]corev1.Pod, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			pods = append(pods, pod)
+		}
+	}
+
+	return pods, nil
+}
+
+// haproxyRuntimeCmd returns the command which runs the commands of the runtime API of HAProxy.
+func haproxyRuntimeCmd(commands ...string) []string {
+	return []string{"/bin/bash", "-c", fmt.Sprintf("echo '%s' | socat stdio /etc/haproxy/pxc/haproxy.sock", strings.Join(commands, "; "))}
+}
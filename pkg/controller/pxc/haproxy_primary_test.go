@@ -0,0 +1,175 @@
+package pxc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestElectHAProxyPrimary(t *testing.T) {
+	pod := func(name string, ready bool) corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		current     string
+		pods        []corev1.Pod
+		unhealthy   []string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "first node",
+			pods:     []corev1.Pod{pod("cluster1-pxc-2", true), pod("cluster1-pxc-1", true), pod("cluster1-pxc-0", true)},
+			expected: "cluster1-pxc-0",
+		},
+		{
+			name:     "current primary is kept",
+			current:  "cluster1-pxc-1",
+			pods:     []corev1.Pod{pod("cluster1-pxc-0", true), pod("cluster1-pxc-1", true), pod("cluster1-pxc-2", true)},
+			expected: "cluster1-pxc-1",
+		},
+		{
+			name:     "backup nodes in reverse order",
+			current:  "cluster1-pxc-0",
+			pods:     []corev1.Pod{pod("cluster1-pxc-0", false), pod("cluster1-pxc-1", true), pod("cluster1-pxc-2", true), pod("cluster1-pxc-10", true)},
+			expected: "cluster1-pxc-10",
+		},
+		{
+			name:      "unhealthy nodes are skipped",
+			current:   "cluster1-pxc-2",
+			pods:      []corev1.Pod{pod("cluster1-pxc-0", true), pod("cluster1-pxc-1", true), pod("cluster1-pxc-2", true)},
+			unhealthy: []string{"cluster1-pxc-2", "cluster1-pxc-0"},
+			expected:  "cluster1-pxc-1",
+		},
+		{
+			name:        "no ready pods",
+			pods:        []corev1.Pod{pod("cluster1-pxc-0", false)},
+			expectedErr: "no ready pxc pods",
+		},
+		{
+			name:        "no healthy pods",
+			pods:        []corev1.Pod{pod("cluster1-pxc-0", true), pod("cluster1-pxc-1", true)},
+			unhealthy:   []string{"cluster1-pxc-0", "cluster1-pxc-1"},
+			expectedErr: "no healthy pxc pods: cluster1-pxc-0: node is Donor/Desynced; cluster1-pxc-1: node is Donor/Desynced",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, err := electHAProxyPrimary(tt.current, tt.pods, func(pod string) error {
+				for _, p := range tt.unhealthy {
+					if p == pod {
+						return errors.New("node is Donor/Desynced")
+					}
+				}
+				return nil
+			})
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, primary)
+		})
+	}
+}
+
+func TestParseHAProxyServers(t *testing.T) {
+	out := `1
+# be_id be_name srv_id srv_name srv_addr srv_op_state srv_admin_state srv_uweight srv_iweight srv_time_since_last_change srv_check_status srv_check_result srv_check_health srv_check_state srv_agent_state bk_f_forced_id srv_f_forced_id srv_fqdn srv_port srvrecord srv_use_ssl srv_check_port srv_check_addr srv_agent_addr srv_agent_port
+3 galera-nodes 1 cluster1-pxc-0 10.0.0.1 2 0 1 1 120 6 3 4 6 0 0 0 cluster1-pxc-0.cluster1-pxc.default 3306 - 0 0 - - 0
+3 galera-nodes 2 cluster1-pxc-1 10.0.0.2 2 1 1 1 120 6 3 4 6 0 0 0 cluster1-pxc-1.cluster1-pxc.default 3306 - 0 0 - - 0
+4 galera-admin-nodes 1 cluster1-pxc-1 10.0.0.2 2 8 1 1 120 6 3 4 6 0 0 0 cluster1-pxc-1.cluster1-pxc.default 33062 - 0 0 - - 0
+5 galera-replica-nodes 1 cluster1-pxc-1 10.0.0.2 2 0 1 1 120 6 3 4 6 0 0 0 cluster1-pxc-1.cluster1-pxc.default 3306 - 0 0 - - 0
+`
+
+	servers, err := parseHAProxyServers(out)
+	assert.NoError(t, err)
+	assert.Equal(t, []haproxyServer{
+		{backend: "galera-nodes", name: "cluster1-pxc-0", adminState: 0},
+		{backend: "galera-nodes", name: "cluster1-pxc-1", adminState: 1},
+		{backend: "galera-admin-nodes", name: "cluster1-pxc-1", adminState: 8},
+	}, servers)
+	assert.Equal(t, haproxyServerStateReady, servers[0].state())
+	assert.Equal(t, haproxyServerStateMaint, servers[1].state())
+	assert.Equal(t, haproxyServerStateDrain, servers[2].state())
+
+	_, err = parseHAProxyServers("1\n3 galera-nodes 1 cluster1-pxc-0 10.0.0.1 2 x 1")
+	assert.Error(t, err)
+}
+
+func TestHAProxyPrimaryCommands(t *testing.T) {
+	servers := []haproxyServer{
+		{backend: "galera-nodes", name: "cluster1-pxc-0"},
+		{backend: "galera-nodes", name: "cluster1-pxc-1", adminState: 1},
+		{backend: "galera-nodes", name: "cluster1-pxc-2"},
+		{backend: "galera-mysqlx-nodes", name: "cluster1-pxc-0"},
+		{backend: "galera-mysqlx-nodes", name: "cluster1-pxc-1", adminState: 1},
+	}
+
+	commands, err := haproxyPrimaryCommands(servers, "cluster1-pxc-1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"set server galera-nodes/cluster1-pxc-0 state maint",
+		"set server galera-nodes/cluster1-pxc-1 state ready",
+		"set server galera-nodes/cluster1-pxc-2 state maint",
+		"set server galera-mysqlx-nodes/cluster1-pxc-0 state maint",
+		"set server galera-mysqlx-nodes/cluster1-pxc-1 state ready",
+	}, commands)
+
+	commands, err = haproxyPrimaryCommands([]haproxyServer{
+		{backend: "galera-nodes", name: "cluster1-pxc-0"},
+		{backend: "galera-nodes", name: "cluster1-pxc-1", adminState: 1},
+	}, "cluster1-pxc-0", "")
+	assert.NoError(t, err)
+	assert.Empty(t, commands)
+
+	commands, err = haproxyPrimaryCommands(servers, "cluster1-pxc-1", "cluster1-pxc-0")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"set server galera-nodes/cluster1-pxc-0 state drain",
+		"set server galera-nodes/cluster1-pxc-1 state ready",
+		"set server galera-nodes/cluster1-pxc-2 state maint",
+		"set server galera-mysqlx-nodes/cluster1-pxc-0 state drain",
+		"set server galera-mysqlx-nodes/cluster1-pxc-1 state ready",
+	}, commands)
+
+	_, err = haproxyPrimaryCommands(servers, "cluster1-pxc-3", "")
+	assert.EqualError(t, err, "server cluster1-pxc-3 is not configured")
+}
+
+func TestParseHAProxySessions(t *testing.T) {
+	out := `# pxname,svname,qcur,qmax,scur,smax,slim
+galera-in,FRONTEND,,,3,10,
+galera-nodes,cluster1-pxc-0,0,0,2,5,
+galera-nodes,cluster1-pxc-1,0,0,1,5,
+galera-admin-nodes,cluster1-pxc-0,0,0,1,2,
+galera-replica-nodes,cluster1-pxc-0,0,0,7,9,
+galera-nodes,BACKEND,0,0,3,10,
+`
+
+	sessions, err := parseHAProxySessions(out, "cluster1-pxc-0")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, sessions)
+
+	sessions, err = parseHAProxySessions(out, "cluster1-pxc-2")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sessions)
+
+	_, err = parseHAProxySessions("galera-nodes,cluster1-pxc-0,0,0,2,5,", "cluster1-pxc-0")
+	assert.Error(t, err)
+}
@@ -2,17 +2,29 @@ package pxc
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/k8s"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app/binlogcollector"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/timeline"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/queries"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/users"
 )
 
 func (r *ReconcilePerconaXtraDBCluster) reconcileBinlogCollector(ctx context.Context, cr *api.PerconaXtraDBCluster) error {
@@ -54,3 +66,330 @@ func (r *ReconcilePerconaXtraDBCluster) reconcileBinlogCollector(ctx context.Con
 
 	return nil
 }
+
+// pitrCheckTimeout is the timeout of the PITR checks which run in the background.
+// Listing and pruning the binlogs can take a long time if the storage has many of them.
+const pitrCheckTimeout = 10 * time.Minute
+
+// pitrCheck is the state of the PITR check of the cluster which runs in the background.
+type pitrCheck struct {
+	mu      sync.Mutex
+	running bool
+	started time.Time
+	// result is the status reported by the last succeeded check
+	result *api.PITRStatus
+}
+
+// runPITRCheck starts the check in the background if the previous check of the cluster is
+// finished and the interval passed since it was started. The check gets a copy of the cluster,
+// so it doesn't block the reconcile. The result of the last succeeded check is returned.
+func runPITRCheck(ctx context.Context, checks *sync.Map, cr *api.PerconaXtraDBCluster, interval time.Duration, name string,
+	check func(ctx context.Context, cr *api.PerconaXtraDBCluster) (*api.PITRStatus, error),
+) *api.PITRStatus {
+	key := types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}.String()
+	v, _ := checks.LoadOrStore(key, new(pitrCheck))
+	c := v.(*pitrCheck)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.running && time.Since(c.started) >= interval {
+		c.running = true
+		c.started = time.Now()
+
+		cr := cr.DeepCopy()
+		go func() {
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), pitrCheckTimeout)
+			defer cancel()
+
+			result, err := check(ctx, cr)
+			if err != nil {
+				logf.FromContext(ctx).Error(err, "failed to check "+name)
+			}
+
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.running = false
+			if err == nil {
+				c.result = result
+			}
+		}()
+	}
+
+	return c.result
+}
+
+// reconcilePITRStorage reports the usage of the PITR storage in the status of the cluster.
+// The storage is checked in the background not more often than the pruning interval.
+func (r *ReconcilePerconaXtraDBCluster) reconcilePITRStorage(ctx context.Context, cr *api.PerconaXtraDBCluster) {
+	result := runPITRCheck(ctx, r.pitrStorageChecks, cr, cr.Spec.Backup.PITR.Pruning.GetInterval(), "PITR storage", r.checkPITRStorage)
+	if result == nil {
+		return
+	}
+
+	if cr.Status.PITR == nil {
+		cr.Status.PITR = new(api.PITRStatus)
+	}
+	status := cr.Status.PITR
+	status.StorageUsage = result.StorageUsage
+	status.Binlogs = result.Binlogs
+	status.OldestBinlogTime = result.OldestBinlogTime
+	if result.LastPruneTime != nil {
+		status.LastPruneTime = result.LastPruneTime
+		status.PrunedBinlogs = result.PrunedBinlogs
+	}
+}
+
+// checkPITRStorage prunes the binlogs which aren't needed to restore the existing backups
+// and returns the usage of the PITR storage.
+func (r *ReconcilePerconaXtraDBCluster) checkPITRStorage(ctx context.Context, cr *api.PerconaXtraDBCluster) (*api.PITRStatus, error) {
+	opts, err := storage.GetOptions(ctx, r.client, cr, cr.Spec.Backup.PITR.StorageName)
+	if err != nil {
+		return nil, errors.Wrap(err, "get storage options")
+	}
+	cli, err := r.newStorageClientFunc(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "new storage client")
+	}
+
+	binlogs, err := backup.ListBinlogs(ctx, cli)
+	if err != nil {
+		return nil, errors.Wrap(err, "list binlogs")
+	}
+
+	status := new(api.PITRStatus)
+	if cr.Spec.Backup.PITR.Pruning.IsEnabled() {
+		pruned, err := r.pruneBinlogs(ctx, cr, cli, binlogs)
+		if err != nil {
+			return nil, errors.Wrap(err, "prune binlogs")
+		}
+		binlogs = binlogs[pruned:]
+		status.PrunedBinlogs = pruned
+		status.LastPruneTime = &metav1.Time{Time: time.Now()}
+	}
+
+	size, err := backup.BinlogsSize(ctx, cli)
+	if err != nil {
+		return nil, errors.Wrap(err, "get size of binlogs")
+	}
+	status.StorageUsage = resource.NewQuantity(size, resource.BinarySI)
+	status.Binlogs = len(binlogs)
+	if len(binlogs) > 0 {
+		status.OldestBinlogTime = &metav1.Time{Time: binlogs[0].FirstEventTime}
+	}
+
+	return status, nil
+}
+
+// reconcilePITRTimeline reports the periods of time the cluster can be restored to
+// from the binlogs in the status of the cluster. The timeline is requested from the binlog
+// collector not more often than the collector uploads the binlogs.
+func (r *ReconcilePerconaXtraDBCluster) reconcilePITRTimeline(ctx context.Context, cr *api.PerconaXtraDBCluster) error {
+	if cr.CompareVersionWith("1.20.0") < 0 {
+		return nil
+	}
+
+	interval := time.Duration(cr.Spec.Backup.PITR.TimeBetweenUploads * float64(time.Second))
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+
+	key := types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}.String()
+	if last, ok := r.pitrTimelineChecks.Load(key); ok && time.Since(last.(time.Time)) < interval {
+		return nil
+	}
+	r.pitrTimelineChecks.Store(key, time.Now())
+
+	t, err := binlogcollector.GetTimeline(ctx, binlogcollector.TimelineURL(cr))
+	if err != nil {
+		return errors.Wrap(err, "get timeline from binlog collector")
+	}
+
+	if cr.Status.PITR == nil {
+		cr.Status.PITR = new(api.PITRStatus)
+	}
+	cr.Status.PITR.RestorableWindows = pitrRestorableWindows(t)
+	cr.Status.PITR.GapDetected = t.GapDetected
+
+	return nil
+}
+
+func pitrRestorableWindows(t *timeline.Timeline) []api.PITRRestorableWindow {
+	windows := make([]api.PITRRestorableWindow, 0, len(t.Windows))
+	for _, w := range t.Windows {
+		windows = append(windows, api.PITRRestorableWindow{
+			Start: metav1.NewTime(w.Start),
+			End:   metav1.NewTime(w.End),
+		})
+	}
+	return windows
+}
+
+// pitrRPOCheckInterval is the interval of the RPO checks of the cluster.
+const pitrRPOCheckInterval = 30 * time.Second
+
+// reconcilePITRRPO reports the recovery point objective of the point-in-time recovery
+// in the status of the cluster. The PITRDegraded condition is set if the RPO exceeds the threshold.
+// The RPO is checked in the background.
+func (r *ReconcilePerconaXtraDBCluster) reconcilePITRRPO(ctx context.Context, cr *api.PerconaXtraDBCluster) {
+	if cr.CompareVersionWith("1.20.0") < 0 {
+		return
+	}
+
+	result := runPITRCheck(ctx, r.pitrRPOChecks, cr, pitrRPOCheckInterval, "PITR RPO", r.checkPITRRPO)
+	if result == nil {
+		return
+	}
+
+	if cr.Status.PITR == nil {
+		cr.Status.PITR = new(api.PITRStatus)
+	}
+	status := cr.Status.PITR
+	status.SourceHost = result.SourceHost
+	status.LastUploadedEventTime = result.LastUploadedEventTime
+	status.RPO = result.RPO
+
+	if result.RPO != nil {
+		setPITRDegradedCondition(cr, result.RPO.Duration)
+	}
+}
+
+// checkPITRRPO compares the transactions executed on the host the binlog collector
+// collects the binlogs from with the last uploaded binlog.
+func (r *ReconcilePerconaXtraDBCluster) checkPITRRPO(ctx context.Context, cr *api.PerconaXtraDBCluster) (*api.PITRStatus, error) {
+	s, err := binlogcollector.GetStatus(ctx, binlogcollector.StatusURL(cr))
+	if err != nil {
+		return nil, errors.Wrap(err, "get status from binlog collector")
+	}
+
+	status := &api.PITRStatus{
+		SourceHost: s.Host,
+	}
+	if !s.LastUploadedEventTime.IsZero() {
+		status.LastUploadedEventTime = &metav1.Time{Time: s.LastUploadedEventTime}
+	}
+
+	// nothing is uploaded since the collector was started
+	if s.Host == "" || s.LastUploadedGTIDSet == "" {
+		return status, nil
+	}
+
+	db, err := queries.New(r.client, cr.Namespace, internalSecretsPrefix+cr.Name, users.Operator, pitrSourceAddr(cr, s.Host), 33062, cr.Spec.PXC.ReadinessProbes.TimeoutSeconds)
+	if err != nil {
+		return nil, errors.Wrapf(err, "connect to %s", s.Host)
+	}
+	defer db.Close()
+
+	executed, err := db.ReadVariable("gtid_executed")
+	if err != nil {
+		return nil, errors.Wrap(err, "get gtid_executed")
+	}
+
+	status.RPO = &metav1.Duration{Duration: pitrRPO(executed, s, time.Now())}
+
+	return status, nil
+}
+
+// clearPITRStatus removes the PITR status and the PITRDegraded condition
+// of the cluster and forgets the checks of the disabled PITR.
+func (r *ReconcilePerconaXtraDBCluster) clearPITRStatus(cr *api.PerconaXtraDBCluster) {
+	key := types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}.String()
+	r.pitrStorageChecks.Delete(key)
+	r.pitrTimelineChecks.Delete(key)
+	r.pitrRPOChecks.Delete(key)
+
+	cr.Status.PITR = nil
+	cr.Status.Conditions = slices.DeleteFunc(cr.Status.Conditions, func(c api.ClusterCondition) bool {
+		return c.Type == naming.ConditionPITRDegraded
+	})
+}
+
+// pitrSourceAddr returns the address of the host the binlog collector collects the binlogs from.
+// The collector reports the hosts of the cluster without the namespace.
+func pitrSourceAddr(cr *api.PerconaXtraDBCluster, host string) string {
+	podName, _, _ := strings.Cut(host, ".")
+	if !strings.HasPrefix(podName, cr.Name+"-pxc-") {
+		return host
+	}
+	return podName + "." + cr.Name + "-pxc." + cr.Namespace
+}
+
+// pitrRPO returns the age of the newest uploaded event if the executed transactions
+// aren't uploaded yet, otherwise nothing is lost and the RPO is zero.
+func pitrRPO(executed string, s *timeline.CollectorStatus, now time.Time) time.Duration {
+	if !timeline.HasTransactionsAfter(executed, s.LastUploadedGTIDSet) {
+		return 0
+	}
+	if s.LastUploadedEventTime.IsZero() || now.Before(s.LastUploadedEventTime) {
+		return 0
+	}
+	return now.Sub(s.LastUploadedEventTime).Truncate(time.Second)
+}
+
+// setPITRDegradedCondition sets the PITRDegraded condition if the threshold of the RPO is configured.
+func setPITRDegradedCondition(cr *api.PerconaXtraDBCluster, rpo time.Duration) {
+	threshold := cr.Spec.Backup.PITR.RPOThreshold
+	if threshold == nil {
+		return
+	}
+
+	c := api.ClusterCondition{
+		Type:    naming.ConditionPITRDegraded,
+		Status:  api.ConditionFalse,
+		Reason:  "RPOWithinThreshold",
+		Message: fmt.Sprintf("RPO %s is within the threshold %s", rpo, threshold.Duration),
+	}
+	if rpo > threshold.Duration {
+		c.Status = api.ConditionTrue
+		c.Reason = "RPOThresholdExceeded"
+		c.Message = fmt.Sprintf("RPO %s exceeds the threshold %s", rpo, threshold.Duration)
+	}
+
+	condition := cr.Status.FindCondition(naming.ConditionPITRDegraded)
+	if condition == nil {
+		c.LastTransitionTime = metav1.NewTime(time.Now().Truncate(time.Second))
+		cr.Status.AddCondition(c)
+		return
+	}
+
+	if condition.Status != c.Status {
+		condition.LastTransitionTime = metav1.NewTime(time.Now().Truncate(time.Second))
+	}
+	condition.Status = c.Status
+	condition.Reason = c.Reason
+	condition.Message = c.Message
+}
+
+// pruneBinlogs deletes the oldest binlogs which aren't needed to restore the backups of the cluster
+// and returns the number of deleted binlogs.
+func (r *ReconcilePerconaXtraDBCluster) pruneBinlogs(ctx context.Context, cr *api.PerconaXtraDBCluster, cli storage.Storage, binlogs []backup.Binlog) (int, error) {
+	log := logf.FromContext(ctx)
+
+	bcpList := api.PerconaXtraDBClusterBackupList{}
+	if err := r.client.List(ctx, &bcpList, &client.ListOptions{Namespace: cr.Namespace}); err != nil {
+		return 0, errors.Wrap(err, "get backup objects")
+	}
+	backups := make([]api.PerconaXtraDBClusterBackup, 0, len(bcpList.Items))
+	for _, bcp := range bcpList.Items {
+		if bcp.Spec.PXCCluster == cr.Name {
+			backups = append(backups, bcp)
+		}
+	}
+
+	cutoff, ok := backup.PITRCutoff(backups, cr.Spec.Backup.PITR.Pruning.GetSafetyMargin())
+	if !ok {
+		return 0, nil
+	}
+
+	toPrune := backup.BinlogsToPrune(binlogs, cutoff)
+	if len(toPrune) == 0 {
+		return 0, nil
+	}
+
+	log.Info("Pruning binlogs", "count", len(toPrune), "cutoff", cutoff)
+	if err := backup.PruneBinlogs(ctx, cli, toPrune); err != nil {
+		return 0, err
+	}
+
+	return len(toPrune), nil
+}
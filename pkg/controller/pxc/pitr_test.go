@@ -0,0 +1,110 @@
+package pxc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/timeline"
+)
+
+func TestPITRRPO(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 10, 0, 0, time.UTC)
+	s := &timeline.CollectorStatus{
+		LastUploadedGTIDSet:   "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-200",
+		LastUploadedEventTime: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	assert.Equal(t, time.Duration(0), pitrRPO("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-200", s, now))
+	assert.Equal(t, 10*time.Minute, pitrRPO("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-201", s, now))
+}
+
+func TestSetPITRDegradedCondition(t *testing.T) {
+	cr := &api.PerconaXtraDBCluster{
+		Spec: api.PerconaXtraDBClusterSpec{
+			Backup: &api.BackupSpec{},
+		},
+	}
+
+	setPITRDegradedCondition(cr, time.Hour)
+	assert.Nil(t, cr.Status.FindCondition(naming.ConditionPITRDegraded))
+
+	cr.Spec.Backup.PITR.RPOThreshold = &metav1.Duration{Duration: 5 * time.Minute}
+	setPITRDegradedCondition(cr, time.Minute)
+	c := cr.Status.FindCondition(naming.ConditionPITRDegraded)
+	assert.NotNil(t, c)
+	assert.Equal(t, api.ConditionFalse, c.Status)
+
+	setPITRDegradedCondition(cr, 10*time.Minute)
+	c = cr.Status.FindCondition(naming.ConditionPITRDegraded)
+	assert.Equal(t, api.ConditionTrue, c.Status)
+	assert.Equal(t, "RPOThresholdExceeded", c.Reason)
+	assert.Len(t, cr.Status.Conditions, 1)
+}
+
+func TestRunPITRCheck(t *testing.T) {
+	ctx := context.Background()
+	cr := &api.PerconaXtraDBCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1",
+			Namespace: "test",
+		},
+	}
+	checks := new(sync.Map)
+
+	calls := make(chan struct{}, 10)
+	release := make(chan struct{})
+	check := func(ctx context.Context, cr *api.PerconaXtraDBCluster) (*api.PITRStatus, error) {
+		calls <- struct{}{}
+		<-release
+		return &api.PITRStatus{SourceHost: cr.Name}, nil
+	}
+
+	// the check runs in the background and the result isn't known yet
+	assert.Nil(t, runPITRCheck(ctx, checks, cr, 0, "test", check))
+	<-calls
+	// the running check isn't started again
+	assert.Nil(t, runPITRCheck(ctx, checks, cr, 0, "test", check))
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		v, _ := checks.Load("test/cluster1")
+		c := v.(*pitrCheck)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return !c.running
+	}, time.Second, 10*time.Millisecond)
+	assert.Len(t, calls, 0)
+
+	result := runPITRCheck(ctx, checks, cr, time.Hour, "test", check)
+	assert.Equal(t, &api.PITRStatus{SourceHost: "cluster1"}, result)
+	assert.Len(t, calls, 0)
+}
+
+func TestClearPITRStatus(t *testing.T) {
+	r := &ReconcilePerconaXtraDBCluster{
+		pitrStorageChecks:  new(sync.Map),
+		pitrTimelineChecks: new(sync.Map),
+		pitrRPOChecks:      new(sync.Map),
+	}
+	cr := &api.PerconaXtraDBCluster{}
+	cr.Status.PITR = &api.PITRStatus{Binlogs: 10}
+	cr.Status.Conditions = []api.ClusterCondition{
+		{Type: api.AppStateReady, Status: api.ConditionTrue},
+		{Type: naming.ConditionPITRDegraded, Status: api.ConditionTrue},
+	}
+	r.pitrRPOChecks.Store("/", new(pitrCheck))
+
+	r.clearPITRStatus(cr)
+	assert.Nil(t, cr.Status.PITR)
+	assert.Nil(t, cr.Status.FindCondition(naming.ConditionPITRDegraded))
+	assert.NotNil(t, cr.Status.FindCondition(api.AppStateReady))
+	_, ok := r.pitrRPOChecks.Load("/")
+	assert.False(t, ok)
+}
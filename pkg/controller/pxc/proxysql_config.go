@@ -0,0 +1,292 @@
+package pxc
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/queries"
+)
+
+// reconcileProxySQLConfig keeps the query rules and the user options of the cluster spec in every ProxySQL instance.
+// The spec is compared with the runtime configuration of the instances on every reconcile,
+// so the changes made through the admin interface and the configuration lost on restart are reverted.
+// The errors of the instances are reported in the status.
+func (r *ReconcilePerconaXtraDBCluster) reconcileProxySQLConfig(ctx context.Context, cr *api.PerconaXtraDBCluster) {
+	log := logf.FromContext(ctx)
+
+	if !cr.ProxySQLEnabled() || cr.CompareVersionWith("1.20.0") < 0 {
+		cr.Status.ProxySQLConfig = nil
+		return
+	}
+
+	rules := proxySQLQueryRules(cr.Spec.ProxySQL.QueryRules)
+	users := proxySQLUsers(cr.Spec.ProxySQL.Users)
+
+	prev := cr.Status.ProxySQLConfig
+	if prev == nil {
+		prev = new(api.ProxySQLConfigStatus)
+	}
+
+	// the rules removed from the spec are deleted until every instance is synced
+	ruleIDs := make([]int, 0, len(rules))
+	for _, rule := range rules {
+		ruleIDs = append(ruleIDs, rule.RuleID)
+	}
+	managedIDs := mergeRuleIDs(ruleIDs, prev.QueryRules)
+
+	if len(managedIDs) == 0 && len(users) == 0 {
+		cr.Status.ProxySQLConfig = nil
+		return
+	}
+
+	if cr.Status.ProxySQL.Status != api.AppStateReady {
+		return
+	}
+
+	usernames := make([]string, 0, len(users))
+	for _, user := range users {
+		usernames = append(usernames, user.Username)
+	}
+
+	hash, err := proxySQLConfigHash(cr.Spec.ProxySQL)
+	if err != nil {
+		log.Error(err, "failed to get hash of ProxySQL configuration")
+		return
+	}
+
+	status := &api.ProxySQLConfigStatus{
+		QueryRules:   managedIDs,
+		Users:        usernames,
+		Hash:         prev.Hash,
+		Drifted:      prev.Drifted,
+		LastSyncTime: prev.LastSyncTime,
+	}
+
+	changed := false
+	var drifted, errs []string
+	for i := 0; i < int(cr.Spec.ProxySQL.Size); i++ {
+		pod := fmt.Sprintf("%s-proxysql-%d", cr.Name, i)
+
+		podChanged, err := r.syncProxySQLConfig(ctx, cr, i, managedIDs, rules, users)
+		if err != nil {
+			errs = append(errs, pod+": "+err.Error())
+			continue
+		}
+		if !podChanged {
+			continue
+		}
+
+		changed = true
+		// the configuration which differs from the already synced spec was changed outside of the operator
+		if prev.Hash == hash {
+			log.Info("ProxySQL configuration drift is reverted", "pod", pod)
+			drifted = append(drifted, pod)
+		} else {
+			log.Info("ProxySQL configuration is synced", "pod", pod)
+		}
+	}
+
+	if changed {
+		now := metav1.Now()
+		status.Drifted = drifted
+		status.LastSyncTime = &now
+	}
+	status.Synced = len(errs) == 0
+	status.Message = strings.Join(errs, "; ")
+	if status.Synced {
+		status.QueryRules = mergeRuleIDs(ruleIDs, nil)
+		status.Hash = hash
+	}
+
+	cr.Status.ProxySQLConfig = status
+}
+
+// syncProxySQLConfig updates the configuration of the ProxySQL instance if it differs from the spec.
+// It returns true if the configuration was changed.
+func (r *ReconcilePerconaXtraDBCluster) syncProxySQLConfig(
+	ctx context.Context,
+	cr *api.PerconaXtraDBCluster,
+	i int,
+	managedIDs []int,
+	rules []queries.ProxySQLQueryRule,
+	users []queries.ProxySQLUser,
+) (bool, error) {
+	db, err := r.connectToProxySQLPod(cr, i)
+	if err != nil {
+		return false, err
+	}
+	defer db.Close()
+
+	changed := false
+
+	actualRules, err := db.ProxySQLQueryRules(ctx, managedIDs)
+	if err != nil {
+		return false, errors.Wrap(err, "get query rules")
+	}
+	if !queryRulesEqual(rules, actualRules) {
+		if err := db.SetProxySQLQueryRules(ctx, managedIDs, rules); err != nil {
+			return false, errors.Wrap(err, "set query rules")
+		}
+		changed = true
+	}
+
+	usernames := make([]string, 0, len(users))
+	for _, user := range users {
+		usernames = append(usernames, user.Username)
+	}
+	actualUsers, err := db.ProxySQLUsers(ctx, usernames)
+	if err != nil {
+		return changed, errors.Wrap(err, "get users")
+	}
+	outdated, err := outdatedProxySQLUsers(users, actualUsers)
+	if err != nil {
+		return changed, errors.Wrap(err, "check users")
+	}
+	if len(outdated) > 0 {
+		if err := db.UpdateProxySQLUsers(ctx, outdated); err != nil {
+			return changed, errors.Wrap(err, "update users")
+		}
+		changed = true
+	}
+
+	return changed, nil
+}
+
+func proxySQLConfigHash(spec *api.ProxySQLSpec) (string, error) {
+	data, err := json.Marshal(struct {
+		QueryRules []api.ProxySQLQueryRule `json:"queryRules,omitempty"`
+		Users      []api.ProxySQLUser      `json:"users,omitempty"`
+	}{spec.QueryRules, spec.Users})
+	if err != nil {
+		return "", errors.Wrap(err, "marshal configuration")
+	}
+	return fmt.Sprintf("%x", md5.Sum(data)), nil
+}
+
+func proxySQLQueryRules(specRules []api.ProxySQLQueryRule) []queries.ProxySQLQueryRule {
+	optString := func(s string) *string {
+		if s == "" {
+			return nil
+		}
+		return &s
+	}
+
+	rules := make([]queries.ProxySQLQueryRule, 0, len(specRules))
+	for _, r := range specRules {
+		rule := queries.ProxySQLQueryRule{
+			RuleID:               r.RuleID,
+			Active:               r.Active == nil || *r.Active,
+			Username:             optString(r.Username),
+			SchemaName:           optString(r.SchemaName),
+			FlagIn:               r.FlagIn,
+			MatchDigest:          optString(r.MatchDigest),
+			MatchPattern:         optString(r.MatchPattern),
+			NegateMatchPattern:   r.NegateMatchPattern,
+			FlagOut:              r.FlagOut,
+			DestinationHostgroup: r.DestinationHostgroup,
+			CacheTTL:             r.CacheTTL,
+			MirrorHostgroup:      r.MirrorHostgroup,
+			Multiplex:            r.Multiplex,
+			Apply:                r.Apply,
+			Comment:              optString(r.Comment),
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].RuleID < rules[j].RuleID
+	})
+
+	return rules
+}
+
+func proxySQLUsers(specUsers []api.ProxySQLUser) []queries.ProxySQLUser {
+	users := make([]queries.ProxySQLUser, 0, len(specUsers))
+	for _, u := range specUsers {
+		users = append(users, queries.ProxySQLUser{
+			Username:              u.Username,
+			DefaultHostgroup:      u.DefaultHostgroup,
+			TransactionPersistent: u.TransactionPersistent,
+			FastForward:           u.FastForward,
+			MaxConnections:        u.MaxConnections,
+			Active:                u.Active,
+		})
+	}
+	return users
+}
+
+// queryRulesEqual compares the rules ordered by the ID.
+func queryRulesEqual(expected, actual []queries.ProxySQLQueryRule) bool {
+	if len(expected) == 0 && len(actual) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
+// outdatedProxySQLUsers returns the users whose options differ from the expected ones.
+// It returns an error if some users aren't found in ProxySQL.
+func outdatedProxySQLUsers(expected, actual []queries.ProxySQLUser) ([]queries.ProxySQLUser, error) {
+	actualUsers := make(map[string]queries.ProxySQLUser, len(actual))
+	for _, user := range actual {
+		actualUsers[user.Username] = user
+	}
+
+	var outdated []queries.ProxySQLUser
+	var missing []string
+	for _, user := range expected {
+		a, ok := actualUsers[user.Username]
+		if !ok {
+			missing = append(missing, user.Username)
+			continue
+		}
+
+		if !optionEqual(user.DefaultHostgroup, a.DefaultHostgroup) ||
+			!optionEqual(user.TransactionPersistent, a.TransactionPersistent) ||
+			!optionEqual(user.FastForward, a.FastForward) ||
+			!optionEqual(user.MaxConnections, a.MaxConnections) ||
+			!optionEqual(user.Active, a.Active) {
+			outdated = append(outdated, user)
+		}
+	}
+
+	if len(missing) > 0 {
+		return outdated, errors.Errorf("users %s aren't found in ProxySQL, the users are synced from PXC", strings.Join(missing, ", "))
+	}
+
+	return outdated, nil
+}
+
+// optionEqual returns true if the option isn't set or it's equal to the actual value.
+func optionEqual[T comparable](expected, actual *T) bool {
+	if expected == nil {
+		return true
+	}
+	return actual != nil && *expected == *actual
+}
+
+func mergeRuleIDs(a, b []int) []int {
+	ids := make(map[int]struct{}, len(a)+len(b))
+	for _, id := range append(append([]int{}, a...), b...) {
+		ids[id] = struct{}{}
+	}
+
+	merged := make([]int, 0, len(ids))
+	for id := range ids {
+		merged = append(merged, id)
+	}
+	sort.Ints(merged)
+
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
@@ -0,0 +1,158 @@
+package pxc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/queries"
+)
+
+func TestProxySQLQueryRulesEqual(t *testing.T) {
+	specRules := []api.ProxySQLQueryRule{
+		{
+			RuleID:               200,
+			MatchDigest:          "^SELECT",
+			DestinationHostgroup: ptr.To(10),
+			Apply:                true,
+		},
+		{
+			RuleID:               100,
+			Active:               ptr.To(false),
+			MatchDigest:          "^SELECT.*FOR UPDATE",
+			DestinationHostgroup: ptr.To(11),
+			Apply:                true,
+			Comment:              "writes",
+		},
+	}
+	rules := proxySQLQueryRules(specRules)
+
+	runtimeRules := func() []queries.ProxySQLQueryRule {
+		return []queries.ProxySQLQueryRule{
+			{
+				RuleID:               100,
+				Active:               false,
+				MatchDigest:          ptr.To("^SELECT.*FOR UPDATE"),
+				DestinationHostgroup: ptr.To(11),
+				Apply:                true,
+				Comment:              ptr.To("writes"),
+			},
+			{
+				RuleID:               200,
+				Active:               true,
+				MatchDigest:          ptr.To("^SELECT"),
+				DestinationHostgroup: ptr.To(10),
+				Apply:                true,
+			},
+		}
+	}
+
+	assert.True(t, queryRulesEqual(rules, runtimeRules()))
+	assert.True(t, queryRulesEqual(proxySQLQueryRules(nil), nil))
+
+	changed := runtimeRules()
+	changed[1].DestinationHostgroup = ptr.To(11)
+	assert.False(t, queryRulesEqual(rules, changed))
+
+	assert.False(t, queryRulesEqual(rules, runtimeRules()[:1]))
+
+	removed := proxySQLQueryRules(specRules[:1])
+	assert.False(t, queryRulesEqual(removed, runtimeRules()))
+}
+
+func TestOutdatedProxySQLUsers(t *testing.T) {
+	actual := []queries.ProxySQLUser{
+		{
+			Username:              "app",
+			DefaultHostgroup:      ptr.To(11),
+			TransactionPersistent: ptr.To(true),
+			FastForward:           ptr.To(false),
+			MaxConnections:        ptr.To(10000),
+			Active:                ptr.To(true),
+		},
+		{
+			Username:              "reports",
+			DefaultHostgroup:      ptr.To(10),
+			TransactionPersistent: ptr.To(true),
+			FastForward:           ptr.To(false),
+			MaxConnections:        ptr.To(100),
+			Active:                ptr.To(true),
+		},
+	}
+
+	t.Run("synced", func(t *testing.T) {
+		outdated, err := outdatedProxySQLUsers(proxySQLUsers([]api.ProxySQLUser{
+			{Username: "app"},
+			{Username: "reports", DefaultHostgroup: ptr.To(10), MaxConnections: ptr.To(100)},
+		}), actual)
+		require.NoError(t, err)
+		assert.Empty(t, outdated)
+	})
+
+	t.Run("outdated", func(t *testing.T) {
+		expected := proxySQLUsers([]api.ProxySQLUser{
+			{Username: "app", DefaultHostgroup: ptr.To(11)},
+			{Username: "reports", DefaultHostgroup: ptr.To(10), MaxConnections: ptr.To(50)},
+		})
+		outdated, err := outdatedProxySQLUsers(expected, actual)
+		require.NoError(t, err)
+		assert.Equal(t, expected[1:], outdated)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		expected := proxySQLUsers([]api.ProxySQLUser{
+			{Username: "app", FastForward: ptr.To(true)},
+			{Username: "etl", DefaultHostgroup: ptr.To(10)},
+		})
+		outdated, err := outdatedProxySQLUsers(expected, actual)
+		assert.EqualError(t, err, "users etl aren't found in ProxySQL, the users are synced from PXC")
+		assert.Equal(t, expected[:1], outdated)
+	})
+}
+
+func TestReconcileProxySQLConfig(t *testing.T) {
+	ctx := context.Background()
+
+	cr, err := readDefaultCR("cluster1", "test")
+	require.NoError(t, err)
+	cr.Spec.HAProxy.Enabled = false
+	cr.Spec.ProxySQL.Enabled = true
+
+	t.Run("nothing to manage", func(t *testing.T) {
+		cr := cr.DeepCopy()
+		cr.Status.ProxySQLConfig = &api.ProxySQLConfigStatus{Synced: true}
+
+		buildFakeClient(nil).reconcileProxySQLConfig(ctx, cr)
+		assert.Nil(t, cr.Status.ProxySQLConfig)
+	})
+
+	t.Run("ProxySQL is disabled", func(t *testing.T) {
+		cr := cr.DeepCopy()
+		cr.Spec.ProxySQL.Enabled = false
+		cr.Spec.ProxySQL.QueryRules = []api.ProxySQLQueryRule{{RuleID: 100}}
+		cr.Status.ProxySQLConfig = &api.ProxySQLConfigStatus{QueryRules: []int{100}, Synced: true}
+
+		buildFakeClient(nil).reconcileProxySQLConfig(ctx, cr)
+		assert.Nil(t, cr.Status.ProxySQLConfig)
+	})
+
+	t.Run("ProxySQL is not ready", func(t *testing.T) {
+		cr := cr.DeepCopy()
+		cr.Spec.ProxySQL.QueryRules = []api.ProxySQLQueryRule{{RuleID: 100}}
+		status := &api.ProxySQLConfigStatus{QueryRules: []int{100, 200}, Synced: true}
+		cr.Status.ProxySQLConfig = status.DeepCopy()
+		cr.Status.ProxySQL.Status = api.AppStateInit
+
+		buildFakeClient(nil).reconcileProxySQLConfig(ctx, cr)
+		assert.Equal(t, status, cr.Status.ProxySQLConfig)
+	})
+}
+
+func TestMergeRuleIDs(t *testing.T) {
+	assert.Equal(t, []int{1, 2, 5, 10}, mergeRuleIDs([]int{10, 2}, []int{5, 1, 2}))
+	assert.Nil(t, mergeRuleIDs(nil, nil))
+}
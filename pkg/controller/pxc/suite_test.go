@@ -3,6 +3,7 @@ package pxc
 import (
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	cmscheme "github.com/cert-manager/cert-manager/pkg/client/clientset/versioned/scheme"
@@ -19,6 +20,7 @@ import (
 	"github.com/percona/percona-xtradb-cluster-operator/clientcmd"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/apis"
 	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/version"
 )
 
@@ -87,6 +89,11 @@ func reconciler() *ReconcilePerconaXtraDBCluster {
 		serverVersion: &version.ServerVersion{
 			Platform: version.PlatformKubernetes,
 		},
+		newStorageClientFunc: storage.NewClient,
+		backupCatalogScans:   new(sync.Map),
+		pitrStorageChecks:    new(sync.Map),
+		pitrTimelineChecks:   new(sync.Map),
+		pitrRPOChecks:        new(sync.Map),
 	})
 }
 
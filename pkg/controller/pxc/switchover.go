@@ -0,0 +1,320 @@
+package pxc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/k8s"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/queries"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/users"
+)
+
+// switchoverTimeout is the time the target has to catch up with the cluster
+// and the proxies have to route the writes to the target.
+const switchoverTimeout = 10 * time.Minute
+
+// reconcileSwitchover moves the writer node to the pod requested by the percona.com/switchover-to annotation.
+// The writer is moved only when the target is synced and has applied all received write-sets.
+// HAProxy pods get the target through the runtime API and from the primary config map,
+// the previous writer is drained in every HAProxy pod until its sessions are finished.
+// ProxySQL gets the target as the preferred writer of pxc_scheduler_handler
+// and the previous writer is drained by pxc_maint_mode until ProxySQL routes the writes to the target.
+func (r *ReconcilePerconaXtraDBCluster) reconcileSwitchover(ctx context.Context, cr *api.PerconaXtraDBCluster) error {
+	log := logf.FromContext(ctx)
+
+	target, ok := cr.Annotations[api.AnnotationSwitchoverTo]
+	if !ok {
+		return nil
+	}
+
+	status := cr.Status.Switchover
+	if status == nil || status.Target != target || (status.State != api.SwitchoverPending && status.State != api.SwitchoverSwitching) {
+		now := metav1.Now()
+		status = &api.SwitchoverStatus{
+			Target:    target,
+			State:     api.SwitchoverPending,
+			StartedAt: &now,
+		}
+		cr.Status.Switchover = status
+		log.Info("Switchover is started", "target", target)
+	}
+
+	if err := validateSwitchover(cr); err != nil {
+		return r.finishSwitchover(ctx, cr, api.SwitchoverFailed, err.Error())
+	}
+
+	pod := new(corev1.Pod)
+	err := r.client.Get(ctx, types.NamespacedName{Name: target, Namespace: cr.Namespace}, pod)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrap(err, "get target pod")
+	}
+	if k8serrors.IsNotFound(err) || !labels.SelectorFromSet(naming.LabelsPXC(cr)).Matches(labels.Set(pod.Labels)) {
+		return r.finishSwitchover(ctx, cr, api.SwitchoverFailed, fmt.Sprintf("pod %s is not a pxc pod of the cluster", target))
+	}
+
+	if status.State == api.SwitchoverSwitching {
+		return r.checkSwitchover(ctx, cr)
+	}
+
+	return r.switchWriter(ctx, cr, pod)
+}
+
+func validateSwitchover(cr *api.PerconaXtraDBCluster) error {
+	if cr.CompareVersionWith("1.20.0") < 0 {
+		return errors.New("switchover requires crVersion 1.20.0 or newer")
+	}
+
+	switch {
+	case cr.HAProxyEnabled():
+		if !cr.HAProxyPrimarySelectedByOperator() {
+			return errors.New("switchover with HAProxy requires haproxy.primarySelection set to operator")
+		}
+	case cr.ProxySQLEnabled():
+		if !cr.Spec.ProxySQL.Scheduler.Enabled {
+			return errors.New("switchover with ProxySQL requires proxysql.scheduler.enabled")
+		}
+	default:
+		return errors.New("switchover requires HAProxy or ProxySQL")
+	}
+
+	return nil
+}
+
+// switchWriter waits for the target to catch up with the cluster and moves the writer to the target.
+func (r *ReconcilePerconaXtraDBCluster) switchWriter(ctx context.Context, cr *api.PerconaXtraDBCluster, pod *corev1.Pod) error {
+	log := logf.FromContext(ctx)
+	status := cr.Status.Switchover
+
+	current, err := r.currentWriter(cr)
+	if err != nil {
+		return r.waitSwitchover(ctx, cr, "get current writer: "+err.Error())
+	}
+	if current == status.Target {
+		return r.finishSwitchover(ctx, cr, api.SwitchoverSucceeded, fmt.Sprintf("pod %s is already the writer", status.Target))
+	}
+	status.Previous = current
+
+	if !k8s.IsPodReady(*pod) {
+		return r.waitSwitchover(ctx, cr, "target pod is not ready")
+	}
+	if err := r.checkPrimaryCandidate(ctx, cr, status.Target); err != nil {
+		return r.waitSwitchover(ctx, cr, "target can't be the writer: "+err.Error())
+	}
+
+	queue, err := r.recvQueue(ctx, cr, status.Target)
+	if err != nil {
+		return r.waitSwitchover(ctx, cr, "get target receive queue: "+err.Error())
+	}
+	if queue > 0 {
+		return r.waitSwitchover(ctx, cr, "target receive queue is "+strconv.Itoa(queue))
+	}
+
+	if cr.HAProxyEnabled() {
+		if err := r.setHAProxyPrimary(ctx, cr, status.Target); err != nil {
+			return errors.Wrap(err, "set HAProxy primary")
+		}
+		// the pods which aren't switched now are switched on the next reconciles of the primary
+		if err := r.applyHAProxyPrimary(ctx, cr, status.Target, status.Previous); err != nil {
+			log.Info("Failed to switch writer in HAProxy pods", "reason", err.Error())
+		}
+	} else {
+		if err := r.setProxySQLWriter(ctx, cr, status.Target); err != nil {
+			return r.waitSwitchover(ctx, cr, "set ProxySQL writer: "+err.Error())
+		}
+		if err := r.setMaintMode(ctx, cr, status.Previous, "MAINTENANCE"); err != nil {
+			return r.waitSwitchover(ctx, cr, "drain previous writer: "+err.Error())
+		}
+	}
+
+	status.State = api.SwitchoverSwitching
+	status.Message = "waiting for the proxies to route the writes to the target"
+	log.Info("Writer is switched", "previous", status.Previous, "target", status.Target)
+
+	return nil
+}
+
+// checkSwitchover finishes the switchover when the proxies route the writes to the target.
+func (r *ReconcilePerconaXtraDBCluster) checkSwitchover(ctx context.Context, cr *api.PerconaXtraDBCluster) error {
+	status := cr.Status.Switchover
+
+	if cr.HAProxyEnabled() {
+		if cr.Status.HAProxyPrimary != status.Target {
+			return r.finishSwitchover(ctx, cr, api.SwitchoverFailed,
+				fmt.Sprintf("pod %s is elected as HAProxy primary instead of the target", cr.Status.HAProxyPrimary))
+		}
+
+		if err := r.checkHAProxyWriter(ctx, cr, status.Target, status.Previous); err != nil {
+			return r.waitSwitchover(ctx, cr, "check HAProxy writer: "+err.Error())
+		}
+
+		sessions, err := r.haproxySessions(ctx, cr, status.Previous)
+		if err != nil {
+			return r.waitSwitchover(ctx, cr, "get HAProxy sessions of previous writer: "+err.Error())
+		}
+		if sessions > 0 {
+			// the writes are already routed to the target, the remaining sessions are shut down
+			// when the previous writer is put into maintenance after the switchover
+			if status.StartedAt != nil && time.Since(status.StartedAt.Time) > switchoverTimeout {
+				return r.finishSwitchover(ctx, cr, api.SwitchoverSucceeded,
+					fmt.Sprintf("%d sessions of the previous writer weren't finished in time", sessions))
+			}
+			return r.waitSwitchover(ctx, cr, fmt.Sprintf("waiting for %d sessions of the previous writer to finish", sessions))
+		}
+	} else {
+		for i := 0; i < int(cr.Spec.ProxySQL.Size); i++ {
+			host, err := r.proxySQLPodWriter(cr, i)
+			if err != nil {
+				return r.waitSwitchover(ctx, cr, "get ProxySQL writer: "+err.Error())
+			}
+			if host != status.Target {
+				return r.waitSwitchover(ctx, cr, fmt.Sprintf("%s-proxysql-%d routes the writes to %s", cr.Name, i, host))
+			}
+		}
+	}
+
+	return r.finishSwitchover(ctx, cr, api.SwitchoverSucceeded, "")
+}
+
+// waitSwitchover keeps the switchover in the current state until the timeout is reached.
+func (r *ReconcilePerconaXtraDBCluster) waitSwitchover(ctx context.Context, cr *api.PerconaXtraDBCluster, message string) error {
+	status := cr.Status.Switchover
+	if status.StartedAt != nil && time.Since(status.StartedAt.Time) > switchoverTimeout {
+		return r.finishSwitchover(ctx, cr, api.SwitchoverFailed, "timeout: "+message)
+	}
+
+	if status.Message != message {
+		logf.FromContext(ctx).Info("Waiting for switchover", "target", status.Target, "reason", message)
+	}
+	status.Message = message
+
+	return nil
+}
+
+// finishSwitchover saves the outcome of the switchover and removes the annotation.
+func (r *ReconcilePerconaXtraDBCluster) finishSwitchover(ctx context.Context, cr *api.PerconaXtraDBCluster, state api.SwitchoverState, message string) error {
+	log := logf.FromContext(ctx)
+	status := cr.Status.Switchover
+
+	if status.State == api.SwitchoverSwitching && cr.ProxySQLEnabled() && status.Previous != "" {
+		// pxc_maint_mode isn't persisted, the restarted node is back in DISABLED mode
+		if err := r.setMaintMode(ctx, cr, status.Previous, "DISABLED"); err != nil {
+			log.Error(err, "failed to return previous writer to the cluster", "pod", status.Previous)
+		}
+	}
+
+	now := metav1.Now()
+	status.State = state
+	status.Message = message
+	status.CompletedAt = &now
+	log.Info("Switchover is finished", "target", status.Target, "state", state, "message", message)
+
+	if err := k8s.DeannotateObject(ctx, r.client, cr, api.AnnotationSwitchoverTo); err != nil {
+		return errors.Wrap(err, "remove switchover annotation")
+	}
+	delete(cr.Annotations, api.AnnotationSwitchoverTo)
+
+	return nil
+}
+
+// currentWriter returns the pxc pod the proxies route the writes to.
+func (r *ReconcilePerconaXtraDBCluster) currentWriter(cr *api.PerconaXtraDBCluster) (string, error) {
+	if cr.HAProxyEnabled() {
+		if cr.Status.HAProxyPrimary == "" {
+			return "", errors.New("HAProxy primary isn't elected yet")
+		}
+		return cr.Status.HAProxyPrimary, nil
+	}
+
+	db, err := pxc.GetProxyConnection(cr, r.client)
+	if err != nil {
+		return "", errors.Wrap(err, "connect to ProxySQL")
+	}
+	defer db.Close()
+
+	host, err := db.PrimaryHost()
+	if err != nil {
+		return "", errors.Wrap(err, "get primary host")
+	}
+
+	return strings.Split(host, ".")[0], nil
+}
+
+func (r *ReconcilePerconaXtraDBCluster) recvQueue(ctx context.Context, cr *api.PerconaXtraDBCluster, pod string) (int, error) {
+	db, err := queries.New(r.client, cr.Namespace, internalSecretsPrefix+cr.Name, users.Operator,
+		pod+"."+cr.Name+"-pxc."+cr.Namespace, 33062, cr.Spec.PXC.ReadinessProbes.TimeoutSeconds)
+	if err != nil {
+		return 0, errors.Wrap(err, "connect")
+	}
+	defer db.Close()
+
+	return db.WsrepLocalRecvQueue(ctx)
+}
+
+func (r *ReconcilePerconaXtraDBCluster) setMaintMode(ctx context.Context, cr *api.PerconaXtraDBCluster, pod, mode string) error {
+	db, err := queries.New(r.client, cr.Namespace, internalSecretsPrefix+cr.Name, users.Operator,
+		pod+"."+cr.Name+"-pxc."+cr.Namespace, 33062, cr.Spec.PXC.ReadinessProbes.TimeoutSeconds)
+	if err != nil {
+		return errors.Wrap(err, "connect")
+	}
+	defer db.Close()
+
+	return db.SetMaintMode(ctx, mode)
+}
+
+// setProxySQLWriter sets the preferred writer on every ProxySQL pod,
+// the pods don't share the configuration of the servers.
+func (r *ReconcilePerconaXtraDBCluster) setProxySQLWriter(ctx context.Context, cr *api.PerconaXtraDBCluster, pod string) error {
+	for i := 0; i < int(cr.Spec.ProxySQL.Size); i++ {
+		db, err := r.connectToProxySQLPod(cr, i)
+		if err != nil {
+			return err
+		}
+
+		err = db.SetProxySQLWriter(ctx, pod+"."+cr.Name+"-pxc")
+		db.Close()
+		if err != nil {
+			return errors.Wrapf(err, "set writer on %s-proxysql-%d", cr.Name, i)
+		}
+	}
+
+	return nil
+}
+
+func (r *ReconcilePerconaXtraDBCluster) proxySQLPodWriter(cr *api.PerconaXtraDBCluster, i int) (string, error) {
+	db, err := r.connectToProxySQLPod(cr, i)
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	host, err := db.PrimaryHost()
+	if err != nil {
+		return "", errors.Wrapf(err, "get primary host of %s-proxysql-%d", cr.Name, i)
+	}
+
+	return strings.Split(host, ".")[0], nil
+}
+
+func (r *ReconcilePerconaXtraDBCluster) connectToProxySQLPod(cr *api.PerconaXtraDBCluster, i int) (queries.Database, error) {
+	host := fmt.Sprintf("%s-proxysql-%d.%s-proxysql-unready.%s", cr.Name, i, cr.Name, cr.Namespace)
+	db, err := queries.New(r.client, cr.Namespace, internalSecretsPrefix+cr.Name, users.ProxyAdmin, host, 6032,
+		cr.Spec.PXC.ReadinessProbes.TimeoutSeconds)
+	if err != nil {
+		return queries.Database{}, errors.Wrapf(err, "connect to %s-proxysql-%d", cr.Name, i)
+	}
+	return db, nil
+}
@@ -0,0 +1,143 @@
+package pxc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+)
+
+func TestReconcileSwitchover(t *testing.T) {
+	ctx := context.Background()
+
+	startedAt := metav1.NewTime(time.Now().Add(-switchoverTimeout - time.Minute))
+
+	tests := []struct {
+		name               string
+		target             string
+		crVersion          string
+		primarySelection   api.HAProxyPrimarySelection
+		status             *api.SwitchoverStatus
+		expectedState      api.SwitchoverState
+		expectedMessage    string
+		expectedAnnotation bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:            "old crVersion",
+			target:          "cluster1-pxc-1",
+			crVersion:       "1.19.0",
+			expectedState:   api.SwitchoverFailed,
+			expectedMessage: "switchover requires crVersion 1.20.0 or newer",
+		},
+		{
+			name:             "HAProxy primary is selected by HAProxy",
+			target:           "cluster1-pxc-1",
+			primarySelection: api.HAProxyPrimarySelectionHAProxy,
+			expectedState:    api.SwitchoverFailed,
+			expectedMessage:  "switchover with HAProxy requires haproxy.primarySelection set to operator",
+		},
+		{
+			name:            "target is not a pxc pod",
+			target:          "cluster1-haproxy-0",
+			expectedState:   api.SwitchoverFailed,
+			expectedMessage: "pod cluster1-haproxy-0 is not a pxc pod of the cluster",
+		},
+		{
+			name:            "target is the writer",
+			target:          "cluster1-pxc-0",
+			expectedState:   api.SwitchoverSucceeded,
+			expectedMessage: "pod cluster1-pxc-0 is already the writer",
+		},
+		{
+			name:               "target is not ready",
+			target:             "cluster1-pxc-1",
+			expectedState:      api.SwitchoverPending,
+			expectedMessage:    "target pod is not ready",
+			expectedAnnotation: true,
+		},
+		{
+			name:            "timeout",
+			target:          "cluster1-pxc-1",
+			status:          &api.SwitchoverStatus{Target: "cluster1-pxc-1", State: api.SwitchoverPending, StartedAt: &startedAt},
+			expectedState:   api.SwitchoverFailed,
+			expectedMessage: "timeout: target pod is not ready",
+		},
+		{
+			name:            "another HAProxy primary is elected while switching",
+			target:          "cluster1-pxc-1",
+			status:          &api.SwitchoverStatus{Target: "cluster1-pxc-1", Previous: "cluster1-pxc-0", State: api.SwitchoverSwitching},
+			expectedState:   api.SwitchoverFailed,
+			expectedMessage: "pod cluster1-pxc-0 is elected as HAProxy primary instead of the target",
+		},
+		{
+			name:          "HAProxy pods use the target",
+			target:        "cluster1-pxc-0",
+			status:        &api.SwitchoverStatus{Target: "cluster1-pxc-0", Previous: "cluster1-pxc-1", State: api.SwitchoverSwitching},
+			expectedState: api.SwitchoverSucceeded,
+		},
+		{
+			name:               "finished switchover is restarted",
+			target:             "cluster1-pxc-1",
+			status:             &api.SwitchoverStatus{Target: "cluster1-pxc-1", State: api.SwitchoverFailed, StartedAt: &startedAt},
+			expectedState:      api.SwitchoverPending,
+			expectedMessage:    "target pod is not ready",
+			expectedAnnotation: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr, err := readDefaultCR("cluster1", "test")
+			require.NoError(t, err)
+			if tt.crVersion != "" {
+				cr.Spec.CRVersion = tt.crVersion
+			}
+			cr.Spec.HAProxy.PrimarySelection = api.HAProxyPrimarySelectionOperator
+			if tt.primarySelection != "" {
+				cr.Spec.HAProxy.PrimarySelection = tt.primarySelection
+			}
+			if tt.target != "" {
+				cr.Annotations = map[string]string{api.AnnotationSwitchoverTo: tt.target}
+			}
+			cr.Status.HAProxyPrimary = "cluster1-pxc-0"
+			cr.Status.Switchover = tt.status
+
+			objs := []runtime.Object{
+				cr.DeepCopy(),
+				newMockPod("cluster1-pxc-0", cr.Namespace, naming.LabelsPXC(cr), corev1.PodStatus{}),
+				newMockPod("cluster1-pxc-1", cr.Namespace, naming.LabelsPXC(cr), corev1.PodStatus{}),
+				newMockPod("cluster1-haproxy-0", cr.Namespace, naming.LabelsHAProxy(cr), corev1.PodStatus{}),
+			}
+			r := buildFakeClient(objs)
+
+			err = r.reconcileSwitchover(ctx, cr)
+			require.NoError(t, err)
+
+			if tt.expectedState == "" {
+				assert.Nil(t, cr.Status.Switchover)
+				return
+			}
+			require.NotNil(t, cr.Status.Switchover)
+			assert.Equal(t, tt.target, cr.Status.Switchover.Target)
+			assert.Equal(t, tt.expectedState, cr.Status.Switchover.State)
+			assert.Equal(t, tt.expectedMessage, cr.Status.Switchover.Message)
+
+			actual := new(api.PerconaXtraDBCluster)
+			require.NoError(t, r.client.Get(ctx, client.ObjectKeyFromObject(cr), actual))
+			_, ok := actual.Annotations[api.AnnotationSwitchoverTo]
+			assert.Equal(t, tt.expectedAnnotation, ok)
+		})
+	}
+}
@@ -298,7 +298,15 @@ func (r *ReconcilePerconaXtraDBCluster) applyNWait(ctx context.Context, cr *api.
 	if pod.ObjectMeta.Labels["controller-revision-hash"] == sfs.Status.UpdateRevision {
 		log.Info("pod already updated", "pod", pod.Name)
 	} else {
+		if connectionDrainingEnabled(cr) {
+			r.drainPod(ctx, cr, pod)
+		}
 		if err := r.client.Delete(ctx, pod); err != nil {
+			if connectionDrainingEnabled(cr) {
+				if err := r.undrainPod(ctx, cr, pod); err != nil {
+					log.Error(err, "failed to undrain pod", "pod", pod.Name)
+				}
+			}
 			return errors.Wrap(err, "failed to delete pod")
 		}
 	}
@@ -320,6 +328,13 @@ func (r *ReconcilePerconaXtraDBCluster) applyNWait(ctx context.Context, cr *api.
 		return errors.Wrap(err, "failed to wait pxc sync")
 	}
 
+	// the pod is undrained even if it was already updated, the previous smart update could fail before it
+	if connectionDrainingEnabled(cr) {
+		if err := r.undrainPod(ctx, cr, pod); err != nil {
+			return errors.Wrap(err, "failed to undrain pod")
+		}
+	}
+
 	if err := r.waitHostgroups(ctx, cr, sfs, pod, waitLimit); err != nil {
 		return errors.Wrap(err, "failed to wait hostgroups status")
 	}
@@ -600,13 +615,13 @@ func (r *ReconcilePerconaXtraDBCluster) isRestoreRunning(clusterName, namespace
 	}
 
 	for _, v := range restoreList.Items {
-		if v.Spec.PXCCluster != clusterName {
+		if v.TargetCluster() != clusterName || v.IsDryRun() {
 			continue
 		}
 
 		switch v.Status.State {
 		case api.RestoreStopCluster, api.RestoreRestore,
-			api.RestoreStartCluster, api.RestorePITR:
+			api.RestoreStartCluster, api.RestorePITR, api.RestoreImportTables:
 			return true, nil
 		}
 	}
@@ -14,6 +14,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -34,6 +35,7 @@ import (
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app/binlogcollector"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app/statefulset"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/version"
@@ -82,6 +84,7 @@ func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 		clientcmd:           cli,
 		chLimit:             make(chan struct{}, limit),
 		bcpDeleteInProgress: new(sync.Map),
+		bcpCopyInProgress:   new(sync.Map),
 	}, nil
 }
 
@@ -106,6 +109,7 @@ type ReconcilePerconaXtraDBClusterBackup struct {
 	clientcmd           *clientcmd.Client
 	chLimit             chan struct{}
 	bcpDeleteInProgress *sync.Map
+	bcpCopyInProgress   *sync.Map
 }
 
 // Reconcile reads that state of the cluster for a PerconaXtraDBClusterBackup object and makes changes based on the state read
@@ -139,7 +143,29 @@ func (r *ReconcilePerconaXtraDBClusterBackup) Reconcile(ctx context.Context, req
 		return reconcile.Result{}, errors.Wrap(err, "run finalizers")
 	}
 
+	if cr.Status.State == api.BackupSucceeded && cr.Spec.Verification.IsEnabled() && cr.DeletionTimestamp == nil {
+		done, err := r.reconcileVerification(ctx, cr)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "reconcile verification")
+		}
+		if !done {
+			return rr, nil
+		}
+	}
+
+	if cr.Status.State == api.BackupSucceeded && len(cr.Spec.CopyTo) > 0 && cr.DeletionTimestamp == nil {
+		if err := r.reconcileCopies(ctx, cr); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "reconcile copies")
+		}
+	}
+
 	if cr.Status.State == api.BackupSucceeded || cr.Status.State == api.BackupFailed {
+		if cr.DeletionTimestamp == nil {
+			if err := r.resyncBackupSource(ctx, cr); err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "resync backup source")
+			}
+		}
+
 		if err := r.runJobFinalizers(ctx, cr); err != nil {
 			return reconcile.Result{}, errors.Wrap(err, "run finalizers")
 		}
@@ -155,6 +181,15 @@ func (r *ReconcilePerconaXtraDBClusterBackup) Reconcile(ctx context.Context, req
 		return rr, nil
 	}
 
+	// imported backups get their status from the cluster controller and never run a backup job
+	if cr.Labels[naming.LabelPerconaBackupType] == naming.BackupTypeImported {
+		return reconcile.Result{}, nil
+	}
+
+	if cr.Spec.IsCopy() {
+		return r.reconcileBackupCopy(ctx, cr)
+	}
+
 	cluster, err := r.getCluster(ctx, cr)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "get cluster")
@@ -183,6 +218,15 @@ func (r *ReconcilePerconaXtraDBClusterBackup) Reconcile(ctx context.Context, req
 		return reconcile.Result{}, err
 	}
 
+	if storage.Throttling != nil && storage.Throttling.ThrottleIOPS > 0 && !features.Enabled(ctx, features.XtrabackupSidecar) {
+		err := fmt.Errorf("backup throttleIOPS is supported only when '%s' feature flag is enabled", features.XtrabackupSidecar)
+
+		if err := r.setFailedStatus(ctx, cr, err); err != nil {
+			return rr, errors.Wrap(err, "update status")
+		}
+		return reconcile.Result{}, err
+	}
+
 	err = cluster.CheckNSetDefaults(r.serverVersion, log)
 	if err != nil {
 		err := errors.Wrap(err, "wrong PXC options")
@@ -279,15 +323,36 @@ func (r *ReconcilePerconaXtraDBClusterBackup) Reconcile(ctx context.Context, req
 		}
 	}
 
-	if cr.Status.S3 == nil || cr.Status.Azure == nil {
+	if cr.Status.S3 == nil || cr.Status.Azure == nil || cr.Status.GCS == nil {
 		cr.Status.S3 = storage.S3
 		cr.Status.Azure = storage.Azure
+		cr.Status.GCS = storage.GCS
 		cr.Status.StorageType = storage.Type
 		cr.Status.Image = cluster.Spec.Backup.Image
 		cr.Status.SSLSecretName = cluster.Spec.PXC.SSLSecretName
 		cr.Status.SSLInternalSecretName = cluster.Spec.PXC.SSLInternalSecretName
 		cr.Status.VaultSecretName = cluster.Spec.PXC.VaultSecretName
 		cr.Status.VerifyTLS = storage.VerifyTLS
+		cr.Status.Encryption = storage.Encryption
+		cr.Status.PXCVersion = cluster.Status.PXC.Version
+	}
+
+	if cr.Status.Type == "" {
+		if err := r.setBackupType(ctx, cr, cluster, storage); err != nil {
+			if err := r.setFailedStatus(ctx, cr, err); err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "update status")
+			}
+
+			return reconcile.Result{}, err
+		}
+	}
+
+	if err := r.reconcileBackupSource(ctx, cr, cluster); err != nil {
+		if err := r.setFailedStatus(ctx, cr, err); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "update status")
+		}
+
+		return reconcile.Result{}, err
 	}
 
 	var job *batchv1.Job
@@ -309,6 +374,77 @@ func (r *ReconcilePerconaXtraDBClusterBackup) Reconcile(ctx context.Context, req
 	return rr, nil
 }
 
+// setBackupType sets the type of the backup in the status and,
+// for incremental backups, resolves the base backup.
+func (r *ReconcilePerconaXtraDBClusterBackup) setBackupType(
+	ctx context.Context,
+	cr *api.PerconaXtraDBClusterBackup,
+	cluster *api.PerconaXtraDBCluster,
+	storage *api.BackupStorageSpec,
+) error {
+	log := logf.FromContext(ctx)
+
+	if cr.Spec.IsLogical() {
+		if err := checkLogicalBackup(cr, cluster, storage); err != nil {
+			return err
+		}
+		cr.Status.Type = api.PXCBackupTypeLogical
+		return nil
+	}
+
+	if !cr.Spec.IsIncremental() {
+		cr.Status.Type = api.PXCBackupTypeFull
+		return nil
+	}
+
+	if !features.Enabled(ctx, features.XtrabackupSidecar) {
+		return errors.Errorf("incremental backups are supported only when '%s' feature flag is enabled", features.XtrabackupSidecar)
+	}
+	if storage.Type == api.BackupStorageFilesystem {
+		return errors.New("incremental backups are not supported for pvc storage")
+	}
+	if storage.Encryption.IsEnabled() {
+		// the operator can't read the LSN of encrypted backups
+		return errors.New("incremental backups are not supported for encrypted storage")
+	}
+
+	base, err := backup.GetBaseBackup(ctx, r.client, cr)
+	if err != nil {
+		if errors.Is(err, backup.ErrNoBaseBackup) {
+			log.Info("No base backup found, taking full backup instead of incremental", "storage", cr.Spec.StorageName)
+			cr.Status.Type = api.PXCBackupTypeFull
+			return nil
+		}
+		return errors.Wrap(err, "get base backup")
+	}
+
+	log.Info("Taking incremental backup", "base", base.Name, "fromLSN", base.Status.LSN.To)
+	backup.SetIncrementalBase(&cr.Status, base)
+
+	return nil
+}
+
+// checkLogicalBackup checks if the logical backup can be taken to the storage.
+// Logical backups are always taken by the backup job, the xtrabackup sidecar isn't used.
+func checkLogicalBackup(cr *api.PerconaXtraDBClusterBackup, cluster *api.PerconaXtraDBCluster, storage *api.BackupStorageSpec) error {
+	if cluster.CompareVersionWith("1.20.0") < 0 {
+		return errors.New("logical backups require crVersion 1.20.0 or newer")
+	}
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.LogicalImage == "" {
+		return errors.New("logical backups require backup.logicalImage with mydumper and myloader")
+	}
+	if storage.Type == api.BackupStorageFilesystem {
+		return errors.New("logical backups are not supported for pvc storage")
+	}
+	if storage.Encryption.IsEnabled() {
+		return errors.New("logical backups are not supported for encrypted storage")
+	}
+	if cr.Spec.Verification.IsEnabled() {
+		return errors.New("verification is not supported for logical backups")
+	}
+	return nil
+}
+
 func (r *ReconcilePerconaXtraDBClusterBackup) createBackupJob(
 	ctx context.Context,
 	cr *api.PerconaXtraDBClusterBackup,
@@ -324,16 +460,38 @@ func (r *ReconcilePerconaXtraDBClusterBackup) createBackupJob(
 		return nil, errors.Wrap(err, "failed to get initImage")
 	}
 
-	xtrabackupEnabled := features.Enabled(ctx, features.XtrabackupSidecar)
+	sourceCluster, err := r.getSourceCluster(ctx, cr, cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "get source cluster")
+	}
+	if sourceCluster.Name != cluster.Name {
+		// the backup job connects to the nodes of the replica cluster with its certificates
+		cr.Status.SSLSecretName = sourceCluster.Spec.PXC.SSLSecretName
+		cr.Status.SSLInternalSecretName = sourceCluster.Spec.PXC.SSLInternalSecretName
+		cr.Status.VaultSecretName = sourceCluster.Spec.PXC.VaultSecretName
+	}
+
+	xtrabackupEnabled := features.Enabled(ctx, features.XtrabackupSidecar) && !cr.Status.IsLogical()
 	getJobSpec := func() (batchv1.JobSpec, error) {
 		if xtrabackupEnabled {
+			if cr.Status.Source != nil {
+				sts := statefulset.NewNode(sourceCluster).StatefulSet()
+				return xtrabackup.JobSpec(cr, cluster, job, initImage, pxc.PodFQDN(cr.Status.Source.Pod, sts))
+			}
 			srcNode, err := pxc.GetHostForSidecarBackup(ctx, r.client, cluster)
 			if err != nil {
 				return batchv1.JobSpec{}, errors.Wrap(err, "failed to get primary pod dns name")
 			}
 			return xtrabackup.JobSpec(cr, cluster, job, initImage, srcNode)
 		}
-		return bcp.JobSpec(cr.Spec, cluster, job, initImage)
+		spec, err := bcp.JobSpec(cr.Spec, cluster, job, initImage)
+		if err != nil {
+			return spec, err
+		}
+		if cr.Status.Source != nil {
+			backup.SetSource(&spec, sourceCluster, cr.Status.Source.Pod)
+		}
+		return spec, nil
 	}
 
 	job.Spec, err = getJobSpec()
@@ -341,6 +499,14 @@ func (r *ReconcilePerconaXtraDBClusterBackup) createBackupJob(
 		return nil, fmt.Errorf("failed to get job spec: %w (xtrabackup enabled: %t)", err, xtrabackupEnabled)
 	}
 
+	backupName := cr.Spec.PXCCluster + "-" + cr.CreationTimestamp.Time.Format("2006-01-02-15:04:05") + "-full"
+	switch {
+	case cr.Status.IsIncremental():
+		backupName = cr.Spec.PXCCluster + "-" + cr.CreationTimestamp.Time.Format("2006-01-02-15:04:05") + "-incr"
+	case cr.Status.IsLogical():
+		backupName = cr.Spec.PXCCluster + "-" + cr.CreationTimestamp.Time.Format("2006-01-02-15:04:05") + "-logical"
+	}
+
 	switch storage.Type {
 	case api.BackupStorageFilesystem:
 		pvc := backup.NewPVC(cr, cluster)
@@ -375,7 +541,7 @@ func (r *ReconcilePerconaXtraDBClusterBackup) createBackupJob(
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to get bucket")
 		}
-		cr.Status.Destination.SetS3Destination(bucket, cr.Spec.PXCCluster+"-"+cr.CreationTimestamp.Time.Format("2006-01-02-15:04:05")+"-full")
+		cr.Status.Destination.SetS3Destination(bucket, backupName)
 
 		if err := backup.SetStorageS3(ctx, &job.Spec, cr); err != nil {
 			return nil, errors.Wrap(err, "set storage FS")
@@ -384,12 +550,22 @@ func (r *ReconcilePerconaXtraDBClusterBackup) createBackupJob(
 		if storage.Azure == nil {
 			return nil, errors.New("azure storage is not specified")
 		}
-		cr.Status.Destination.SetAzureDestination(storage.Azure.ContainerPath, cr.Spec.PXCCluster+"-"+cr.CreationTimestamp.Time.Format("2006-01-02-15:04:05")+"-full")
+		cr.Status.Destination.SetAzureDestination(storage.Azure.ContainerPath, backupName)
 
 		err := backup.SetStorageAzure(ctx, &job.Spec, cr)
 		if err != nil {
 			return nil, errors.Wrap(err, "set storage FS for Azure")
 		}
+	case api.BackupStorageGCS:
+		if storage.GCS == nil {
+			return nil, errors.New("gcs storage is not specified")
+		}
+		bucket, _ := storage.GCS.BucketAndPrefix()
+		cr.Status.Destination.SetGCSDestination(bucket, backupName)
+
+		if err := backup.SetStorageGCS(ctx, &job.Spec, cr); err != nil {
+			return nil, errors.Wrap(err, "set storage for GCS")
+		}
 	}
 
 	if xtrabackupEnabled {
@@ -481,7 +657,22 @@ func (r *ReconcilePerconaXtraDBClusterBackup) runBackupFinalizers(ctx context.Co
 		var err error
 		switch f {
 		case naming.FinalizerDeleteBackup:
-			if (cr.Status.S3 == nil && cr.Status.Azure == nil && cr.Status.PVC == nil) || cr.Status.Destination == "" {
+			if (cr.Status.S3 == nil && cr.Status.Azure == nil && cr.Status.GCS == nil && cr.Status.PVC == nil) || cr.Status.Destination == "" {
+				continue
+			}
+
+			// the incremental backups can't be restored without their base backups,
+			// so the base backup is deleted only after the backups which depend on it
+			dependent, err := backup.DependentBackups(ctx, r.client, cr)
+			if err != nil {
+				log.Error(err, "failed to get dependent backups")
+				finalizers = append(finalizers, f)
+				continue
+			}
+			if len(dependent) > 0 {
+				log.Info("backup is the base of incremental backups, waiting for them to be deleted",
+					"backup", cr.Name, "dependent", strings.Join(dependent, ", "))
+				finalizers = append(finalizers, f)
 				continue
 			}
 
@@ -493,6 +684,8 @@ func (r *ReconcilePerconaXtraDBClusterBackup) runBackupFinalizers(ctx context.Co
 				err = r.runS3BackupFinalizer(ctx, cr)
 			case api.BackupStorageAzure:
 				err = r.runAzureBackupFinalizer(ctx, cr)
+			case api.BackupStorageGCS:
+				err = r.runGCSBackupFinalizer(ctx, cr)
 			case api.BackupStorageFilesystem:
 				err = r.runFilesystemBackupFinalizer(ctx, cr)
 			default:
@@ -512,6 +705,12 @@ func (r *ReconcilePerconaXtraDBClusterBackup) runBackupFinalizers(ctx context.Co
 				log.Error(err, "failed to release backup lock")
 				finalizers = append(finalizers, f)
 			}
+		case naming.FinalizerResyncBackupSource:
+			err = r.runResyncSourceFinalizer(ctx, cr)
+			if err != nil {
+				log.Error(err, "failed to resync backup source")
+				finalizers = append(finalizers, f)
+			}
 		default:
 			finalizers = append(finalizers, f)
 		}
@@ -586,6 +785,31 @@ func (r *ReconcilePerconaXtraDBClusterBackup) runAzureBackupFinalizer(ctx contex
 	return nil
 }
 
+func (r *ReconcilePerconaXtraDBClusterBackup) runGCSBackupFinalizer(ctx context.Context, cr *api.PerconaXtraDBClusterBackup) error {
+	log := logf.FromContext(ctx)
+
+	if cr.Status.GCS == nil {
+		return errors.New("gcs storage is not specified")
+	}
+
+	opts, err := storage.GetOptionsFromBackup(ctx, r.client, nil, cr)
+	if err != nil {
+		return errors.Wrap(err, "get storage options")
+	}
+	gcsStorage, err := storage.NewClient(ctx, opts)
+	if err != nil {
+		return errors.Wrap(err, "new gcs storage")
+	}
+
+	backupName := cr.Status.Destination.BackupName()
+	log.Info("Deleting backup from gcs", "name", cr.Name, "bucket", cr.Status.GCS.Bucket, "backupName", backupName)
+	err = retry.OnError(retry.DefaultBackoff, func(e error) bool { return true }, removeBackupObjects(ctx, gcsStorage, backupName))
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete backup %s", cr.Name)
+	}
+	return nil
+}
+
 func (r *ReconcilePerconaXtraDBClusterBackup) runFilesystemBackupFinalizer(ctx context.Context, cr *api.PerconaXtraDBClusterBackup) error {
 	log := logf.FromContext(ctx)
 
@@ -709,6 +933,7 @@ func (r *ReconcilePerconaXtraDBClusterBackup) updateJobStatus(
 		StorageName:           storageName,
 		S3:                    storage.S3,
 		Azure:                 storage.Azure,
+		GCS:                   storage.GCS,
 		PVC:                   bcp.Status.PVC,
 		StorageType:           storage.Type,
 		Image:                 bcp.Status.Image,
@@ -716,6 +941,14 @@ func (r *ReconcilePerconaXtraDBClusterBackup) updateJobStatus(
 		SSLInternalSecretName: bcp.Status.SSLInternalSecretName,
 		VaultSecretName:       bcp.Status.VaultSecretName,
 		VerifyTLS:             storage.VerifyTLS,
+		Type:                  bcp.Status.Type,
+		BaseBackupName:        bcp.Status.BaseBackupName,
+		BaseChain:             bcp.Status.BaseChain,
+		LSN:                   bcp.Status.LSN,
+		Encryption:            bcp.Status.Encryption,
+		PXCVersion:            bcp.Status.PXCVersion,
+		Size:                  bcp.Status.Size,
+		Source:                bcp.Status.Source,
 	}
 
 	if status.State == api.BackupSucceeded {
@@ -751,7 +984,30 @@ func (r *ReconcilePerconaXtraDBClusterBackup) updateJobStatus(
 	case api.BackupSucceeded:
 		log.Info("Backup succeeded")
 
-		if cluster.PITREnabled() {
+		// logical backups have no InnoDB data files, so they have no LSN
+		if storage.Type != api.BackupStorageFilesystem && !bcp.Status.Encryption.IsEnabled() && !bcp.Status.IsLogical() {
+			lsn, err := r.getBackupLSN(ctx, bcp)
+			if err != nil {
+				// backup is usable without LSN, it just can't be a base for incremental backups
+				log.Error(err, "failed to get backup LSN")
+			} else {
+				bcp.Status.LSN = lsn
+			}
+		}
+
+		if storage.Type != api.BackupStorageFilesystem {
+			size, err := r.getBackupSize(ctx, bcp)
+			if err != nil {
+				// size is used only by the retention of scheduled backups
+				log.Error(err, "failed to get backup size")
+			} else {
+				bcp.Status.Size = size
+			}
+		}
+
+		// binlogs can't be applied to logical backups,
+		// so the gaps in the binlogs are still relevant for the physical ones
+		if cluster.PITREnabled() && !bcp.Status.IsLogical() {
 			collectorPod, err := binlogcollector.GetPod(ctx, r.client, cluster)
 			if err != nil {
 				return errors.Wrap(err, "get binlog collector pod")
@@ -791,6 +1047,32 @@ func (r *ReconcilePerconaXtraDBClusterBackup) updateJobStatus(
 	return nil
 }
 
+func (r *ReconcilePerconaXtraDBClusterBackup) getBackupLSN(ctx context.Context, cr *api.PerconaXtraDBClusterBackup) (*api.PXCBackupLSN, error) {
+	opts, err := storage.GetOptionsFromBackup(ctx, r.client, nil, cr)
+	if err != nil {
+		return nil, errors.Wrap(err, "get storage options")
+	}
+	stg, err := storage.NewClient(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "new storage client")
+	}
+
+	return backup.GetBackupLSN(ctx, stg, cr.Status.Destination.BackupName())
+}
+
+func (r *ReconcilePerconaXtraDBClusterBackup) getBackupSize(ctx context.Context, cr *api.PerconaXtraDBClusterBackup) (*resource.Quantity, error) {
+	opts, err := storage.GetOptionsFromBackup(ctx, r.client, nil, cr)
+	if err != nil {
+		return nil, errors.Wrap(err, "get storage options")
+	}
+	stg, err := storage.NewClient(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "new storage client")
+	}
+
+	return backup.GetBackupSize(ctx, stg, cr.Status.Destination.BackupName())
+}
+
 func (r *ReconcilePerconaXtraDBClusterBackup) updateStatus(ctx context.Context, cr *api.PerconaXtraDBClusterBackup) error {
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		localCr := new(api.PerconaXtraDBClusterBackup)
@@ -270,6 +270,7 @@ func newTestReconciler() *ReconcilePerconaXtraDBClusterBackup {
 		clientcmd:           cli,
 		chLimit:             make(chan struct{}, 10),
 		bcpDeleteInProgress: new(sync.Map),
+		bcpCopyInProgress:   new(sync.Map),
 	}
 }
 
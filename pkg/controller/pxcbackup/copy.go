@@ -0,0 +1,204 @@
+package pxcbackup
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+)
+
+// reconcileCopies creates backup objects for the copies of the succeeded backup
+// in the storages listed in spec.copyTo.
+func (r *ReconcilePerconaXtraDBClusterBackup) reconcileCopies(ctx context.Context, cr *api.PerconaXtraDBClusterBackup) error {
+	log := logf.FromContext(ctx)
+
+	copies := make([]api.PXCBackupCopyStatus, 0, len(cr.Spec.CopyTo))
+	for _, spec := range cr.Spec.CopyTo {
+		cp := new(api.PerconaXtraDBClusterBackup)
+		err := r.client.Get(ctx, types.NamespacedName{Name: naming.BackupCopyName(cr.Name, spec.StorageName), Namespace: cr.Namespace}, cp)
+		if client.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, "get backup copy")
+		}
+		if k8sErrors.IsNotFound(err) {
+			cp = newBackupCopy(cr, spec)
+			if err := r.client.Create(ctx, cp); err != nil {
+				return errors.Wrapf(err, "create backup copy %s", cp.Name)
+			}
+			log.Info("Created backup copy", "copy", cp.Name, "storage", spec.StorageName)
+		}
+
+		copies = append(copies, api.PXCBackupCopyStatus{
+			StorageName: spec.StorageName,
+			BackupName:  cp.Name,
+			State:       cp.Status.State,
+		})
+	}
+
+	if reflect.DeepEqual(cr.Status.Copies, copies) {
+		return nil
+	}
+	cr.Status.Copies = copies
+
+	return r.updateStatus(ctx, cr)
+}
+
+func newBackupCopy(cr *api.PerconaXtraDBClusterBackup, spec api.BackupCopySpec) *api.PerconaXtraDBClusterBackup {
+	cp := &api.PerconaXtraDBClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.BackupCopyName(cr.Name, spec.StorageName),
+			Namespace: cr.Namespace,
+			Labels:    maps.Clone(cr.Labels),
+		},
+		Spec: api.PXCBackupSpec{
+			PXCCluster:  cr.Spec.PXCCluster,
+			StorageName: spec.StorageName,
+			CopyFrom:    cr.Name,
+		},
+	}
+	if spec.DeleteFromStorage {
+		cp.Finalizers = []string{naming.FinalizerDeleteBackup}
+	}
+	return cp
+}
+
+// reconcileBackupCopy copies the data of the source backup to the storage of the backup copy.
+// The data is copied in background, the copy is marked as succeeded or failed when it's finished.
+func (r *ReconcilePerconaXtraDBClusterBackup) reconcileBackupCopy(ctx context.Context, cr *api.PerconaXtraDBClusterBackup) (reconcile.Result, error) {
+	rr := reconcile.Result{
+		RequeueAfter: time.Second * 5,
+	}
+
+	source := new(api.PerconaXtraDBClusterBackup)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: cr.Spec.CopyFrom, Namespace: cr.Namespace}, source); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return reconcile.Result{}, r.setCopyFailedStatus(ctx, cr, errors.Errorf("source backup %s is not found", cr.Spec.CopyFrom))
+		}
+		return reconcile.Result{}, errors.Wrap(err, "get source backup")
+	}
+	if source.Status.State != api.BackupSucceeded {
+		return reconcile.Result{}, r.setCopyFailedStatus(ctx, cr, errors.Errorf("source backup %s is not succeeded, current state: %s", source.Name, source.Status.State))
+	}
+
+	if cr.Status.State == api.BackupNew {
+		cluster, err := r.getCluster(ctx, cr)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "get cluster")
+		}
+		stg, ok := cluster.Spec.Backup.Storages[cr.Spec.StorageName]
+		if !ok {
+			return reconcile.Result{}, r.setCopyFailedStatus(ctx, cr, errors.Errorf("storage %s doesn't exist", cr.Spec.StorageName))
+		}
+
+		status, err := backup.CopyStatus(source, cr.Spec.StorageName, stg)
+		if err != nil {
+			return reconcile.Result{}, r.setCopyFailedStatus(ctx, cr, err)
+		}
+		status.State = api.BackupRunning
+		cr.Status = status
+
+		if err := r.updateStatus(ctx, cr); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "update status")
+		}
+		if err := r.setSourceCopyState(ctx, cr); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "update source backup status")
+		}
+	}
+
+	key := types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}.String()
+	if _, ok := r.bcpCopyInProgress.LoadOrStore(key, struct{}{}); !ok {
+		go r.runBackupCopy(ctx, key, cr.DeepCopy(), source.DeepCopy())
+	}
+
+	return rr, nil
+}
+
+func (r *ReconcilePerconaXtraDBClusterBackup) runBackupCopy(ctx context.Context, key string, cr, source *api.PerconaXtraDBClusterBackup) {
+	log := logf.FromContext(ctx).WithValues("copy", cr.Name, "source", source.Name)
+
+	defer r.bcpCopyInProgress.Delete(key)
+
+	log.Info("Copying backup", "from", source.Status.Destination, "to", cr.Status.Destination)
+	if err := r.copyBackupData(ctx, cr, source); err != nil {
+		log.Error(err, "failed to copy backup")
+		cr.SetFailedStatusWithError(err)
+	} else {
+		log.Info("Backup copied")
+		cr.Status.State = api.BackupSucceeded
+		cr.Status.CompletedAt = &metav1.Time{Time: time.Now()}
+	}
+
+	if err := r.updateStatus(ctx, cr); err != nil {
+		log.Error(err, "failed to update status")
+		return
+	}
+	if err := r.setSourceCopyState(ctx, cr); err != nil {
+		log.Error(err, "failed to update source backup status")
+	}
+}
+
+func (r *ReconcilePerconaXtraDBClusterBackup) copyBackupData(ctx context.Context, cr, source *api.PerconaXtraDBClusterBackup) error {
+	srcOpts, err := storage.GetOptionsFromBackup(ctx, r.client, nil, source)
+	if err != nil {
+		return errors.Wrap(err, "get source storage options")
+	}
+	src, err := storage.NewClient(ctx, srcOpts)
+	if err != nil {
+		return errors.Wrap(err, "new source storage client")
+	}
+
+	dstOpts, err := storage.GetOptionsFromBackup(ctx, r.client, nil, cr)
+	if err != nil {
+		return errors.Wrap(err, "get destination storage options")
+	}
+	dst, err := storage.NewClient(ctx, dstOpts)
+	if err != nil {
+		return errors.Wrap(err, "new destination storage client")
+	}
+
+	return backup.CopyBackup(ctx, src, dst, source.Status.Destination.BackupName())
+}
+
+func (r *ReconcilePerconaXtraDBClusterBackup) setCopyFailedStatus(ctx context.Context, cr *api.PerconaXtraDBClusterBackup, err error) error {
+	if err := r.setFailedStatus(ctx, cr, err); err != nil {
+		return errors.Wrap(err, "update status")
+	}
+	return r.setSourceCopyState(ctx, cr)
+}
+
+// setSourceCopyState sets the state of the copy in the status of the source backup.
+func (r *ReconcilePerconaXtraDBClusterBackup) setSourceCopyState(ctx context.Context, cr *api.PerconaXtraDBClusterBackup) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		source := new(api.PerconaXtraDBClusterBackup)
+		err := r.client.Get(ctx, types.NamespacedName{Name: cr.Spec.CopyFrom, Namespace: cr.Namespace}, source)
+		if err != nil {
+			return client.IgnoreNotFound(err)
+		}
+
+		for i := range source.Status.Copies {
+			if source.Status.Copies[i].BackupName != cr.Name {
+				continue
+			}
+			if source.Status.Copies[i].State == cr.Status.State {
+				return nil
+			}
+			source.Status.Copies[i].State = cr.Status.State
+			return r.client.Status().Update(ctx, source)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,92 @@
+package pxcbackup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/version"
+)
+
+func TestCheckLogicalBackup(t *testing.T) {
+	s3 := &api.BackupStorageSpec{Type: api.BackupStorageS3}
+
+	tests := []struct {
+		name        string
+		crVersion   string
+		noImage     bool
+		storage     *api.BackupStorageSpec
+		bcp         api.PXCBackupSpec
+		expectedErr string
+	}{
+		{
+			name:      "s3",
+			crVersion: version.Version(),
+			storage:   s3,
+		},
+		{
+			name:        "old crVersion",
+			crVersion:   "1.19.0",
+			storage:     s3,
+			expectedErr: "logical backups require crVersion 1.20.0 or newer",
+		},
+		{
+			name:        "no logical image",
+			crVersion:   version.Version(),
+			noImage:     true,
+			storage:     s3,
+			expectedErr: "logical backups require backup.logicalImage with mydumper and myloader",
+		},
+		{
+			name:        "pvc",
+			crVersion:   version.Version(),
+			storage:     &api.BackupStorageSpec{Type: api.BackupStorageFilesystem},
+			expectedErr: "logical backups are not supported for pvc storage",
+		},
+		{
+			name:      "encryption",
+			crVersion: version.Version(),
+			storage: &api.BackupStorageSpec{
+				Type: api.BackupStorageS3,
+				Encryption: &api.BackupEncryptionSpec{
+					KeySecret: &corev1.SecretKeySelector{Key: "key"},
+				},
+			},
+			expectedErr: "logical backups are not supported for encrypted storage",
+		},
+		{
+			name:      "verification",
+			crVersion: version.Version(),
+			storage:   s3,
+			bcp: api.PXCBackupSpec{
+				Verification: &api.PXCBackupVerification{Enabled: true},
+			},
+			expectedErr: "verification is not supported for logical backups",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &api.PerconaXtraDBCluster{
+				Spec: api.PerconaXtraDBClusterSpec{
+					CRVersion: tt.crVersion,
+					Backup:    &api.BackupSpec{LogicalImage: "percona/percona-xtrabackup:8.0-mydumper"},
+				},
+			}
+			if tt.noImage {
+				cluster.Spec.Backup.LogicalImage = ""
+			}
+			cr := &api.PerconaXtraDBClusterBackup{Spec: tt.bcp}
+			cr.Spec.Type = api.PXCBackupTypeLogical
+
+			err := checkLogicalBackup(cr, cluster, tt.storage)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package pxcbackup
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/features"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/k8s"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/queries"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/users"
+)
+
+const sourceQueryTimeout = 10
+
+// reconcileBackupSource selects the node the backup is taken from and,
+// if it's requested, removes the node from the routing and desyncs it.
+// The node is selected once, before the backup job is created.
+func (r *ReconcilePerconaXtraDBClusterBackup) reconcileBackupSource(
+	ctx context.Context,
+	cr *api.PerconaXtraDBClusterBackup,
+	cluster *api.PerconaXtraDBCluster,
+) error {
+	log := logf.FromContext(ctx)
+
+	if cr.Spec.Source == nil || cr.Status.Source != nil {
+		return nil
+	}
+	if cluster.CompareVersionWith("1.20.0") < 0 {
+		return errors.New("backup source requires crVersion 1.20.0 or newer")
+	}
+
+	sourceCluster := cluster
+	if cr.Spec.Source.Policy == api.PXCBackupSourceReplica {
+		var err error
+		sourceCluster, err = r.getReplicaCluster(ctx, cluster, cr.Spec.Source.ReplicaCluster)
+		if err != nil {
+			return err
+		}
+	}
+
+	pods, err := readyPXCPods(ctx, r.client, sourceCluster)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return errors.Errorf("cluster %s has no ready pods", sourceCluster.Name)
+	}
+
+	var pod string
+	switch cr.Spec.Source.Policy {
+	case api.PXCBackupSourcePod:
+		for _, p := range pods {
+			if p.Name == cr.Spec.Source.Pod {
+				pod = p.Name
+			}
+		}
+		if pod == "" {
+			return errors.Errorf("pod %s is not a ready pod of cluster %s", cr.Spec.Source.Pod, sourceCluster.Name)
+		}
+	case api.PXCBackupSourceLeastLoaded, api.PXCBackupSourceReplica:
+		pod, err = leastLoadedPod(pods, func(pod string) (int, error) {
+			db, err := connectToPod(r.client, sourceCluster, pod)
+			if err != nil {
+				return 0, err
+			}
+			defer db.Close()
+			return db.WsrepLocalRecvQueue(ctx)
+		})
+		if err != nil {
+			return err
+		}
+	default:
+		return errors.Errorf("unknown backup source policy %s", cr.Spec.Source.Policy)
+	}
+
+	log.Info("Backup source is selected", "policy", cr.Spec.Source.Policy, "cluster", sourceCluster.Name, "pod", pod)
+	cr.Status.Source = &api.PXCBackupSourceStatus{
+		Cluster: sourceCluster.Name,
+		Pod:     pod,
+	}
+
+	if !cr.Spec.Source.Desync {
+		return nil
+	}
+	if len(pods) < 2 {
+		log.Info("Backup source is the only ready node, it isn't desynced", "pod", pod)
+		return nil
+	}
+
+	// the finalizer and the status are saved before the node is desynced,
+	// so the node is resynced even if the backup is deleted in between
+	orig := cr.DeepCopy()
+	if controllerutil.AddFinalizer(cr, naming.FinalizerResyncBackupSource) {
+		if err := r.client.Patch(ctx, cr.DeepCopy(), client.MergeFrom(orig)); err != nil {
+			return errors.Wrap(err, "patch finalizers")
+		}
+	}
+	cr.Status.Source.Desynced = true
+	if err := r.updateStatus(ctx, cr); err != nil {
+		return errors.Wrap(err, "update status")
+	}
+
+	db, err := connectToPod(r.client, sourceCluster, pod)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.SetMaintMode(ctx, "MAINTENANCE"); err != nil {
+		return err
+	}
+	if desyncsSource(ctx, cr) {
+		if err := db.SetWsrepDesync(ctx, true); err != nil {
+			return err
+		}
+	}
+	log.Info("Backup source is desynced", "cluster", sourceCluster.Name, "pod", pod)
+
+	return nil
+}
+
+// resyncBackupSource returns the node desynced for the finished backup to the cluster.
+func (r *ReconcilePerconaXtraDBClusterBackup) resyncBackupSource(ctx context.Context, cr *api.PerconaXtraDBClusterBackup) error {
+	if !controllerutil.ContainsFinalizer(cr, naming.FinalizerResyncBackupSource) {
+		return nil
+	}
+
+	if err := r.runResyncSourceFinalizer(ctx, cr); err != nil {
+		return err
+	}
+
+	orig := cr.DeepCopy()
+	controllerutil.RemoveFinalizer(cr, naming.FinalizerResyncBackupSource)
+	if err := r.client.Patch(ctx, cr.DeepCopy(), client.MergeFrom(orig)); err != nil {
+		return errors.Wrap(err, "patch finalizers")
+	}
+
+	if cr.Status.Source != nil {
+		cr.Status.Source.Desynced = false
+		if err := r.updateStatus(ctx, cr); err != nil {
+			return errors.Wrap(err, "update status")
+		}
+	}
+
+	return nil
+}
+
+func (r *ReconcilePerconaXtraDBClusterBackup) runResyncSourceFinalizer(ctx context.Context, cr *api.PerconaXtraDBClusterBackup) error {
+	log := logf.FromContext(ctx)
+
+	if cr.Status.Source == nil || !cr.Status.Source.Desynced {
+		return nil
+	}
+
+	sourceCluster := new(api.PerconaXtraDBCluster)
+	err := r.client.Get(ctx, types.NamespacedName{Name: cr.Status.Source.Cluster, Namespace: cr.Namespace}, sourceCluster)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrap(err, "get source cluster")
+	}
+
+	db, err := connectToPod(r.client, sourceCluster, cr.Status.Source.Pod)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if desyncsSource(ctx, cr) {
+		if err := db.SetWsrepDesync(ctx, false); err != nil {
+			return err
+		}
+	}
+	if err := db.SetMaintMode(ctx, "DISABLED"); err != nil {
+		return err
+	}
+	log.Info("Backup source is resynced", "cluster", sourceCluster.Name, "pod", cr.Status.Source.Pod)
+
+	return nil
+}
+
+// desyncsSource returns true if the operator desyncs the source node with wsrep_desync.
+// The backups streamed by Galera SST aren't desynced by the operator:
+// the donor is desynced by Galera and a desynced node can't be selected as a donor.
+func desyncsSource(ctx context.Context, cr *api.PerconaXtraDBClusterBackup) bool {
+	return features.Enabled(ctx, features.XtrabackupSidecar) || cr.Status.IsLogical()
+}
+
+// getSourceCluster returns the cluster the backup is taken from.
+func (r *ReconcilePerconaXtraDBClusterBackup) getSourceCluster(
+	ctx context.Context,
+	cr *api.PerconaXtraDBClusterBackup,
+	cluster *api.PerconaXtraDBCluster,
+) (*api.PerconaXtraDBCluster, error) {
+	if cr.Status.Source == nil || cr.Status.Source.Cluster == cluster.Name {
+		return cluster, nil
+	}
+	return r.getReplicaCluster(ctx, cluster, cr.Status.Source.Cluster)
+}
+
+// getReplicaCluster returns the cluster which replicates from the cluster by the async replication.
+func (r *ReconcilePerconaXtraDBClusterBackup) getReplicaCluster(
+	ctx context.Context,
+	cluster *api.PerconaXtraDBCluster,
+	name string,
+) (*api.PerconaXtraDBCluster, error) {
+	replica := new(api.PerconaXtraDBCluster)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: cluster.Namespace}, replica); err != nil {
+		return nil, errors.Wrapf(err, "get replica cluster %s", name)
+	}
+
+	isReplica := false
+	if replica.Spec.PXC != nil {
+		for _, channel := range replica.Spec.PXC.ReplicationChannels {
+			if !channel.IsSource {
+				isReplica = true
+			}
+		}
+	}
+	if !isReplica {
+		return nil, errors.Errorf("cluster %s has no replica replication channels", name)
+	}
+
+	if err := replica.CheckNSetDefaults(r.serverVersion, logf.FromContext(ctx)); err != nil {
+		return nil, errors.Wrapf(err, "wrong options of replica cluster %s", name)
+	}
+
+	return replica, nil
+}
+
+func readyPXCPods(ctx context.Context, cl client.Client, cluster *api.PerconaXtraDBCluster) ([]corev1.Pod, error) {
+	podList := new(corev1.PodList)
+	if err := cl.List(ctx, podList, client.InNamespace(cluster.Namespace), client.MatchingLabels(naming.LabelsPXC(cluster))); err != nil {
+		return nil, errors.Wrap(err, "list pxc pods")
+	}
+
+	var pods []corev1.Pod
+	for _, pod := range podList.Items {
+		if k8s.IsPodReady(pod) {
+			pods = append(pods, pod)
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	return pods, nil
+}
+
+// leastLoadedPod returns the pod with the shortest receive queue.
+// The pods which can't be queried are skipped.
+func leastLoadedPod(pods []corev1.Pod, recvQueue func(pod string) (int, error)) (string, error) {
+	pod := ""
+	minQueue := 0
+	var lastErr error
+	for _, p := range pods {
+		queue, err := recvQueue(p.Name)
+		if err != nil {
+			lastErr = errors.Wrapf(err, "get receive queue of pod %s", p.Name)
+			continue
+		}
+		if pod == "" || queue < minQueue {
+			pod = p.Name
+			minQueue = queue
+		}
+	}
+	if pod == "" {
+		return "", errors.Wrap(lastErr, "no pod can be selected")
+	}
+
+	return pod, nil
+}
+
+func connectToPod(cl client.Client, cluster *api.PerconaXtraDBCluster, pod string) (queries.Database, error) {
+	db, err := queries.New(cl, cluster.Namespace, "internal-"+cluster.Name, users.Operator,
+		pod+"."+cluster.Name+"-pxc."+cluster.Namespace, 33062, sourceQueryTimeout)
+	if err != nil {
+		return queries.Database{}, errors.Wrapf(err, "connect to pod %s", pod)
+	}
+	return db, nil
+}
@@ -0,0 +1,163 @@
+package pxcbackup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+)
+
+func TestLeastLoadedPod(t *testing.T) {
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster1-pxc-0"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster1-pxc-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster1-pxc-2"}},
+	}
+
+	tests := []struct {
+		name        string
+		queues      map[string]int
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "shortest queue",
+			queues:   map[string]int{"cluster1-pxc-0": 10, "cluster1-pxc-1": 2, "cluster1-pxc-2": 5},
+			expected: "cluster1-pxc-1",
+		},
+		{
+			name:     "first pod on equal queues",
+			queues:   map[string]int{"cluster1-pxc-0": 0, "cluster1-pxc-1": 0, "cluster1-pxc-2": 0},
+			expected: "cluster1-pxc-0",
+		},
+		{
+			name:     "unreachable pods are skipped",
+			queues:   map[string]int{"cluster1-pxc-2": 7},
+			expected: "cluster1-pxc-2",
+		},
+		{
+			name:        "no reachable pods",
+			queues:      map[string]int{},
+			expectedErr: "no pod can be selected: get receive queue of pod cluster1-pxc-2: connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod, err := leastLoadedPod(pods, func(pod string) (int, error) {
+				queue, ok := tt.queues[pod]
+				if !ok {
+					return 0, errors.New("connection refused")
+				}
+				return queue, nil
+			})
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, pod)
+		})
+	}
+}
+
+func TestReconcileBackupSource(t *testing.T) {
+	ctx := context.Background()
+
+	cluster, err := readDefaultCR("cluster1", "test")
+	require.NoError(t, err)
+
+	pod := func(name string, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: cluster.Namespace,
+				Labels:    naming.LabelsPXC(cluster),
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+
+	replica := cluster.DeepCopy()
+	replica.Name = "replica1"
+	replica.Spec.PXC.ReplicationChannels = []pxcv1.ReplicationChannel{{Name: "channel1", IsSource: true}}
+
+	tests := []struct {
+		name        string
+		source      *pxcv1.PXCBackupSource
+		expected    *pxcv1.PXCBackupSourceStatus
+		expectedErr string
+	}{
+		{
+			name: "no source",
+		},
+		{
+			name:     "pod",
+			source:   &pxcv1.PXCBackupSource{Policy: pxcv1.PXCBackupSourcePod, Pod: "cluster1-pxc-2"},
+			expected: &pxcv1.PXCBackupSourceStatus{Cluster: "cluster1", Pod: "cluster1-pxc-2"},
+		},
+		{
+			name:        "pod is not ready",
+			source:      &pxcv1.PXCBackupSource{Policy: pxcv1.PXCBackupSourcePod, Pod: "cluster1-pxc-1"},
+			expectedErr: "pod cluster1-pxc-1 is not a ready pod of cluster cluster1",
+		},
+		{
+			name:        "replica cluster is not found",
+			source:      &pxcv1.PXCBackupSource{Policy: pxcv1.PXCBackupSourceReplica, ReplicaCluster: "replica2"},
+			expectedErr: `get replica cluster replica2: perconaxtradbclusters.pxc.percona.com "replica2" not found`,
+		},
+		{
+			name:        "cluster is not a replica",
+			source:      &pxcv1.PXCBackupSource{Policy: pxcv1.PXCBackupSourceReplica, ReplicaCluster: "replica1"},
+			expectedErr: "cluster replica1 has no replica replication channels",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bcp, err := readDefaultBackup("backup1", "test")
+			require.NoError(t, err)
+			bcp.Spec.Source = tt.source
+
+			objs := []runtime.Object{
+				cluster.DeepCopy(), replica.DeepCopy(), bcp,
+				pod("cluster1-pxc-0", true), pod("cluster1-pxc-1", false), pod("cluster1-pxc-2", true),
+			}
+			r := reconciler(buildFakeClient(objs...))
+
+			err = r.reconcileBackupSource(ctx, bcp, cluster)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, bcp.Status.Source)
+		})
+	}
+
+	t.Run("old crVersion", func(t *testing.T) {
+		bcp, err := readDefaultBackup("backup1", "test")
+		require.NoError(t, err)
+		bcp.Spec.Source = &pxcv1.PXCBackupSource{Policy: pxcv1.PXCBackupSourceLeastLoaded}
+
+		cluster := cluster.DeepCopy()
+		cluster.Spec.CRVersion = "1.19.0"
+
+		err = reconciler(buildFakeClient()).reconcileBackupSource(ctx, bcp, cluster)
+		assert.EqualError(t, err, "backup source requires crVersion 1.20.0 or newer")
+	})
+}
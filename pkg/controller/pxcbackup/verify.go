@@ -0,0 +1,125 @@
+package pxcbackup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/k8s"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup"
+)
+
+const (
+	verificationReasonRunning   = "VerificationRunning"
+	verificationReasonSucceeded = "VerificationSucceeded"
+	verificationReasonFailed    = "VerificationFailed"
+)
+
+// reconcileVerification runs the verification job for the succeeded backup
+// and sets the Verified condition once the job is finished.
+// It returns true if the verification is finished.
+func (r *ReconcilePerconaXtraDBClusterBackup) reconcileVerification(ctx context.Context, cr *api.PerconaXtraDBClusterBackup) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	if cond := meta.FindStatusCondition(cr.Status.Conditions, api.BackupConditionVerified); cond != nil && cond.Status != metav1.ConditionUnknown {
+		return true, nil
+	}
+
+	job := new(batchv1.Job)
+	err := r.client.Get(ctx, types.NamespacedName{Name: naming.BackupVerifyJobName(cr.Name), Namespace: cr.Namespace}, job)
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return false, errors.Wrap(err, "get verification job")
+	}
+	if k8sErrors.IsNotFound(err) {
+		job, err = r.verificationJob(ctx, cr)
+		if err != nil {
+			if errors.Is(err, errVerificationNotPossible) {
+				return true, r.setVerifiedCondition(ctx, cr, metav1.ConditionFalse, verificationReasonFailed, err.Error())
+			}
+			return false, err
+		}
+		if err := r.client.Create(ctx, job); err != nil && !k8sErrors.IsAlreadyExists(err) {
+			return false, errors.Wrap(err, "create verification job")
+		}
+		log.Info("Created backup verification job", "job", job.Name)
+
+		return false, r.setVerifiedCondition(ctx, cr, metav1.ConditionUnknown, verificationReasonRunning, "Verification job is running")
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			msg := "Backup is verified"
+			if job.Status.StartTime != nil && job.Status.CompletionTime != nil {
+				duration := job.Status.CompletionTime.Sub(job.Status.StartTime.Time).Round(time.Second)
+				msg = fmt.Sprintf("Backup is verified in %s", duration)
+			}
+			log.Info("Backup verification succeeded", "job", job.Name)
+
+			return true, r.setVerifiedCondition(ctx, cr, metav1.ConditionTrue, verificationReasonSucceeded, msg)
+		case batchv1.JobFailed:
+			log.Info("Backup verification failed", "job", job.Name, "reason", cond.Reason)
+
+			return true, r.setVerifiedCondition(ctx, cr, metav1.ConditionFalse, verificationReasonFailed,
+				fmt.Sprintf("Verification job %s failed: %s", job.Name, cond.Message))
+		}
+	}
+
+	return false, nil
+}
+
+var errVerificationNotPossible = errors.New("backup can't be verified")
+
+func (r *ReconcilePerconaXtraDBClusterBackup) verificationJob(ctx context.Context, cr *api.PerconaXtraDBClusterBackup) (*batchv1.Job, error) {
+	cluster, err := r.getCluster(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cluster.CheckNSetDefaults(r.serverVersion, logf.FromContext(ctx)); err != nil {
+		return nil, errors.Wrap(err, "wrong PXC options")
+	}
+
+	initImage, err := k8s.GetInitImage(ctx, cluster, r.client)
+	if err != nil {
+		return nil, errors.Wrap(err, "get init image")
+	}
+
+	job, err := backup.VerifyJob(ctx, cr, cluster, initImage, r.scheme)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errVerificationNotPossible, err)
+	}
+
+	return job, nil
+}
+
+func (r *ReconcilePerconaXtraDBClusterBackup) setVerifiedCondition(
+	ctx context.Context,
+	cr *api.PerconaXtraDBClusterBackup,
+	status metav1.ConditionStatus,
+	reason, msg string,
+) error {
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               api.BackupConditionVerified,
+		Status:             status,
+		Reason:             reason,
+		Message:            msg,
+		LastTransitionTime: metav1.Now(),
+	})
+
+	return r.updateStatus(ctx, cr)
+}
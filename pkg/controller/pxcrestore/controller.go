@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	batchv1 "k8s.io/api/batch/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	k8sretry "k8s.io/client-go/util/retry"
@@ -106,17 +107,30 @@ func (r *ReconcilePerconaXtraDBClusterRestore) Reconcile(ctx context.Context, re
 		return reconcile.Result{}, err
 	}
 
+	if cr.Status.State == api.RestoreFailed && cr.Annotations[api.AnnotationResumeRestore] != "" {
+		return r.resumeRestore(ctx, cr)
+	}
+
 	switch cr.Status.State {
 	case api.RestoreSucceeded, api.RestoreFailed:
 		if err := r.runJobFinalizers(ctx, cr); err != nil {
 			return reconcile.Result{}, errors.Wrap(err, "run job finalizers")
 		}
+		if cr.IsPartial() {
+			if err := r.cleanupPartialRestore(ctx, cr); err != nil {
+				return reconcile.Result{}, errors.Wrap(err, "cleanup partial restore")
+			}
+		}
 		return reconcile.Result{}, nil
 	}
 
 	cr.Status.Comments = ""
 
+	state := cr.Status.State
 	defer func() {
+		if cr.Status.State == api.RestoreFailed && state.IsResumable() {
+			cr.Status.FailedState = state
+		}
 		if err := setStatus(ctx, r.client, cr); err != nil {
 			log.Error(err, "failed to set status")
 		}
@@ -144,12 +158,21 @@ func (r *ReconcilePerconaXtraDBClusterRestore) Reconcile(ctx context.Context, re
 	}
 
 	cluster := new(api.PerconaXtraDBCluster)
-	if err := r.client.Get(ctx, types.NamespacedName{Name: cr.Spec.PXCCluster, Namespace: cr.Namespace}, cluster); err != nil {
+	if err := r.client.Get(ctx, types.NamespacedName{Name: cr.TargetCluster(), Namespace: cr.Namespace}, cluster); err != nil {
+		if k8serrors.IsNotFound(err) && cr.Spec.NewCluster != nil && cr.Status.State == api.RestoreStarting {
+			if err := r.createNewCluster(ctx, cr); err != nil {
+				cr.Status.State = api.RestoreFailed
+				cr.Status.Comments = err.Error()
+				return reconcile.Result{}, errors.Wrap(err, "create new cluster")
+			}
+			log.Info("creating new cluster", "cluster", cr.TargetCluster(), "source", cr.Spec.PXCCluster)
+			return rr, nil
+		}
 		if k8serrors.IsNotFound(err) {
 			cr.Status.State = api.RestoreFailed
 			cr.Status.Comments = err.Error()
 		}
-		return reconcile.Result{}, errors.Wrapf(err, "get cluster %s", cr.Spec.PXCCluster)
+		return reconcile.Result{}, errors.Wrapf(err, "get cluster %s", cr.TargetCluster())
 	}
 
 	if err := cluster.CheckNSetDefaults(r.serverVersion, log); err != nil {
@@ -174,7 +197,7 @@ func (r *ReconcilePerconaXtraDBClusterRestore) Reconcile(ctx context.Context, re
 	case api.RestoreStarting:
 		return r.reconcileStateNew(ctx, restorer, cr, cluster, bcp)
 	case api.RestoreStopCluster:
-		return r.reconcileStateStopCluster(ctx, restorer, cr, cluster)
+		return r.reconcileStateStopCluster(ctx, restorer, cr, cluster, bcp)
 	case api.RestoreRestore:
 		return r.reconcileStateRestore(ctx, restorer, cr, cluster)
 	case api.RestorePITR:
@@ -183,6 +206,12 @@ func (r *ReconcilePerconaXtraDBClusterRestore) Reconcile(ctx context.Context, re
 		return r.reconcileStatePrepareCluster(ctx, cr, bcp, cluster)
 	case api.RestoreStartCluster:
 		return r.reconcileStateStartCluster(ctx, restorer, cr, cluster)
+	case api.RestoreExportTables:
+		return r.reconcileStateExportTables(ctx, cr)
+	case api.RestoreImportTables:
+		return r.reconcileStateImportTables(ctx, cr, cluster)
+	case api.RestoreLoadLogical:
+		return r.reconcileStateLoadLogical(ctx, cr)
 	}
 
 	return reconcile.Result{}, errors.Errorf("unknown state: %s", cr.Status.State)
@@ -229,10 +258,20 @@ func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateStartCluster(ctx co
 			return rr, errors.Wrap(err, "failed to finalize restore")
 		}
 
+		if cr.Status.Progress != nil {
+			cr.Status.Progress.CompletePhase(api.RestorePhaseSST)
+		}
 		cr.Status.State = api.RestoreSucceeded
 		return rr, nil
 	}
 
+	// the restored node is started first and the other nodes join the cluster with SST
+	if p := cr.Status.Progress; p != nil && p.Phase != api.RestorePhaseSST && cluster.Status.PXC.Ready > 0 {
+		p.Phase = api.RestorePhaseSST
+		p.PhaseStarted = &metav1.Time{Time: time.Now()}
+		p.ETA = nil
+	}
+
 	log.Info("Waiting for cluster to start", "cluster", cluster.Name)
 	return rr, nil
 }
@@ -268,6 +307,21 @@ func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateNew(ctx context.Con
 		RequeueAfter: time.Second * 5,
 	}
 
+	if bcp.Status.IsLogical() {
+		return r.reconcileLogicalStateNew(ctx, restorer, cr, cluster)
+	}
+
+	if cr.IsPartial() {
+		return r.reconcilePartialStateNew(ctx, restorer, cr, cluster, bcp)
+	}
+
+	// the new cluster is restored once it's started,
+	// so the secrets and volumes of the cluster are created by the operator
+	if cr.Spec.NewCluster != nil && (cluster.Status.ObservedGeneration != cluster.Generation || cluster.Status.PXC.Status != api.AppStateReady) {
+		log.Info("Waiting for new cluster to start", "cluster", cluster.Name)
+		return rr, nil
+	}
+
 	if cr.Spec.PITR != nil {
 		if err := backup.CheckPITRErrors(ctx, r.client, r.clientcmd, cluster, r.newStorageClientFunc); err != nil {
 			return reconcile.Result{}, err
@@ -288,6 +342,14 @@ func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateNew(ctx context.Con
 		}
 	}
 
+	if cr.IsCrossNamespace() {
+		if err := validateBackupVersion(bcp, cluster); err != nil {
+			cr.Status.Comments = err.Error()
+			cr.Status.State = api.RestoreFailed
+			return reconcile.Result{}, nil
+		}
+	}
+
 	if err := validate(ctx, restorer, cr); err != nil {
 		if errors.Is(err, errWaitValidate) {
 			return rr, nil
@@ -296,6 +358,20 @@ func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateNew(ctx context.Con
 		cr.Status.State = api.RestoreFailed
 		return rr, err
 	}
+
+	// dry run only lists the transactions, so the cluster isn't stopped
+	if cr.IsDryRun() {
+		log.Info("listing point-in-time recovery transactions", "cluster", cr.TargetCluster())
+		if err := createRestoreJob(ctx, r.client, restorer, true); err != nil {
+			if errors.Is(err, errWaitInit) {
+				return rr, nil
+			}
+			return rr, errors.Wrap(err, "run pitr dry run")
+		}
+		cr.Status.State = api.RestorePITR
+		return rr, nil
+	}
+
 	cr.Status.PXCSize = cluster.Spec.PXC.Size
 	if cluster.Spec.ProxySQL != nil {
 		cr.Status.ProxySQLSize = cluster.Spec.ProxySQL.Size
@@ -305,7 +381,7 @@ func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateNew(ctx context.Con
 	}
 	cr.Status.Unsafe = cluster.Spec.Unsafe
 
-	log.Info("stopping cluster", "cluster", cr.Spec.PXCCluster)
+	log.Info("stopping cluster", "cluster", cr.TargetCluster())
 	cr.Status.State = api.RestoreStopCluster
 	return rr, nil
 }
@@ -326,6 +402,14 @@ func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateRestore(ctx context
 		Name:      restorerJob.Name,
 		Namespace: restorerJob.Namespace,
 	}, job); err != nil {
+		// the job of the resumed restore is deleted
+		if k8serrors.IsNotFound(err) {
+			log.Info("resuming restore", "cluster", cr.TargetCluster(), "backup", cr.Spec.BackupName)
+			if err := createRestoreJob(ctx, r.client, restorer, false); err != nil && !errors.Is(err, errWaitInit) {
+				return rr, errors.Wrap(err, "create restore job")
+			}
+			return rr, nil
+		}
 		return rr, errors.Wrap(err, "failed to get restore job")
 	}
 
@@ -336,10 +420,25 @@ func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateRestore(ctx context
 		return rr, err
 	}
 	if !finished {
+		if cr.Status.Progress != nil {
+			if err := r.updateProgress(ctx, cr, job, naming.RestoreProgressDir); err != nil {
+				log.Error(err, "failed to update restore progress")
+			}
+		}
 		log.Info("Waiting for restore job to finish", "job", job.Name)
 		return rr, nil
 	}
 
+	if p := cr.Status.Progress; p != nil {
+		p.CompletePhase(api.RestorePhaseDownload)
+		p.CompletePhase(api.RestorePhasePrepare)
+		p.CompletePhase(api.RestorePhaseCopyBack)
+		if cr.Spec.PITR != nil {
+			p.Phase = api.RestorePhasePITR
+			p.PhaseStarted = &metav1.Time{Time: time.Now()}
+		}
+	}
+
 	if cluster.Spec.Backup.PITR.Enabled {
 		if err := binlogcollector.InvalidateCache(ctx, r.client, cluster); err != nil {
 			log.Error(err, "failed to invalidate binlog collector cache")
@@ -380,7 +479,7 @@ func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateRestore(ctx context
 			}
 		}
 
-		log.Info("point-in-time recovering", "cluster", cr.Spec.PXCCluster)
+		log.Info("point-in-time recovering", "cluster", cr.TargetCluster())
 		if err := createRestoreJob(ctx, r.client, restorer, true); err != nil {
 			if errors.Is(err, errWaitInit) {
 				return rr, nil
@@ -392,10 +491,10 @@ func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateRestore(ctx context
 	}
 
 	if cluster.CompareVersionWith("1.18.0") >= 0 {
-		log.Info("preparing cluster", "cluster", cr.Spec.PXCCluster)
+		log.Info("preparing cluster", "cluster", cr.TargetCluster())
 		cr.Status.State = api.RestorePrepareCluster
 	} else {
-		log.Info("starting cluster", "cluster", cr.Spec.PXCCluster)
+		log.Info("starting cluster", "cluster", cr.TargetCluster())
 		cr.Status.State = api.RestoreStartCluster
 	}
 
@@ -418,6 +517,14 @@ func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStatePITR(ctx context.Co
 		Name:      restorerJob.Name,
 		Namespace: restorerJob.Namespace,
 	}, job); err != nil {
+		// the job of the resumed restore is deleted, the applied transactions are skipped by the new job
+		if k8serrors.IsNotFound(err) {
+			log.Info("resuming point-in-time recovery", "cluster", cr.TargetCluster())
+			if err := createRestoreJob(ctx, r.client, restorer, true); err != nil && !errors.Is(err, errWaitInit) {
+				return rr, errors.Wrap(err, "create pitr job")
+			}
+			return rr, nil
+		}
 		return rr, errors.Wrap(err, "failed to get pitr job")
 	}
 
@@ -428,16 +535,31 @@ func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStatePITR(ctx context.Co
 		return rr, err
 	}
 	if !finished {
+		if cr.Status.Progress != nil {
+			if err := r.updateProgress(ctx, cr, job, naming.PITRRestoreProgressDir); err != nil {
+				log.Error(err, "failed to update restore progress")
+			}
+		}
 		log.Info("Waiting for restore job to finish", "job", job.Name)
 		return rr, nil
 	}
 
-	log.Info("starting cluster", "cluster", cr.Spec.PXCCluster)
+	if cr.Status.Progress != nil {
+		cr.Status.Progress.CompletePhase(api.RestorePhasePITR)
+	}
+
+	if cr.IsDryRun() {
+		cr.Status.Comments = fmt.Sprintf("Transactions of the point-in-time recovery are listed in the logs of job %s", job.Name)
+		cr.Status.State = api.RestoreSucceeded
+		return rr, nil
+	}
+
+	log.Info("starting cluster", "cluster", cr.TargetCluster())
 	cr.Status.State = api.RestoreStartCluster
 	return rr, nil
 }
 
-func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateStopCluster(ctx context.Context, restorer Restorer, cr *api.PerconaXtraDBClusterRestore, cluster *api.PerconaXtraDBCluster) (reconcile.Result, error) {
+func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateStopCluster(ctx context.Context, restorer Restorer, cr *api.PerconaXtraDBClusterRestore, cluster *api.PerconaXtraDBCluster, bcp *api.PerconaXtraDBClusterBackup) (reconcile.Result, error) {
 	log := logf.FromContext(ctx)
 	rr := reconcile.Result{
 		// TODO: do not depend on the RequeueAfter
@@ -450,7 +572,7 @@ func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateStopCluster(ctx con
 		return rr, errors.Wrapf(err, "stop cluster %s", cluster.Name)
 	}
 
-	log.Info("starting restore", "cluster", cr.Spec.PXCCluster, "backup", cr.Spec.BackupName)
+	log.Info("starting restore", "cluster", cr.TargetCluster(), "backup", cr.Spec.BackupName)
 	if err := createRestoreJob(ctx, r.client, restorer, false); err != nil {
 		if errors.Is(err, errWaitInit) {
 			return rr, nil
@@ -459,6 +581,13 @@ func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateStopCluster(ctx con
 		cr.Status.State = api.RestoreFailed
 		return rr, err
 	}
+	if cluster.CompareVersionWith("1.20.0") >= 0 && cr.Status.Progress == nil {
+		cr.Status.Progress = new(api.RestoreProgress)
+		// the size of the incremental backups doesn't include their base backups
+		if bcp.Status.Size != nil && !bcp.Status.IsIncremental() {
+			cr.Status.Progress.BytesTotal = bcp.Status.Size.Value()
+		}
+	}
 	cr.Status.State = api.RestoreRestore
 	return rr, nil
 }
@@ -525,7 +654,7 @@ func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStatePrepareCluster(
 		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 
-	log.Info("starting cluster", "cluster", cr.Spec.PXCCluster)
+	log.Info("starting cluster", "cluster", cr.TargetCluster())
 	cr.Status.State = api.RestoreStartCluster
 	return reconcile.Result{}, nil
 }
@@ -535,6 +664,8 @@ func (r *ReconcilePerconaXtraDBClusterRestore) runJobFinalizers(ctx context.Cont
 		naming.RestoreJobName(cr, false),
 		naming.RestoreJobName(cr, true),
 		naming.PrepareJobName(cr),
+		naming.ExportJobName(cr),
+		naming.LogicalRestoreJobName(cr),
 	} {
 		if err := k8sretry.RetryOnConflict(k8sretry.DefaultRetry, func() error {
 			job := new(batchv1.Job)
@@ -0,0 +1,159 @@
+package pxcrestore
+
+import (
+	"context"
+
+	v "github.com/hashicorp/go-version"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+)
+
+// getCrossNamespaceBackup returns the backup from another namespace prepared to be restored
+// in the namespace of the restore. The storage secrets of the backup are copied
+// to the namespace of the restore and the returned backup refers to the copies.
+func getCrossNamespaceBackup(ctx context.Context, cl client.Client, cr *api.PerconaXtraDBClusterRestore) (*api.PerconaXtraDBClusterBackup, error) {
+	bcp := &api.PerconaXtraDBClusterBackup{}
+	if err := cl.Get(ctx, types.NamespacedName{Name: cr.Spec.BackupName, Namespace: cr.Spec.BackupNamespace}, bcp); err != nil {
+		return bcp, errors.Wrapf(err, "get backup %s/%s", cr.Spec.BackupNamespace, cr.Spec.BackupName)
+	}
+	if bcp.Status.State != api.BackupSucceeded {
+		return bcp, errors.Errorf("backup %s/%s didn't finished yet, current state: %s", bcp.Namespace, bcp.Name, bcp.Status.State)
+	}
+
+	source := new(api.PerconaXtraDBCluster)
+	if err := cl.Get(ctx, types.NamespacedName{Name: bcp.Spec.PXCCluster, Namespace: bcp.Namespace}, source); err != nil {
+		return bcp, errors.Wrapf(err, "get cluster %s/%s", bcp.Namespace, bcp.Spec.PXCCluster)
+	}
+	if !source.Spec.Backup.IsRestoreAllowedTo(cr.Namespace) {
+		return bcp, errors.Errorf("cluster %s/%s doesn't allow restoring its backups in namespace %s", source.Namespace, source.Name, cr.Namespace)
+	}
+	if bcp.Status.PXCVersion == "" {
+		bcp.Status.PXCVersion = source.Status.PXC.Version
+	}
+	if err := checkVaultSecret(ctx, cl, bcp); err != nil {
+		return bcp, err
+	}
+
+	copySecret := func(name string) (string, error) {
+		if name == "" {
+			return "", nil
+		}
+		return copyBackupSecret(ctx, cl, cr, bcp.Namespace, name)
+	}
+
+	var err error
+	if s3 := bcp.Status.S3; s3 != nil {
+		if s3.CredentialsSecret, err = copySecret(s3.CredentialsSecret); err != nil {
+			return bcp, err
+		}
+		if s3.CABundle != nil {
+			if s3.CABundle.Name, err = copySecret(s3.CABundle.Name); err != nil {
+				return bcp, err
+			}
+		}
+	}
+	if azure := bcp.Status.Azure; azure != nil {
+		if azure.CredentialsSecret, err = copySecret(azure.CredentialsSecret); err != nil {
+			return bcp, err
+		}
+	}
+	if gcs := bcp.Status.GCS; gcs != nil {
+		if gcs.CredentialsSecret, err = copySecret(gcs.CredentialsSecret); err != nil {
+			return bcp, err
+		}
+	}
+	if enc := bcp.Status.Encryption; enc != nil && enc.KeySecret != nil {
+		if enc.KeySecret.Name, err = copySecret(enc.KeySecret.Name); err != nil {
+			return bcp, err
+		}
+	}
+
+	bcp.Namespace = cr.Namespace
+
+	return bcp, nil
+}
+
+// checkVaultSecret returns an error if the backup was taken by the cluster which uses
+// the Vault keyring. The restore job and the restored cluster use the Vault secret of
+// the target cluster, so the tablespaces of such backups can't be decrypted after
+// a restore in another namespace.
+func checkVaultSecret(ctx context.Context, cl client.Client, bcp *api.PerconaXtraDBClusterBackup) error {
+	if bcp.Status.VaultSecretName == "" {
+		return nil
+	}
+
+	err := cl.Get(ctx, types.NamespacedName{Name: bcp.Status.VaultSecretName, Namespace: bcp.Namespace}, new(corev1.Secret))
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "get secret %s/%s", bcp.Namespace, bcp.Status.VaultSecretName)
+	}
+
+	return errors.Errorf("backup %s/%s is encrypted with the Vault keyring from secret %s, backups which use the Vault keyring can't be restored in another namespace",
+		bcp.Namespace, bcp.Name, bcp.Status.VaultSecretName)
+}
+
+// copyBackupSecret copies the secret to the namespace of the restore and returns the name of the copy.
+// The copy is owned by the restore, so it's deleted together with the restore.
+func copyBackupSecret(ctx context.Context, cl client.Client, cr *api.PerconaXtraDBClusterRestore, namespace, name string) (string, error) {
+	src := new(corev1.Secret)
+	if err := cl.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, src); err != nil {
+		return "", errors.Wrapf(err, "get secret %s/%s", namespace, name)
+	}
+
+	dst := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.RestoreSecretName(cr, name),
+			Namespace: cr.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, cl, dst, func() error {
+		dst.Data = src.Data
+		if dst.CreationTimestamp.IsZero() {
+			dst.Type = src.Type
+		}
+		return controllerutil.SetOwnerReference(cr, dst, cl.Scheme())
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "copy secret %s/%s", namespace, name)
+	}
+
+	return dst.Name, nil
+}
+
+// validateBackupVersion checks that the backup can be restored to the cluster.
+// Physical backups can be restored only to the same major version of PXC
+// which isn't older than the version the backup was taken on.
+func validateBackupVersion(bcp *api.PerconaXtraDBClusterBackup, cluster *api.PerconaXtraDBCluster) error {
+	if bcp.Status.PXCVersion == "" || cluster.Status.PXC.Version == "" {
+		return nil
+	}
+
+	bcpVersion, err := v.NewVersion(bcp.Status.PXCVersion)
+	if err != nil {
+		return errors.Wrapf(err, "parse backup version %s", bcp.Status.PXCVersion)
+	}
+	clusterVersion, err := v.NewVersion(cluster.Status.PXC.Version)
+	if err != nil {
+		return errors.Wrapf(err, "parse cluster version %s", cluster.Status.PXC.Version)
+	}
+
+	bs, cs := bcpVersion.Segments(), clusterVersion.Segments()
+	if bs[0] != cs[0] || bs[1] != cs[1] {
+		return errors.Errorf("backup of PXC %s can't be restored to cluster with PXC %s: major versions differ", bcp.Status.PXCVersion, cluster.Status.PXC.Version)
+	}
+	if clusterVersion.Core().LessThan(bcpVersion.Core()) {
+		return errors.Errorf("backup of PXC %s can't be restored to cluster with older PXC %s", bcp.Status.PXCVersion, cluster.Status.PXC.Version)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package pxcrestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+)
+
+func TestGetCrossNamespaceBackup(t *testing.T) {
+	ctx := context.Background()
+
+	const clusterName = "prod"
+	const sourceNamespace = "production"
+	const targetNamespace = "staging"
+	const backupName = "prod-backup"
+	const s3SecretName = "my-cluster-name-backup-s3"
+
+	source := readDefaultCR(t, clusterName, sourceNamespace)
+	source.Status.PXC.Version = "8.0.36-28.1"
+
+	bcp := readDefaultBackup(t, backupName, sourceNamespace)
+	bcp.Spec.PXCCluster = clusterName
+	bcp.Spec.StorageName = "s3-us-west"
+	bcp.Status.Destination.SetS3Destination("some-bucket", "prod-2024-01-01-10:00:00-full")
+	bcp.Status.S3 = &api.BackupStorageS3Spec{
+		Bucket:            "some-bucket",
+		CredentialsSecret: s3SecretName,
+	}
+	bcp.Status.State = api.BackupSucceeded
+	bcp.Status.VaultSecretName = clusterName + "-vault"
+
+	s3Secret := readDefaultS3Secret(t, s3SecretName, sourceNamespace)
+	vaultSecret := &corev1.Secret{}
+	vaultSecret.Name = clusterName + "-vault"
+	vaultSecret.Namespace = sourceNamespace
+
+	cr := readDefaultRestore(t, "staging-refresh", targetNamespace)
+	cr.Spec.PXCCluster = "staging"
+	cr.Spec.BackupName = backupName
+	cr.Spec.BackupNamespace = sourceNamespace
+
+	tests := []struct {
+		name        string
+		allowed     []string
+		objects     []runtime.Object
+		expectedErr string
+	}{
+		{
+			name:    "allowed namespace",
+			allowed: []string{targetNamespace},
+			objects: []runtime.Object{s3Secret},
+		},
+		{
+			name:    "all namespaces allowed",
+			allowed: []string{"*"},
+			objects: []runtime.Object{s3Secret},
+		},
+		{
+			name:        "namespace is not allowed",
+			allowed:     []string{"dev"},
+			objects:     []runtime.Object{s3Secret},
+			expectedErr: "cluster production/prod doesn't allow restoring its backups in namespace staging",
+		},
+		{
+			name:        "vault keyring",
+			allowed:     []string{targetNamespace},
+			objects:     []runtime.Object{s3Secret, vaultSecret},
+			expectedErr: "backup production/prod-backup is encrypted with the Vault keyring from secret prod-vault, backups which use the Vault keyring can't be restored in another namespace",
+		},
+		{
+			name:        "no secret",
+			allowed:     []string{targetNamespace},
+			expectedErr: `get secret production/my-cluster-name-backup-s3: secrets "my-cluster-name-backup-s3" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := source.DeepCopy()
+			cluster.Spec.Backup.AllowedRestoreNamespaces = tt.allowed
+
+			cl := buildFakeClient(append(tt.objects, cluster, bcp.DeepCopy(), cr.DeepCopy())...)
+
+			restore := new(api.PerconaXtraDBClusterRestore)
+			if err := cl.Get(ctx, types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, restore); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := getBackup(ctx, cl, restore)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			assert.Equal(t, targetNamespace, got.Namespace)
+			assert.Equal(t, "8.0.36-28.1", got.Status.PXCVersion)
+			assert.Equal(t, "restore-staging-refresh-"+s3SecretName, got.Status.S3.CredentialsSecret)
+
+			secret := new(corev1.Secret)
+			err = cl.Get(ctx, types.NamespacedName{Name: got.Status.S3.CredentialsSecret, Namespace: targetNamespace}, secret)
+			assert.NoError(t, err)
+			assert.Equal(t, s3Secret.Data, secret.Data)
+			assert.Len(t, secret.OwnerReferences, 1)
+			assert.Equal(t, restore.Name, secret.OwnerReferences[0].Name)
+		})
+	}
+}
+
+func TestValidateBackupVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		backupVersion  string
+		clusterVersion string
+		wantErr        bool
+	}{
+		{
+			name:           "same version",
+			backupVersion:  "8.0.36-28.1",
+			clusterVersion: "8.0.36-28.1",
+		},
+		{
+			name:           "newer cluster",
+			backupVersion:  "8.0.35-27.1",
+			clusterVersion: "8.0.36-28.1",
+		},
+		{
+			name:           "unknown backup version",
+			clusterVersion: "8.0.36-28.1",
+		},
+		{
+			name:           "older cluster",
+			backupVersion:  "8.0.36-28.1",
+			clusterVersion: "8.0.35-27.1",
+			wantErr:        true,
+		},
+		{
+			name:           "different major version",
+			backupVersion:  "8.0.36-28.1",
+			clusterVersion: "8.4.0-1.1",
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bcp := new(api.PerconaXtraDBClusterBackup)
+			bcp.Status.PXCVersion = tt.backupVersion
+			cluster := new(api.PerconaXtraDBCluster)
+			cluster.Status.PXC.Version = tt.clusterVersion
+
+			err := validateBackupVersion(bcp, cluster)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
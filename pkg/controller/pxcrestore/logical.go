@@ -0,0 +1,102 @@
+package pxcrestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup"
+)
+
+// reconcileLogicalStateNew starts the job which loads the logical backup into the running cluster.
+// The cluster isn't stopped and the version of the backup isn't checked,
+// so the logical backup can be restored into a cluster of another major version.
+func (r *ReconcilePerconaXtraDBClusterRestore) reconcileLogicalStateNew(ctx context.Context, restorer Restorer, cr *api.PerconaXtraDBClusterRestore, cluster *api.PerconaXtraDBCluster) (reconcile.Result, error) {
+	log := logf.FromContext(ctx)
+	rr := reconcile.Result{
+		// TODO: do not depend on the RequeueAfter
+		RequeueAfter: time.Second * 5,
+	}
+
+	if cr.Spec.PITR != nil {
+		cr.Status.Comments = "point-in-time recovery is not supported for logical backups"
+		cr.Status.State = api.RestoreFailed
+		return reconcile.Result{}, nil
+	}
+
+	if cluster.Status.ObservedGeneration != cluster.Generation || cluster.Status.PXC.Status != api.AppStateReady {
+		log.Info("Waiting for cluster to be ready", "cluster", cluster.Name)
+		return rr, nil
+	}
+
+	if err := validate(ctx, restorer, cr); err != nil {
+		if errors.Is(err, errWaitValidate) {
+			return rr, nil
+		}
+		cr.Status.Comments = fmt.Sprintf("failed to validate restore job: %s", err.Error())
+		cr.Status.State = api.RestoreFailed
+		return rr, err
+	}
+
+	restoreJob, err := restorer.Job(ctx)
+	if err != nil {
+		return rr, errors.Wrap(err, "failed to get restore job")
+	}
+	job, err := backup.LogicalRestoreJob(restoreJob, cr, cluster)
+	if err != nil {
+		cr.Status.Comments = err.Error()
+		cr.Status.State = api.RestoreFailed
+		return rr, err
+	}
+
+	if err := r.client.Create(ctx, job); err != nil && !k8serrors.IsAlreadyExists(err) {
+		cr.Status.Comments = fmt.Sprintf("failed to run logical restore: %s", err.Error())
+		cr.Status.State = api.RestoreFailed
+		return rr, errors.Wrap(err, "create logical restore job")
+	}
+
+	log.Info("loading logical backup", "cluster", cr.TargetCluster(), "backup", cr.Spec.BackupName)
+	cr.Status.State = api.RestoreLoadLogical
+	return rr, nil
+}
+
+func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateLoadLogical(ctx context.Context, cr *api.PerconaXtraDBClusterRestore) (reconcile.Result, error) {
+	log := logf.FromContext(ctx)
+	rr := reconcile.Result{
+		// TODO: do not depend on the RequeueAfter
+		RequeueAfter: time.Second * 5,
+	}
+
+	job := new(batchv1.Job)
+	if err := r.client.Get(ctx, client.ObjectKey{Name: naming.LogicalRestoreJobName(cr), Namespace: cr.Namespace}, job); err != nil {
+		if k8serrors.IsNotFound(err) {
+			cr.Status.Comments = "logical restore job is not found"
+			cr.Status.State = api.RestoreFailed
+		}
+		return rr, errors.Wrap(err, "get logical restore job")
+	}
+
+	finished, err := isJobFinished(job)
+	if err != nil {
+		cr.Status.Comments = err.Error()
+		cr.Status.State = api.RestoreFailed
+		return rr, nil
+	}
+	if !finished {
+		log.Info("Waiting for logical backup to be loaded", "job", job.Name)
+		return rr, nil
+	}
+
+	log.Info("logical backup is loaded", "cluster", cr.TargetCluster())
+	cr.Status.State = api.RestoreSucceeded
+	return rr, nil
+}
@@ -0,0 +1,153 @@
+package pxcrestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+	fakestorage "github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage/fake"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/version"
+)
+
+func TestLogicalRestore(t *testing.T) {
+	ctx := context.Background()
+
+	const clusterName = "test-cluster"
+	const namespace = "namespace"
+	const backupName = clusterName + "-backup"
+	const restoreName = clusterName + "-restore"
+	const s3SecretName = "my-cluster-name-backup-s3"
+
+	cluster := readDefaultCR(t, clusterName, namespace)
+	require.NoError(t, cluster.CheckNSetDefaults(new(version.ServerVersion), logf.FromContext(ctx)))
+	cluster.Status.PXC.Status = api.AppStateReady
+	cluster.Spec.Backup.LogicalImage = "percona/percona-xtrabackup:8.0-mydumper"
+
+	bcp := readDefaultBackup(t, backupName, namespace)
+	bcp.Spec.StorageName = "s3-us-west"
+	bcp.Spec.Type = api.PXCBackupTypeLogical
+	bcp.Status.Type = api.PXCBackupTypeLogical
+	bcp.Status.State = api.BackupSucceeded
+	bcp.Status.Destination.SetS3Destination("some-dest", "dest")
+	bcp.Status.S3 = &api.BackupStorageS3Spec{
+		Bucket:            "some-bucket",
+		CredentialsSecret: s3SecretName,
+	}
+
+	cr := readDefaultRestore(t, restoreName, namespace)
+	cr.Spec.BackupName = backupName
+	cr.Spec.PXCCluster = clusterName
+	cr.Spec.Databases = []string{"app"}
+
+	nn := types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}
+
+	newReconciler := func(cr *api.PerconaXtraDBClusterRestore) *ReconcilePerconaXtraDBClusterRestore {
+		cl := buildFakeClient(cr, cluster.DeepCopy(), bcp.DeepCopy(),
+			readDefaultCRSecret(t, clusterName+"-secrets", namespace),
+			readDefaultS3Secret(t, s3SecretName, namespace))
+		r := reconciler(cl)
+		r.newStorageClientFunc = func(ctx context.Context, opts storage.Options) (storage.Storage, error) {
+			defaultFakeClient, err := fakestorage.NewStorage(ctx, opts)
+			if err != nil {
+				return nil, err
+			}
+			return &fakeStorageClient{defaultFakeClient, false, false}, nil
+		}
+		return r
+	}
+
+	t.Run("load logical backup", func(t *testing.T) {
+		r := newReconciler(cr.DeepCopy())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: nn})
+		require.NoError(t, err)
+
+		restore := new(api.PerconaXtraDBClusterRestore)
+		require.NoError(t, r.client.Get(ctx, nn, restore))
+		assert.Equal(t, api.RestoreLoadLogical, restore.Status.State, restore.Status.Comments)
+
+		job := new(batchv1.Job)
+		require.NoError(t, r.client.Get(ctx, types.NamespacedName{Name: naming.LogicalRestoreJobName(cr), Namespace: namespace}, job))
+		assert.Contains(t, job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "LOGICAL_RESTORE_DATABASES", Value: "app"})
+		assert.Equal(t, "percona/percona-xtrabackup:8.0-mydumper", job.Spec.Template.Spec.Containers[0].Image)
+
+		// the cluster isn't stopped by the logical restore
+		current := new(api.PerconaXtraDBCluster)
+		require.NoError(t, r.client.Get(ctx, types.NamespacedName{Name: clusterName, Namespace: namespace}, current))
+		assert.False(t, current.Spec.Pause)
+
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: nn})
+		require.NoError(t, err)
+		require.NoError(t, r.client.Get(ctx, nn, restore))
+		assert.Equal(t, api.RestoreLoadLogical, restore.Status.State)
+
+		job.Status.Conditions = []batchv1.JobCondition{
+			{
+				Type:   batchv1.JobComplete,
+				Status: corev1.ConditionTrue,
+			},
+		}
+		require.NoError(t, r.client.Status().Update(ctx, job))
+
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: nn})
+		require.NoError(t, err)
+		require.NoError(t, r.client.Get(ctx, nn, restore))
+		assert.Equal(t, api.RestoreSucceeded, restore.Status.State)
+	})
+
+	t.Run("failed job", func(t *testing.T) {
+		cr := cr.DeepCopy()
+		cr.Status.State = api.RestoreLoadLogical
+		r := newReconciler(cr)
+
+		job := &batchv1.Job{}
+		job.Name = naming.LogicalRestoreJobName(cr)
+		job.Namespace = namespace
+		job.Status.Conditions = []batchv1.JobCondition{
+			{
+				Type:    batchv1.JobFailed,
+				Status:  corev1.ConditionTrue,
+				Message: "BackoffLimitExceeded",
+			},
+		}
+		require.NoError(t, r.client.Create(ctx, job))
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: nn})
+		require.NoError(t, err)
+
+		restore := new(api.PerconaXtraDBClusterRestore)
+		require.NoError(t, r.client.Get(ctx, nn, restore))
+		assert.Equal(t, api.RestoreFailed, restore.Status.State)
+		assert.Contains(t, restore.Status.Comments, "BackoffLimitExceeded")
+	})
+
+	t.Run("pitr", func(t *testing.T) {
+		cr := cr.DeepCopy()
+		cr.Spec.Databases = nil
+		cr.Spec.PITR = &api.PITR{
+			Type: "latest",
+			BackupSource: &api.PXCBackupStatus{
+				StorageName: "s3-us-west",
+			},
+		}
+		r := newReconciler(cr)
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: nn})
+		require.NoError(t, err)
+
+		restore := new(api.PerconaXtraDBClusterRestore)
+		require.NoError(t, r.client.Get(ctx, nn, restore))
+		assert.Equal(t, api.RestoreFailed, restore.Status.State)
+		assert.Equal(t, "point-in-time recovery is not supported for logical backups", restore.Status.Comments)
+	})
+}
@@ -0,0 +1,140 @@
+package pxcrestore
+
+import (
+	"context"
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+)
+
+// createNewCluster creates the cluster the backup is restored into.
+// The cluster isn't owned by the restore, so it's kept after the restore is deleted.
+func (r *ReconcilePerconaXtraDBClusterRestore) createNewCluster(ctx context.Context, cr *api.PerconaXtraDBClusterRestore) error {
+	source := new(api.PerconaXtraDBCluster)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: cr.Spec.PXCCluster, Namespace: cr.Namespace}, source); err != nil {
+		return errors.Wrapf(err, "get cluster %s", cr.Spec.PXCCluster)
+	}
+
+	cluster, err := newCluster(cr, source)
+	if err != nil {
+		return err
+	}
+
+	if err := r.copyUsersSecret(ctx, source, cluster); err != nil {
+		return errors.Wrap(err, "copy users secret")
+	}
+
+	if err := r.client.Create(ctx, cluster); err != nil {
+		return errors.Wrapf(err, "create cluster %s", cluster.Name)
+	}
+
+	return nil
+}
+
+// copyUsersSecret creates the users secret of the new cluster with the passwords of the source cluster,
+// the restored datadir has the users of the source cluster. An existing secret isn't changed.
+func (r *ReconcilePerconaXtraDBClusterRestore) copyUsersSecret(ctx context.Context, source, cluster *api.PerconaXtraDBCluster) error {
+	sourceName := source.Spec.SecretsName
+	if sourceName == "" {
+		sourceName = source.Name + "-secrets"
+	}
+	if cluster.Spec.SecretsName == sourceName {
+		return nil
+	}
+
+	err := r.client.Get(ctx, types.NamespacedName{Name: cluster.Spec.SecretsName, Namespace: cluster.Namespace}, new(corev1.Secret))
+	if err == nil {
+		return nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return errors.Wrapf(err, "get secret %s", cluster.Spec.SecretsName)
+	}
+
+	sourceSecret := new(corev1.Secret)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: sourceName, Namespace: source.Namespace}, sourceSecret); err != nil {
+		return errors.Wrapf(err, "get secret %s", sourceName)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.Spec.SecretsName,
+			Namespace: cluster.Namespace,
+		},
+		Type: sourceSecret.Type,
+		Data: sourceSecret.Data,
+	}
+	if err := r.client.Create(ctx, secret); err != nil {
+		return errors.Wrapf(err, "create secret %s", secret.Name)
+	}
+
+	return nil
+}
+
+// newCluster returns the cluster the backup is restored into. The spec of the cluster
+// is the template of the restore or the spec of the source cluster with the overrides applied.
+// The cloned spec doesn't refer to the secrets of the source cluster except the vault secret,
+// which is required to restore encrypted backups, and doesn't write to the backup storages.
+// The users secret of the new cluster is <name>-secrets if it isn't set by the template or the overrides.
+func newCluster(cr *api.PerconaXtraDBClusterRestore, source *api.PerconaXtraDBCluster) (*api.PerconaXtraDBCluster, error) {
+	nc := cr.Spec.NewCluster
+
+	spec := source.Spec.DeepCopy()
+	spec.Pause = false
+	spec.SecretsName = ""
+	spec.SSLSecretName = ""
+	spec.SSLInternalSecretName = ""
+	spec.LogCollectorSecretName = ""
+	if spec.VaultSecretName == "" {
+		spec.VaultSecretName = source.Name + "-vault"
+	}
+	if spec.Backup != nil {
+		spec.Backup.Schedule = nil
+		spec.Backup.PITR.Enabled = false
+	}
+	if spec.PXC != nil {
+		spec.PXC.ReplicationChannels = nil
+	}
+
+	if nc.Template != nil && len(nc.Template.Raw) > 0 {
+		spec = new(api.PerconaXtraDBClusterSpec)
+		if err := json.Unmarshal(nc.Template.Raw, spec); err != nil {
+			return nil, errors.Wrap(err, "unmarshal new cluster template")
+		}
+	}
+
+	if nc.Overrides != nil && len(nc.Overrides.Raw) > 0 {
+		data, err := json.Marshal(spec)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal new cluster spec")
+		}
+		data, err = jsonpatch.MergePatch(data, nc.Overrides.Raw)
+		if err != nil {
+			return nil, errors.Wrap(err, "apply new cluster overrides")
+		}
+		spec = new(api.PerconaXtraDBClusterSpec)
+		if err := json.Unmarshal(data, spec); err != nil {
+			return nil, errors.Wrap(err, "unmarshal new cluster spec")
+		}
+	}
+
+	if spec.SecretsName == "" {
+		spec.SecretsName = nc.Name + "-secrets"
+	}
+
+	return &api.PerconaXtraDBCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        nc.Name,
+			Namespace:   cr.Namespace,
+			Labels:      nc.Labels,
+			Annotations: nc.Annotations,
+		},
+		Spec: *spec,
+	}, nil
+}
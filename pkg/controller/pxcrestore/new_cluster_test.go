@@ -0,0 +1,194 @@
+package pxcrestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+)
+
+func TestNewCluster(t *testing.T) {
+	source := readDefaultCR(t, "prod", "default")
+	source.Spec.SecretsName = "prod-secrets"
+	source.Spec.SSLSecretName = "prod-ssl"
+	source.Spec.Backup.PITR.Enabled = true
+	source.Spec.Backup.Schedule = []api.PXCScheduledBackupSchedule{{Name: "daily", Schedule: "0 0 * * *"}}
+	source.Spec.PXC.ReplicationChannels = []api.ReplicationChannel{{Name: "channel"}}
+
+	newRestore := func(nc *api.RestoreNewClusterSpec) *api.PerconaXtraDBClusterRestore {
+		cr := readDefaultRestore(t, "forensics", "default")
+		cr.Spec.PXCCluster = source.Name
+		cr.Spec.NewCluster = nc
+		return cr
+	}
+
+	t.Run("clone", func(t *testing.T) {
+		cr := newRestore(&api.RestoreNewClusterSpec{
+			Name:   "prod-yesterday",
+			Labels: map[string]string{"purpose": "forensics"},
+		})
+
+		cluster, err := newCluster(cr, source)
+		require.NoError(t, err)
+
+		assert.Equal(t, "prod-yesterday", cluster.Name)
+		assert.Equal(t, "default", cluster.Namespace)
+		assert.Equal(t, map[string]string{"purpose": "forensics"}, cluster.Labels)
+		assert.Equal(t, "prod-yesterday-secrets", cluster.Spec.SecretsName)
+		assert.Empty(t, cluster.Spec.SSLSecretName)
+		assert.Equal(t, "prod-vault", cluster.Spec.VaultSecretName)
+		assert.False(t, cluster.Spec.Backup.PITR.Enabled)
+		assert.Empty(t, cluster.Spec.Backup.Schedule)
+		assert.Empty(t, cluster.Spec.PXC.ReplicationChannels)
+		assert.Equal(t, source.Spec.PXC.Image, cluster.Spec.PXC.Image)
+		assert.Equal(t, source.Spec.Backup.Storages, cluster.Spec.Backup.Storages)
+
+		// the source cluster must not be changed
+		assert.True(t, source.Spec.Backup.PITR.Enabled)
+		assert.Equal(t, "prod-secrets", source.Spec.SecretsName)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		cr := newRestore(&api.RestoreNewClusterSpec{
+			Name: "prod-yesterday",
+			Overrides: &runtime.RawExtension{
+				Raw: []byte(`{"pxc":{"size":1},"haproxy":{"enabled":false},"unsafeFlags":{"pxcSize":true}}`),
+			},
+		})
+
+		cluster, err := newCluster(cr, source)
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(1), cluster.Spec.PXC.Size)
+		assert.False(t, cluster.Spec.HAProxy.Enabled)
+		assert.True(t, cluster.Spec.Unsafe.PXCSize)
+		assert.Equal(t, source.Spec.PXC.Image, cluster.Spec.PXC.Image)
+	})
+
+	t.Run("template", func(t *testing.T) {
+		cr := newRestore(&api.RestoreNewClusterSpec{
+			Name: "prod-yesterday",
+			Template: &runtime.RawExtension{
+				Raw: []byte(`{"crVersion":"1.19.0","pxc":{"size":3,"image":"percona/percona-xtradb-cluster:8.0"}}`),
+			},
+			Overrides: &runtime.RawExtension{
+				Raw: []byte(`{"pxc":{"size":1}}`),
+			},
+		})
+
+		cluster, err := newCluster(cr, source)
+		require.NoError(t, err)
+
+		assert.Equal(t, "1.19.0", cluster.Spec.CRVersion)
+		assert.Equal(t, "percona/percona-xtradb-cluster:8.0", cluster.Spec.PXC.Image)
+		assert.Equal(t, int32(1), cluster.Spec.PXC.Size)
+		assert.Nil(t, cluster.Spec.Backup)
+		assert.Equal(t, "prod-yesterday-secrets", cluster.Spec.SecretsName)
+	})
+
+	t.Run("invalid overrides", func(t *testing.T) {
+		cr := newRestore(&api.RestoreNewClusterSpec{
+			Name: "prod-yesterday",
+			Overrides: &runtime.RawExtension{
+				Raw: []byte(`{"pxc":`),
+			},
+		})
+
+		_, err := newCluster(cr, source)
+		assert.Error(t, err)
+	})
+}
+
+func TestCreateNewClusterUsersSecret(t *testing.T) {
+	ctx := context.Background()
+
+	source := readDefaultCR(t, "prod", "default")
+	source.Spec.SecretsName = "prod-secrets"
+	sourceSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prod-secrets",
+			Namespace: "default",
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			"root":     []byte("root-password"),
+			"operator": []byte("operator-password"),
+		},
+	}
+
+	newRestore := func(overrides string) *api.PerconaXtraDBClusterRestore {
+		cr := readDefaultRestore(t, "forensics", "default")
+		cr.Spec.PXCCluster = source.Name
+		cr.Spec.NewCluster = &api.RestoreNewClusterSpec{Name: "prod-yesterday"}
+		if overrides != "" {
+			cr.Spec.NewCluster.Overrides = &runtime.RawExtension{Raw: []byte(overrides)}
+		}
+		return cr
+	}
+
+	t.Run("copy source secret", func(t *testing.T) {
+		cl := buildFakeClient(source.DeepCopy(), sourceSecret.DeepCopy())
+		r := reconciler(cl)
+
+		require.NoError(t, r.createNewCluster(ctx, newRestore("")))
+
+		cluster := new(api.PerconaXtraDBCluster)
+		require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: "prod-yesterday", Namespace: "default"}, cluster))
+		assert.Equal(t, "prod-yesterday-secrets", cluster.Spec.SecretsName)
+
+		secret := new(corev1.Secret)
+		require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: cluster.Spec.SecretsName, Namespace: "default"}, secret))
+		assert.Equal(t, sourceSecret.Data, secret.Data)
+		assert.Empty(t, secret.OwnerReferences)
+	})
+
+	t.Run("existing secret", func(t *testing.T) {
+		existing := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "prod-yesterday-secrets",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{"root": []byte("other-password")},
+		}
+		cl := buildFakeClient(source.DeepCopy(), sourceSecret.DeepCopy(), existing)
+		r := reconciler(cl)
+
+		require.NoError(t, r.createNewCluster(ctx, newRestore("")))
+
+		secret := new(corev1.Secret)
+		require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: "prod-yesterday-secrets", Namespace: "default"}, secret))
+		assert.Equal(t, existing.Data, secret.Data)
+	})
+
+	t.Run("source secret in overrides", func(t *testing.T) {
+		cl := buildFakeClient(source.DeepCopy(), sourceSecret.DeepCopy())
+		r := reconciler(cl)
+
+		require.NoError(t, r.createNewCluster(ctx, newRestore(`{"secretsName":"prod-secrets"}`)))
+
+		cluster := new(api.PerconaXtraDBCluster)
+		require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: "prod-yesterday", Namespace: "default"}, cluster))
+		assert.Equal(t, "prod-secrets", cluster.Spec.SecretsName)
+
+		err := cl.Get(ctx, types.NamespacedName{Name: "prod-yesterday-secrets", Namespace: "default"}, new(corev1.Secret))
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+
+	t.Run("no source secret", func(t *testing.T) {
+		cl := buildFakeClient(source.DeepCopy())
+		r := reconciler(cl)
+
+		assert.Error(t, r.createNewCluster(ctx, newRestore("")))
+
+		err := cl.Get(ctx, types.NamespacedName{Name: "prod-yesterday", Namespace: "default"}, new(api.PerconaXtraDBCluster))
+		assert.True(t, k8serrors.IsNotFound(err))
+	})
+}
@@ -0,0 +1,372 @@
+package pxcrestore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/k8s"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/queries"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/users"
+)
+
+// importTimeout is the timeout of the queries which import the tables.
+// DDL and IMPORT TABLESPACE of the large tables can take a long time.
+const importTimeout = 3600
+
+const internalSecretsPrefix = "internal-"
+
+func (r *ReconcilePerconaXtraDBClusterRestore) reconcilePartialStateNew(ctx context.Context, restorer Restorer, cr *api.PerconaXtraDBClusterRestore, cluster *api.PerconaXtraDBCluster, bcp *api.PerconaXtraDBClusterBackup) (reconcile.Result, error) {
+	log := logf.FromContext(ctx)
+	rr := reconcile.Result{
+		// TODO: do not depend on the RequeueAfter
+		RequeueAfter: time.Second * 5,
+	}
+
+	if bcp.Status.GetStorageType(cluster) == api.BackupStorageFilesystem {
+		cr.Status.Comments = "restore of databases and tables is not supported for filesystem backups"
+		cr.Status.State = api.RestoreFailed
+		return reconcile.Result{}, nil
+	}
+
+	if err := validate(ctx, restorer, cr); err != nil {
+		if errors.Is(err, errWaitValidate) {
+			return rr, nil
+		}
+		cr.Status.Comments = fmt.Sprintf("failed to validate restore job: %s", err.Error())
+		cr.Status.State = api.RestoreFailed
+		return rr, err
+	}
+
+	if cluster.Status.PXC.Status != api.AppStateReady {
+		log.Info("Waiting for cluster to be ready", "cluster", cluster.Name)
+		return rr, nil
+	}
+
+	restoreJob, err := restorer.Job(ctx)
+	if err != nil {
+		return rr, errors.Wrap(err, "failed to get restore job")
+	}
+	job, err := backup.ExportJob(restoreJob, cr, cluster)
+	if err != nil {
+		cr.Status.Comments = err.Error()
+		cr.Status.State = api.RestoreFailed
+		return rr, err
+	}
+
+	if cluster.Spec.PXC.VolumeSpec != nil && cluster.Spec.PXC.VolumeSpec.PersistentVolumeClaim != nil {
+		pvc := backup.ExportPVC(cr, cluster)
+		if err := controllerutil.SetControllerReference(cr, pvc, r.scheme); err != nil {
+			return rr, errors.Wrap(err, "set controller reference")
+		}
+		if err := r.client.Create(ctx, pvc); err != nil && !k8serrors.IsAlreadyExists(err) {
+			return rr, errors.Wrap(err, "create export pvc")
+		}
+	}
+
+	if err := r.client.Create(ctx, job); err != nil && !k8serrors.IsAlreadyExists(err) {
+		cr.Status.Comments = fmt.Sprintf("failed to run export: %s", err.Error())
+		cr.Status.State = api.RestoreFailed
+		return rr, errors.Wrap(err, "create export job")
+	}
+
+	log.Info("exporting tables", "cluster", cr.TargetCluster(), "backup", cr.Spec.BackupName)
+	cr.Status.State = api.RestoreExportTables
+	return rr, nil
+}
+
+func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateExportTables(ctx context.Context, cr *api.PerconaXtraDBClusterRestore) (reconcile.Result, error) {
+	log := logf.FromContext(ctx)
+	rr := reconcile.Result{
+		// TODO: do not depend on the RequeueAfter
+		RequeueAfter: time.Second * 5,
+	}
+
+	pod, err := r.exportPod(ctx, cr)
+	if err != nil {
+		cr.Status.State = api.RestoreFailed
+		cr.Status.Comments = err.Error()
+		return rr, err
+	}
+	if pod == nil || !k8s.IsPodReady(*pod) {
+		log.Info("Waiting for tables to be exported", "job", naming.ExportJobName(cr))
+		return rr, nil
+	}
+
+	log.Info("importing tables", "cluster", cr.TargetCluster())
+	cr.Status.State = api.RestoreImportTables
+	return rr, nil
+}
+
+// reconcileStateImportTables imports the exported tables one by one.
+// The imported tables are tracked in the status, so the import continues
+// from the next table if the operator is restarted.
+func (r *ReconcilePerconaXtraDBClusterRestore) reconcileStateImportTables(ctx context.Context, cr *api.PerconaXtraDBClusterRestore, cluster *api.PerconaXtraDBCluster) (reconcile.Result, error) {
+	log := logf.FromContext(ctx)
+	rr := reconcile.Result{
+		// TODO: do not depend on the RequeueAfter
+		RequeueAfter: time.Second * 5,
+	}
+
+	exportPod, err := r.exportPod(ctx, cr)
+	if err != nil {
+		cr.Status.State = api.RestoreFailed
+		cr.Status.Comments = err.Error()
+		return rr, err
+	}
+	if exportPod == nil || !k8s.IsPodReady(*exportPod) {
+		return rr, errors.New("export pod is not ready")
+	}
+
+	out, err := r.execExportPod(exportPod, []string{"cat", backup.ExportTablesFile}, nil)
+	if err != nil {
+		return rr, errors.Wrap(err, "get exported tables")
+	}
+	exported := strings.Fields(out.String())
+
+	for _, table := range exported {
+		if slices.Contains(cr.Status.ImportedTables, table) {
+			continue
+		}
+
+		// The table is recorded in the status before it's created. If the import is
+		// interrupted, the table is dropped and imported again on the next reconcile.
+		if cr.Status.ImportingTable != table {
+			if err := r.checkTableNotExists(ctx, cr, cluster, table); err != nil {
+				cr.Status.State = api.RestoreFailed
+				cr.Status.Comments = fmt.Sprintf("failed to import table %s: %s", table, err.Error())
+				return rr, errors.Wrapf(err, "import table %s", table)
+			}
+			cr.Status.ImportingTable = table
+			return rr, nil
+		}
+
+		log.Info("importing table", "table", table)
+		if err := r.importTable(ctx, cr, cluster, exportPod, table); err != nil {
+			cr.Status.State = api.RestoreFailed
+			cr.Status.Comments = fmt.Sprintf("failed to import table %s: %s", table, err.Error())
+			return rr, errors.Wrapf(err, "import table %s", table)
+		}
+		cr.Status.ImportedTables = append(cr.Status.ImportedTables, table)
+		cr.Status.ImportingTable = ""
+		return rr, nil
+	}
+
+	if _, err := r.execExportPod(exportPod, []string{"touch", backup.ExportDoneFile}, nil); err != nil {
+		return rr, errors.Wrap(err, "finish export job")
+	}
+
+	if len(exported) == 0 {
+		cr.Status.Comments = "no tables found in the backup"
+		cr.Status.State = api.RestoreFailed
+		return rr, nil
+	}
+
+	log.Info("tables are imported", "cluster", cr.TargetCluster(), "tables", len(exported))
+	cr.Status.State = api.RestoreSucceeded
+	return rr, nil
+}
+
+// targetTable returns the database and the name of the table in the cluster
+// into which the exported table is imported.
+func targetTable(cr *api.PerconaXtraDBClusterRestore, table string) (string, string) {
+	database, name, _ := strings.Cut(table, ".")
+	if cr.Spec.TargetDatabase != "" {
+		database = cr.Spec.TargetDatabase
+	}
+	return database, name
+}
+
+// checkTableNotExists returns an error if the table already exists in the cluster,
+// so the restore doesn't overwrite the tables which weren't created by it.
+func (r *ReconcilePerconaXtraDBClusterRestore) checkTableNotExists(ctx context.Context, cr *api.PerconaXtraDBClusterRestore, cluster *api.PerconaXtraDBCluster, table string) error {
+	target, name := targetTable(cr, table)
+
+	db, err := queries.New(r.client, cluster.Namespace, internalSecretsPrefix+cluster.Name, users.Operator,
+		cluster.Name+"-pxc."+cluster.Namespace, 33062, importTimeout)
+	if err != nil {
+		return errors.Wrap(err, "connect to cluster")
+	}
+	defer db.Close()
+
+	exists, err := db.TableExists(ctx, target, name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.Errorf("table %s.%s already exists", target, name)
+	}
+
+	return nil
+}
+
+// importTable creates the table in the cluster and imports its tablespace on each node.
+// The table is dropped first if it was created by the interrupted import.
+func (r *ReconcilePerconaXtraDBClusterRestore) importTable(ctx context.Context, cr *api.PerconaXtraDBClusterRestore, cluster *api.PerconaXtraDBCluster, exportPod *corev1.Pod, table string) error {
+	database, name, _ := strings.Cut(table, ".")
+	dir := path.Join(backup.ExportDir, database, name)
+	target, _ := targetTable(cr, table)
+
+	createStatement, err := r.execExportPod(exportPod, []string{"cat", path.Join(dir, "create.sql")}, nil)
+	if err != nil {
+		return errors.Wrap(err, "get table definition")
+	}
+	out, err := r.execExportPod(exportPod, []string{"ls", dir}, nil)
+	if err != nil {
+		return errors.Wrap(err, "list tablespace files")
+	}
+	var files []string
+	for _, f := range strings.Fields(out.String()) {
+		if f != "create.sql" {
+			files = append(files, f)
+		}
+	}
+
+	db, err := queries.New(r.client, cluster.Namespace, internalSecretsPrefix+cluster.Name, users.Operator,
+		cluster.Name+"-pxc."+cluster.Namespace, 33062, importTimeout)
+	if err != nil {
+		return errors.Wrap(err, "connect to cluster")
+	}
+	defer db.Close()
+
+	if err := db.DropTable(ctx, target, name); err != nil {
+		return err
+	}
+	if err := db.CreateTable(ctx, target, createStatement.String()); err != nil {
+		return err
+	}
+
+	pods := new(corev1.PodList)
+	if err := r.client.List(ctx, pods, client.InNamespace(cluster.Namespace), client.MatchingLabels(naming.LabelsPXC(cluster))); err != nil {
+		return errors.Wrap(err, "list pxc pods")
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+
+		db, err := queries.New(r.client, cluster.Namespace, internalSecretsPrefix+cluster.Name, users.Operator,
+			pod.Name+"."+cluster.Name+"-pxc."+cluster.Namespace, 33062, importTimeout)
+		if err != nil {
+			return errors.Wrapf(err, "connect to pod %s", pod.Name)
+		}
+
+		err = db.ImportTablespace(ctx, target, name, func() error {
+			for _, f := range files {
+				if err := r.copyExportedFile(exportPod, pod, path.Join(dir, f), path.Join("/var/lib/mysql", target, f)); err != nil {
+					return errors.Wrapf(err, "copy %s to pod %s", f, pod.Name)
+				}
+			}
+			return nil
+		})
+		db.Close()
+		if err != nil {
+			return errors.Wrapf(err, "import tablespace on pod %s", pod.Name)
+		}
+	}
+
+	return nil
+}
+
+// copyExportedFile streams the file from the export pod into the pxc container.
+func (r *ReconcilePerconaXtraDBClusterRestore) copyExportedFile(exportPod, pod *corev1.Pod, src, dst string) error {
+	pr, pw := io.Pipe()
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := r.execExportPod(exportPod, []string{"cat", src}, pw)
+		pw.CloseWithError(err)
+		errCh <- err
+	}()
+
+	var errb bytes.Buffer
+	err := r.clientcmd.Exec(pod, "pxc", []string{"sh", "-c", `cat > "$1"`, "sh", dst}, pr, nil, &errb, false)
+	pr.Close()
+	if err != nil {
+		return errors.Wrapf(err, "write file: %s", errb.String())
+	}
+
+	return <-errCh
+}
+
+// execExportPod runs the command in the export container. The output is written
+// to stdout if it's not nil, otherwise it's returned in the buffer.
+func (r *ReconcilePerconaXtraDBClusterRestore) execExportPod(pod *corev1.Pod, command []string, stdout io.Writer) (*bytes.Buffer, error) {
+	var outb, errb bytes.Buffer
+	if stdout == nil {
+		stdout = &outb
+	}
+
+	if err := r.clientcmd.Exec(pod, "export", command, nil, stdout, &errb, false); err != nil {
+		return nil, errors.Wrapf(err, "run %s: %s", strings.Join(command, " "), errb.String())
+	}
+
+	return &outb, nil
+}
+
+// exportPod returns the running pod of the export job.
+// It returns nil if the pod is not created yet.
+func (r *ReconcilePerconaXtraDBClusterRestore) exportPod(ctx context.Context, cr *api.PerconaXtraDBClusterRestore) (*corev1.Pod, error) {
+	job := new(batchv1.Job)
+	if err := r.client.Get(ctx, client.ObjectKey{Name: naming.ExportJobName(cr), Namespace: cr.Namespace}, job); err != nil {
+		return nil, errors.Wrap(err, "get export job")
+	}
+	if _, err := isJobFinished(job); err != nil {
+		return nil, err
+	}
+
+	pods := new(corev1.PodList)
+	if err := r.client.List(ctx, pods, client.InNamespace(cr.Namespace), client.MatchingLabels{
+		naming.LabelPerconaRestoreJobName: job.Name,
+	}); err != nil {
+		return nil, errors.Wrap(err, "list export pods")
+	}
+
+	for i := range pods.Items {
+		if pods.Items[i].Status.Phase == corev1.PodRunning {
+			return &pods.Items[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// cleanupPartialRestore stops the export job and deletes the volume
+// with the exported tables once the partial restore is finished.
+func (r *ReconcilePerconaXtraDBClusterRestore) cleanupPartialRestore(ctx context.Context, cr *api.PerconaXtraDBClusterRestore) error {
+	pod, err := r.exportPod(ctx, cr)
+	if err != nil && !k8serrors.IsNotFound(errors.Cause(err)) {
+		logf.FromContext(ctx).Error(err, "failed to get export pod")
+	}
+	if pod != nil {
+		if _, err := r.execExportPod(pod, []string{"touch", backup.ExportDoneFile}, nil); err != nil {
+			return errors.Wrap(err, "finish export job")
+		}
+	}
+
+	pvc := new(corev1.PersistentVolumeClaim)
+	pvc.Name = naming.ExportPVCName(cr)
+	pvc.Namespace = cr.Namespace
+
+	if err := r.client.Delete(ctx, pvc); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Wrap(err, "delete export pvc")
+	}
+
+	return nil
+}
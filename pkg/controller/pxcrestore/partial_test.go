@@ -0,0 +1,22 @@
+package pxcrestore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+)
+
+func TestTargetTable(t *testing.T) {
+	cr := new(api.PerconaXtraDBClusterRestore)
+
+	database, name := targetTable(cr, "db1.t1")
+	assert.Equal(t, "db1", database)
+	assert.Equal(t, "t1", name)
+
+	cr.Spec.TargetDatabase = "restored"
+	database, name = targetTable(cr, "db1.t1")
+	assert.Equal(t, "restored", database)
+	assert.Equal(t, "t1", name)
+}
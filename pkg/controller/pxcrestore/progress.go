@@ -0,0 +1,125 @@
+package pxcrestore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+)
+
+// updateProgress reads the progress of the restore from the running pod of the job
+// and reports it in the status of the restore.
+func (r *ReconcilePerconaXtraDBClusterRestore) updateProgress(ctx context.Context, cr *api.PerconaXtraDBClusterRestore, job *batchv1.Job, dir string) error {
+	if r.clientcmd == nil {
+		return nil
+	}
+
+	pods := new(corev1.PodList)
+	if err := r.client.List(ctx, pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return errors.Wrap(err, "list job pods")
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+
+		var stdout, stderr bytes.Buffer
+		cmd := []string{"sh", "-c", `cat "$1"/* 2>/dev/null || true`, "sh", dir}
+		if err := r.clientcmd.Exec(pod, "xtrabackup", cmd, nil, &stdout, &stderr, false); err != nil {
+			return errors.Wrapf(err, "read progress from pod %s: %s", pod.Name, stderr.String())
+		}
+		if stdout.Len() == 0 {
+			return nil
+		}
+
+		if cr.Status.Progress == nil {
+			cr.Status.Progress = new(api.RestoreProgress)
+		}
+		parseProgress(stdout.Bytes(), cr.Status.Progress)
+		estimateCompletion(cr.Status.Progress, time.Now())
+		return nil
+	}
+
+	return nil
+}
+
+// parseProgress updates the progress from the key=value lines written by the restore jobs.
+// The lines with unknown keys are ignored.
+func parseProgress(data []byte, p *api.RestoreProgress) {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(s.Text()), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "phase":
+			if p.Phase != api.RestorePhase(value) {
+				p.ETA = nil
+			}
+			p.Phase = api.RestorePhase(value)
+		case "started":
+			if ts, err := strconv.ParseInt(value, 10, 64); err == nil {
+				p.PhaseStarted = &metav1.Time{Time: time.Unix(ts, 0)}
+			}
+		case "completed":
+			switch phase := api.RestorePhase(value); phase {
+			case api.RestorePhaseDownload, api.RestorePhasePrepare, api.RestorePhaseCopyBack:
+				if !p.IsPhaseCompleted(phase) {
+					p.CompletedPhases = append(p.CompletedPhases, phase)
+				}
+			}
+		case "downloaded":
+			p.BytesDownloaded, _ = strconv.ParseInt(value, 10, 64)
+		case "binlogs_applied":
+			p.BinlogsApplied, _ = strconv.Atoi(value)
+		case "binlogs_total":
+			p.BinlogsTotal, _ = strconv.Atoi(value)
+		case "gtid":
+			p.CurrentGTID = value
+		}
+	}
+
+	now := metav1.Now()
+	p.LastUpdated = &now
+}
+
+// estimateCompletion sets the estimated end of the download and the point-in-time recovery
+// by the rate of the phase. The end of other phases can't be estimated.
+func estimateCompletion(p *api.RestoreProgress, now time.Time) {
+	p.ETA = nil
+	if p.PhaseStarted == nil {
+		return
+	}
+
+	var done, total int64
+	switch p.Phase {
+	case api.RestorePhaseDownload:
+		done, total = p.BytesDownloaded, p.BytesTotal
+	case api.RestorePhasePITR:
+		done, total = int64(p.BinlogsApplied), int64(p.BinlogsTotal)
+	default:
+		return
+	}
+
+	elapsed := now.Sub(p.PhaseStarted.Time)
+	if done <= 0 || total <= 0 || done > total || elapsed <= 0 {
+		return
+	}
+
+	remaining := time.Duration(float64(elapsed) * float64(total-done) / float64(done))
+	p.ETA = &metav1.Time{Time: now.Add(remaining).Truncate(time.Second)}
+}
@@ -0,0 +1,57 @@
+package pxcrestore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+)
+
+func TestParseProgress(t *testing.T) {
+	p := &api.RestoreProgress{BytesTotal: 1000}
+	parseProgress([]byte("restore=uid\nphase=download\nstarted=1704110400\ncompleted=unknown\ndownloaded=250\n"), p)
+
+	assert.Equal(t, api.RestorePhaseDownload, p.Phase)
+	assert.Equal(t, time.Unix(1704110400, 0), p.PhaseStarted.Time)
+	assert.Equal(t, int64(250), p.BytesDownloaded)
+	assert.Empty(t, p.CompletedPhases)
+	assert.NotNil(t, p.LastUpdated)
+
+	estimateCompletion(p, time.Unix(1704110400, 0).Add(10*time.Minute))
+	assert.Equal(t, time.Unix(1704110400, 0).Add(40*time.Minute), p.ETA.Time)
+
+	parseProgress([]byte("phase=prepare\nstarted=1704111000\ncompleted=download\n"), p)
+	assert.Equal(t, api.RestorePhasePrepare, p.Phase)
+	assert.Equal(t, []api.RestorePhase{api.RestorePhaseDownload}, p.CompletedPhases)
+	estimateCompletion(p, time.Unix(1704111600, 0))
+	assert.Nil(t, p.ETA)
+}
+
+func TestParsePITRProgress(t *testing.T) {
+	p := new(api.RestoreProgress)
+	parseProgress([]byte("phase=pitr\nstarted=1704110400\nbinlogs_applied=3\nbinlogs_total=12\ngtid=3e11fa47-71ca-11e1-9e33-c80aa9429562:1-200\n"), p)
+
+	assert.Equal(t, api.RestorePhasePITR, p.Phase)
+	assert.Equal(t, 3, p.BinlogsApplied)
+	assert.Equal(t, 12, p.BinlogsTotal)
+	assert.Equal(t, "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-200", p.CurrentGTID)
+
+	estimateCompletion(p, time.Unix(1704110400, 0).Add(3*time.Minute))
+	assert.Equal(t, time.Unix(1704110400, 0).Add(12*time.Minute), p.ETA.Time)
+}
+
+func TestCompletePhase(t *testing.T) {
+	p := &api.RestoreProgress{
+		Phase:        api.RestorePhasePITR,
+		PhaseStarted: &metav1.Time{Time: time.Now()},
+	}
+	p.CompletePhase(api.RestorePhasePITR)
+	p.CompletePhase(api.RestorePhasePITR)
+
+	assert.Empty(t, p.Phase)
+	assert.Nil(t, p.PhaseStarted)
+	assert.Equal(t, []api.RestorePhase{api.RestorePhasePITR}, p.CompletedPhases)
+}
@@ -78,7 +78,7 @@ func (s *s3) Validate(ctx context.Context) error {
 		return errors.New("backup not found")
 	}
 
-	return nil
+	return s.validateBaseChain(ctx, s3cli)
 }
 
 type pvc struct{ *restorerOptions }
@@ -227,7 +227,43 @@ func (s *azure) Validate(ctx context.Context) error {
 	if len(blobs) == 0 {
 		return errors.New("no backups found")
 	}
-	return nil
+	return s.validateBaseChain(ctx, azurecli)
+}
+
+type gcs struct{ *restorerOptions }
+
+func (s *gcs) Init(context.Context) error { return nil }
+
+func (s *gcs) Finalize(context.Context) error { return nil }
+
+func (s *gcs) Job(ctx context.Context) (*batchv1.Job, error) {
+	return backup.RestoreJob(ctx, s.cr, s.bcp, s.cluster, s.initImage, s.scheme, s.bcp.Status.Destination, false)
+}
+
+func (s *gcs) PITRJob(ctx context.Context) (*batchv1.Job, error) {
+	return backup.RestoreJob(ctx, s.cr, s.bcp, s.cluster, s.initImage, s.scheme, s.bcp.Status.Destination, true)
+}
+
+func (s *gcs) Validate(ctx context.Context) error {
+	opts, err := storage.GetOptionsFromBackup(ctx, s.k8sClient, s.cluster, s.bcp)
+	if err != nil {
+		return errors.Wrap(err, "failed to get storage options")
+	}
+	gcscli, err := s.newStorageClient(ctx, opts)
+	if err != nil {
+		return errors.Wrap(err, "failed to create gcs client")
+	}
+
+	backupName := s.bcp.Status.Destination.BackupName() + "/"
+	objs, err := gcscli.ListObjects(ctx, backupName)
+	if err != nil {
+		return errors.Wrap(err, "failed to list objects")
+	}
+	if len(objs) == 0 {
+		return errors.New("backup not found")
+	}
+
+	return s.validateBaseChain(ctx, gcscli)
 }
 
 func (r *ReconcilePerconaXtraDBClusterRestore) getRestorer(
@@ -260,6 +296,9 @@ func (r *ReconcilePerconaXtraDBClusterRestore) getRestorer(
 	case api.AzureBlobStoragePrefix:
 		sr := azure{&s}
 		return &sr, nil
+	case api.GCSBlobStoragePrefix:
+		sr := gcs{&s}
+		return &sr, nil
 	}
 	return nil, errors.Errorf("unknown backup storage type")
 }
@@ -274,6 +313,28 @@ type restorerOptions struct {
 	initImage        string
 }
 
+// validateBaseChain checks that all backups required to restore an incremental backup exist
+func (opts *restorerOptions) validateBaseChain(ctx context.Context, cli storage.Storage) error {
+	if !opts.bcp.Status.IsIncremental() {
+		return nil
+	}
+	if len(opts.bcp.Status.BaseChain) == 0 {
+		return errors.New("incremental backup has no base backups")
+	}
+
+	for _, dest := range opts.bcp.Status.BaseChain {
+		objs, err := cli.ListObjects(ctx, dest.BackupName()+"/")
+		if err != nil {
+			return errors.Wrapf(err, "list objects of base backup %s", dest)
+		}
+		if len(objs) == 0 {
+			return errors.Errorf("base backup %s not found", dest)
+		}
+	}
+
+	return nil
+}
+
 func (opts *restorerOptions) ValidateJob(ctx context.Context, job *batchv1.Job) error {
 	cl := opts.k8sClient
 
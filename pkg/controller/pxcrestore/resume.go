@@ -0,0 +1,107 @@
+package pxcrestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+)
+
+// resumeRestore resumes the failed restore from the state it failed in.
+// The failed job of the state is deleted, so it's created again by the state
+// and the new job continues from the last completed phase of the failed job.
+func (r *ReconcilePerconaXtraDBClusterRestore) resumeRestore(ctx context.Context, cr *api.PerconaXtraDBClusterRestore) (reconcile.Result, error) {
+	log := logf.FromContext(ctx)
+
+	if !cr.Status.FailedState.IsResumable() {
+		if err := r.removeResumeAnnotation(ctx, cr); err != nil {
+			return reconcile.Result{}, err
+		}
+		cr.Status.Comments = fmt.Sprintf("restore can't be resumed from state %q, create a new restore", cr.Status.FailedState)
+		return reconcile.Result{}, setStatus(ctx, r.client, cr)
+	}
+
+	if jobName := failedJobName(cr); jobName != "" {
+		deleted, err := r.deleteFailedJob(ctx, cr, jobName)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrapf(err, "delete job %s", jobName)
+		}
+		if !deleted {
+			log.Info("Waiting for failed job to be deleted", "job", jobName)
+			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+	}
+
+	if err := r.removeResumeAnnotation(ctx, cr); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	log.Info("resuming restore", "state", cr.Status.FailedState)
+	cr.Status.State = cr.Status.FailedState
+	cr.Status.FailedState = ""
+	cr.Status.Comments = ""
+	if err := setStatus(ctx, r.client, cr); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "set status")
+	}
+
+	return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+}
+
+// failedJobName returns the name of the job run by the state the restore failed in.
+func failedJobName(cr *api.PerconaXtraDBClusterRestore) string {
+	switch cr.Status.FailedState {
+	case api.RestoreRestore:
+		return naming.RestoreJobName(cr, false)
+	case api.RestorePITR:
+		return naming.RestoreJobName(cr, true)
+	case api.RestorePrepareCluster:
+		return naming.PrepareJobName(cr)
+	}
+	return ""
+}
+
+// deleteFailedJob deletes the job with its pods and returns true once the job is deleted.
+func (r *ReconcilePerconaXtraDBClusterRestore) deleteFailedJob(ctx context.Context, cr *api.PerconaXtraDBClusterRestore, name string) (bool, error) {
+	job := new(batchv1.Job)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: cr.Namespace}, job); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, errors.Wrap(err, "get job")
+	}
+
+	if controllerutil.RemoveFinalizer(job, naming.FinalizerKeepJob) {
+		if err := r.client.Update(ctx, job); err != nil {
+			return false, errors.Wrap(err, "remove finalizer")
+		}
+	}
+
+	if job.DeletionTimestamp == nil {
+		if err := r.client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return false, errors.Wrap(err, "delete job")
+		}
+	}
+
+	return false, nil
+}
+
+func (r *ReconcilePerconaXtraDBClusterRestore) removeResumeAnnotation(ctx context.Context, cr *api.PerconaXtraDBClusterRestore) error {
+	patch := client.MergeFrom(cr.DeepCopy())
+	delete(cr.Annotations, api.AnnotationResumeRestore)
+	if err := r.client.Patch(ctx, cr, patch); err != nil {
+		return errors.Wrap(err, "remove resume annotation")
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package pxcrestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+)
+
+func TestResumeRestore(t *testing.T) {
+	ctx := context.Background()
+
+	cr := readDefaultRestore(t, "restore1", "pxc")
+	cr.Annotations = map[string]string{api.AnnotationResumeRestore: "true"}
+	cr.Status.State = api.RestoreFailed
+	cr.Status.FailedState = api.RestoreRestore
+	cr.Status.Comments = "job failed"
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       naming.RestoreJobName(cr, false),
+			Namespace:  cr.Namespace,
+			Finalizers: []string{naming.FinalizerKeepJob},
+		},
+	}
+
+	cl := buildFakeClient(cr, job)
+	r := reconciler(cl)
+
+	// the restore is resumed once the failed job is deleted
+	_, err := r.resumeRestore(ctx, cr)
+	assert.NoError(t, err)
+	assert.Equal(t, api.RestoreFailed, cr.Status.State)
+	err = cl.Get(ctx, client.ObjectKeyFromObject(job), new(batchv1.Job))
+	assert.True(t, k8serrors.IsNotFound(err))
+
+	_, err = r.resumeRestore(ctx, cr)
+	assert.NoError(t, err)
+
+	restore := new(api.PerconaXtraDBClusterRestore)
+	assert.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(cr), restore))
+	assert.Equal(t, api.RestoreRestore, restore.Status.State)
+	assert.Empty(t, restore.Status.FailedState)
+	assert.Empty(t, restore.Status.Comments)
+	assert.NotContains(t, restore.Annotations, api.AnnotationResumeRestore)
+}
+
+func TestResumeRestoreNotResumable(t *testing.T) {
+	ctx := context.Background()
+
+	cr := readDefaultRestore(t, "restore1", "pxc")
+	cr.Annotations = map[string]string{api.AnnotationResumeRestore: "true"}
+	cr.Status.State = api.RestoreFailed
+	cr.Status.FailedState = api.RestoreStarting
+
+	cl := buildFakeClient(cr)
+	r := reconciler(cl)
+
+	_, err := r.resumeRestore(ctx, cr)
+	assert.NoError(t, err)
+
+	restore := new(api.PerconaXtraDBClusterRestore)
+	assert.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(cr), restore))
+	assert.Equal(t, api.RestoreFailed, restore.Status.State)
+	assert.Contains(t, restore.Status.Comments, "can't be resumed")
+	assert.NotContains(t, restore.Annotations, api.AnnotationResumeRestore)
+}
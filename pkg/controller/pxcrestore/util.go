@@ -37,6 +37,10 @@ func getBackup(ctx context.Context, cl client.Client, cr *api.PerconaXtraDBClust
 		}, nil
 	}
 
+	if cr.IsCrossNamespace() {
+		return getCrossNamespaceBackup(ctx, cl, cr)
+	}
+
 	bcp := &api.PerconaXtraDBClusterBackup{}
 	if err := cl.Get(ctx, types.NamespacedName{Name: cr.Spec.BackupName, Namespace: cr.Namespace}, bcp); err != nil {
 		return bcp, errors.Wrapf(err, "get backup %s", cr.Spec.BackupName)
@@ -83,7 +87,7 @@ func isOtherRestoreInProgress(ctx context.Context, cl client.Client, cr *api.Per
 	}
 
 	for _, j := range rJobsList.Items {
-		if j.Spec.PXCCluster == cr.Spec.PXCCluster &&
+		if j.TargetCluster() == cr.TargetCluster() &&
 			j.Name != cr.Name && j.Status.State != api.RestoreFailed &&
 			j.Status.State != api.RestoreSucceeded {
 			return &j, nil
@@ -0,0 +1,72 @@
+package pxcrestoredrill
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/queries"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/users"
+)
+
+// assertionTimeout is the timeout of the assertion queries in seconds.
+const assertionTimeout = 600
+
+type checkAssertionsFunc func(ctx context.Context, cl client.Client, cr *api.PerconaXtraDBClusterRestoreDrill, clusterName string) ([]api.RestoreDrillAssertionResult, error)
+
+// checkAssertions runs the assertion queries on the drill cluster. An error is returned
+// only if the cluster can't be connected, the errors of the queries fail the assertions.
+// The queries are run by root with the password from the users secret of the drill cluster,
+// the restore copies the secret from pxcCluster, so the password matches the restored data.
+func checkAssertions(ctx context.Context, cl client.Client, cr *api.PerconaXtraDBClusterRestoreDrill, clusterName string) ([]api.RestoreDrillAssertionResult, error) {
+	if len(cr.Spec.Assertions) == 0 {
+		return nil, nil
+	}
+
+	cluster := new(api.PerconaXtraDBCluster)
+	if err := cl.Get(ctx, types.NamespacedName{Name: clusterName, Namespace: cr.Namespace}, cluster); err != nil {
+		return nil, errors.Wrapf(err, "get cluster %s", clusterName)
+	}
+	secrets := cluster.Spec.SecretsName
+	if secrets == "" {
+		secrets = cluster.Name + "-secrets"
+	}
+
+	db, err := queries.New(cl, cluster.Namespace, secrets, users.Root, cluster.Name+"-pxc."+cluster.Namespace, 3306, assertionTimeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "connect to cluster")
+	}
+	defer db.Close()
+
+	results := make([]api.RestoreDrillAssertionResult, 0, len(cr.Spec.Assertions))
+	for _, a := range cr.Spec.Assertions {
+		results = append(results, checkAssertion(ctx, &db, a))
+	}
+
+	return results, nil
+}
+
+type valueQuerier interface {
+	QueryValue(ctx context.Context, query string) (string, error)
+}
+
+func checkAssertion(ctx context.Context, db valueQuerier, a api.RestoreDrillAssertion) api.RestoreDrillAssertionResult {
+	ctx, cancel := context.WithTimeout(ctx, assertionTimeout*time.Second)
+	defer cancel()
+
+	res := api.RestoreDrillAssertionResult{Name: a.Name}
+	value, err := db.QueryValue(ctx, a.Query)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Actual = value
+	res.Passed = strings.TrimSpace(value) == strings.TrimSpace(a.Expected)
+
+	return res
+}
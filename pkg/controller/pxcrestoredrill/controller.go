@@ -0,0 +1,228 @@
+package pxcrestoredrill
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	k8sretry "k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+)
+
+// Add creates a new PerconaXtraDBClusterRestoreDrill Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcilePerconaXtraDBClusterRestoreDrill{
+		client:          mgr.GetClient(),
+		scheme:          mgr.GetScheme(),
+		checkAssertions: checkAssertions,
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	return builder.ControllerManagedBy(mgr).
+		Named("pxcrestoredrill-controller").
+		For(&api.PerconaXtraDBClusterRestoreDrill{}).
+		Owns(&api.PerconaXtraDBClusterRestore{}).
+		Complete(r)
+}
+
+var _ reconcile.Reconciler = &ReconcilePerconaXtraDBClusterRestoreDrill{}
+
+// ReconcilePerconaXtraDBClusterRestoreDrill reconciles a PerconaXtraDBClusterRestoreDrill object
+type ReconcilePerconaXtraDBClusterRestoreDrill struct {
+	client client.Client
+	scheme *runtime.Scheme
+
+	checkAssertions checkAssertionsFunc
+}
+
+// Reconcile starts the drills on the schedule and follows the running drill
+// until the restore is finished, the assertions are checked and the drill cluster is deleted.
+func (r *ReconcilePerconaXtraDBClusterRestoreDrill) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	log := logf.FromContext(ctx)
+
+	rr := reconcile.Result{
+		RequeueAfter: time.Second * 30,
+	}
+
+	cr := &api.PerconaXtraDBClusterRestoreDrill{}
+	if err := r.client.Get(ctx, request.NamespacedName, cr); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if cr.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	if err := cr.CheckNSetDefaults(); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "wrong restore drill options")
+	}
+
+	status := cr.Status.DeepCopy()
+	defer func() {
+		if reflect.DeepEqual(status, &cr.Status) {
+			return
+		}
+		if err := setStatus(ctx, r.client, cr); err != nil {
+			log.Error(err, "failed to set status")
+		}
+	}()
+
+	if cr.Status.Current != nil {
+		return r.reconcileRun(ctx, cr)
+	}
+
+	if cr.Spec.Suspend {
+		return reconcile.Result{}, nil
+	}
+
+	next, err := cr.NextScheduleTime()
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "get next schedule time")
+	}
+	now := time.Now()
+	if now.Before(next) {
+		return reconcile.Result{RequeueAfter: next.Sub(now)}, nil
+	}
+
+	cr.Status.LastScheduleTime = &metav1.Time{Time: now}
+	run, err := r.startRun(ctx, cr, now)
+	if err != nil {
+		// the drill is failed before the restore is created, e.g. there are no backups
+		log.Error(err, "failed to start restore drill")
+		completed := metav1.NewTime(now)
+		cr.Status.Current = &api.RestoreDrillRun{
+			State:       api.RestoreDrillFailed,
+			StartedAt:   completed,
+			CompletedAt: &completed,
+			Message:     err.Error(),
+		}
+		cr.FinishRun()
+		return rr, nil
+	}
+
+	log.Info("restore drill started", "restore", run.Restore, "cluster", run.Cluster, "backup", run.Backup, "pitrDate", run.PITRDate)
+	cr.Status.Current = run
+	return rr, nil
+}
+
+// reconcileRun follows the restore of the running drill, checks the assertions on the restored
+// cluster and deletes the drill cluster once the result of the drill is known.
+func (r *ReconcilePerconaXtraDBClusterRestoreDrill) reconcileRun(ctx context.Context, cr *api.PerconaXtraDBClusterRestoreDrill) (reconcile.Result, error) {
+	log := logf.FromContext(ctx)
+	rr := reconcile.Result{
+		RequeueAfter: time.Second * 30,
+	}
+	run := cr.Status.Current
+
+	if run.State != api.RestoreDrillRunning {
+		return r.teardown(ctx, cr)
+	}
+
+	now := time.Now()
+	timedOut := now.Sub(run.StartedAt.Time) > cr.Spec.Timeout.Duration
+
+	restore := new(api.PerconaXtraDBClusterRestore)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: run.Restore, Namespace: cr.Namespace}, restore); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, errors.Wrapf(err, "get restore %s", run.Restore)
+		}
+		finishRun(run, api.RestoreDrillFailed, now, fmt.Sprintf("restore %s not found", run.Restore))
+		return r.teardown(ctx, cr)
+	}
+
+	if err := r.adoptCluster(ctx, cr, run.Cluster); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "adopt cluster %s", run.Cluster)
+	}
+
+	switch restore.Status.State {
+	case api.RestoreFailed:
+		finishRun(run, api.RestoreDrillFailed, now, "restore failed: "+restore.Status.Comments)
+	case api.RestoreSucceeded:
+		if run.RTO == nil {
+			readyAt := now
+			if restore.Status.CompletedAt != nil {
+				readyAt = restore.Status.CompletedAt.Time
+			}
+			run.RTO = &metav1.Duration{Duration: readyAt.Sub(run.StartedAt.Time).Round(time.Second)}
+		}
+
+		results, err := r.checkAssertions(ctx, r.client, cr, run.Cluster)
+		if err != nil {
+			if !timedOut {
+				log.Info("waiting for restored cluster to accept connections", "cluster", run.Cluster, "error", err.Error())
+				return rr, nil
+			}
+			finishRun(run, api.RestoreDrillFailed, now, "check assertions: "+err.Error())
+			break
+		}
+		run.Assertions = results
+
+		var failed []string
+		for _, res := range results {
+			if !res.Passed {
+				failed = append(failed, res.Name)
+			}
+		}
+		if len(failed) > 0 {
+			finishRun(run, api.RestoreDrillFailed, now, fmt.Sprintf("assertions failed: %v", failed))
+			break
+		}
+		finishRun(run, api.RestoreDrillSucceeded, now, "")
+	default:
+		if !timedOut {
+			return rr, nil
+		}
+		finishRun(run, api.RestoreDrillFailed, now, fmt.Sprintf("timed out after %s in state %q", cr.Spec.Timeout.Duration, restore.Status.State))
+	}
+
+	log.Info("restore drill finished", "restore", run.Restore, "state", run.State, "rto", run.RTO, "message", run.Message)
+	return r.teardown(ctx, cr)
+}
+
+func finishRun(run *api.RestoreDrillRun, state api.RestoreDrillState, now time.Time, message string) {
+	completed := metav1.NewTime(now)
+	run.State = state
+	run.CompletedAt = &completed
+	run.Message = message
+}
+
+func setStatus(ctx context.Context, cl client.Client, cr *api.PerconaXtraDBClusterRestoreDrill) error {
+	err := k8sretry.RetryOnConflict(k8sretry.DefaultRetry, func() error {
+		drill := new(api.PerconaXtraDBClusterRestoreDrill)
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(cr), drill); err != nil {
+			return err
+		}
+
+		drill.Status = cr.Status
+
+		return cl.Status().Update(ctx, drill)
+	})
+	if err != nil {
+		return errors.Wrap(err, "send update")
+	}
+
+	return nil
+}
@@ -0,0 +1,394 @@
+package pxcrestoredrill
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/test"
+)
+
+func newDrill(lastSchedule time.Time) *api.PerconaXtraDBClusterRestoreDrill {
+	return &api.PerconaXtraDBClusterRestoreDrill{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "monthly",
+			Namespace: "default",
+		},
+		Spec: api.PerconaXtraDBClusterRestoreDrillSpec{
+			PXCCluster: "prod",
+			Schedule:   "0 0 1 * *",
+			Assertions: []api.RestoreDrillAssertion{
+				{Name: "orders", Query: "SELECT COUNT(*) > 0 FROM shop.orders", Expected: "1"},
+			},
+		},
+		Status: api.PerconaXtraDBClusterRestoreDrillStatus{
+			LastScheduleTime: &metav1.Time{Time: lastSchedule},
+		},
+	}
+}
+
+func newBackup(name string, completed time.Time) *api.PerconaXtraDBClusterBackup {
+	return &api.PerconaXtraDBClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: api.PXCBackupSpec{
+			PXCCluster:  "prod",
+			StorageName: "s3-us-west",
+		},
+		Status: api.PXCBackupStatus{
+			State:       api.BackupSucceeded,
+			CompletedAt: &metav1.Time{Time: completed},
+		},
+	}
+}
+
+func reconcileDrill(t *testing.T, cl client.Client, check checkAssertionsFunc) (*api.PerconaXtraDBClusterRestoreDrill, reconcile.Result) {
+	t.Helper()
+
+	r := &ReconcilePerconaXtraDBClusterRestoreDrill{
+		client:          cl,
+		scheme:          cl.Scheme(),
+		checkAssertions: check,
+	}
+	res, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "monthly", Namespace: "default"}})
+	require.NoError(t, err)
+
+	drill := new(api.PerconaXtraDBClusterRestoreDrill)
+	require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: "monthly", Namespace: "default"}, drill))
+	return drill, res
+}
+
+func TestReconcileSchedule(t *testing.T) {
+	cluster := &api.PerconaXtraDBCluster{ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: "default"}}
+
+	t.Run("not due", func(t *testing.T) {
+		cl := test.BuildFakeClient(newDrill(time.Now()), cluster, newBackup("daily", time.Now().Add(-time.Hour)))
+
+		drill, res := reconcileDrill(t, cl, nil)
+		assert.Nil(t, drill.Status.Current)
+		assert.Positive(t, res.RequeueAfter)
+
+		restores := new(api.PerconaXtraDBClusterRestoreList)
+		require.NoError(t, cl.List(context.Background(), restores))
+		assert.Empty(t, restores.Items)
+	})
+
+	t.Run("due", func(t *testing.T) {
+		cl := test.BuildFakeClient(
+			newDrill(time.Now().AddDate(0, -2, 0)),
+			cluster,
+			newBackup("old", time.Now().Add(-48*time.Hour)),
+			newBackup("latest", time.Now().Add(-time.Hour)),
+		)
+
+		drill, _ := reconcileDrill(t, cl, nil)
+		require.NotNil(t, drill.Status.Current)
+		run := drill.Status.Current
+		assert.Equal(t, api.RestoreDrillRunning, run.State)
+		assert.Equal(t, "latest", run.Backup)
+		assert.LessOrEqual(t, len(run.Cluster), 22)
+
+		restore := new(api.PerconaXtraDBClusterRestore)
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: run.Restore, Namespace: "default"}, restore))
+		assert.Equal(t, "prod", restore.Spec.PXCCluster)
+		assert.Equal(t, "latest", restore.Spec.BackupName)
+		require.NotNil(t, restore.Spec.NewCluster)
+		assert.Equal(t, run.Cluster, restore.Spec.NewCluster.Name)
+		assert.Equal(t, "monthly", restore.Spec.NewCluster.Labels[naming.LabelPerconaRestoreDrillName])
+		assert.True(t, metav1.IsControlledBy(restore, drill))
+	})
+
+	t.Run("no backups", func(t *testing.T) {
+		cl := test.BuildFakeClient(newDrill(time.Now().AddDate(0, -2, 0)), cluster)
+
+		drill, _ := reconcileDrill(t, cl, nil)
+		assert.Nil(t, drill.Status.Current)
+		require.Len(t, drill.Status.History, 1)
+		assert.Equal(t, api.RestoreDrillFailed, drill.Status.History[0].State)
+		assert.Contains(t, drill.Status.History[0].Message, "no succeeded backups")
+	})
+}
+
+func TestDrillUsersSecret(t *testing.T) {
+	ctx := context.Background()
+	cluster := &api.PerconaXtraDBCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: "default"},
+		Spec:       api.PerconaXtraDBClusterSpec{SecretsName: "prod-secrets"},
+	}
+
+	t.Run("drill cluster", func(t *testing.T) {
+		drill := newDrill(time.Now().AddDate(0, -2, 0))
+		drill.Spec.Overrides = &runtime.RawExtension{Raw: []byte(`{"pxc":{"size":1}}`)}
+		cl := test.BuildFakeClient(drill, cluster, newBackup("latest", time.Now().Add(-time.Hour)))
+
+		drill, _ = reconcileDrill(t, cl, nil)
+		require.NotNil(t, drill.Status.Current)
+
+		// the restore copies prod-secrets into <cluster>-secrets
+		// only if neither the template nor the overrides set secretsName
+		restore := new(api.PerconaXtraDBClusterRestore)
+		require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: drill.Status.Current.Restore, Namespace: "default"}, restore))
+		require.NotNil(t, restore.Spec.NewCluster)
+		assert.Nil(t, restore.Spec.NewCluster.Template)
+		assert.NotContains(t, string(restore.Spec.NewCluster.Overrides.Raw), "secretsName")
+	})
+
+	t.Run("overrides with secretsName", func(t *testing.T) {
+		drill := newDrill(time.Now().AddDate(0, -2, 0))
+		drill.Spec.Overrides = &runtime.RawExtension{Raw: []byte(`{"secretsName":"prod-secrets"}`)}
+		cl := test.BuildFakeClient(drill, cluster, newBackup("latest", time.Now().Add(-time.Hour)))
+
+		drill, _ = reconcileDrill(t, cl, nil)
+		assert.Nil(t, drill.Status.Current)
+		require.Len(t, drill.Status.History, 1)
+		assert.Equal(t, api.RestoreDrillFailed, drill.Status.History[0].State)
+		assert.Contains(t, drill.Status.History[0].Message, "can't set secretsName")
+
+		restores := new(api.PerconaXtraDBClusterRestoreList)
+		require.NoError(t, cl.List(ctx, restores))
+		assert.Empty(t, restores.Items)
+	})
+
+	t.Run("assertions", func(t *testing.T) {
+		drill := newDrill(time.Now())
+		drillCluster := &api.PerconaXtraDBCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "monthly-drill", Namespace: "default"},
+			Spec:       api.PerconaXtraDBClusterSpec{SecretsName: "monthly-drill-secrets"},
+		}
+		cl := test.BuildFakeClient(drill, cluster, drillCluster)
+
+		// the secret is read before the connection, the missing secret shows which secret is used
+		_, err := checkAssertions(ctx, cl, drill, "monthly-drill")
+		require.Error(t, err)
+		assert.True(t, k8serrors.IsNotFound(err))
+		assert.Contains(t, err.Error(), `"monthly-drill-secrets"`)
+	})
+}
+
+func TestReconcileRun(t *testing.T) {
+	started := time.Now().Add(-time.Hour)
+
+	newRunningDrill := func() *api.PerconaXtraDBClusterRestoreDrill {
+		drill := newDrill(started)
+		drill.Status.Current = &api.RestoreDrillRun{
+			Restore:   "monthly-drill",
+			Cluster:   "monthly-drill",
+			Backup:    "latest",
+			State:     api.RestoreDrillRunning,
+			StartedAt: metav1.NewTime(started),
+		}
+		return drill
+	}
+	newRestore := func(state api.RestoreState) *api.PerconaXtraDBClusterRestore {
+		completed := metav1.NewTime(started.Add(40 * time.Minute))
+		return &api.PerconaXtraDBClusterRestore{
+			ObjectMeta: metav1.ObjectMeta{Name: "monthly-drill", Namespace: "default"},
+			Status: api.PerconaXtraDBClusterRestoreStatus{
+				State:       state,
+				CompletedAt: &completed,
+				Comments:    "backup is corrupted",
+			},
+		}
+	}
+	checkAssertions := func(passed bool) checkAssertionsFunc {
+		return func(_ context.Context, _ client.Client, _ *api.PerconaXtraDBClusterRestoreDrill, _ string) ([]api.RestoreDrillAssertionResult, error) {
+			return []api.RestoreDrillAssertionResult{{Name: "orders", Passed: passed, Actual: "1"}}, nil
+		}
+	}
+
+	t.Run("running", func(t *testing.T) {
+		cl := test.BuildFakeClient(newRunningDrill(), newRestore(api.RestoreRestore))
+
+		drill, _ := reconcileDrill(t, cl, checkAssertions(true))
+		require.NotNil(t, drill.Status.Current)
+		assert.Equal(t, api.RestoreDrillRunning, drill.Status.Current.State)
+	})
+
+	t.Run("succeeded", func(t *testing.T) {
+		cl := test.BuildFakeClient(newRunningDrill(), newRestore(api.RestoreSucceeded))
+
+		drill, _ := reconcileDrill(t, cl, checkAssertions(true))
+		assert.Nil(t, drill.Status.Current)
+		require.Len(t, drill.Status.History, 1)
+		run := drill.Status.History[0]
+		assert.Equal(t, api.RestoreDrillSucceeded, run.State)
+		require.NotNil(t, run.RTO)
+		assert.Equal(t, 40*time.Minute, run.RTO.Duration)
+		require.Len(t, run.Assertions, 1)
+		assert.True(t, run.Assertions[0].Passed)
+		assert.Equal(t, run.CompletedAt, drill.Status.LastSuccessfulTime)
+
+		err := cl.Get(context.Background(), types.NamespacedName{Name: "monthly-drill", Namespace: "default"}, new(api.PerconaXtraDBClusterRestore))
+		assert.True(t, k8serrors.IsNotFound(err), "restore should be deleted")
+	})
+
+	t.Run("assertion failed", func(t *testing.T) {
+		cl := test.BuildFakeClient(newRunningDrill(), newRestore(api.RestoreSucceeded))
+
+		drill, _ := reconcileDrill(t, cl, checkAssertions(false))
+		require.Len(t, drill.Status.History, 1)
+		assert.Equal(t, api.RestoreDrillFailed, drill.Status.History[0].State)
+		assert.Contains(t, drill.Status.History[0].Message, "orders")
+		assert.Nil(t, drill.Status.LastSuccessfulTime)
+	})
+
+	t.Run("restore failed", func(t *testing.T) {
+		cl := test.BuildFakeClient(newRunningDrill(), newRestore(api.RestoreFailed))
+
+		drill, _ := reconcileDrill(t, cl, checkAssertions(true))
+		require.Len(t, drill.Status.History, 1)
+		assert.Equal(t, api.RestoreDrillFailed, drill.Status.History[0].State)
+		assert.Contains(t, drill.Status.History[0].Message, "backup is corrupted")
+	})
+
+	t.Run("timed out", func(t *testing.T) {
+		drill := newRunningDrill()
+		drill.Spec.Timeout = &metav1.Duration{Duration: 30 * time.Minute}
+		cl := test.BuildFakeClient(drill, newRestore(api.RestoreRestore))
+
+		drill, _ = reconcileDrill(t, cl, checkAssertions(true))
+		require.Len(t, drill.Status.History, 1)
+		assert.Equal(t, api.RestoreDrillFailed, drill.Status.History[0].State)
+		assert.Contains(t, drill.Status.History[0].Message, "timed out")
+	})
+
+	t.Run("teardown", func(t *testing.T) {
+		cluster := &api.PerconaXtraDBCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "monthly-drill",
+				Namespace: "default",
+				Labels:    map[string]string{naming.LabelPerconaRestoreDrillName: "monthly"},
+			},
+		}
+		cl := test.BuildFakeClient(newRunningDrill(), newRestore(api.RestoreSucceeded), cluster)
+
+		drill, _ := reconcileDrill(t, cl, checkAssertions(true))
+		require.NotNil(t, drill.Status.Current, "drill should wait for the cluster to be deleted")
+		assert.Equal(t, api.RestoreDrillSucceeded, drill.Status.Current.State)
+
+		err := cl.Get(context.Background(), client.ObjectKeyFromObject(cluster), cluster)
+		require.NoError(t, err)
+		assert.NotNil(t, cluster.DeletionTimestamp)
+		assert.ElementsMatch(t, drillClusterFinalizers, cluster.Finalizers)
+		assert.True(t, metav1.IsControlledBy(cluster, drill))
+	})
+}
+
+func TestFinishRunHistoryLimit(t *testing.T) {
+	drill := newDrill(time.Now())
+	limit := int32(2)
+	drill.Spec.HistoryLimit = &limit
+
+	for i := 0; i < 3; i++ {
+		completed := metav1.NewTime(time.Now().Add(time.Duration(i) * time.Hour))
+		drill.Status.Current = &api.RestoreDrillRun{
+			Restore:     "run-" + string(rune('a'+i)),
+			State:       api.RestoreDrillSucceeded,
+			CompletedAt: &completed,
+		}
+		drill.FinishRun()
+	}
+
+	require.Len(t, drill.Status.History, 2)
+	assert.Equal(t, "run-c", drill.Status.History[0].Restore)
+	assert.Equal(t, "run-b", drill.Status.History[1].Restore)
+	assert.Equal(t, drill.Status.History[0].CompletedAt, drill.Status.LastSuccessfulTime)
+}
+
+func TestPITRDate(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	bcp := newBackup("daily", now.Add(-10*time.Hour))
+	status := &api.PITRStatus{
+		RestorableWindows: []api.PITRRestorableWindow{
+			{Start: metav1.NewTime(now.Add(-20 * time.Hour)), End: metav1.NewTime(now.Add(-5 * time.Hour))},
+			{Start: metav1.NewTime(now.Add(-3 * time.Hour)), End: metav1.NewTime(now.Add(-time.Minute))},
+		},
+	}
+
+	first := func(int64) int64 { return 0 }
+	last := func(n int64) int64 { return n - 1 }
+
+	tests := map[string]struct {
+		window   *metav1.Duration
+		rand     func(int64) int64
+		expected time.Time
+		err      string
+	}{
+		"after backup": {
+			rand:     first,
+			expected: now.Add(-10 * time.Hour),
+		},
+		"second window": {
+			rand:     func(int64) int64 { return int64(5 * time.Hour) },
+			expected: now.Add(-3 * time.Hour),
+		},
+		"latest": {
+			rand:     last,
+			expected: now.Add(-time.Minute - time.Second),
+		},
+		"drill window": {
+			window:   &metav1.Duration{Duration: 2 * time.Hour},
+			rand:     first,
+			expected: now.Add(-2 * time.Hour),
+		},
+		"no point after backup": {
+			window: &metav1.Duration{Duration: 30 * time.Second},
+			rand:   first,
+			err:    "no restorable point in time",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			date, err := pitrDate(status, bcp, tt.window, now, tt.rand)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, date)
+		})
+	}
+
+	_, err := pitrDate(nil, bcp, nil, now, first)
+	assert.ErrorContains(t, err, "no restorable windows")
+}
+
+type fakeQuerier map[string]string
+
+func (q fakeQuerier) QueryValue(_ context.Context, query string) (string, error) {
+	v, ok := q[query]
+	if !ok {
+		return "", errors.New("table doesn't exist")
+	}
+	return v, nil
+}
+
+func TestCheckAssertion(t *testing.T) {
+	db := fakeQuerier{"SELECT COUNT(*) FROM shop.orders": "42\n"}
+
+	res := checkAssertion(context.Background(), db, api.RestoreDrillAssertion{Name: "count", Query: "SELECT COUNT(*) FROM shop.orders", Expected: "42"})
+	assert.True(t, res.Passed)
+	assert.Equal(t, "42\n", res.Actual)
+
+	res = checkAssertion(context.Background(), db, api.RestoreDrillAssertion{Name: "count", Query: "SELECT COUNT(*) FROM shop.orders", Expected: "43"})
+	assert.False(t, res.Passed)
+
+	res = checkAssertion(context.Background(), db, api.RestoreDrillAssertion{Name: "missing", Query: "SELECT 1 FROM shop.missing", Expected: "1"})
+	assert.False(t, res.Passed)
+	assert.Equal(t, "table doesn't exist", res.Error)
+}
@@ -0,0 +1,290 @@
+package pxcrestoredrill
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+)
+
+// pitrDateFormat is the format of the date of the point-in-time recovery.
+const pitrDateFormat = "2006-01-02 15:04:05"
+
+// startRun creates the restore of the latest backup into the drill cluster.
+func (r *ReconcilePerconaXtraDBClusterRestoreDrill) startRun(ctx context.Context, cr *api.PerconaXtraDBClusterRestoreDrill, now time.Time) (*api.RestoreDrillRun, error) {
+	cluster := new(api.PerconaXtraDBCluster)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: cr.Spec.PXCCluster, Namespace: cr.Namespace}, cluster); err != nil {
+		return nil, errors.Wrapf(err, "get cluster %s", cr.Spec.PXCCluster)
+	}
+
+	if err := validateOverrides(cr.Spec.Overrides); err != nil {
+		return nil, err
+	}
+
+	bcp, err := latestBackup(ctx, r.client, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	name := naming.RestoreDrillName(cr, now)
+	// the drill cluster is deleted after the drill,
+	// so an existing cluster must never be used by the restore
+	err = r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: cr.Namespace}, new(api.PerconaXtraDBCluster))
+	if err == nil {
+		return nil, errors.Errorf("cluster %s already exists", name)
+	}
+	if !k8serrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "get cluster %s", name)
+	}
+
+	labels := map[string]string{
+		naming.LabelPerconaRestoreDrillName: cr.Name,
+	}
+	restore := &api.PerconaXtraDBClusterRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cr.Namespace,
+			Labels:    labels,
+		},
+		Spec: api.PerconaXtraDBClusterRestoreSpec{
+			PXCCluster: cr.Spec.PXCCluster,
+			BackupName: bcp.Name,
+			NewCluster: &api.RestoreNewClusterSpec{
+				Name:      name,
+				Labels:    labels,
+				Overrides: cr.Spec.Overrides,
+			},
+		},
+	}
+
+	run := &api.RestoreDrillRun{
+		Restore:   name,
+		Cluster:   name,
+		Backup:    bcp.Name,
+		State:     api.RestoreDrillRunning,
+		StartedAt: metav1.NewTime(now),
+	}
+
+	if cr.Spec.PITR != nil && cr.Spec.PITR.Enabled {
+		if cluster.Spec.Backup == nil || !cluster.Spec.Backup.PITR.Enabled {
+			return nil, errors.Errorf("point-in-time recovery is not enabled in cluster %s", cluster.Name)
+		}
+		date, err := pitrDate(cluster.Status.PITR, bcp, cr.Spec.PITR.Window, now, rand.Int63n)
+		if err != nil {
+			return nil, err
+		}
+		run.PITRDate = date.UTC().Format(pitrDateFormat)
+		restore.Spec.PITR = &api.PITR{
+			Type: "date",
+			Date: run.PITRDate,
+			BackupSource: &api.PXCBackupStatus{
+				StorageName: cluster.Spec.Backup.PITR.StorageName,
+			},
+		}
+	}
+
+	if err := controllerutil.SetControllerReference(cr, restore, r.scheme); err != nil {
+		return nil, errors.Wrap(err, "set controller reference")
+	}
+	if err := r.client.Create(ctx, restore); err != nil {
+		return nil, errors.Wrapf(err, "create restore %s", restore.Name)
+	}
+
+	return run, nil
+}
+
+// validateOverrides checks that the drill cluster gets its own users secret,
+// which is copied from pxcCluster by the restore. The secret is deleted with the drill cluster.
+func validateOverrides(overrides *runtime.RawExtension) error {
+	if overrides == nil || len(overrides.Raw) == 0 {
+		return nil
+	}
+
+	spec := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(overrides.Raw, &spec); err != nil {
+		return errors.Wrap(err, "unmarshal overrides")
+	}
+	if _, ok := spec["secretsName"]; ok {
+		return errors.New("overrides can't set secretsName, the users secret of the drill cluster is deleted with the cluster")
+	}
+
+	return nil
+}
+
+// latestBackup returns the latest succeeded backup of the cluster of the drill.
+func latestBackup(ctx context.Context, cl client.Client, cr *api.PerconaXtraDBClusterRestoreDrill) (*api.PerconaXtraDBClusterBackup, error) {
+	list := new(api.PerconaXtraDBClusterBackupList)
+	if err := cl.List(ctx, list, client.InNamespace(cr.Namespace)); err != nil {
+		return nil, errors.Wrap(err, "list backups")
+	}
+
+	var latest *api.PerconaXtraDBClusterBackup
+	for i, bcp := range list.Items {
+		if bcp.Spec.PXCCluster != cr.Spec.PXCCluster || bcp.Status.State != api.BackupSucceeded {
+			continue
+		}
+		if cr.Spec.StorageName != "" && bcp.Spec.StorageName != cr.Spec.StorageName {
+			continue
+		}
+		// binlogs can't be applied to logical backups
+		if cr.Spec.PITR != nil && cr.Spec.PITR.Enabled && bcp.Status.IsLogical() {
+			continue
+		}
+		if latest == nil || completedAt(latest).Before(completedAt(&bcp)) {
+			latest = &list.Items[i]
+		}
+	}
+	if latest == nil {
+		return nil, errors.Errorf("no succeeded backups of cluster %s", cr.Spec.PXCCluster)
+	}
+
+	return latest, nil
+}
+
+func completedAt(bcp *api.PerconaXtraDBClusterBackup) time.Time {
+	if bcp.Status.CompletedAt != nil {
+		return bcp.Status.CompletedAt.Time
+	}
+	return bcp.CreationTimestamp.Time
+}
+
+// pitrDate returns a random point in time the backup can be recovered to.
+// The point is chosen from the restorable windows of the binlogs after the backup
+// and within the window of the drill if it's set.
+func pitrDate(status *api.PITRStatus, bcp *api.PerconaXtraDBClusterBackup, window *metav1.Duration, now time.Time, randInt63n func(int64) int64) (time.Time, error) {
+	if status == nil || len(status.RestorableWindows) == 0 {
+		return time.Time{}, errors.New("no restorable windows in the binlogs")
+	}
+
+	from := completedAt(bcp)
+	if window != nil && window.Duration > 0 && now.Add(-window.Duration).After(from) {
+		from = now.Add(-window.Duration)
+	}
+
+	type interval struct{ start, end time.Time }
+	var intervals []interval
+	var total time.Duration
+	for _, w := range status.RestorableWindows {
+		start, end := w.Start.Time, w.End.Time
+		if start.Before(from) {
+			start = from
+		}
+		if !end.After(start) {
+			continue
+		}
+		intervals = append(intervals, interval{start, end})
+		total += end.Sub(start)
+	}
+	if len(intervals) == 0 {
+		return time.Time{}, errors.Errorf("no restorable point in time after %s", from.UTC().Format(pitrDateFormat))
+	}
+
+	// the date is truncated to the seconds, so the intervals are shortened
+	// to not choose a point before their start
+	offset := time.Duration(randInt63n(int64(total)))
+	for _, i := range intervals {
+		d := i.end.Sub(i.start)
+		if offset < d {
+			t := i.start.Add(offset).Truncate(time.Second)
+			if t.Before(i.start) {
+				t = t.Add(time.Second)
+			}
+			return t, nil
+		}
+		offset -= d
+	}
+
+	return intervals[len(intervals)-1].end.Truncate(time.Second), nil
+}
+
+// adoptCluster makes the drill the owner of the drill cluster and sets the finalizers
+// which delete the volumes and the certificates of the cluster. The cluster is deleted
+// with its data after the drill or if the drill object is deleted.
+func (r *ReconcilePerconaXtraDBClusterRestoreDrill) adoptCluster(ctx context.Context, cr *api.PerconaXtraDBClusterRestoreDrill, name string) error {
+	cluster := new(api.PerconaXtraDBCluster)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: name, Namespace: cr.Namespace}, cluster); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !isDrillCluster(cr, cluster) || metav1.IsControlledBy(cluster, cr) {
+		return nil
+	}
+
+	orig := cluster.DeepCopy()
+	if err := controllerutil.SetControllerReference(cr, cluster, r.scheme); err != nil {
+		return errors.Wrap(err, "set controller reference")
+	}
+	for _, f := range drillClusterFinalizers {
+		if !slices.Contains(cluster.Finalizers, f) {
+			cluster.Finalizers = append(cluster.Finalizers, f)
+		}
+	}
+
+	return r.client.Patch(ctx, cluster, client.MergeFrom(orig))
+}
+
+var drillClusterFinalizers = []string{
+	naming.FinalizerDeleteSSL,
+	naming.FinalizerDeleteProxysqlPvc,
+	naming.FinalizerDeletePxcPvc,
+}
+
+// isDrillCluster checks if the cluster was created by the restore of the drill.
+func isDrillCluster(cr *api.PerconaXtraDBClusterRestoreDrill, cluster *api.PerconaXtraDBCluster) bool {
+	return cluster.Labels[naming.LabelPerconaRestoreDrillName] == cr.Name
+}
+
+// teardown deletes the drill cluster and the restore and moves the drill to the history
+// once the cluster is deleted.
+func (r *ReconcilePerconaXtraDBClusterRestoreDrill) teardown(ctx context.Context, cr *api.PerconaXtraDBClusterRestoreDrill) (reconcile.Result, error) {
+	log := logf.FromContext(ctx)
+	run := cr.Status.Current
+
+	if err := r.adoptCluster(ctx, cr, run.Cluster); err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "adopt cluster %s", run.Cluster)
+	}
+
+	cluster := new(api.PerconaXtraDBCluster)
+	err := r.client.Get(ctx, types.NamespacedName{Name: run.Cluster, Namespace: cr.Namespace}, cluster)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return reconcile.Result{}, errors.Wrapf(err, "get cluster %s", run.Cluster)
+	}
+	if err == nil && isDrillCluster(cr, cluster) {
+		if cluster.DeletionTimestamp == nil {
+			log.Info("deleting drill cluster", "cluster", cluster.Name)
+			if err := r.client.Delete(ctx, cluster); err != nil && !k8serrors.IsNotFound(err) {
+				return reconcile.Result{}, errors.Wrapf(err, "delete cluster %s", cluster.Name)
+			}
+		}
+		return reconcile.Result{RequeueAfter: time.Second * 10}, nil
+	}
+
+	restore := &api.PerconaXtraDBClusterRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      run.Restore,
+			Namespace: cr.Namespace,
+		},
+	}
+	if err := r.client.Delete(ctx, restore); err != nil && !k8serrors.IsNotFound(err) {
+		return reconcile.Result{}, errors.Wrapf(err, "delete restore %s", restore.Name)
+	}
+
+	cr.FinishRun()
+	return reconcile.Result{Requeue: true}, nil
+}
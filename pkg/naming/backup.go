@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"hash/crc32"
 	"strconv"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -27,6 +28,11 @@ func BackupJobName(crName string) string {
 	return trimJobName("xb-" + crName)
 }
 
+// BackupVerifyJobName generates name for the job which verifies the backup.
+func BackupVerifyJobName(crName string) string {
+	return trimJobName("xb-verify-" + crName)
+}
+
 // trimJobName trims the provided string to ensure it stays within the 63-character limit.
 // The job name will be included in the "batch.kubernetes.io/job-name" label in the ".spec.template" section of the job.
 // Labels have a maximum length of 63 characters, so this function ensures the job name fits within that limit.
@@ -81,3 +87,32 @@ func ScheduledBackupName(crName, storageName, schedule string) string {
 	result += "-" + strconv.FormatUint(uint64(crc32.ChecksumIEEE([]byte(schedule))), 32)[:5]
 	return result
 }
+
+// ImportedBackupName generates the name of the backup object
+// for the backup found in the storage by the backup catalog.
+func ImportedBackupName(storageName, backupName string) string {
+	return backupObjectName(storageName + "-" + strings.ReplaceAll(backupName, ":", ""))
+}
+
+// BackupCopyName generates the name of the backup object for the copy of the backup in the storage.
+func BackupCopyName(backupName, storageName string) string {
+	return backupObjectName(backupName + "-" + storageName)
+}
+
+// backupObjectName replaces the characters which aren't allowed in object names
+// and trims the name to the maximum length.
+func backupObjectName(name string) string {
+	b := []byte(strings.ToLower(name))
+	for i, c := range b {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '.' {
+			b[i] = '-'
+		}
+	}
+
+	result := strings.Trim(string(b), "-.")
+	if len(result) > validation.DNS1123SubdomainMaxLength {
+		result = strings.TrimRight(result[:validation.DNS1123SubdomainMaxLength], "-.")
+	}
+
+	return result
+}
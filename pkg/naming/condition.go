@@ -6,6 +6,9 @@ import (
 
 const ConditionTLS api.AppState = "tls"
 
+// ConditionPITRDegraded is set if the RPO of the point-in-time recovery exceeds the threshold.
+const ConditionPITRDegraded api.AppState = "PITRDegraded"
+
 type ConditionTLSState string
 
 const (
@@ -12,3 +12,10 @@ const (
 	TimelinePath     = "/tmp/pitr-timeline" // path to file with timeline
 	LatestBackupPath = "/tmp/latest-backup"
 )
+
+const (
+	// RestoreProgressDir is the directory with the progress of the restore job in the restored datadir.
+	RestoreProgressDir = "/datadir/.restore-progress"
+	// PITRRestoreProgressDir is the directory with the progress of the point-in-time recovery job.
+	PITRRestoreProgressDir = "/tmp/restore-progress"
+)
@@ -27,11 +27,16 @@ const (
 	LabelPerconaBackupName         = perconaPrefix + "backup-name"
 	LabelPerconaBackupJobName      = perconaPrefix + "backup-job-name"
 	LabelPerconaBackupAncestorName = perconaPrefix + "backup-ancestor"
+	LabelPerconaStorageName        = perconaPrefix + "storage-name"
 
 	LabelPerconaRestoreServiceName = perconaPrefix + "restore-svc-name"
 	LabelPerconaRestoreJobName     = perconaPrefix + "restore-job-name"
+	LabelPerconaRestoreDrillName   = perconaPrefix + "restore-drill"
 )
 
+// BackupTypeImported is the backup type of the backup objects created by the backup catalog.
+const BackupTypeImported = "imported"
+
 func GetLabelBackupType(cr *api.PerconaXtraDBCluster) string {
 	if cr.CompareVersionWith("1.16.0") < 0 {
 		return "type"
@@ -155,6 +160,17 @@ func LabelsBackup(cluster *api.PerconaXtraDBCluster) map[string]string {
 	}
 }
 
+// LabelsImportedBackup returns labels of the backup objects created by the backup catalog.
+func LabelsImportedBackup(cluster *api.PerconaXtraDBCluster, storageName string) map[string]string {
+	labels := make(map[string]string)
+	util.MergeMaps(labels, LabelsCluster(cluster), map[string]string{
+		LabelPerconaBackupType:  BackupTypeImported,
+		LabelPerconaClusterName: cluster.Name,
+		LabelPerconaStorageName: storageName,
+	})
+	return labels
+}
+
 func LabelsBackupJob(cr *api.PerconaXtraDBClusterBackup, cluster *api.PerconaXtraDBCluster, jobName string) map[string]string {
 	labels := make(map[string]string)
 	util.MergeMaps(labels, cluster.Spec.Backup.Storages[cr.Spec.StorageName].Labels)
@@ -13,6 +13,7 @@ const (
 	FinalizerDeleteBackup         = annotationPrefix + "delete-backup"
 	FinalizerReleaseLock          = internalAnnotationPrefix + "release-lock"
 	FinalizerKeepJob              = internalAnnotationPrefix + "keep-job"
+	FinalizerResyncBackupSource   = internalAnnotationPrefix + "resync-backup-source"
 )
 
 const (
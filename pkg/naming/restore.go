@@ -1,11 +1,15 @@
 package naming
 
 import (
+	"strconv"
+	"strings"
+	"time"
+
 	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
 )
 
 func PrepareJobName(restore *pxcv1.PerconaXtraDBClusterRestore) string {
-	return "prepare-job-" + restore.Name + "-" + restore.Spec.PXCCluster
+	return "prepare-job-" + restore.Name + "-" + restore.TargetCluster()
 }
 
 func RestoreJobName(cr *pxcv1.PerconaXtraDBClusterRestore, pitr bool) string {
@@ -13,5 +17,36 @@ func RestoreJobName(cr *pxcv1.PerconaXtraDBClusterRestore, pitr bool) string {
 	if pitr {
 		prefix = "pitr-job-"
 	}
-	return prefix + cr.Name + "-" + cr.Spec.PXCCluster
+	return prefix + cr.Name + "-" + cr.TargetCluster()
+}
+
+// RestoreSecretName generates the name of the copy of the backup storage secret
+// created in the namespace of the restore for cross-namespace restores.
+func RestoreSecretName(cr *pxcv1.PerconaXtraDBClusterRestore, secretName string) string {
+	return "restore-" + cr.Name + "-" + secretName
+}
+
+// ExportJobName generates the name of the job which exports the tables of the partial restore.
+func ExportJobName(cr *pxcv1.PerconaXtraDBClusterRestore) string {
+	return "export-job-" + cr.Name + "-" + cr.TargetCluster()
+}
+
+// LogicalRestoreJobName generates the name of the job which loads the logical backup into the cluster.
+func LogicalRestoreJobName(cr *pxcv1.PerconaXtraDBClusterRestore) string {
+	return "logical-restore-job-" + cr.Name + "-" + cr.TargetCluster()
+}
+
+// ExportPVCName generates the name of the volume with the tables exported by the partial restore.
+func ExportPVCName(cr *pxcv1.PerconaXtraDBClusterRestore) string {
+	return "export-" + cr.Name + "-" + cr.TargetCluster()
+}
+
+// RestoreDrillName generates the name of the restore and the cluster of the drill started at the time.
+// The name of the drill is truncated to fit the maximum length of the cluster name.
+func RestoreDrillName(drill *pxcv1.PerconaXtraDBClusterRestoreDrill, t time.Time) string {
+	prefix := drill.Name
+	if len(prefix) > 14 {
+		prefix = strings.TrimRight(prefix[:14], "-.")
+	}
+	return prefix + "-" + strconv.FormatInt(t.Unix(), 36)
 }
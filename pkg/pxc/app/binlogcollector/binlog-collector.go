@@ -3,10 +3,13 @@ package binlogcollector
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
@@ -23,12 +26,19 @@ import (
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app/statefulset"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/encryption"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/throttling"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/timeline"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/users"
 )
 
 const gtidCacheKey = "gtid-binlog-cache.json"
 
+// timelineRequestTimeout is the timeout of the requests to the collector. The collector
+// lists the binlogs in the storage to build the timeline.
+const timelineRequestTimeout = 30 * time.Second
+
 func GetService(cr *api.PerconaXtraDBCluster) *corev1.Service {
 	labels := naming.LabelsPITR(cr)
 
@@ -113,6 +123,33 @@ func GetDeployment(cr *api.PerconaXtraDBCluster, initImage string, existingMatch
 		})
 	}
 
+	if cr.CompareVersionWith("1.20.0") >= 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "STORAGE_NAME",
+			Value: cr.Spec.Backup.PITR.StorageName,
+		})
+	}
+
+	if n := cr.Spec.Backup.PITR.ParallelUploads; n > 1 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "PARALLEL_UPLOADS",
+			Value: strconv.Itoa(int(n)),
+		})
+	}
+
+	if streaming := cr.Spec.Backup.PITR.Streaming; streaming.IsEnabled() {
+		envs = append(envs, []corev1.EnvVar{
+			{
+				Name:  "STREAMING_ENABLED",
+				Value: "true",
+			},
+			{
+				Name:  "STREAMING_FLUSH_SEC",
+				Value: strconv.Itoa(int(streaming.GetFlushInterval().Seconds())),
+			},
+		}...)
+	}
+
 	container := corev1.Container{
 		Name:            "pitr",
 		Image:           cr.Spec.Backup.Image,
@@ -191,6 +228,17 @@ func GetDeployment(cr *api.PerconaXtraDBCluster, initImage string, existingMatch
 		)
 	}
 
+	// the encryption key is fetched from Vault by the collector
+	if ok && storage.Encryption.IsEnabled() && storage.Encryption.VaultPath != "" {
+		volumes = append(volumes, app.GetSecretVolumes(statefulset.VaultSecretVolumeName, cr.Spec.PXC.VaultSecretName, true))
+		container.VolumeMounts = append(container.VolumeMounts,
+			corev1.VolumeMount{
+				Name:      statefulset.VaultSecretVolumeName,
+				MountPath: statefulset.VaultSecretMountPath,
+			},
+		)
+	}
+
 	depl := appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "apps/v1",
@@ -341,6 +389,32 @@ func getStorageEnvs(cr *api.PerconaXtraDBCluster) ([]corev1.EnvVar, error) {
 				Value: "azure",
 			},
 		}
+	case api.BackupStorageGCS:
+		if storage.GCS == nil {
+			return nil, errors.New("gcs storage is not specified")
+		}
+		envs = []corev1.EnvVar{
+			{
+				Name:  "GCS_BUCKET_URL",
+				Value: storage.GCS.Bucket,
+			},
+			{
+				Name:  "GCS_ENDPOINT",
+				Value: storage.GCS.EndpointURL,
+			},
+			{
+				Name:  "STORAGE_TYPE",
+				Value: "gcs",
+			},
+		}
+		if storage.GCS.CredentialsSecret != "" {
+			envs = append(envs, corev1.EnvVar{
+				Name: "GCS_CREDENTIALS_JSON",
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: app.SecretKeySelectorWithOptional(storage.GCS.CredentialsSecret, "GCS_CREDENTIALS_JSON", true),
+				},
+			})
+		}
 	default:
 		return nil, errors.Errorf("%s storage has unsupported type %s", cr.Spec.Backup.PITR.StorageName, storage.Type)
 	}
@@ -352,6 +426,9 @@ func getStorageEnvs(cr *api.PerconaXtraDBCluster) ([]corev1.EnvVar, error) {
 		})
 	}
 
+	envs = append(envs, encryption.Envs("", storage.Encryption)...)
+	envs = append(envs, throttling.Envs(storage.Throttling)...)
+
 	return envs, nil
 }
 
@@ -445,3 +522,58 @@ func InvalidateCache(
 
 	return stg.DeleteObject(ctx, gtidCacheKey)
 }
+
+// TimelineURL returns the URL of the timeline served by the binlog collector of the cluster.
+func TimelineURL(cr *api.PerconaXtraDBCluster) string {
+	return fmt.Sprintf("http://%s.%s:8080/timeline", naming.BinlogCollectorServiceName(cr), cr.Namespace)
+}
+
+// GetTimeline returns the periods of time the cluster can be restored to reported by the binlog collector.
+func GetTimeline(ctx context.Context, url string) (*timeline.Timeline, error) {
+	t := new(timeline.Timeline)
+	if err := getJSON(ctx, url, t); err != nil {
+		return nil, errors.Wrap(err, "get timeline")
+	}
+
+	return t, nil
+}
+
+// StatusURL returns the URL of the collection status served by the binlog collector of the cluster.
+func StatusURL(cr *api.PerconaXtraDBCluster) string {
+	return fmt.Sprintf("http://%s.%s:8080/status", naming.BinlogCollectorServiceName(cr), cr.Namespace)
+}
+
+// GetStatus returns the host the binlog collector collects the binlogs from and the last uploaded binlog.
+func GetStatus(ctx context.Context, url string) (*timeline.CollectorStatus, error) {
+	s := new(timeline.CollectorStatus)
+	if err := getJSON(ctx, url, s); err != nil {
+		return nil, errors.Wrap(err, "get collector status")
+	}
+
+	return s, nil
+}
+
+func getJSON(ctx context.Context, url string, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, timelineRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrap(err, "new request")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "get %s", url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("get %s: unexpected status %s", url, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return errors.Wrap(err, "decode response")
+	}
+
+	return nil
+}
@@ -1,13 +1,20 @@
 package binlogcollector
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/timeline"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/version"
 )
 
@@ -159,3 +166,52 @@ func TestGetDeployment(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTimeline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/timeline" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"windows":[{"start":"2024-01-01T08:00:00Z","end":"2024-01-01T12:00:00Z"}],"gapDetected":"3e11fa47-71ca-11e1-9e33-c80aa9429562:201-250"}`))
+	}))
+	defer srv.Close()
+
+	tl, err := GetTimeline(context.Background(), srv.URL+"/timeline")
+	assert.NoError(t, err)
+	assert.Equal(t, &timeline.Timeline{
+		Windows: []timeline.Window{{
+			Start: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC),
+			End:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		}},
+		GapDetected: "3e11fa47-71ca-11e1-9e33-c80aa9429562:201-250",
+	}, tl)
+
+	// the collector doesn't serve the timeline
+	_, err = GetTimeline(context.Background(), srv.URL+"/unknown")
+	assert.Error(t, err)
+
+	cr := &api.PerconaXtraDBCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: "pxc"}}
+	assert.Equal(t, "http://cluster1-pitr.pxc:8080/timeline", TimelineURL(cr))
+}
+
+func TestGetStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"host":"cluster1-pxc-1.cluster1-pxc","lastUploadedGTIDSet":"3e11fa47-71ca-11e1-9e33-c80aa9429562:1-200","lastUploadedEventTime":"2024-01-01T12:00:00Z","lastUploadTime":"2024-01-01T12:00:30Z","sourceEventTime":"2024-01-01T12:01:00Z"}`))
+	}))
+	defer srv.Close()
+
+	s, err := GetStatus(context.Background(), srv.URL+"/status")
+	assert.NoError(t, err)
+	assert.Equal(t, &timeline.CollectorStatus{
+		Host:                  "cluster1-pxc-1.cluster1-pxc",
+		LastUploadedGTIDSet:   "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-200",
+		LastUploadedEventTime: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		LastUploadTime:        time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC),
+		SourceEventTime:       time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC),
+	}, s)
+	assert.Equal(t, time.Minute, s.Lag())
+
+	cr := &api.PerconaXtraDBCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: "pxc"}}
+	assert.Equal(t, "http://cluster1-pitr.pxc:8080/status", StatusURL(cr))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
@@ -24,6 +25,9 @@ const (
 	// HA_SERVER_OPTIONS environment variable is used by haproxy_add_pxc_nodes.sh
 	// to configure the "server" lines in the HAProxy backend configuration.
 	haConfigEnvVarName = "HA_SERVER_OPTIONS"
+
+	haproxyPrimaryVolumeName = "haproxy-primary"
+	haproxyPrimaryMountPath  = "/etc/haproxy/primary"
 )
 
 type HAProxy struct {
@@ -338,6 +342,19 @@ func (c *HAProxy) SidecarContainers(ctx context.Context, cl client.Client, spec
 		container.Args = append(container.Args, "-protocol=$(PEER_LIST_SRV_PROTOCOL)")
 	}
 
+	if cr.CompareVersionWith("1.20.0") >= 0 {
+		container.Env = append(container.Env, haproxyReplicasSelectionEnvs(cr.Spec.HAProxy.ReplicasSelection)...)
+	}
+
+	// the HAProxy config is regenerated when the operator elects another primary
+	if cr.HAProxyPrimarySelectedByOperator() {
+		container.Args = append(container.Args, "-watch-file="+haproxyPrimaryMountPath+"/primary")
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      haproxyPrimaryVolumeName,
+			MountPath: haproxyPrimaryMountPath,
+		})
+	}
+
 	return []corev1.Container{container}, nil
 }
 
@@ -547,6 +564,9 @@ func (c *HAProxy) Volumes(podSpec *api.PodSpec, cr *api.PerconaXtraDBCluster, vg
 			},
 		)
 	}
+	if cr.HAProxyPrimarySelectedByOperator() {
+		vol.Volumes = append(vol.Volumes, app.GetConfigVolumes(haproxyPrimaryVolumeName, naming.HAProxyPrimaryConfigMapName(cr)))
+	}
 	if cr.CompareVersionWith("1.16.0") >= 0 {
 		for i := range vol.PVCs {
 			vol.PVCs[i].Labels = c.Labels()
@@ -625,6 +645,42 @@ func buildHAProxyHealthCheckEnvValue(healthCheck *api.HAProxyHealthCheckSpec) st
 	return fmt.Sprintf("resolvers kubernetes check inter %d rise %d fall %d weight 1 on-marked-down shutdown-sessions", interval, rise, fall)
 }
 
+// haproxyReplicasSelectionEnvs returns the settings of the replicas backend.
+// haproxy_add_pxc_nodes.sh adds the async replicas to the backend and passes the settings to haproxy_check_pxc.sh.
+func haproxyReplicasSelectionEnvs(selection *api.HAProxyReplicasSelectionSpec) []corev1.EnvVar {
+	if selection == nil {
+		return nil
+	}
+
+	var envs []corev1.EnvVar
+	if selection.MaxRecvQueue != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "REPLICAS_MAX_RECV_QUEUE",
+			Value: strconv.Itoa(int(*selection.MaxRecvQueue)),
+		})
+	}
+	if selection.ExcludeDonors {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "REPLICAS_EXCLUDE_DONORS",
+			Value: "true",
+		})
+	}
+	if selection.AsyncReplicas != nil && len(selection.AsyncReplicas.Hosts) > 0 {
+		envs = append(envs,
+			corev1.EnvVar{
+				Name:  "REPLICAS_ASYNC_HOSTS",
+				Value: strings.Join(selection.AsyncReplicas.Hosts, " "),
+			},
+			corev1.EnvVar{
+				Name:  "REPLICAS_MAX_SECONDS_BEHIND_SOURCE",
+				Value: strconv.Itoa(int(selection.AsyncReplicas.MaxSecondsBehindSource)),
+			},
+		)
+	}
+
+	return envs
+}
+
 func haConfigFromEnvSecret(ctx context.Context, cl client.Client, cr *api.PerconaXtraDBCluster, envName string) (string, error) {
 	secretName := cr.Spec.HAProxy.EnvVarsSecretName
 	if secretName == "" {
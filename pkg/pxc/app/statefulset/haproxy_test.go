@@ -3,6 +3,7 @@ package statefulset
 import (
 	"context"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -188,6 +189,8 @@ func TestSidecarContainers_HAProxy(t *testing.T) {
 		expectedEnvFrom []corev1.EnvFromSource
 		expectError     bool
 		secret          corev1.Secret
+
+		primarySelection api.HAProxyPrimarySelection
 	}{
 		"success - container construction": {
 			spec: api.PodSpec{
@@ -217,6 +220,36 @@ func TestSidecarContainers_HAProxy(t *testing.T) {
 			},
 			expectError: false,
 		},
+		"operator primary selection": {
+			spec: api.PodSpec{
+				Enabled:           true,
+				Image:             "test-image",
+				ImagePullPolicy:   corev1.PullIfNotPresent,
+				EnvVarsSecretName: "test-secret",
+			},
+			secrets:          "monitor-secret",
+			crVersion:        "1.20.0",
+			primarySelection: api.HAProxyPrimarySelectionOperator,
+			expectedName:     "pxc-monit",
+			expectedImage:    "test-image",
+			expectedArgs: []string{
+				"/opt/percona/peer-list",
+				"-on-change=/opt/percona/haproxy_add_pxc_nodes.sh",
+				"-service=$(PXC_SERVICE)",
+				"-protocol=$(PEER_LIST_SRV_PROTOCOL)",
+				"-watch-file=/etc/haproxy/primary/primary",
+			},
+			expectedEnvFrom: []corev1.EnvFromSource{
+				{
+					SecretRef: &corev1.SecretEnvSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: "test-secret",
+						},
+						Optional: pointerToTrue(),
+					},
+				},
+			},
+		},
 	}
 	ctx := context.Background()
 	for name, tt := range tests {
@@ -228,8 +261,9 @@ func TestSidecarContainers_HAProxy(t *testing.T) {
 				Spec: api.PerconaXtraDBClusterSpec{
 					CRVersion: tt.crVersion,
 					HAProxy: &api.HAProxySpec{
-						PodSpec:        tt.spec,
-						ExposeReplicas: &api.ReplicasServiceExpose{OnlyReaders: true},
+						PodSpec:          tt.spec,
+						ExposeReplicas:   &api.ReplicasServiceExpose{OnlyReaders: true},
+						PrimarySelection: tt.primarySelection,
 					},
 					PXC: &api.PXCSpec{
 						PodSpec: &api.PodSpec{
@@ -415,3 +449,82 @@ func TestHAProxyHealthCheckEnvVars(t *testing.T) {
 		})
 	}
 }
+
+func TestHAProxyReplicasSelectionEnvVars(t *testing.T) {
+	maxRecvQueue := int32(10)
+
+	tests := map[string]struct {
+		crVersion    string
+		selection    *api.HAProxyReplicasSelectionSpec
+		expectedEnvs []corev1.EnvVar
+	}{
+		"not set": {
+			crVersion: "1.20.0",
+		},
+		"all settings": {
+			crVersion: "1.20.0",
+			selection: &api.HAProxyReplicasSelectionSpec{
+				MaxRecvQueue:  &maxRecvQueue,
+				ExcludeDonors: true,
+				AsyncReplicas: &api.HAProxyAsyncReplicasSpec{
+					Hosts:                  []string{"replica1-haproxy-replicas.test", "10.0.0.5:33306"},
+					MaxSecondsBehindSource: 30,
+				},
+			},
+			expectedEnvs: []corev1.EnvVar{
+				{Name: "REPLICAS_MAX_RECV_QUEUE", Value: "10"},
+				{Name: "REPLICAS_EXCLUDE_DONORS", Value: "true"},
+				{Name: "REPLICAS_ASYNC_HOSTS", Value: "replica1-haproxy-replicas.test 10.0.0.5:33306"},
+				{Name: "REPLICAS_MAX_SECONDS_BEHIND_SOURCE", Value: "30"},
+			},
+		},
+		"old crVersion": {
+			crVersion: "1.19.0",
+			selection: &api.HAProxyReplicasSelectionSpec{ExcludeDonors: true},
+		},
+	}
+	ctx := context.Background()
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := &api.PerconaXtraDBCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: api.PerconaXtraDBClusterSpec{
+					CRVersion: tt.crVersion,
+					HAProxy: &api.HAProxySpec{
+						PodSpec: api.PodSpec{
+							Image:             "test-image",
+							EnvVarsSecretName: "test-secret",
+						},
+						ExposeReplicas:    &api.ReplicasServiceExpose{},
+						ReplicasSelection: tt.selection,
+					},
+					PXC: &api.PXCSpec{
+						PodSpec: &api.PodSpec{
+							Configuration: "config",
+						},
+					},
+				},
+			}
+
+			haproxy := &HAProxy{cr: cr}
+			cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+			containers, err := haproxy.SidecarContainers(ctx, cl, &cr.Spec.HAProxy.PodSpec, "test-secret", cr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var envs []corev1.EnvVar
+			for _, env := range containers[0].Env {
+				if strings.HasPrefix(env.Name, "REPLICAS_") && env.Name != "REPLICAS_SVC_ONLY_READERS" {
+					envs = append(envs, env)
+				}
+			}
+			if !reflect.DeepEqual(envs, tt.expectedEnvs) {
+				t.Errorf("expected envs %v, got %v", tt.expectedEnvs, envs)
+			}
+		})
+	}
+}
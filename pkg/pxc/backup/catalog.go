@@ -0,0 +1,260 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+)
+
+const xtrabackupInfo = "xtrabackup_info"
+
+// CatalogBackup is a complete backup found in the storage.
+type CatalogBackup struct {
+	// Name is the name of the backup directory in the storage.
+	Name        string
+	Type        api.PXCBackupType
+	CompletedAt time.Time
+	GTID        string
+	LSN         *api.PXCBackupLSN
+	// BaseChain contains names of the full backup and the incremental backups
+	// which should be applied before this incremental backup.
+	BaseChain []string
+}
+
+// ScanStorage lists all objects in the storage and returns the complete backups sorted by name.
+// A backup is complete if its xtrabackup_info file is uploaded and, for the storages
+// where xbcloud creates it, the .md5 file exists. If readMetadata is false, backup files
+// aren't read, so the completion time is taken from the backup name and there is no GTID
+// and LSN information. It should be used for encrypted backups.
+func ScanStorage(ctx context.Context, s storage.Storage, storageType api.BackupStorageType, readMetadata bool) ([]CatalogBackup, error) {
+	log := logf.FromContext(ctx)
+
+	objs, err := s.ListObjects(ctx, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "list objects")
+	}
+
+	files := make(map[string]map[string][]string)
+	md5 := make(map[string]bool)
+	for _, obj := range objs {
+		dir, file, ok := strings.Cut(obj, "/")
+		if !ok {
+			if name, ok := strings.CutSuffix(obj, ".md5"); ok {
+				md5[name] = true
+			}
+			continue
+		}
+		if strings.Contains(file, "/") {
+			continue
+		}
+		for _, f := range []string{xtrabackupInfo, xtrabackupCheckpoints} {
+			if !strings.HasPrefix(file, f) {
+				continue
+			}
+			if files[dir] == nil {
+				files[dir] = make(map[string][]string)
+			}
+			files[dir][f] = append(files[dir][f], obj)
+		}
+	}
+
+	backups := []CatalogBackup{}
+	for name, f := range files {
+		if len(f[xtrabackupInfo]) == 0 {
+			continue
+		}
+		// xbcloud doesn't create md5 file for azure
+		if storageType != api.BackupStorageAzure && !md5[name] {
+			continue
+		}
+
+		bcp := CatalogBackup{
+			Name:        name,
+			Type:        api.PXCBackupTypeFull,
+			CompletedAt: timeFromBackupName(name),
+		}
+		if strings.HasSuffix(name, "-incr") {
+			bcp.Type = api.PXCBackupTypeIncremental
+		}
+
+		if readMetadata {
+			if err := readCatalogMetadata(ctx, s, &bcp, f); err != nil {
+				log.Info("failed to read backup metadata", "backup", name, "error", err.Error())
+			}
+		}
+
+		backups = append(backups, bcp)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name < backups[j].Name
+	})
+	setCatalogBaseChains(backups)
+
+	return backups, nil
+}
+
+func readCatalogMetadata(ctx context.Context, s storage.Storage, bcp *CatalogBackup, files map[string][]string) error {
+	info, err := readStorageFile(ctx, s, files[xtrabackupInfo], xtrabackupInfo)
+	if err != nil {
+		return errors.Wrapf(err, "read %s", xtrabackupInfo)
+	}
+	endTime, gtid, err := parseXtrabackupInfo(info)
+	if err != nil {
+		return errors.Wrapf(err, "parse %s", xtrabackupInfo)
+	}
+	if !endTime.IsZero() {
+		bcp.CompletedAt = endTime
+	}
+	bcp.GTID = gtid
+
+	if len(files[xtrabackupCheckpoints]) == 0 {
+		return nil
+	}
+	checkpoints, err := readStorageFile(ctx, s, files[xtrabackupCheckpoints], xtrabackupCheckpoints)
+	if err != nil {
+		return errors.Wrapf(err, "read %s", xtrabackupCheckpoints)
+	}
+	bcp.LSN, err = parseCheckpoints(checkpoints)
+	if err != nil {
+		return errors.Wrapf(err, "parse %s", xtrabackupCheckpoints)
+	}
+
+	return nil
+}
+
+func readStorageFile(ctx context.Context, s storage.Storage, objs []string, name string) ([]byte, error) {
+	sort.Strings(objs)
+
+	obj, err := s.GetObject(ctx, objs[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "get %s object", objs[0])
+	}
+	defer obj.Close() //nolint:errcheck
+
+	return readXbstreamFile(obj, name)
+}
+
+var xtrabackupInfoGTIDRe = regexp.MustCompile(`GTID of the last change '([^']*)'`)
+
+// parseXtrabackupInfo returns the end time of the backup and the GTID set of the last change.
+func parseXtrabackupInfo(content []byte) (time.Time, string, error) {
+	var endTime time.Time
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok || strings.TrimSpace(key) != "end_time" {
+			continue
+		}
+
+		t, err := time.Parse(time.DateTime, strings.TrimSpace(value))
+		if err != nil {
+			return time.Time{}, "", errors.Wrap(err, "parse end_time")
+		}
+		endTime = t
+	}
+	if err := scanner.Err(); err != nil {
+		return time.Time{}, "", errors.Wrap(err, "scan xtrabackup_info")
+	}
+
+	gtid := ""
+	if m := xtrabackupInfoGTIDRe.FindSubmatch(content); m != nil {
+		// GTID set can be split into several lines
+		gtid = strings.Join(strings.Fields(string(m[1])), "")
+	}
+
+	return endTime, gtid, nil
+}
+
+var backupNameTimeRe = regexp.MustCompile(`(\d{4}-\d{2}-\d{2}-\d{2}:\d{2}:\d{2})-(full|incr)$`)
+
+// timeFromBackupName returns the backup creation time from the backup name
+// generated by the operator, e.g. cluster1-2024-01-01-10:00:00-full.
+func timeFromBackupName(name string) time.Time {
+	m := backupNameTimeRe.FindStringSubmatch(name)
+	if m == nil {
+		return time.Time{}
+	}
+	t, err := time.Parse("2006-01-02-15:04:05", m[1])
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// setCatalogBaseChains finds the base backups of incremental backups by LSNs.
+// The chain of an incremental backup stays empty if any of its base backups is not found.
+func setCatalogBaseChains(backups []CatalogBackup) {
+	byToLSN := make(map[int64]*CatalogBackup)
+	for i := range backups {
+		if backups[i].LSN != nil && backups[i].LSN.To != 0 {
+			byToLSN[backups[i].LSN.To] = &backups[i]
+		}
+	}
+
+	for i := range backups {
+		bcp := &backups[i]
+		if bcp.Type != api.PXCBackupTypeIncremental || bcp.LSN == nil {
+			continue
+		}
+
+		chain := []string{}
+		cur := bcp
+		for cur.Type == api.PXCBackupTypeIncremental && len(chain) < len(backups) {
+			base, ok := byToLSN[cur.LSN.From]
+			if !ok || base == cur {
+				chain = nil
+				break
+			}
+			chain = append([]string{base.Name}, chain...)
+			cur = base
+		}
+		if cur.Type == api.PXCBackupTypeFull {
+			bcp.BaseChain = chain
+		}
+	}
+}
+
+// CatalogDestination returns the destination of the backup found in the storage.
+// It's built in the same way as the destination of the backups created by the operator.
+func CatalogDestination(stg *api.BackupStorageSpec, backupName string) (api.PXCBackupDestination, error) {
+	var dest api.PXCBackupDestination
+
+	switch stg.Type {
+	case api.BackupStorageS3:
+		if stg.S3 == nil {
+			return "", errors.New("s3 storage is not specified")
+		}
+		bucket, err := stg.S3.BucketURL()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get bucket")
+		}
+		dest.SetS3Destination(bucket, backupName)
+	case api.BackupStorageAzure:
+		if stg.Azure == nil {
+			return "", errors.New("azure storage is not specified")
+		}
+		dest.SetAzureDestination(stg.Azure.ContainerPath, backupName)
+	case api.BackupStorageGCS:
+		if stg.GCS == nil {
+			return "", errors.New("gcs storage is not specified")
+		}
+		bucket, _ := stg.GCS.BucketAndPrefix()
+		dest.SetGCSDestination(bucket, backupName)
+	default:
+		return "", errors.Errorf("storage type %s is not supported", stg.Type)
+	}
+
+	return dest, nil
+}
@@ -0,0 +1,140 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage/mock"
+)
+
+func xbstreamFile(name, content string) io.ReadCloser {
+	stream := append(
+		xbstreamChunk(xbstreamChunkTypePayload, name, []byte(content)),
+		xbstreamChunk(xbstreamChunkTypeEOF, name, nil)...,
+	)
+	return io.NopCloser(bytes.NewReader(stream))
+}
+
+func TestParseXtrabackupInfo(t *testing.T) {
+	info := `uuid = 2b4a4b8c-a49c-11ee-a1e6-0242ac110002
+tool_version = 8.0.35-30
+start_time = 2024-01-01 10:00:01
+end_time = 2024-01-01 10:00:42
+binlog_pos = filename 'binlog.000003', position '197', GTID of the last change '1ac1b2ee-a49c-11ee-8e8c-0242ac110002:1-25,
+3bd1c0ae-a49c-11ee-9d4b-0242ac110002:1-3'
+innodb_from_lsn = 0
+`
+	endTime, gtid, err := parseXtrabackupInfo([]byte(info))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 42, 0, time.UTC), endTime)
+	assert.Equal(t, "1ac1b2ee-a49c-11ee-8e8c-0242ac110002:1-25,3bd1c0ae-a49c-11ee-9d4b-0242ac110002:1-3", gtid)
+
+	_, _, err = parseXtrabackupInfo([]byte("end_time = yesterday\n"))
+	assert.Error(t, err)
+}
+
+func TestScanStorage(t *testing.T) {
+	ctx := context.Background()
+
+	info := func(endTime, gtid string) string {
+		return "end_time = " + endTime + "\nbinlog_pos = filename 'binlog.000003', position '197', GTID of the last change '" + gtid + "'\n"
+	}
+	checkpoints := func(from, to string) string {
+		return "from_lsn = " + from + "\nto_lsn = " + to + "\n"
+	}
+
+	full := "cluster1-2024-01-01-10:00:00-full"
+	incr := "cluster1-2024-01-01-11:00:00-incr"
+	orphanIncr := "cluster1-2024-01-01-12:00:00-incr"
+	inProgress := "cluster1-2024-01-01-13:00:00-full"
+
+	objects := []string{
+		full + "/ibdata1.00000000000000000000",
+		full + "/xtrabackup_checkpoints.00000000000000000000",
+		full + "/xtrabackup_info.00000000000000000000",
+		full + "/xtrabackup_info.00000000000000000001",
+		full + ".md5",
+		incr + "/xtrabackup_checkpoints.00000000000000000000",
+		incr + "/xtrabackup_info.00000000000000000000",
+		incr + ".md5",
+		orphanIncr + "/xtrabackup_checkpoints.00000000000000000000",
+		orphanIncr + "/xtrabackup_info.00000000000000000000",
+		orphanIncr + ".md5",
+		inProgress + "/ibdata1.00000000000000000000",
+		inProgress + "/xtrabackup_info.00000000000000000000",
+		"binlog_1704103200_0a1b2c3d4e5f",
+		"some/nested/xtrabackup_info.00000000000000000000",
+	}
+
+	t.Run("read metadata", func(t *testing.T) {
+		s := mock.NewStorage(t)
+		s.On("ListObjects", ctx, "").Return(objects, nil)
+		s.On("GetObject", ctx, full+"/xtrabackup_info.00000000000000000000").
+			Return(xbstreamFile(xtrabackupInfo, info("2024-01-01 10:00:42", "uuid:1-25")), nil)
+		s.On("GetObject", ctx, full+"/xtrabackup_checkpoints.00000000000000000000").
+			Return(xbstreamFile(xtrabackupCheckpoints, checkpoints("0", "100")), nil)
+		s.On("GetObject", ctx, incr+"/xtrabackup_info.00000000000000000000").
+			Return(xbstreamFile(xtrabackupInfo, info("2024-01-01 11:00:10", "uuid:1-30")), nil)
+		s.On("GetObject", ctx, incr+"/xtrabackup_checkpoints.00000000000000000000").
+			Return(xbstreamFile(xtrabackupCheckpoints, checkpoints("100", "200")), nil)
+		s.On("GetObject", ctx, orphanIncr+"/xtrabackup_info.00000000000000000000").
+			Return(xbstreamFile(xtrabackupInfo, info("2024-01-01 12:00:10", "uuid:1-40")), nil)
+		s.On("GetObject", ctx, orphanIncr+"/xtrabackup_checkpoints.00000000000000000000").
+			Return(xbstreamFile(xtrabackupCheckpoints, checkpoints("300", "400")), nil)
+
+		backups, err := ScanStorage(ctx, s, pxcv1.BackupStorageS3, true)
+		assert.NoError(t, err)
+		assert.Equal(t, []CatalogBackup{
+			{
+				Name:        full,
+				Type:        pxcv1.PXCBackupTypeFull,
+				CompletedAt: time.Date(2024, 1, 1, 10, 0, 42, 0, time.UTC),
+				GTID:        "uuid:1-25",
+				LSN:         &pxcv1.PXCBackupLSN{From: 0, To: 100},
+			},
+			{
+				Name:        incr,
+				Type:        pxcv1.PXCBackupTypeIncremental,
+				CompletedAt: time.Date(2024, 1, 1, 11, 0, 10, 0, time.UTC),
+				GTID:        "uuid:1-30",
+				LSN:         &pxcv1.PXCBackupLSN{From: 100, To: 200},
+				BaseChain:   []string{full},
+			},
+			{
+				Name:        orphanIncr,
+				Type:        pxcv1.PXCBackupTypeIncremental,
+				CompletedAt: time.Date(2024, 1, 1, 12, 0, 10, 0, time.UTC),
+				GTID:        "uuid:1-40",
+				LSN:         &pxcv1.PXCBackupLSN{From: 300, To: 400},
+			},
+		}, backups)
+	})
+
+	t.Run("without metadata", func(t *testing.T) {
+		s := mock.NewStorage(t)
+		s.On("ListObjects", ctx, "").Return(objects, nil)
+
+		backups, err := ScanStorage(ctx, s, pxcv1.BackupStorageS3, false)
+		assert.NoError(t, err)
+		assert.Len(t, backups, 3)
+		assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), backups[0].CompletedAt)
+		assert.Empty(t, backups[0].GTID)
+		assert.Nil(t, backups[1].BaseChain)
+	})
+
+	t.Run("azure", func(t *testing.T) {
+		s := mock.NewStorage(t)
+		s.On("ListObjects", ctx, "").Return(objects, nil)
+
+		backups, err := ScanStorage(ctx, s, pxcv1.BackupStorageAzure, false)
+		assert.NoError(t, err)
+		assert.Len(t, backups, 4)
+		assert.Equal(t, inProgress, backups[3].Name)
+	})
+}
@@ -0,0 +1,89 @@
+package backup
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+)
+
+// CopyBackup copies all objects of the backup with the given name from one storage to another.
+// Objects are streamed, so the data is never fully loaded into memory.
+func CopyBackup(ctx context.Context, src, dst storage.Storage, backupName string) error {
+	objs, err := src.ListObjects(ctx, backupName+"/")
+	if err != nil {
+		return errors.Wrap(err, "list backup objects")
+	}
+	if len(objs) == 0 {
+		return errors.Errorf("backup %s is not found in the source storage", backupName)
+	}
+
+	md5, err := src.ListObjects(ctx, backupName+".md5")
+	if err != nil {
+		return errors.Wrap(err, "list md5 objects")
+	}
+
+	for _, obj := range append(objs, md5...) {
+		if err := copyObject(ctx, src, dst, obj); err != nil {
+			return errors.Wrapf(err, "copy %s", obj)
+		}
+	}
+
+	return nil
+}
+
+func copyObject(ctx context.Context, src, dst storage.Storage, name string) error {
+	r, err := src.GetObject(ctx, name)
+	if err != nil {
+		return errors.Wrap(err, "get object")
+	}
+	defer r.Close() //nolint:errcheck
+
+	if err := dst.PutObject(ctx, name, r, -1); err != nil {
+		return errors.Wrap(err, "put object")
+	}
+
+	return nil
+}
+
+// CopyStatus returns the status of the copy of the backup in the storage.
+// The state of the returned status is not set.
+func CopyStatus(source *api.PerconaXtraDBClusterBackup, storageName string, stg *api.BackupStorageSpec) (api.PXCBackupStatus, error) {
+	dest, err := CatalogDestination(stg, source.Status.Destination.BackupName())
+	if err != nil {
+		return api.PXCBackupStatus{}, errors.Wrap(err, "get destination")
+	}
+
+	status := api.PXCBackupStatus{
+		Destination:           dest,
+		StorageName:           storageName,
+		S3:                    stg.S3,
+		Azure:                 stg.Azure,
+		GCS:                   stg.GCS,
+		StorageType:           stg.Type,
+		VerifyTLS:             stg.VerifyTLS,
+		Image:                 source.Status.Image,
+		SSLSecretName:         source.Status.SSLSecretName,
+		SSLInternalSecretName: source.Status.SSLInternalSecretName,
+		VaultSecretName:       source.Status.VaultSecretName,
+		Type:                  source.Status.Type,
+		LSN:                   source.Status.LSN,
+		GTID:                  source.Status.GTID,
+		PXCVersion:            source.Status.PXCVersion,
+		Size:                  source.Status.Size,
+		// the data is copied as is, so the copy is decrypted with the key of the source storage
+		Encryption: source.Status.Encryption,
+	}
+	// base backups are expected to be copied to the same storage
+	for _, base := range source.Status.BaseChain {
+		baseDest, err := CatalogDestination(stg, base.BackupName())
+		if err != nil {
+			return api.PXCBackupStatus{}, errors.Wrap(err, "get base backup destination")
+		}
+		status.BaseChain = append(status.BaseChain, baseDest)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,87 @@
+package backup
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+
+	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage/mock"
+)
+
+func TestCopyBackup(t *testing.T) {
+	ctx := context.Background()
+
+	const name = "cluster1-2024-01-01-10:00:00-full"
+	objects := map[string]string{
+		name + "/ibdata1.00000000000000000000":         "ibdata",
+		name + "/xtrabackup_info.00000000000000000000": "info",
+		name + ".md5": "md5",
+	}
+
+	t.Run("copy all objects", func(t *testing.T) {
+		src := mock.NewStorage(t)
+		dst := mock.NewStorage(t)
+
+		src.On("ListObjects", ctx, name+"/").Return([]string{
+			name + "/ibdata1.00000000000000000000",
+			name + "/xtrabackup_info.00000000000000000000",
+		}, nil)
+		src.On("ListObjects", ctx, name+".md5").Return([]string{name + ".md5"}, nil)
+
+		copied := make(map[string]string)
+		for obj, content := range objects {
+			src.On("GetObject", ctx, obj).Return(io.NopCloser(strings.NewReader(content)), nil)
+			dst.On("PutObject", ctx, obj, testifymock.Anything, int64(-1)).
+				Run(func(args testifymock.Arguments) {
+					data, err := io.ReadAll(args.Get(2).(io.Reader))
+					assert.NoError(t, err)
+					copied[obj] = string(data)
+				}).
+				Return(nil)
+		}
+
+		assert.NoError(t, CopyBackup(ctx, src, dst, name))
+		assert.Equal(t, objects, copied)
+	})
+
+	t.Run("backup not found", func(t *testing.T) {
+		src := mock.NewStorage(t)
+		dst := mock.NewStorage(t)
+
+		src.On("ListObjects", ctx, name+"/").Return([]string{}, nil)
+
+		assert.EqualError(t, CopyBackup(ctx, src, dst, name), "backup "+name+" is not found in the source storage")
+	})
+}
+
+func TestCopyStatus(t *testing.T) {
+	source := new(pxcv1.PerconaXtraDBClusterBackup)
+	source.Status.Destination.SetS3Destination("primary/cluster1", "cluster1-2024-01-01-11:00:00-incr")
+	source.Status.Type = pxcv1.PXCBackupTypeIncremental
+	source.Status.GTID = "uuid:1-30"
+	source.Status.PXCVersion = "8.0.36-28.1"
+	source.Status.BaseChain = []pxcv1.PXCBackupDestination{"s3://primary/cluster1/cluster1-2024-01-01-10:00:00-full"}
+
+	stg := &pxcv1.BackupStorageSpec{
+		Type: pxcv1.BackupStorageS3,
+		S3: &pxcv1.BackupStorageS3Spec{
+			Bucket:            "secondary/dr",
+			CredentialsSecret: "dr-secret",
+		},
+	}
+
+	status, err := CopyStatus(source, "s3-dr", stg)
+	assert.NoError(t, err)
+	assert.Equal(t, pxcv1.PXCBackupDestination("s3://secondary/dr/cluster1-2024-01-01-11:00:00-incr"), status.Destination)
+	assert.Equal(t, []pxcv1.PXCBackupDestination{"s3://secondary/dr/cluster1-2024-01-01-10:00:00-full"}, status.BaseChain)
+	assert.Equal(t, "s3-dr", status.StorageName)
+	assert.Equal(t, "dr-secret", status.S3.CredentialsSecret)
+	assert.Equal(t, source.Status.Type, status.Type)
+	assert.Equal(t, source.Status.GTID, status.GTID)
+	assert.Equal(t, source.Status.PXCVersion, status.PXCVersion)
+}
@@ -0,0 +1,140 @@
+package encryption
+
+import (
+	"context"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+)
+
+const (
+	EnvAlgorithm = "ENCRYPTION_ALGORITHM"
+	EnvKey       = "ENCRYPTION_KEY"
+	EnvVaultPath = "ENCRYPTION_VAULT_PATH"
+
+	// BinlogEnvPrefix is used for the encryption of PITR storage in the restore job.
+	BinlogEnvPrefix = "BINLOG_"
+)
+
+// Config is the encryption configuration passed to backup, restore and binlog collector containers.
+type Config struct {
+	Algorithm string `env:"ENCRYPTION_ALGORITHM"`
+	Key       string `env:"ENCRYPTION_KEY"`
+	VaultPath string `env:"ENCRYPTION_VAULT_PATH"`
+}
+
+func (c Config) Enabled() bool {
+	return c.Key != "" || c.VaultPath != ""
+}
+
+func (c Config) GetAlgorithm() string {
+	if c.Algorithm == "" {
+		return string(api.BackupEncryptionAES256)
+	}
+	return c.Algorithm
+}
+
+// GetKey returns the encryption key. If VaultPath is set, the key is fetched
+// from Vault using the cluster Vault configuration.
+func (c Config) GetKey(ctx context.Context) (string, error) {
+	if c.VaultPath == "" {
+		if c.Key == "" {
+			return "", errors.New("encryption key is empty")
+		}
+		return c.Key, nil
+	}
+
+	vaultCfg, err := ReadVaultConfig(VaultConfigPath)
+	if err != nil {
+		return "", errors.Wrap(err, "read vault config")
+	}
+
+	return vaultCfg.GetKey(ctx, c.VaultPath)
+}
+
+// WithKey returns the config with the key resolved by GetKey, so the key files
+// can be written without fetching the key from Vault every time.
+func (c Config) WithKey(ctx context.Context) (Config, error) {
+	key, err := c.GetKey(ctx)
+	if err != nil {
+		return Config{}, errors.Wrap(err, "get encryption key")
+	}
+	return Config{Algorithm: c.GetAlgorithm(), Key: key}, nil
+}
+
+// WriteKeyFile resolves the encryption key and writes it into a new file in dir.
+// It returns the path of the file.
+func (c Config) WriteKeyFile(ctx context.Context, dir string) (string, error) {
+	key, err := c.GetKey(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "get encryption key")
+	}
+
+	f, err := os.CreateTemp(dir, "encryption-key-")
+	if err != nil {
+		return "", errors.Wrap(err, "create encryption key file")
+	}
+	defer f.Close() //nolint:errcheck
+
+	if _, err := f.WriteString(key); err != nil {
+		return "", errors.Wrap(err, "write encryption key file")
+	}
+
+	return f.Name(), nil
+}
+
+// XbcryptCmd returns a command which reads data from stdin and writes
+// encrypted (or decrypted if decrypt is true) data to stdout.
+func (c Config) XbcryptCmd(ctx context.Context, keyFile string, decrypt bool) *exec.Cmd {
+	args := []string{
+		"--encrypt-algo=" + c.GetAlgorithm(),
+		"--encrypt-key-file=" + keyFile,
+	}
+	if decrypt {
+		args = append([]string{"--decrypt"}, args...)
+	}
+	return exec.CommandContext(ctx, "xbcrypt", args...)
+}
+
+// XbstreamArgs returns arguments for xbstream to decrypt extracted files.
+func (c Config) XbstreamArgs(keyFile string) []string {
+	return []string{
+		"--decrypt=" + c.GetAlgorithm(),
+		"--encrypt-key-file=" + keyFile,
+	}
+}
+
+// Envs returns env variables with the encryption configuration of the storage.
+// The prefix is prepended to the variable names.
+func Envs(prefix string, spec *api.BackupEncryptionSpec) []corev1.EnvVar {
+	if !spec.IsEnabled() {
+		return nil
+	}
+
+	envs := []corev1.EnvVar{
+		{
+			Name:  prefix + EnvAlgorithm,
+			Value: string(spec.GetAlgorithm()),
+		},
+	}
+	if spec.KeySecret != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name: prefix + EnvKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: spec.KeySecret,
+			},
+		})
+	}
+	if spec.VaultPath != "" {
+		envs = append(envs, corev1.EnvVar{
+			Name:  prefix + EnvVaultPath,
+			Value: spec.VaultPath,
+		})
+	}
+
+	return envs
+}
@@ -0,0 +1,168 @@
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// VaultConfigPath is the path of the cluster Vault configuration (keyring_vault.conf).
+const VaultConfigPath = "/etc/mysql/vault-keyring-secret/keyring_vault.conf"
+
+// VaultConfig is the part of keyring_vault.conf used to fetch encryption keys.
+type VaultConfig struct {
+	URL              string `json:"vault_url"`
+	Token            string `json:"token"`
+	SecretMountPoint string `json:"secret_mount_point"`
+	CA               string `json:"vault_ca"`
+	// MountPointVersion is the KV secrets engine version: "1", "2" or "AUTO".
+	MountPointVersion string `json:"secret_mount_point_version"`
+}
+
+// ReadVaultConfig reads keyring_vault.conf. The file is in JSON format
+// for the keyring component (8.4) and in ini format for the keyring plugin.
+func ReadVaultConfig(path string) (*VaultConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read file")
+	}
+
+	return parseVaultConfig(data)
+}
+
+func parseVaultConfig(data []byte) (*VaultConfig, error) {
+	cfg := new(VaultConfig)
+	if json.Valid(data) {
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, errors.Wrap(err, "unmarshal json")
+		}
+		return cfg, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "vault_url":
+			cfg.URL = value
+		case "token":
+			cfg.Token = value
+		case "secret_mount_point":
+			cfg.SecretMountPoint = value
+		case "vault_ca":
+			cfg.CA = value
+		case "secret_mount_point_version":
+			cfg.MountPointVersion = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "scan ini")
+	}
+
+	return cfg, nil
+}
+
+// GetKey reads the "key" field of the secret at the path
+// relative to the secret mount point.
+func (c *VaultConfig) GetKey(ctx context.Context, path string) (string, error) {
+	if c.URL == "" || c.Token == "" {
+		return "", errors.New("vault_url and token should be set in vault config")
+	}
+
+	cl, err := c.httpClient()
+	if err != nil {
+		return "", errors.Wrap(err, "create http client")
+	}
+
+	mountPoint := strings.Trim(c.SecretMountPoint, "/")
+	path = strings.Trim(path, "/")
+
+	url := fmt.Sprintf("%s/v1/%s/%s", strings.TrimSuffix(c.URL, "/"), mountPoint, path)
+	if c.MountPointVersion == "2" {
+		url = fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimSuffix(c.URL, "/"), mountPoint, path)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "create request")
+	}
+	req.Header.Set("X-Vault-Request", "true")
+	req.Header.Set("X-Vault-Token", c.Token)
+
+	resp, err := cl.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "read response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("get secret %s: unexpected status %s", path, resp.Status)
+	}
+
+	secret := struct {
+		Data struct {
+			Key  string `json:"key"`
+			Data struct {
+				Key string `json:"key"`
+			} `json:"data"`
+		} `json:"data"`
+	}{}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", errors.Wrap(err, "unmarshal response")
+	}
+
+	// KV version 2 has the secret data nested into the data field
+	key := secret.Data.Key
+	if key == "" {
+		key = secret.Data.Data.Key
+	}
+	if key == "" {
+		return "", errors.Errorf("no key in vault secret %s", path)
+	}
+
+	return key, nil
+}
+
+func (c *VaultConfig) httpClient() (*http.Client, error) {
+	cl := &http.Client{Timeout: 30 * time.Second}
+	if c.CA == "" {
+		return cl, nil
+	}
+
+	ca, err := os.ReadFile(c.CA)
+	if err != nil {
+		return nil, errors.Wrap(err, "read vault CA")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("failed to append vault CA")
+	}
+
+	cl.Transport = &http.Transport{
+		TLSClientConfig: &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+
+	return cl, nil
+}
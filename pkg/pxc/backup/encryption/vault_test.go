@@ -0,0 +1,108 @@
+package encryption
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVaultConfig(t *testing.T) {
+	expected := &VaultConfig{
+		URL:               "https://vault.example.com:8200",
+		Token:             "s.token",
+		SecretMountPoint:  "secret",
+		CA:                "/etc/mysql/vault-keyring-secret/ca.cert",
+		MountPointVersion: "2",
+	}
+
+	t.Run("ini", func(t *testing.T) {
+		cfg, err := parseVaultConfig([]byte(`token = s.token
+vault_url = https://vault.example.com:8200
+secret_mount_point = secret
+secret_mount_point_version = 2
+vault_ca = /etc/mysql/vault-keyring-secret/ca.cert
+`))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, cfg)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		cfg, err := parseVaultConfig([]byte(`{
+  "vault_url": "https://vault.example.com:8200",
+  "secret_mount_point": "secret",
+  "secret_mount_point_version": "2",
+  "token": "s.token",
+  "vault_ca": "/etc/mysql/vault-keyring-secret/ca.cert"
+}`))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, cfg)
+	})
+}
+
+func TestVaultGetKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/backup/cluster1":
+			_, _ = w.Write([]byte(`{"data":{"key":"kv1-key"}}`))
+		case "/v1/secret/data/backup/cluster1":
+			_, _ = w.Write([]byte(`{"data":{"data":{"key":"kv2-key"},"metadata":{"version":1}}}`))
+		case "/v1/secret/backup/no-key":
+			_, _ = w.Write([]byte(`{"data":{"other":"value"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		cfg      VaultConfig
+		path     string
+		expected string
+		wantErr  bool
+	}{
+		"kv v1": {
+			cfg:      VaultConfig{URL: srv.URL, Token: "s.token", SecretMountPoint: "secret"},
+			path:     "backup/cluster1",
+			expected: "kv1-key",
+		},
+		"kv v2": {
+			cfg:      VaultConfig{URL: srv.URL, Token: "s.token", SecretMountPoint: "secret", MountPointVersion: "2"},
+			path:     "/backup/cluster1",
+			expected: "kv2-key",
+		},
+		"no key": {
+			cfg:     VaultConfig{URL: srv.URL, Token: "s.token", SecretMountPoint: "secret"},
+			path:    "backup/no-key",
+			wantErr: true,
+		},
+		"not found": {
+			cfg:     VaultConfig{URL: srv.URL, Token: "s.token", SecretMountPoint: "secret"},
+			path:    "backup/missing",
+			wantErr: true,
+		},
+		"wrong token": {
+			cfg:     VaultConfig{URL: srv.URL, Token: "wrong", SecretMountPoint: "secret"},
+			path:    "backup/cluster1",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			key, err := tt.cfg.GetKey(context.Background(), tt.path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, key)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os/exec"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var xbstreamChunkMagic = []byte("XBSTCK01")
+
+const (
+	xbstreamChunkTypePayload = 'P'
+	xbstreamChunkTypeEOF     = 'E'
+
+	// XbcryptSuffix is added to the names of encrypted files, xbstream -x --decrypt decrypts only such files
+	XbcryptSuffix = ".xbcrypt"
+
+	xbstreamPayloadSize = 1 << 20
+)
+
+// EncryptXbstream reads the xbstream archive from r and writes it to w with every file
+// encrypted by the command returned by newCmd, the same way xtrabackup --encrypt does it.
+// The command should read the file from stdin and write the encrypted file to stdout.
+// It's used for the backups which are received as the SST stream from a donor node.
+func EncryptXbstream(ctx context.Context, r io.Reader, w io.Writer, newCmd func(ctx context.Context) *exec.Cmd) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	out := &xbstreamWriter{w: w}
+	files := make(map[string]*encryptedFile)
+	defer func() {
+		// the files are left only if the stream is broken, the commands are killed by the canceled context
+		cancel()
+		for _, f := range files {
+			_ = f.stdin.Close()
+			_ = f.cmd.Wait()
+		}
+	}()
+
+	for {
+		header := make([]byte, len(xbstreamChunkMagic)+6)
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return errors.Wrap(err, "read chunk header")
+		}
+		if !bytes.Equal(header[:len(xbstreamChunkMagic)], xbstreamChunkMagic) {
+			return errors.New("wrong chunk magic")
+		}
+		chunkType := header[len(xbstreamChunkMagic)+1]
+		pathLen := binary.LittleEndian.Uint32(header[len(xbstreamChunkMagic)+2:])
+
+		pathBytes := make([]byte, pathLen)
+		if _, err := io.ReadFull(r, pathBytes); err != nil {
+			return errors.Wrap(err, "read chunk path")
+		}
+		path := string(pathBytes)
+
+		switch chunkType {
+		case xbstreamChunkTypePayload:
+			// payload length, payload offset and checksum
+			meta := make([]byte, 20)
+			if _, err := io.ReadFull(r, meta); err != nil {
+				return errors.Wrap(err, "read chunk metadata")
+			}
+			payloadLen := binary.LittleEndian.Uint64(meta)
+			offset := binary.LittleEndian.Uint64(meta[8:])
+
+			f, ok := files[path]
+			if !ok {
+				var err error
+				f, err = startEncryptedFile(ctx, newCmd(ctx), out, path+XbcryptSuffix)
+				if err != nil {
+					return errors.Wrapf(err, "start encryption of %s", path)
+				}
+				files[path] = f
+			}
+			if offset != f.read {
+				return errors.Errorf("%s: unexpected payload offset %d, expected %d", path, offset, f.read)
+			}
+			if _, err := io.CopyN(f.stdin, r, int64(payloadLen)); err != nil {
+				return errors.Wrapf(err, "encrypt %s", path)
+			}
+			f.read += payloadLen
+		case xbstreamChunkTypeEOF:
+			f, ok := files[path]
+			if !ok {
+				// an empty file
+				var err error
+				f, err = startEncryptedFile(ctx, newCmd(ctx), out, path+XbcryptSuffix)
+				if err != nil {
+					return errors.Wrapf(err, "start encryption of %s", path)
+				}
+			}
+			delete(files, path)
+			if err := f.finish(); err != nil {
+				return errors.Wrapf(err, "encrypt %s", path)
+			}
+		default:
+			return errors.Errorf("%s: unsupported chunk type %q", path, chunkType)
+		}
+	}
+
+	if len(files) > 0 {
+		return errors.Errorf("stream ended before the end of %d files", len(files))
+	}
+
+	return nil
+}
+
+// encryptedFile is a file of the stream which is being encrypted
+type encryptedFile struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	read  uint64
+	done  chan error
+}
+
+func startEncryptedFile(ctx context.Context, cmd *exec.Cmd, out *xbstreamWriter, path string) (*encryptedFile, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "stdin pipe")
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "stdout pipe")
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrap(err, "start")
+	}
+
+	f := &encryptedFile{
+		cmd:   cmd,
+		stdin: stdin,
+		done:  make(chan error, 1),
+	}
+	go func() {
+		f.done <- out.copyFile(ctx, path, stdout)
+	}()
+
+	return f, nil
+}
+
+// finish waits until the file is encrypted and written to the stream
+func (f *encryptedFile) finish() error {
+	if err := f.stdin.Close(); err != nil {
+		return errors.Wrap(err, "close stdin")
+	}
+	copyErr := <-f.done
+	if err := f.cmd.Wait(); err != nil {
+		return errors.Wrap(err, "wait")
+	}
+	return copyErr
+}
+
+// xbstreamWriter writes chunks of several files into the xbstream archive
+type xbstreamWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// copyFile writes the content of r as the file with the given path
+func (x *xbstreamWriter) copyFile(ctx context.Context, path string, r io.Reader) error {
+	buf := make([]byte, xbstreamPayloadSize)
+	var offset uint64
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if err := x.writeChunk(path, xbstreamChunkTypePayload, buf[:n], offset); err != nil {
+				return err
+			}
+			offset += uint64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "read encrypted data")
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	return x.writeChunk(path, xbstreamChunkTypeEOF, nil, 0)
+}
+
+func (x *xbstreamWriter) writeChunk(path string, chunkType byte, payload []byte, offset uint64) error {
+	chunk := make([]byte, 0, len(xbstreamChunkMagic)+6+len(path)+20)
+	chunk = append(chunk, xbstreamChunkMagic...)
+	chunk = append(chunk, 0, chunkType)
+	chunk = binary.LittleEndian.AppendUint32(chunk, uint32(len(path)))
+	chunk = append(chunk, path...)
+	if chunkType == xbstreamChunkTypePayload {
+		chunk = binary.LittleEndian.AppendUint64(chunk, uint64(len(payload)))
+		chunk = binary.LittleEndian.AppendUint64(chunk, offset)
+		chunk = binary.LittleEndian.AppendUint32(chunk, crc32.ChecksumIEEE(payload))
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if _, err := x.w.Write(chunk); err != nil {
+		return errors.Wrap(err, "write chunk header")
+	}
+	if _, err := x.w.Write(payload); err != nil {
+		return errors.Wrap(err, "write chunk payload")
+	}
+	return nil
+}
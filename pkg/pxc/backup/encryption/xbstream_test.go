@@ -0,0 +1,132 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os/exec"
+	"testing"
+)
+
+type xbstreamFile struct {
+	content []byte
+	eof     bool
+}
+
+// readXbstream returns the files of the xbstream archive and checks the chunk checksums and offsets
+func readXbstream(t *testing.T, r io.Reader) map[string]*xbstreamFile {
+	t.Helper()
+
+	files := make(map[string]*xbstreamFile)
+	for {
+		header := make([]byte, len(xbstreamChunkMagic)+6)
+		if _, err := io.ReadFull(r, header); err == io.EOF {
+			return files
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(header[:len(xbstreamChunkMagic)], xbstreamChunkMagic) {
+			t.Fatal("wrong chunk magic")
+		}
+		path := make([]byte, binary.LittleEndian.Uint32(header[len(xbstreamChunkMagic)+2:]))
+		if _, err := io.ReadFull(r, path); err != nil {
+			t.Fatal(err)
+		}
+		f, ok := files[string(path)]
+		if !ok {
+			f = new(xbstreamFile)
+			files[string(path)] = f
+		}
+		if header[len(xbstreamChunkMagic)+1] == xbstreamChunkTypeEOF {
+			f.eof = true
+			continue
+		}
+
+		meta := make([]byte, 20)
+		if _, err := io.ReadFull(r, meta); err != nil {
+			t.Fatal(err)
+		}
+		payload := make([]byte, binary.LittleEndian.Uint64(meta))
+		if _, err := io.ReadFull(r, payload); err != nil {
+			t.Fatal(err)
+		}
+		if offset := binary.LittleEndian.Uint64(meta[8:]); offset != uint64(len(f.content)) {
+			t.Fatalf("%s: unexpected offset %d", path, offset)
+		}
+		if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(meta[16:]) {
+			t.Fatalf("%s: wrong checksum", path)
+		}
+		f.content = append(f.content, payload...)
+	}
+}
+
+func TestEncryptXbstream(t *testing.T) {
+	in := new(bytes.Buffer)
+	w := &xbstreamWriter{w: in}
+	for _, c := range []struct {
+		path    string
+		payload string
+		offset  uint64
+	}{
+		{"ibdata1", "abc", 0},
+		{"db/t1.ibd", "xyz", 0},
+		{"ibdata1", "def", 3},
+	} {
+		if err := w.writeChunk(c.path, xbstreamChunkTypePayload, []byte(c.payload), c.offset); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, path := range []string{"db/t1.ibd", "ibdata1", "empty"} {
+		if err := w.writeChunk(path, xbstreamChunkTypeEOF, nil, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := new(bytes.Buffer)
+	newCmd := func(ctx context.Context) *exec.Cmd {
+		return exec.CommandContext(ctx, "tr", "a-z", "A-Z")
+	}
+	if err := EncryptXbstream(context.Background(), in, out, newCmd); err != nil {
+		t.Fatal(err)
+	}
+
+	files := readXbstream(t, out)
+	expected := map[string]string{
+		"ibdata1.xbcrypt":   "ABCDEF",
+		"db/t1.ibd.xbcrypt": "XYZ",
+		"empty.xbcrypt":     "",
+	}
+	if len(files) != len(expected) {
+		t.Fatalf("expected %d files, got %d", len(expected), len(files))
+	}
+	for path, content := range expected {
+		f, ok := files[path]
+		if !ok {
+			t.Fatalf("%s is not found", path)
+		}
+		if !f.eof {
+			t.Fatalf("%s has no EOF chunk", path)
+		}
+		if string(f.content) != content {
+			t.Fatalf("%s: expected %q, got %q", path, content, f.content)
+		}
+	}
+}
+
+func TestEncryptXbstreamIncomplete(t *testing.T) {
+	in := new(bytes.Buffer)
+	w := &xbstreamWriter{w: in}
+	if err := w.writeChunk("ibdata1", xbstreamChunkTypePayload, []byte("abc"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	newCmd := func(ctx context.Context) *exec.Cmd {
+		return exec.CommandContext(ctx, "cat")
+	}
+	err := EncryptXbstream(context.Background(), in, io.Discard, newCmd)
+	if err == nil || err.Error() != "stream ended before the end of 1 files" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+)
+
+var ErrNoBaseBackup = errors.New("no base backup found")
+
+// GetBaseBackup returns the backup which the incremental backup should be based on.
+// If the base backup name is not specified, the latest succeeded backup
+// of the same cluster on the same storage is returned.
+func GetBaseBackup(ctx context.Context, cl client.Client, cr *api.PerconaXtraDBClusterBackup) (*api.PerconaXtraDBClusterBackup, error) {
+	if cr.Spec.BaseBackupName != "" {
+		base := new(api.PerconaXtraDBClusterBackup)
+		if err := cl.Get(ctx, types.NamespacedName{Name: cr.Spec.BaseBackupName, Namespace: cr.Namespace}, base); err != nil {
+			return nil, errors.Wrapf(err, "get base backup %s", cr.Spec.BaseBackupName)
+		}
+		if err := validateBaseBackup(cr, base); err != nil {
+			return nil, err
+		}
+		return base, nil
+	}
+
+	bcpList := api.PerconaXtraDBClusterBackupList{}
+	if err := cl.List(ctx, &bcpList, &client.ListOptions{Namespace: cr.Namespace}); err != nil {
+		return nil, errors.Wrap(err, "get backup objects")
+	}
+
+	var latest *api.PerconaXtraDBClusterBackup
+	for i := range bcpList.Items {
+		bcp := &bcpList.Items[i]
+		if bcp.Name == cr.Name || validateBaseBackup(cr, bcp) != nil {
+			continue
+		}
+		if latest == nil || latest.CreationTimestamp.Before(&bcp.CreationTimestamp) {
+			latest = bcp
+		}
+	}
+	if latest == nil {
+		return nil, ErrNoBaseBackup
+	}
+
+	return latest, nil
+}
+
+func validateBaseBackup(cr, base *api.PerconaXtraDBClusterBackup) error {
+	switch {
+	case base.Status.State != api.BackupSucceeded:
+		return errors.Errorf("base backup %s is not succeeded, current state: %s", base.Name, base.Status.State)
+	case base.Spec.PXCCluster != cr.Spec.PXCCluster:
+		return errors.Errorf("base backup %s belongs to another cluster: %s", base.Name, base.Spec.PXCCluster)
+	case base.Status.StorageName != cr.Spec.StorageName:
+		return errors.Errorf("base backup %s is stored on another storage: %s", base.Name, base.Status.StorageName)
+	case base.Status.IsLogical():
+		return errors.Errorf("base backup %s is a logical backup", base.Name)
+	case base.DeletionTimestamp != nil:
+		return errors.Errorf("base backup %s is being deleted", base.Name)
+	case base.Status.LSN == nil || base.Status.LSN.To == 0:
+		return errors.Errorf("base backup %s has no LSN information", base.Name)
+	}
+	return nil
+}
+
+// DependentBackups returns the names of the incremental backups which can't be restored
+// without the backup. The failed backups and the backups which are being deleted are skipped.
+func DependentBackups(ctx context.Context, cl client.Client, cr *api.PerconaXtraDBClusterBackup) ([]string, error) {
+	if cr.Status.Destination == "" {
+		return nil, nil
+	}
+
+	bcpList := api.PerconaXtraDBClusterBackupList{}
+	if err := cl.List(ctx, &bcpList, &client.ListOptions{Namespace: cr.Namespace}); err != nil {
+		return nil, errors.Wrap(err, "get backup objects")
+	}
+
+	var dependent []string
+	for _, bcp := range bcpList.Items {
+		if bcp.Name == cr.Name || bcp.Status.State == api.BackupFailed || bcp.DeletionTimestamp != nil {
+			continue
+		}
+		for _, base := range bcp.Status.BaseChain {
+			if base == cr.Status.Destination {
+				dependent = append(dependent, bcp.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(dependent)
+
+	return dependent, nil
+}
+
+// SetIncrementalBase sets the chain of base backups and
+// the starting LSN of the incremental backup.
+func SetIncrementalBase(status *api.PXCBackupStatus, base *api.PerconaXtraDBClusterBackup) {
+	status.Type = api.PXCBackupTypeIncremental
+	status.BaseBackupName = base.Name
+	status.BaseChain = append(append([]api.PXCBackupDestination{}, base.Status.BaseChain...), base.Status.Destination)
+	status.LSN = &api.PXCBackupLSN{
+		From: base.Status.LSN.To,
+	}
+}
+
+const xtrabackupCheckpoints = "xtrabackup_checkpoints"
+
+// GetBackupLSN reads xtrabackup_checkpoints file uploaded by xbcloud
+// and returns the range of LSNs covered by the backup.
+func GetBackupLSN(ctx context.Context, s storage.Storage, backupName string) (*api.PXCBackupLSN, error) {
+	objs, err := s.ListObjects(ctx, strings.TrimSuffix(backupName, "/")+"/"+xtrabackupCheckpoints)
+	if err != nil {
+		return nil, errors.Wrapf(err, "list %s objects", xtrabackupCheckpoints)
+	}
+	if len(objs) == 0 {
+		return nil, errors.Errorf("no %s objects found", xtrabackupCheckpoints)
+	}
+	sort.Strings(objs)
+
+	obj, err := s.GetObject(ctx, objs[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "get %s object", objs[0])
+	}
+	defer obj.Close()
+
+	content, err := readXbstreamFile(obj, xtrabackupCheckpoints)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read %s", objs[0])
+	}
+
+	return parseCheckpoints(content)
+}
+
+func parseCheckpoints(content []byte) (*api.PXCBackupLSN, error) {
+	lsn := new(api.PXCBackupLSN)
+	found := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+
+		var dst *int64
+		switch strings.TrimSpace(key) {
+		case "from_lsn":
+			dst = &lsn.From
+		case "to_lsn":
+			dst = &lsn.To
+		default:
+			continue
+		}
+
+		v, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse %s", strings.TrimSpace(key))
+		}
+		*dst = v
+		found++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "scan checkpoints")
+	}
+	if found != 2 {
+		return nil, errors.New("from_lsn or to_lsn is not found")
+	}
+
+	return lsn, nil
+}
+
+var xbstreamChunkMagic = []byte("XBSTCK01")
+
+const (
+	xbstreamChunkTypePayload = 'P'
+	xbstreamChunkTypeEOF     = 'E'
+)
+
+// readXbstreamFile returns the content of the file with the given name from the xbstream archive.
+// Only the regular payload chunks are supported, since xtrabackup metadata files aren't sparse.
+func readXbstreamFile(r io.Reader, name string) ([]byte, error) {
+	content := new(bytes.Buffer)
+	found := false
+
+	for {
+		header := make([]byte, len(xbstreamChunkMagic)+6)
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.Wrap(err, "read chunk header")
+		}
+		if !bytes.Equal(header[:len(xbstreamChunkMagic)], xbstreamChunkMagic) {
+			return nil, errors.New("wrong chunk magic")
+		}
+		chunkType := header[len(xbstreamChunkMagic)+1]
+		pathLen := binary.LittleEndian.Uint32(header[len(xbstreamChunkMagic)+2:])
+
+		path := make([]byte, pathLen)
+		if _, err := io.ReadFull(r, path); err != nil {
+			return nil, errors.Wrap(err, "read chunk path")
+		}
+
+		switch chunkType {
+		case xbstreamChunkTypeEOF:
+			continue
+		case xbstreamChunkTypePayload:
+		default:
+			return nil, errors.Errorf("unsupported chunk type %q", chunkType)
+		}
+
+		// payload length, payload offset and checksum
+		meta := make([]byte, 20)
+		if _, err := io.ReadFull(r, meta); err != nil {
+			return nil, errors.Wrap(err, "read chunk metadata")
+		}
+		payloadLen := binary.LittleEndian.Uint64(meta)
+
+		w := io.Discard
+		if string(path) == name {
+			w = content
+			found = true
+		}
+		if _, err := io.CopyN(w, r, int64(payloadLen)); err != nil {
+			return nil, errors.Wrap(err, "read chunk payload")
+		}
+	}
+	if !found {
+		return nil, errors.Errorf("%s is not found in the stream", name)
+	}
+
+	return content.Bytes(), nil
+}
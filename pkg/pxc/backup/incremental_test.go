@@ -0,0 +1,243 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage/mock"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/test"
+)
+
+func xbstreamChunk(chunkType byte, path string, payload []byte) []byte {
+	buf := new(bytes.Buffer)
+	buf.Write(xbstreamChunkMagic)
+	buf.WriteByte(0)
+	buf.WriteByte(chunkType)
+	_ = binary.Write(buf, binary.LittleEndian, uint32(len(path)))
+	buf.WriteString(path)
+	if chunkType == xbstreamChunkTypeEOF {
+		return buf.Bytes()
+	}
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(payload)))
+	_ = binary.Write(buf, binary.LittleEndian, uint64(0))
+	_ = binary.Write(buf, binary.LittleEndian, uint32(0))
+	buf.Write(payload)
+	return buf.Bytes()
+}
+
+func TestGetBackupLSN(t *testing.T) {
+	ctx := context.Background()
+
+	checkpoints := "backup_type = incremental\nfrom_lsn = 25147368\nto_lsn = 25286841\nlast_lsn = 25286851\nflushed_lsn = 25286841\n"
+	stream := append(
+		xbstreamChunk(xbstreamChunkTypePayload, xtrabackupCheckpoints, []byte(checkpoints)),
+		xbstreamChunk(xbstreamChunkTypeEOF, xtrabackupCheckpoints, nil)...,
+	)
+
+	tests := []struct {
+		name        string
+		mockFn      func(*mock.Storage)
+		expected    *pxcv1.PXCBackupLSN
+		expectedErr bool
+	}{
+		{
+			name: "checkpoints found",
+			mockFn: func(s *mock.Storage) {
+				s.On("ListObjects", ctx, "backup-name/xtrabackup_checkpoints").Return([]string{"backup-name/xtrabackup_checkpoints.00000000000000000000"}, nil)
+				s.On("GetObject", ctx, "backup-name/xtrabackup_checkpoints.00000000000000000000").Return(io.NopCloser(bytes.NewReader(stream)), nil)
+			},
+			expected: &pxcv1.PXCBackupLSN{From: 25147368, To: 25286841},
+		},
+		{
+			name: "no checkpoints",
+			mockFn: func(s *mock.Storage) {
+				s.On("ListObjects", ctx, "backup-name/xtrabackup_checkpoints").Return([]string{}, nil)
+			},
+			expectedErr: true,
+		},
+		{
+			name: "not xbstream",
+			mockFn: func(s *mock.Storage) {
+				s.On("ListObjects", ctx, "backup-name/xtrabackup_checkpoints").Return([]string{"backup-name/xtrabackup_checkpoints.00000000000000000000"}, nil)
+				s.On("GetObject", ctx, "backup-name/xtrabackup_checkpoints.00000000000000000000").Return(io.NopCloser(bytes.NewReader([]byte(checkpoints))), nil)
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := mock.NewStorage(t)
+			tt.mockFn(s)
+
+			lsn, err := GetBackupLSN(ctx, s, "backup-name")
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, lsn)
+		})
+	}
+}
+
+func TestGetBaseBackup(t *testing.T) {
+	ctx := context.Background()
+
+	const ns = "test-ns"
+
+	newBackup := func(name, storageName string, state pxcv1.PXCBackupState, created time.Time, lsn *pxcv1.PXCBackupLSN) *pxcv1.PerconaXtraDBClusterBackup {
+		return &pxcv1.PerconaXtraDBClusterBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         ns,
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Spec: pxcv1.PXCBackupSpec{
+				PXCCluster:  "cluster1",
+				StorageName: storageName,
+			},
+			Status: pxcv1.PXCBackupStatus{
+				State:       state,
+				StorageName: storageName,
+				LSN:         lsn,
+			},
+		}
+	}
+
+	now := time.Now().Truncate(time.Second)
+	lsn := &pxcv1.PXCBackupLSN{From: 0, To: 100}
+
+	tests := []struct {
+		name         string
+		baseName     string
+		objs         []runtime.Object
+		expectedName string
+		expectedErr  error
+	}{
+		{
+			name: "latest succeeded backup on the same storage",
+			objs: []runtime.Object{
+				newBackup("full-1", "s3", pxcv1.BackupSucceeded, now.Add(-3*time.Hour), lsn),
+				newBackup("full-2", "s3", pxcv1.BackupSucceeded, now.Add(-2*time.Hour), lsn),
+				newBackup("failed", "s3", pxcv1.BackupFailed, now.Add(-time.Hour), lsn),
+				newBackup("other-storage", "azure", pxcv1.BackupSucceeded, now.Add(-time.Hour), lsn),
+				newBackup("no-lsn", "s3", pxcv1.BackupSucceeded, now.Add(-time.Hour), nil),
+			},
+			expectedName: "full-2",
+		},
+		{
+			name: "no suitable backups",
+			objs: []runtime.Object{
+				newBackup("no-lsn", "s3", pxcv1.BackupSucceeded, now.Add(-time.Hour), nil),
+			},
+			expectedErr: ErrNoBaseBackup,
+		},
+		{
+			name:     "base backup name",
+			baseName: "full-1",
+			objs: []runtime.Object{
+				newBackup("full-1", "s3", pxcv1.BackupSucceeded, now.Add(-3*time.Hour), lsn),
+				newBackup("full-2", "s3", pxcv1.BackupSucceeded, now.Add(-2*time.Hour), lsn),
+			},
+			expectedName: "full-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr := newBackup("incr", "s3", pxcv1.BackupNew, now, nil)
+			cr.Spec.Type = pxcv1.PXCBackupTypeIncremental
+			cr.Spec.BaseBackupName = tt.baseName
+
+			cl := test.BuildFakeClient(tt.objs...)
+
+			base, err := GetBaseBackup(ctx, cl, cr)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedName, base.Name)
+
+			SetIncrementalBase(&cr.Status, base)
+			assert.Equal(t, pxcv1.PXCBackupTypeIncremental, cr.Status.Type)
+			assert.Equal(t, tt.expectedName, cr.Status.BaseBackupName)
+			assert.Equal(t, int64(100), cr.Status.LSN.From)
+			assert.Len(t, cr.Status.BaseChain, 1)
+		})
+	}
+
+	t.Run("base backup on another storage", func(t *testing.T) {
+		cr := newBackup("incr", "s3", pxcv1.BackupNew, now, nil)
+		cr.Spec.BaseBackupName = "other-storage"
+
+		cl := test.BuildFakeClient(newBackup("other-storage", "azure", pxcv1.BackupSucceeded, now.Add(-time.Hour), lsn))
+
+		_, err := GetBaseBackup(ctx, cl, cr)
+		assert.Error(t, err)
+	})
+
+	t.Run("logical base backup", func(t *testing.T) {
+		cr := newBackup("incr", "s3", pxcv1.BackupNew, now, nil)
+		cr.Spec.BaseBackupName = "logical"
+
+		logical := newBackup("logical", "s3", pxcv1.BackupSucceeded, now.Add(-time.Hour), lsn)
+		logical.Status.Type = pxcv1.PXCBackupTypeLogical
+		cl := test.BuildFakeClient(logical)
+
+		_, err := GetBaseBackup(ctx, cl, cr)
+		assert.Error(t, err)
+	})
+}
+
+func TestDependentBackups(t *testing.T) {
+	ctx := context.Background()
+
+	newBackup := func(name string, state pxcv1.PXCBackupState, chain ...*pxcv1.PerconaXtraDBClusterBackup) *pxcv1.PerconaXtraDBClusterBackup {
+		bcp := &pxcv1.PerconaXtraDBClusterBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test-ns",
+			},
+		}
+		bcp.Status.State = state
+		bcp.Status.Destination.SetS3Destination("bucket", name)
+		for _, base := range chain {
+			bcp.Status.BaseChain = append(bcp.Status.BaseChain, base.Status.Destination)
+		}
+		return bcp
+	}
+
+	full := newBackup("full", pxcv1.BackupSucceeded)
+	incr1 := newBackup("incr1", pxcv1.BackupSucceeded, full)
+	incr2 := newBackup("incr2", pxcv1.BackupRunning, full, incr1)
+	failed := newBackup("failed", pxcv1.BackupFailed, full)
+	deleting := newBackup("deleting", pxcv1.BackupSucceeded, full)
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	deleting.Finalizers = []string{"test"}
+	other := newBackup("other", pxcv1.BackupSucceeded)
+
+	cl := test.BuildFakeClient(full, incr1, incr2, failed, deleting, other)
+
+	dependent, err := DependentBackups(ctx, cl, full)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"incr1", "incr2"}, dependent)
+
+	dependent, err = DependentBackups(ctx, cl, incr1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"incr2"}, dependent)
+
+	dependent, err = DependentBackups(ctx, cl, other)
+	assert.NoError(t, err)
+	assert.Empty(t, dependent)
+}
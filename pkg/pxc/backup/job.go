@@ -15,6 +15,8 @@ import (
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app/statefulset"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/encryption"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/throttling"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/users"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/util"
 )
@@ -78,6 +80,10 @@ func (bcp *Backup) JobSpec(spec api.PXCBackupSpec, cluster *api.PerconaXtraDBClu
 			Value: strconv.FormatBool(verifyTLS),
 		},
 	}
+	// the stream received from the donor is encrypted by the backup job, the key is fetched from Vault
+	// using the cluster keyring_vault.conf mounted by appendStorageSecret
+	envs = append(envs, encryption.Envs("", storage.Encryption)...)
+	envs = append(envs, throttling.Envs(storage.Throttling)...)
 	envs = util.MergeEnvLists(envs, spec.ContainerOptions.GetEnvVar(cluster, spec.StorageName))
 
 	var volumeMounts []corev1.VolumeMount
@@ -103,10 +109,26 @@ func (bcp *Backup) JobSpec(spec api.PXCBackupSpec, cluster *api.PerconaXtraDBClu
 		initContainers = append(initContainers, statefulset.BackupInitContainer(cluster, initImage, storage.ContainerSecurityContext))
 	}
 
+	image := bcp.image
 	cmd := []string{"bash", "/usr/bin/backup.sh"}
 	if cluster.CompareVersionWith("1.18.0") >= 0 {
 		cmd = []string{"bash", "/opt/percona/backup/backup.sh"}
 	}
+	if spec.IsLogical() {
+		image = cluster.Spec.Backup.LogicalImage
+		cmd = []string{"bash", "/opt/percona/backup/logical-backup.sh"}
+		envs = append(envs, logicalBackupEnvs(spec.Logical)...)
+		volumes = append(volumes, corev1.Volume{
+			Name: logicalDumpVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      logicalDumpVolumeName,
+			MountPath: LogicalDumpDir,
+		})
+	}
 
 	return batchv1.JobSpec{
 		ActiveDeadlineSeconds:   activeDeadlineSeconds,
@@ -130,7 +152,7 @@ func (bcp *Backup) JobSpec(spec api.PXCBackupSpec, cluster *api.PerconaXtraDBClu
 				Containers: []corev1.Container{
 					{
 						Name:            "xtrabackup",
-						Image:           bcp.image,
+						Image:           image,
 						SecurityContext: storage.ContainerSecurityContext,
 						ImagePullPolicy: bcp.imagePullPolicy,
 						Command:         cmd,
@@ -379,3 +401,49 @@ func SetStorageS3(ctx context.Context, job *batchv1.JobSpec, cr *api.PerconaXtra
 
 	return nil
 }
+
+func SetStorageGCS(ctx context.Context, job *batchv1.JobSpec, cr *api.PerconaXtraDBClusterBackup) error {
+	if cr.Status.GCS == nil {
+		return errors.New("gcs storage is not specified in backup status")
+	}
+
+	if len(job.Template.Spec.Containers) == 0 {
+		return errors.New("no containers in job spec")
+	}
+
+	gcs := cr.Status.GCS
+
+	job.Template.Spec.Containers[0].Env = append(job.Template.Spec.Containers[0].Env, gcsCredentialsEnvs("", gcs.CredentialsSecret)...)
+
+	bucket, prefix := gcs.BucketAndPrefix()
+	if bucket == "" {
+		bucket, prefix = cr.Status.Destination.BucketAndPrefix()
+	}
+	bucketPath := path.Join(prefix, cr.Status.Destination.BackupName())
+
+	job.Template.Spec.Containers[0].Env = append(job.Template.Spec.Containers[0].Env,
+		corev1.EnvVar{
+			Name:  "GCS_ENDPOINT",
+			Value: gcs.EndpointURL,
+		},
+		corev1.EnvVar{
+			Name:  "GCS_STORAGE_CLASS",
+			Value: gcs.StorageClass,
+		},
+		corev1.EnvVar{
+			Name:  "GCS_BUCKET",
+			Value: bucket,
+		},
+		corev1.EnvVar{
+			Name:  "GCS_BUCKET_PATH",
+			Value: bucketPath,
+		},
+	)
+
+	// add SSL volumes
+	if err := appendStorageSecret(job, cr); err != nil {
+		return errors.Wrap(err, "failed to append storage secrets")
+	}
+
+	return nil
+}
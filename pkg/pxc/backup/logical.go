@@ -0,0 +1,122 @@
+package backup
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+)
+
+const (
+	// LogicalDumpDir is the directory the logical backup is dumped into before the upload
+	// and the logical restore downloads the backup into before it's loaded.
+	LogicalDumpDir = "/dump"
+
+	logicalDumpVolumeName = "dump"
+)
+
+func logicalBackupEnvs(opts *api.PXCLogicalBackupOptions) []corev1.EnvVar {
+	envs := []corev1.EnvVar{
+		{
+			Name:  "LOGICAL_DUMP_DIR",
+			Value: LogicalDumpDir,
+		},
+	}
+	if opts == nil {
+		return envs
+	}
+	if opts.Threads > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "LOGICAL_THREADS",
+			Value: strconv.Itoa(int(opts.Threads)),
+		})
+	}
+	if len(opts.Databases) > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "LOGICAL_DATABASES",
+			Value: strings.Join(opts.Databases, " "),
+		})
+	}
+	return envs
+}
+
+// LogicalRestoreJob returns the job which downloads the logical backup and loads it
+// with myloader into the running cluster. The job is based on the restore job of the backup,
+// so it has the same storage configuration, but the datadir of the cluster isn't mounted
+// and the logical image of the cluster is used.
+func LogicalRestoreJob(restoreJob *batchv1.Job, cr *api.PerconaXtraDBClusterRestore, cluster *api.PerconaXtraDBCluster) (*batchv1.Job, error) {
+	if cluster.CompareVersionWith("1.20.0") < 0 {
+		return nil, errors.New("restore of logical backups requires crVersion 1.20.0 or newer")
+	}
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.LogicalImage == "" {
+		return nil, errors.New("restore of logical backups requires backup.logicalImage with mydumper and myloader")
+	}
+
+	job := restoreJob.DeepCopy()
+	job.Name = naming.LogicalRestoreJobName(cr)
+	job.Labels[naming.LabelPerconaRestoreJobName] = job.Name
+	job.Spec.Template.Labels[naming.LabelPerconaRestoreJobName] = job.Name
+
+	spec := &job.Spec.Template.Spec
+	if len(spec.Containers) != 1 {
+		return nil, errors.New("unexpected containers of the restore job")
+	}
+
+	volumes := []corev1.Volume{
+		{
+			Name: logicalDumpVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	for _, v := range spec.Volumes {
+		if v.Name != "datadir" {
+			volumes = append(volumes, v)
+		}
+	}
+	spec.Volumes = volumes
+
+	c := &spec.Containers[0]
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      logicalDumpVolumeName,
+			MountPath: LogicalDumpDir,
+		},
+	}
+	for _, m := range c.VolumeMounts {
+		if m.Name != "datadir" {
+			volumeMounts = append(volumeMounts, m)
+		}
+	}
+	c.VolumeMounts = volumeMounts
+	c.Image = cluster.Spec.Backup.LogicalImage
+	c.Command = []string{"/opt/percona/backup/recovery-logical.sh"}
+	c.Env = append(c.Env,
+		corev1.EnvVar{
+			Name:  "LOGICAL_DUMP_DIR",
+			Value: LogicalDumpDir,
+		},
+		corev1.EnvVar{
+			Name:  "LOGICAL_RESTORE_DATABASES",
+			Value: strings.Join(cr.Spec.Databases, " "),
+		},
+		corev1.EnvVar{
+			Name:  "LOGICAL_RESTORE_TABLES",
+			Value: strings.Join(cr.Spec.Tables, " "),
+		},
+		corev1.EnvVar{
+			Name:  "LOGICAL_RESTORE_TARGET_DATABASE",
+			Value: cr.Spec.TargetDatabase,
+		},
+	)
+	// the loaded tables aren't overwritten, so the job can't be retried
+	job.Spec.BackoffLimit = new(int32)
+
+	return job, nil
+}
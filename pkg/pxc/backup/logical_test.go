@@ -0,0 +1,210 @@
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+
+	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/test"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/version"
+)
+
+func newLogicalTestCluster(t *testing.T, crVersion string) *pxcv1.PerconaXtraDBCluster {
+	t.Helper()
+
+	cluster := &pxcv1.PerconaXtraDBCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-ns",
+		},
+		Spec: pxcv1.PerconaXtraDBClusterSpec{
+			CRVersion: crVersion,
+			Backup: &pxcv1.BackupSpec{
+				Image:        "percona/percona-xtrabackup:8.0",
+				LogicalImage: "percona/percona-xtrabackup:8.0-mydumper",
+				Storages: map[string]*pxcv1.BackupStorageSpec{
+					"test-storage": {
+						Type: pxcv1.BackupStorageS3,
+						S3: &pxcv1.BackupStorageS3Spec{
+							Bucket:            "operator-testing",
+							Region:            "us-west-1",
+							CredentialsSecret: "test-secret",
+						},
+					},
+				},
+			},
+			PXC: &pxcv1.PXCSpec{
+				PodSpec: &pxcv1.PodSpec{
+					Size:     3,
+					Image:    "percona/percona-xtradb-cluster:8.4",
+					Affinity: &pxcv1.PodAffinity{},
+					VolumeSpec: &pxcv1.VolumeSpec{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimSpec{
+							Resources: corev1.VolumeResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceStorage: resource.MustParse("1Gi"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	err := cluster.CheckNSetDefaults(&version.ServerVersion{
+		Platform: version.PlatformKubernetes,
+		Info:     k8sversion.Info{},
+	}, log)
+	require.NoError(t, err)
+	return cluster
+}
+
+func TestLogicalBackupJobSpec(t *testing.T) {
+	cluster := newLogicalTestCluster(t, version.Version())
+
+	cr := &pxcv1.PerconaXtraDBClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-backup",
+			Namespace: "test-ns",
+		},
+		Spec: pxcv1.PXCBackupSpec{
+			PXCCluster:  "test-cluster",
+			StorageName: "test-storage",
+			Type:        pxcv1.PXCBackupTypeLogical,
+			Logical: &pxcv1.PXCLogicalBackupOptions{
+				Threads:   8,
+				Databases: []string{"app", "shop"},
+			},
+		},
+	}
+
+	bcp := New(cluster)
+	job := bcp.Job(cr, cluster)
+	spec, err := bcp.JobSpec(cr.Spec, cluster, job, "init-image")
+	require.NoError(t, err)
+
+	c := spec.Template.Spec.Containers[0]
+	assert.Equal(t, "percona/percona-xtrabackup:8.0-mydumper", c.Image)
+	assert.Equal(t, []string{"bash", "/opt/percona/backup/logical-backup.sh"}, c.Command)
+	assert.Contains(t, c.Env, corev1.EnvVar{Name: "LOGICAL_DUMP_DIR", Value: LogicalDumpDir})
+	assert.Contains(t, c.Env, corev1.EnvVar{Name: "LOGICAL_THREADS", Value: "8"})
+	assert.Contains(t, c.Env, corev1.EnvVar{Name: "LOGICAL_DATABASES", Value: "app shop"})
+	assert.Contains(t, c.VolumeMounts, corev1.VolumeMount{Name: logicalDumpVolumeName, MountPath: LogicalDumpDir})
+
+	cr.Spec.Type = pxcv1.PXCBackupTypeFull
+	spec, err = bcp.JobSpec(cr.Spec, cluster, job, "init-image")
+	require.NoError(t, err)
+	assert.Equal(t, "percona/percona-xtrabackup:8.0", spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, []string{"bash", "/opt/percona/backup/backup.sh"}, spec.Template.Spec.Containers[0].Command)
+	for _, m := range spec.Template.Spec.Containers[0].VolumeMounts {
+		assert.NotEqual(t, logicalDumpVolumeName, m.Name)
+	}
+}
+
+func TestLogicalRestoreJob(t *testing.T) {
+	ctx := context.Background()
+
+	bcp := &pxcv1.PerconaXtraDBClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-backup",
+			Namespace: "test-ns",
+		},
+		Spec: pxcv1.PXCBackupSpec{
+			PXCCluster:  "test-cluster",
+			StorageName: "test-storage",
+			Type:        pxcv1.PXCBackupTypeLogical,
+		},
+		Status: pxcv1.PXCBackupStatus{
+			Type:        pxcv1.PXCBackupTypeLogical,
+			StorageName: "test-storage",
+			S3: &pxcv1.BackupStorageS3Spec{
+				Bucket:            "operator-testing",
+				CredentialsSecret: "test-secret",
+			},
+		},
+	}
+	bcp.Status.Destination.SetS3Destination("operator-testing", "test-cluster-2024-01-01-00:00:00-logical")
+
+	restore := &pxcv1.PerconaXtraDBClusterRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-restore",
+			Namespace: "test-ns",
+		},
+		Spec: pxcv1.PerconaXtraDBClusterRestoreSpec{
+			PXCCluster:     "test-cluster",
+			BackupName:     "test-backup",
+			Tables:         []string{"shop.orders", "shop.items"},
+			TargetDatabase: "shop_copy",
+		},
+	}
+
+	cl := test.BuildFakeClient()
+
+	t.Run("logical restore job", func(t *testing.T) {
+		cluster := newLogicalTestCluster(t, version.Version())
+
+		restoreJob, err := RestoreJob(ctx, restore, bcp, cluster, "init-image", cl.Scheme(), bcp.Status.Destination, false)
+		require.NoError(t, err)
+
+		job, err := LogicalRestoreJob(restoreJob, restore, cluster)
+		require.NoError(t, err)
+
+		assert.Equal(t, "logical-restore-job-test-restore-test-cluster", job.Name)
+		assert.Equal(t, job.Name, job.Labels[naming.LabelPerconaRestoreJobName])
+		assert.Equal(t, job.Name, job.Spec.Template.Labels[naming.LabelPerconaRestoreJobName])
+		assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+
+		spec := job.Spec.Template.Spec
+		require.Len(t, spec.Containers, 1)
+		c := spec.Containers[0]
+		assert.Equal(t, "percona/percona-xtrabackup:8.0-mydumper", c.Image)
+		assert.Equal(t, []string{"/opt/percona/backup/recovery-logical.sh"}, c.Command)
+		assert.Contains(t, c.Env, corev1.EnvVar{Name: "PXC_SERVICE", Value: "test-cluster-pxc"})
+		assert.Contains(t, c.Env, corev1.EnvVar{Name: "LOGICAL_RESTORE_TABLES", Value: "shop.orders shop.items"})
+		assert.Contains(t, c.Env, corev1.EnvVar{Name: "LOGICAL_RESTORE_TARGET_DATABASE", Value: "shop_copy"})
+		assert.Contains(t, c.VolumeMounts, corev1.VolumeMount{Name: logicalDumpVolumeName, MountPath: LogicalDumpDir})
+		assert.Contains(t, c.VolumeMounts, corev1.VolumeMount{Name: app.BinVolumeName, MountPath: app.BinVolumeMountPath})
+
+		// the datadir of the cluster must not be mounted
+		for _, m := range c.VolumeMounts {
+			assert.NotEqual(t, "datadir", m.Name)
+		}
+		for _, v := range spec.Volumes {
+			assert.NotEqual(t, "datadir", v.Name)
+		}
+
+		// the restore job must not be changed
+		assert.Equal(t, "datadir-test-cluster-pxc-0", restoreJob.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		assert.Equal(t, []string{"/opt/percona/backup/recovery-cloud.sh"}, restoreJob.Spec.Template.Spec.Containers[0].Command)
+	})
+
+	t.Run("old crVersion", func(t *testing.T) {
+		cluster := newLogicalTestCluster(t, "1.19.0")
+
+		restoreJob, err := RestoreJob(ctx, restore, bcp, cluster, "init-image", cl.Scheme(), bcp.Status.Destination, false)
+		require.NoError(t, err)
+
+		_, err = LogicalRestoreJob(restoreJob, restore, cluster)
+		assert.Error(t, err)
+	})
+
+	t.Run("no logical image", func(t *testing.T) {
+		cluster := newLogicalTestCluster(t, version.Version())
+		cluster.Spec.Backup.LogicalImage = ""
+
+		restoreJob, err := RestoreJob(ctx, restore, bcp, cluster, "init-image", cl.Scheme(), bcp.Status.Destination, false)
+		require.NoError(t, err)
+
+		_, err = LogicalRestoreJob(restoreJob, restore, cluster)
+		assert.EqualError(t, err, "restore of logical backups requires backup.logicalImage with mydumper and myloader")
+	})
+}
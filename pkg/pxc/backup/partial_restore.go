@@ -0,0 +1,128 @@
+package backup
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app"
+)
+
+const (
+	// ExportDir is the directory of the export volume with the exported tables.
+	// Each table is exported into the <database>/<table> subdirectory with its
+	// tablespace files and the create.sql file with the table definition.
+	ExportDir = "/datadir/export"
+	// ExportTablesFile lists the exported tables as database.table, one per line.
+	ExportTablesFile = ExportDir + "/tables"
+	// ExportDoneFile is created by the operator when the tables are imported.
+	// The export job finishes once the file exists.
+	ExportDoneFile = ExportDir + "/.done"
+
+	exportReadyFile = ExportDir + "/.ready"
+)
+
+// ExportJob returns the job which downloads the backup, prepares it with --export
+// and exports the tables of the partial restore into the export volume.
+// The job is based on the restore job of the backup: the backup is restored
+// by the init container into the export volume instead of the datadir of the cluster.
+// The job keeps running until the operator imports the tables.
+func ExportJob(restoreJob *batchv1.Job, cr *api.PerconaXtraDBClusterRestore, cluster *api.PerconaXtraDBCluster) (*batchv1.Job, error) {
+	if cluster.CompareVersionWith("1.18.0") < 0 {
+		return nil, errors.New("restore of databases and tables requires crVersion 1.18.0 or newer")
+	}
+
+	job := restoreJob.DeepCopy()
+	job.Name = naming.ExportJobName(cr)
+	job.Labels[naming.LabelPerconaRestoreJobName] = job.Name
+	job.Spec.Template.Labels[naming.LabelPerconaRestoreJobName] = job.Name
+
+	spec := &job.Spec.Template.Spec
+	if len(spec.Containers) != 1 {
+		return nil, errors.New("unexpected containers of the restore job")
+	}
+
+	volumeSource := corev1.VolumeSource{
+		EmptyDir: &corev1.EmptyDirVolumeSource{},
+	}
+	if cluster.Spec.PXC.VolumeSpec != nil && cluster.Spec.PXC.VolumeSpec.PersistentVolumeClaim != nil {
+		volumeSource = corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: naming.ExportPVCName(cr),
+			},
+		}
+	}
+	for i := range spec.Volumes {
+		if spec.Volumes[i].Name == "datadir" {
+			spec.Volumes[i].VolumeSource = volumeSource
+		}
+	}
+
+	restore := spec.Containers[0]
+	restore.Env = append(restore.Env, corev1.EnvVar{
+		Name:  "PARTIAL_RESTORE",
+		Value: "true",
+	})
+	spec.InitContainers = append(spec.InitContainers, restore)
+
+	var volumeMounts []corev1.VolumeMount
+	for _, m := range restore.VolumeMounts {
+		if m.Name == "datadir" || m.Name == app.BinVolumeName {
+			volumeMounts = append(volumeMounts, m)
+		}
+	}
+	spec.Containers = []corev1.Container{
+		{
+			Name:            "export",
+			Image:           cluster.Spec.PXC.Image,
+			ImagePullPolicy: cluster.Spec.PXC.ImagePullPolicy,
+			Command:         []string{"/opt/percona/backup/recovery-partial.sh"},
+			SecurityContext: cluster.Spec.PXC.ContainerSecurityContext,
+			VolumeMounts:    volumeMounts,
+			Env: []corev1.EnvVar{
+				{
+					Name:  "PARTIAL_RESTORE_DATABASES",
+					Value: strings.Join(cr.Spec.Databases, " "),
+				},
+				{
+					Name:  "PARTIAL_RESTORE_TABLES",
+					Value: strings.Join(cr.Spec.Tables, " "),
+				},
+			},
+			Resources: restore.Resources,
+			ReadinessProbe: &corev1.Probe{
+				ProbeHandler: corev1.ProbeHandler{
+					Exec: &corev1.ExecAction{
+						Command: []string{"test", "-f", exportReadyFile},
+					},
+				},
+				PeriodSeconds: 5,
+			},
+		},
+	}
+	// the job can't be retried once the tables are imported
+	job.Spec.BackoffLimit = new(int32)
+
+	return job, nil
+}
+
+// ExportPVC returns the volume for the export job. The volume has the same
+// storage class and size as the datadir of the cluster.
+func ExportPVC(cr *api.PerconaXtraDBClusterRestore, cluster *api.PerconaXtraDBCluster) *corev1.PersistentVolumeClaim {
+	pvcSpec := cluster.Spec.PXC.VolumeSpec.PersistentVolumeClaim.DeepCopy()
+	pvcSpec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.ExportPVCName(cr),
+			Namespace: cr.Namespace,
+			Labels:    naming.LabelsCluster(cluster),
+		},
+		Spec: *pvcSpec,
+	}
+}
@@ -0,0 +1,170 @@
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+
+	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/test"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/version"
+)
+
+func TestExportJob(t *testing.T) {
+	ctx := context.Background()
+
+	newCluster := func(crVersion string) *pxcv1.PerconaXtraDBCluster {
+		cluster := &pxcv1.PerconaXtraDBCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test-ns",
+			},
+			Spec: pxcv1.PerconaXtraDBClusterSpec{
+				CRVersion: crVersion,
+				Backup: &pxcv1.BackupSpec{
+					Image: "percona/percona-xtrabackup:8.0",
+					Storages: map[string]*pxcv1.BackupStorageSpec{
+						"test-storage": {
+							Type: pxcv1.BackupStorageS3,
+							S3: &pxcv1.BackupStorageS3Spec{
+								Bucket:            "operator-testing",
+								Region:            "us-west-1",
+								CredentialsSecret: "test-secret",
+							},
+						},
+					},
+				},
+				PXC: &pxcv1.PXCSpec{
+					PodSpec: &pxcv1.PodSpec{
+						Size:     3,
+						Image:    "percona/percona-xtradb-cluster:8.0",
+						Affinity: &pxcv1.PodAffinity{},
+						VolumeSpec: &pxcv1.VolumeSpec{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimSpec{
+								Resources: corev1.VolumeResourceRequirements{
+									Requests: corev1.ResourceList{
+										corev1.ResourceStorage: resource.MustParse("1Gi"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		err := cluster.CheckNSetDefaults(&version.ServerVersion{
+			Platform: version.PlatformKubernetes,
+			Info:     k8sversion.Info{},
+		}, log)
+		require.NoError(t, err)
+		return cluster
+	}
+
+	bcp := &pxcv1.PerconaXtraDBClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-backup",
+			Namespace: "test-ns",
+		},
+		Spec: pxcv1.PXCBackupSpec{
+			PXCCluster:  "test-cluster",
+			StorageName: "test-storage",
+		},
+		Status: pxcv1.PXCBackupStatus{
+			StorageName: "test-storage",
+			S3: &pxcv1.BackupStorageS3Spec{
+				Bucket:            "operator-testing",
+				CredentialsSecret: "test-secret",
+			},
+		},
+	}
+	bcp.Status.Destination.SetS3Destination("operator-testing", "test-cluster-2024-01-01-00:00:00-full")
+
+	restore := &pxcv1.PerconaXtraDBClusterRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-restore",
+			Namespace: "test-ns",
+		},
+		Spec: pxcv1.PerconaXtraDBClusterRestoreSpec{
+			PXCCluster: "test-cluster",
+			BackupName: "test-backup",
+			Databases:  []string{"app"},
+			Tables:     []string{"shop.orders", "shop.items"},
+		},
+	}
+
+	cl := test.BuildFakeClient()
+
+	t.Run("export job", func(t *testing.T) {
+		cluster := newCluster(version.Version())
+
+		restoreJob, err := RestoreJob(ctx, restore, bcp, cluster, "init-image", cl.Scheme(), bcp.Status.Destination, false)
+		require.NoError(t, err)
+
+		job, err := ExportJob(restoreJob, restore, cluster)
+		require.NoError(t, err)
+
+		assert.Equal(t, "export-job-test-restore-test-cluster", job.Name)
+		assert.Equal(t, job.Name, job.Labels[naming.LabelPerconaRestoreJobName])
+		assert.Equal(t, job.Name, job.Spec.Template.Labels[naming.LabelPerconaRestoreJobName])
+		assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+
+		spec := job.Spec.Template.Spec
+		require.Len(t, spec.InitContainers, 2)
+		assert.Equal(t, restoreJob.Spec.Template.Spec.InitContainers[0].Name, spec.InitContainers[0].Name)
+		assert.Equal(t, []string{"/opt/percona/backup/recovery-cloud.sh"}, spec.InitContainers[1].Command)
+		assert.Contains(t, spec.InitContainers[1].Env, corev1.EnvVar{Name: "PARTIAL_RESTORE", Value: "true"})
+
+		require.Len(t, spec.Containers, 1)
+		export := spec.Containers[0]
+		assert.Equal(t, "export", export.Name)
+		assert.Equal(t, "percona/percona-xtradb-cluster:8.0", export.Image)
+		assert.Equal(t, []string{"/opt/percona/backup/recovery-partial.sh"}, export.Command)
+		assert.Equal(t, []corev1.EnvVar{
+			{Name: "PARTIAL_RESTORE_DATABASES", Value: "app"},
+			{Name: "PARTIAL_RESTORE_TABLES", Value: "shop.orders shop.items"},
+		}, export.Env)
+		assert.ElementsMatch(t, []corev1.VolumeMount{
+			{Name: "datadir", MountPath: "/datadir"},
+			{Name: app.BinVolumeName, MountPath: app.BinVolumeMountPath},
+		}, export.VolumeMounts)
+
+		for _, v := range spec.Volumes {
+			if v.Name == "datadir" {
+				require.NotNil(t, v.PersistentVolumeClaim)
+				assert.Equal(t, "export-test-restore-test-cluster", v.PersistentVolumeClaim.ClaimName)
+			}
+		}
+
+		// the restore job must not be changed
+		assert.Equal(t, "datadir-test-cluster-pxc-0", restoreJob.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		assert.Len(t, restoreJob.Spec.Template.Spec.InitContainers, 1)
+	})
+
+	t.Run("old crVersion", func(t *testing.T) {
+		cluster := newCluster("1.17.0")
+
+		restoreJob, err := RestoreJob(ctx, restore, bcp, cluster, "init-image", cl.Scheme(), bcp.Status.Destination, false)
+		require.NoError(t, err)
+
+		_, err = ExportJob(restoreJob, restore, cluster)
+		assert.Error(t, err)
+	})
+
+	t.Run("export pvc", func(t *testing.T) {
+		cluster := newCluster(version.Version())
+
+		pvc := ExportPVC(restore, cluster)
+		assert.Equal(t, "export-test-restore-test-cluster", pvc.Name)
+		assert.Equal(t, "test-ns", pvc.Namespace)
+		assert.Equal(t, resource.MustParse("1Gi"), pvc.Spec.Resources.Requests[corev1.ResourceStorage])
+		assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, pvc.Spec.AccessModes)
+	})
+}
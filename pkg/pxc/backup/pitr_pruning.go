@@ -0,0 +1,102 @@
+package backup
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/timeline"
+)
+
+// Binlog is a binlog uploaded to the PITR storage by the binlog collector.
+type Binlog struct {
+	Name string
+	// FirstEventTime is the time of the first event in the binlog.
+	FirstEventTime time.Time
+}
+
+// ListBinlogs returns the binlogs in the PITR storage ordered by the time of their first event.
+func ListBinlogs(ctx context.Context, s storage.Storage) ([]Binlog, error) {
+	objs, err := s.ListObjects(ctx, timeline.BinlogPrefix)
+	if err != nil {
+		return nil, errors.Wrapf(err, "list objects with prefix %s", timeline.BinlogPrefix)
+	}
+
+	var binlogs []Binlog
+	for _, obj := range objs {
+		if strings.HasSuffix(obj, timeline.BinlogGTIDSetSuffix) {
+			continue
+		}
+		t, err := timeline.ParseBinlogName(obj)
+		if err != nil {
+			// not uploaded by the binlog collector
+			continue
+		}
+		binlogs = append(binlogs, Binlog{Name: obj, FirstEventTime: t})
+	}
+
+	sort.SliceStable(binlogs, func(i, j int) bool {
+		return binlogs[i].FirstEventTime.Before(binlogs[j].FirstEventTime)
+	})
+
+	return binlogs, nil
+}
+
+// BinlogsSize returns the total size of the binlogs and their GTID sets in the PITR storage.
+func BinlogsSize(ctx context.Context, s storage.Storage) (int64, error) {
+	return s.ObjectsSize(ctx, timeline.BinlogPrefix)
+}
+
+// BinlogsToPrune returns the binlogs which aren't needed to restore to any point in time after the cutoff.
+// The binlogs must be ordered by the time of their first event. The binlog which contains the cutoff is kept.
+func BinlogsToPrune(binlogs []Binlog, cutoff time.Time) []Binlog {
+	keepFrom := 0
+	for i, b := range binlogs {
+		if b.FirstEventTime.After(cutoff) {
+			break
+		}
+		keepFrom = i
+	}
+	return binlogs[:keepFrom]
+}
+
+// PruneBinlogs deletes the binlogs with their GTID sets from the storage, starting from the oldest one.
+// The binlog is deleted before its GTID set, so the restore never finds a binlog without the GTID set.
+func PruneBinlogs(ctx context.Context, s storage.Storage, binlogs []Binlog) error {
+	for _, b := range binlogs {
+		for _, obj := range []string{b.Name, b.Name + timeline.BinlogGTIDSetSuffix} {
+			if err := s.DeleteObject(ctx, obj); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+				return errors.Wrapf(err, "delete %s", obj)
+			}
+		}
+	}
+	return nil
+}
+
+// PITRCutoff returns the time before which binlogs aren't needed to restore any of the backups
+// with the safety margin. It returns false if there are no succeeded backups.
+func PITRCutoff(backups []api.PerconaXtraDBClusterBackup, margin time.Duration) (time.Time, bool) {
+	var oldest time.Time
+	for _, bcp := range backups {
+		if bcp.Status.State != api.BackupSucceeded || bcp.DeletionTimestamp != nil {
+			continue
+		}
+		t := bcp.CreationTimestamp.Time
+		// imported backups are created after they are completed
+		if bcp.Status.CompletedAt != nil && bcp.Status.CompletedAt.Time.Before(t) {
+			t = bcp.Status.CompletedAt.Time
+		}
+		if oldest.IsZero() || t.Before(oldest) {
+			oldest = t
+		}
+	}
+	if oldest.IsZero() {
+		return time.Time{}, false
+	}
+	return oldest.Add(-margin), true
+}
@@ -0,0 +1,111 @@
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage/mock"
+)
+
+func TestPruneBinlogs(t *testing.T) {
+	ctx := context.Background()
+
+	s := mock.NewStorage(t)
+	s.On("ListObjects", ctx, "binlog_").Return([]string{
+		"binlog_1704103200_000012_0a1b",
+		"binlog_1704103200_000012_0a1b-gtid-set",
+		"binlog_1704096000_000011_9f8e",
+		"binlog_1704096000_000011_9f8e-gtid-set",
+		"binlog_1704110400_000013_5c6d",
+		"binlog_1704110400_000013_5c6d-gtid-set",
+		"binlog_unexpected",
+	}, nil)
+
+	binlogs, err := ListBinlogs(ctx, s)
+	assert.NoError(t, err)
+	assert.Equal(t, []Binlog{
+		{Name: "binlog_1704096000_000011_9f8e", FirstEventTime: time.Unix(1704096000, 0)},
+		{Name: "binlog_1704103200_000012_0a1b", FirstEventTime: time.Unix(1704103200, 0)},
+		{Name: "binlog_1704110400_000013_5c6d", FirstEventTime: time.Unix(1704110400, 0)},
+	}, binlogs)
+
+	// the second binlog contains the events of the cutoff time
+	toPrune := BinlogsToPrune(binlogs, time.Unix(1704105000, 0))
+	assert.Equal(t, binlogs[:1], toPrune)
+	assert.Empty(t, BinlogsToPrune(binlogs, time.Unix(1704100000, 0)))
+	assert.Empty(t, BinlogsToPrune(binlogs, time.Unix(1704000000, 0)))
+	assert.Equal(t, binlogs[:2], BinlogsToPrune(binlogs, time.Unix(1704200000, 0)))
+
+	s.On("DeleteObject", ctx, "binlog_1704096000_000011_9f8e").Return(nil).Once()
+	s.On("DeleteObject", ctx, "binlog_1704096000_000011_9f8e-gtid-set").Return(storage.ErrObjectNotFound).Once()
+	assert.NoError(t, PruneBinlogs(ctx, s, toPrune))
+}
+
+func TestPITRCutoff(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	newBackup := func(created time.Time, completed *time.Time, state api.PXCBackupState) api.PerconaXtraDBClusterBackup {
+		bcp := api.PerconaXtraDBClusterBackup{}
+		bcp.CreationTimestamp = metav1.Time{Time: created}
+		bcp.Status.State = state
+		if completed != nil {
+			bcp.Status.CompletedAt = &metav1.Time{Time: *completed}
+		}
+		return bcp
+	}
+
+	completed := now.Add(-47 * time.Hour)
+	imported := now.Add(-96 * time.Hour)
+
+	deleting := newBackup(now.Add(-200*time.Hour), nil, api.BackupSucceeded)
+	deleting.DeletionTimestamp = &metav1.Time{Time: now}
+
+	tests := []struct {
+		name     string
+		backups  []api.PerconaXtraDBClusterBackup
+		expected time.Time
+		ok       bool
+	}{
+		{
+			name: "no succeeded backups",
+			backups: []api.PerconaXtraDBClusterBackup{
+				newBackup(now.Add(-48*time.Hour), nil, api.BackupFailed),
+				deleting,
+			},
+		},
+		{
+			name: "oldest backup",
+			backups: []api.PerconaXtraDBClusterBackup{
+				newBackup(now.Add(-24*time.Hour), nil, api.BackupSucceeded),
+				newBackup(now.Add(-48*time.Hour), &completed, api.BackupSucceeded),
+				newBackup(now.Add(-72*time.Hour), nil, api.BackupFailed),
+				deleting,
+			},
+			expected: now.Add(-48*time.Hour - time.Hour),
+			ok:       true,
+		},
+		{
+			name: "imported backup",
+			backups: []api.PerconaXtraDBClusterBackup{
+				newBackup(now.Add(-24*time.Hour), nil, api.BackupSucceeded),
+				newBackup(now, &imported, api.BackupSucceeded),
+			},
+			expected: now.Add(-96*time.Hour - time.Hour),
+			ok:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cutoff, ok := PITRCutoff(tt.backups, time.Hour)
+			assert.Equal(t, tt.ok, ok)
+			assert.True(t, tt.expected.Equal(cutoff), "expected %s, got %s", tt.expected, cutoff)
+		})
+	}
+}
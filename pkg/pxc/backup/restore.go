@@ -23,6 +23,8 @@ import (
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app/config"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app/statefulset"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/encryption"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/throttling"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/users"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/util"
 )
@@ -69,7 +71,7 @@ func PVCRestoreService(cr *api.PerconaXtraDBClusterRestore, cluster *api.Percona
 }
 
 func pvcRestoreSvcName(cr *api.PerconaXtraDBClusterRestore) string {
-	return "restore-src-" + cr.Name + "-" + cr.Spec.PXCCluster
+	return "restore-src-" + cr.Name + "-" + cr.TargetCluster()
 }
 
 func PVCRestorePod(cr *api.PerconaXtraDBClusterRestore, bcpStorageName, pvcName string, cluster *api.PerconaXtraDBCluster, initImage string) (*corev1.Pod, error) {
@@ -230,6 +232,10 @@ func RestoreJob(
 		if bcp.Status.S3 == nil {
 			return nil, errors.New("nil s3 backup status storage")
 		}
+	case api.BackupStorageGCS:
+		if bcp.Status.GCS == nil {
+			return nil, errors.New("nil gcs backup status storage")
+		}
 	case api.BackupStorageFilesystem:
 	default:
 		return nil, errors.Errorf("no storage type was specified in status, got: %s", bcp.Status.GetStorageType(cluster))
@@ -250,7 +256,7 @@ func RestoreJob(
 			Name: "datadir",
 			VolumeSource: corev1.VolumeSource{
 				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-					ClaimName: "datadir-" + cr.Spec.PXCCluster + "-pxc-0",
+					ClaimName: "datadir-" + cr.TargetCluster() + "-pxc-0",
 				},
 			},
 		},
@@ -283,7 +289,7 @@ func RestoreJob(
 			app.GetSecretVolumes("ssl-internal", cluster.Spec.PXC.SSLInternalSecretName, true),
 			sslVolume,
 		}...)
-	case api.BackupStorageAzure, api.BackupStorageS3:
+	case api.BackupStorageAzure, api.BackupStorageS3, api.BackupStorageGCS:
 		command = []string{"recovery-cloud.sh"}
 		if cluster.CompareVersionWith("1.18.0") >= 0 {
 			command = []string{"/opt/percona/backup/recovery-cloud.sh"}
@@ -335,6 +341,14 @@ func RestoreJob(
 	if err != nil {
 		return nil, errors.Wrap(err, "restore job envs")
 	}
+	// the progress of the restore is stored with the id, so the next pod of the job
+	// resumes the restore and the progress of other restores is ignored
+	if cluster.CompareVersionWith("1.20.0") >= 0 && !pitr {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "RESTORE_ID",
+			Value: string(cr.UID),
+		})
+	}
 
 	if cluster.CompareVersionWith("1.18.0") >= 0 && !pitr {
 		volumes = append(volumes,
@@ -401,6 +415,22 @@ func RestoreJob(
 	if cluster.CompareVersionWith("1.16.0") < 0 {
 		job.Labels = cluster.Spec.PXC.Labels
 	}
+	// the evicted pods aren't counted as failures, the next pod resumes the restore
+	if cluster.CompareVersionWith("1.20.0") >= 0 {
+		job.Spec.PodFailurePolicy = &batchv1.PodFailurePolicy{
+			Rules: []batchv1.PodFailurePolicyRule{
+				{
+					Action: batchv1.PodFailurePolicyActionIgnore,
+					OnPodConditions: []batchv1.PodFailurePolicyOnPodConditionsPattern{
+						{
+							Type:   corev1.DisruptionTarget,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+		}
+	}
 
 	if err := controllerutil.SetControllerReference(cr, job, scheme); err != nil {
 		return nil, errors.Wrap(err, "set controller reference")
@@ -424,7 +454,7 @@ func restoreJobEnvs(
 			[]corev1.EnvVar{
 				{
 					Name:  "RESTORE_SRC_SERVICE",
-					Value: "restore-src-" + cr.Name + "-" + cr.Spec.PXCCluster,
+					Value: "restore-src-" + cr.Name + "-" + cr.TargetCluster(),
 				},
 			},
 			cr.Spec.ContainerOptions.GetEnvVar(cluster, bcp.Spec.StorageName),
@@ -452,7 +482,7 @@ func restoreJobEnvs(
 	envs := []corev1.EnvVar{
 		{
 			Name:  "PXC_SERVICE",
-			Value: cr.Spec.PXCCluster + "-pxc",
+			Value: cr.TargetCluster() + "-pxc",
 		},
 		{
 			Name:  "PXC_USER",
@@ -479,7 +509,23 @@ func restoreJobEnvs(
 				Name:  "PITR_RECOVERY_TYPE",
 				Value: cr.Spec.PITR.Type,
 			},
+			{
+				Name:  "PITR_DRY_RUN",
+				Value: strconv.FormatBool(cr.Spec.PITR.DryRun),
+			},
 		}...)
+		if cr.Spec.PITR.Type == api.PITRTypePosition {
+			envs = append(envs, []corev1.EnvVar{
+				{
+					Name:  "PITR_BINLOG_FILE",
+					Value: cr.Spec.PITR.BinlogFile,
+				},
+				{
+					Name:  "PITR_BINLOG_POSITION",
+					Value: strconv.FormatInt(cr.Spec.PITR.BinlogPosition, 10),
+				},
+			}...)
+		}
 		if bs := cr.Spec.PITR.BackupSource; bs != nil {
 			if bs.StorageName != "" {
 				storage, ok := cluster.Spec.Backup.Storages[bs.StorageName]
@@ -497,6 +543,7 @@ func restoreJobEnvs(
 		Name:  "VERIFY_TLS",
 		Value: strconv.FormatBool(verifyTLS),
 	})
+	envs = append(envs, throttling.Envs(restoreThrottling(cr, bcp, cluster, pitr))...)
 
 	if features.Enabled(ctx, features.XtrabackupSidecar) {
 		envs = append(envs, corev1.EnvVar{
@@ -505,6 +552,68 @@ func restoreJobEnvs(
 		})
 	}
 
+	storageEnvs, err := storageEnvs(cr, bcp, cluster, destination, pitr)
+	if err != nil {
+		return nil, err
+	}
+	envs = append(envs, storageEnvs...)
+
+	return util.MergeEnvLists(
+		envs,
+		cr.Spec.ContainerOptions.GetEnvVar(cluster, bcp.Spec.StorageName),
+	), nil
+}
+
+// restoreThrottling returns the throttling configuration of the storage
+// the backup (or binlogs if pitr is true) are downloaded from.
+func restoreThrottling(
+	cr *api.PerconaXtraDBClusterRestore,
+	bcp *api.PerconaXtraDBClusterBackup,
+	cluster *api.PerconaXtraDBCluster,
+	pitr bool,
+) *api.BackupThrottlingSpec {
+	if cluster.Spec.Backup == nil {
+		return nil
+	}
+
+	storageName := bcp.Spec.StorageName
+	if bs := cr.Spec.BackupSource; bs != nil && bs.StorageName != "" {
+		storageName = bs.StorageName
+	}
+	if pitr && cr.Spec.PITR != nil {
+		if bs := cr.Spec.PITR.BackupSource; bs != nil && bs.StorageName != "" {
+			storageName = bs.StorageName
+		}
+	}
+
+	storage, ok := cluster.Spec.Backup.Storages[storageName]
+	if !ok || storage == nil {
+		return nil
+	}
+	return storage.Throttling
+}
+
+// storageEnvs returns env variables needed by recovery-cloud.sh to download the backup.
+// The restore object is used only for PITR.
+func storageEnvs(
+	cr *api.PerconaXtraDBClusterRestore,
+	bcp *api.PerconaXtraDBClusterBackup,
+	cluster *api.PerconaXtraDBCluster,
+	destination api.PXCBackupDestination,
+	pitr bool,
+) ([]corev1.EnvVar, error) {
+	var envs []corev1.EnvVar
+
+	if bcp.Status.IsIncremental() {
+		if len(bcp.Status.BaseChain) == 0 {
+			return nil, errors.New("incremental backup has no base backups")
+		}
+		envs = append(envs, corev1.EnvVar{
+			Name:  "BACKUP_BASE_CHAIN",
+			Value: baseChainPaths(bcp.Status.GetStorageType(cluster), bcp.Status.BaseChain),
+		})
+	}
+
 	switch bcp.Status.GetStorageType(cluster) {
 	case api.BackupStorageAzure:
 		azureEnvs, err := azureEnvs(cr, bcp, cluster, destination, pitr)
@@ -518,13 +627,52 @@ func restoreJobEnvs(
 			return nil, err
 		}
 		envs = append(envs, s3Envs...)
+	case api.BackupStorageGCS:
+		gcsEnvs, err := gcsEnvs(cr, bcp, cluster, destination, pitr)
+		if err != nil {
+			return nil, err
+		}
+		envs = append(envs, gcsEnvs...)
 	default:
 		return nil, errors.Errorf("invalid storage type was specified in status, got: %s", bcp.Status.GetStorageType(cluster))
 	}
-	return util.MergeEnvLists(
-		envs,
-		cr.Spec.ContainerOptions.GetEnvVar(cluster, bcp.Spec.StorageName),
-	), nil
+
+	envs = append(envs, encryption.Envs("", bcp.Status.Encryption)...)
+	if pitr {
+		envs = append(envs, encryption.Envs(encryption.BinlogEnvPrefix, pitrEncryption(cr, cluster))...)
+	}
+
+	return envs, nil
+}
+
+// pitrEncryption returns the encryption configuration of the storage with binlogs.
+func pitrEncryption(cr *api.PerconaXtraDBClusterRestore, cluster *api.PerconaXtraDBCluster) *api.BackupEncryptionSpec {
+	bs := cr.Spec.PITR.BackupSource
+	if bs == nil {
+		return nil
+	}
+	if bs.Encryption != nil {
+		return bs.Encryption
+	}
+	if storage, ok := cluster.Spec.Backup.Storages[bs.StorageName]; ok && bs.StorageName != "" {
+		return storage.Encryption
+	}
+	return nil
+}
+
+// baseChainPaths returns space separated paths of the base backups
+// in the same format as the backup path of the restored backup.
+func baseChainPaths(storageType api.BackupStorageType, chain []api.PXCBackupDestination) string {
+	paths := make([]string, 0, len(chain))
+	for _, dest := range chain {
+		switch storageType {
+		case api.BackupStorageS3:
+			paths = append(paths, "s3://"+strings.TrimPrefix(dest.String(), dest.StorageTypePrefix()))
+		default:
+			paths = append(paths, dest.PathWithoutBucket())
+		}
+	}
+	return strings.Join(paths, " ")
 }
 
 func azureEnvs(cr *api.PerconaXtraDBClusterRestore, bcp *api.PerconaXtraDBClusterBackup, cluster *api.PerconaXtraDBCluster, destination api.PXCBackupDestination, pitr bool) ([]corev1.EnvVar, error) {
@@ -767,6 +915,82 @@ func s3Envs(cr *api.PerconaXtraDBClusterRestore, bcp *api.PerconaXtraDBClusterBa
 	return envs, nil
 }
 
+func gcsEnvs(cr *api.PerconaXtraDBClusterRestore, bcp *api.PerconaXtraDBClusterBackup, cluster *api.PerconaXtraDBCluster, destination api.PXCBackupDestination, pitr bool) ([]corev1.EnvVar, error) {
+	gcs := bcp.Status.GCS
+	bucket, prefix := gcs.BucketAndPrefix()
+	if bucket == "" {
+		bucket, prefix = destination.BucketAndPrefix()
+	}
+	envs := []corev1.EnvVar{
+		{
+			Name:  "GCS_BUCKET",
+			Value: bucket,
+		},
+		{
+			Name:  "GCS_BUCKET_PATH",
+			Value: path.Join(prefix, destination.BackupName()),
+		},
+		{
+			Name:  "GCS_ENDPOINT",
+			Value: gcs.EndpointURL,
+		},
+	}
+	envs = append(envs, gcsCredentialsEnvs("", gcs.CredentialsSecret)...)
+
+	if pitr {
+		storageGCS := new(api.BackupStorageGCSSpec)
+		if bs := cr.Spec.PITR.BackupSource; bs != nil {
+			if bs.StorageName != "" {
+				storage, ok := cluster.Spec.Backup.Storages[bs.StorageName]
+				if ok && storage.GCS != nil {
+					storageGCS = storage.GCS
+				}
+			}
+			if bs.GCS != nil {
+				storageGCS = bs.GCS
+			}
+		}
+		if len(storageGCS.Bucket) == 0 {
+			return nil, errors.New("no bucket in storage")
+		}
+		envs = append(envs, []corev1.EnvVar{
+			{
+				Name:  "BINLOG_GCS_BUCKET_URL",
+				Value: storageGCS.Bucket,
+			},
+			{
+				Name:  "BINLOG_GCS_ENDPOINT",
+				Value: storageGCS.EndpointURL,
+			},
+			{
+				Name:  "STORAGE_TYPE",
+				Value: string(api.BackupStorageGCS),
+			},
+		}...)
+		envs = append(envs, gcsCredentialsEnvs("BINLOG_", storageGCS.CredentialsSecret)...)
+	}
+	return envs, nil
+}
+
+// gcsCredentialsEnvs returns env variables with GCS credentials.
+// All keys are optional since credentials can be provided by workload identity.
+func gcsCredentialsEnvs(namePrefix, secretName string) []corev1.EnvVar {
+	if secretName == "" {
+		return nil
+	}
+
+	envs := make([]corev1.EnvVar, 0, 3)
+	for _, key := range []string{"GCS_CREDENTIALS_JSON", "GCS_ACCESS_KEY", "GCS_SECRET_KEY"} {
+		envs = append(envs, corev1.EnvVar{
+			Name: namePrefix + key,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: app.SecretKeySelectorWithOptional(secretName, key, true),
+			},
+		})
+	}
+	return envs
+}
+
 func xtrabackupContainer(cr *api.PerconaXtraDBClusterRestore, cluster *api.PerconaXtraDBCluster, cmd []string, volumeMounts []corev1.VolumeMount, envs []corev1.EnvVar) corev1.Container {
 	container := corev1.Container{
 		Name:            "xtrabackup",
@@ -859,7 +1083,7 @@ func PrepareJob(
 			Name: "datadir",
 			VolumeSource: corev1.VolumeSource{
 				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-					ClaimName: "datadir-" + cr.Spec.PXCCluster + "-pxc-0",
+					ClaimName: "datadir-" + cr.TargetCluster() + "-pxc-0",
 				},
 			},
 		},
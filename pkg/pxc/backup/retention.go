@@ -0,0 +1,202 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+)
+
+// GetBackupSize returns the total size of the backup objects in the storage.
+func GetBackupSize(ctx context.Context, s storage.Storage, backupName string) (*resource.Quantity, error) {
+	var total int64
+	for _, prefix := range []string{backupName + "/", backupName + ".md5"} {
+		size, err := s.ObjectsSize(ctx, prefix)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get size of %s", prefix)
+		}
+		total += size
+	}
+
+	return resource.NewQuantity(total, resource.BinarySI), nil
+}
+
+// BackupsToDelete returns the succeeded backups which are not retained by the retention.
+// The latest backup and backups which are the base of a retained or unfinished incremental backup are always retained.
+// Others are the other backups in the storage of the retention, e.g. backups of other schedules, copies
+// and manual backups. They are never deleted, but they share the MaxTotalSize budget of the storage.
+// Returned backups are ordered from the oldest to the newest.
+func BackupsToDelete(retention api.PXCScheduledBackupRetention, backups, others []api.PerconaXtraDBClusterBackup, now time.Time) []api.PerconaXtraDBClusterBackup {
+	succeeded := make([]api.PerconaXtraDBClusterBackup, 0, len(backups))
+	for _, bcp := range backups {
+		if bcp.Status.State == api.BackupSucceeded {
+			succeeded = append(succeeded, bcp)
+		}
+	}
+	if len(succeeded) == 0 {
+		return nil
+	}
+
+	// newest first, the backups are ordered by the creation time as the count retention always did,
+	// the completion time is used only to find the period or the age of the backup
+	sort.SliceStable(succeeded, func(i, j int) bool {
+		return succeeded[j].CreationTimestamp.Before(&succeeded[i].CreationTimestamp)
+	})
+
+	var retained []bool
+	switch retention.Type {
+	case api.PXCScheduledBackupRetentionCount:
+		retained = retainCount(succeeded, retention.Count)
+	case api.PXCScheduledBackupRetentionGFS:
+		retained = retainGFS(succeeded, retention.GFS)
+	case api.PXCScheduledBackupRetentionAge:
+		retained = retainAge(succeeded, retention.MaxAge, now)
+	default:
+		retained = retainCount(succeeded, 0)
+	}
+	if retention.MaxTotalSize != nil && !retention.MaxTotalSize.IsZero() {
+		retainSize(succeeded, retained, others, retention.MaxTotalSize.Value())
+	}
+	retained[0] = true
+
+	// the chain of incremental backups can't be restored without its base backups
+	required := make(map[api.PXCBackupDestination]struct{})
+	for i, bcp := range succeeded {
+		if !retained[i] {
+			continue
+		}
+		for _, base := range bcp.Status.BaseChain {
+			required[base] = struct{}{}
+		}
+	}
+	for _, bcp := range backups {
+		if bcp.Status.State == api.BackupSucceeded || bcp.Status.State == api.BackupFailed {
+			continue
+		}
+		for _, base := range bcp.Status.BaseChain {
+			required[base] = struct{}{}
+		}
+	}
+
+	var toDelete []api.PerconaXtraDBClusterBackup
+	for i := len(succeeded) - 1; i >= 0; i-- {
+		if retained[i] {
+			continue
+		}
+		if _, ok := required[succeeded[i].Status.Destination]; ok {
+			continue
+		}
+		toDelete = append(toDelete, succeeded[i])
+	}
+
+	return toDelete
+}
+
+// retainCount retains the latest count backups. Backups are retained if count is not positive.
+func retainCount(backups []api.PerconaXtraDBClusterBackup, count int) []bool {
+	retained := make([]bool, len(backups))
+	for i := range backups {
+		retained[i] = count <= 0 || i < count
+	}
+	return retained
+}
+
+// retainGFS retains the latest backup of each of the last days, weeks and months which have backups.
+func retainGFS(backups []api.PerconaXtraDBClusterBackup, gfs *api.PXCScheduledBackupGFSRetention) []bool {
+	retained := make([]bool, len(backups))
+	if gfs == nil {
+		return retainCount(backups, 0)
+	}
+
+	retainPeriods := func(keep int, period func(t time.Time) string) {
+		seen := make(map[string]struct{})
+		for i, bcp := range backups {
+			if len(seen) >= keep {
+				return
+			}
+			p := period(backupTime(bcp).UTC())
+			if _, ok := seen[p]; ok {
+				continue
+			}
+			seen[p] = struct{}{}
+			retained[i] = true
+		}
+	}
+
+	retainPeriods(gfs.Daily, func(t time.Time) string {
+		return t.Format(time.DateOnly)
+	})
+	retainPeriods(gfs.Weekly, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	})
+	retainPeriods(gfs.Monthly, func(t time.Time) string {
+		return t.Format("2006-01")
+	})
+
+	return retained
+}
+
+// retainAge retains the backups which are not older than maxAge.
+func retainAge(backups []api.PerconaXtraDBClusterBackup, maxAge *metav1.Duration, now time.Time) []bool {
+	retained := make([]bool, len(backups))
+	for i, bcp := range backups {
+		retained[i] = maxAge == nil || now.Sub(backupTime(bcp)) <= maxAge.Duration
+	}
+	return retained
+}
+
+// retainSize stops retaining the oldest retained backups once the total size of the retained backups
+// and the other succeeded backups in the storage exceeds maxSize. The backups are counted from the newest.
+func retainSize(backups []api.PerconaXtraDBClusterBackup, retained []bool, others []api.PerconaXtraDBClusterBackup, maxSize int64) {
+	type sizedBackup struct {
+		time  time.Time
+		size  int64
+		index int // the index in backups, -1 for the other backups
+	}
+
+	size := func(bcp api.PerconaXtraDBClusterBackup) int64 {
+		if bcp.Status.Size == nil {
+			return 0
+		}
+		return bcp.Status.Size.Value()
+	}
+
+	sized := make([]sizedBackup, 0, len(backups)+len(others))
+	for i, bcp := range backups {
+		if retained[i] {
+			sized = append(sized, sizedBackup{backupTime(bcp), size(bcp), i})
+		}
+	}
+	for _, bcp := range others {
+		if bcp.Status.State == api.BackupSucceeded {
+			sized = append(sized, sizedBackup{backupTime(bcp), size(bcp), -1})
+		}
+	}
+	sort.SliceStable(sized, func(i, j int) bool {
+		return sized[i].time.After(sized[j].time)
+	})
+
+	var total int64
+	for _, bcp := range sized {
+		total += bcp.size
+		if total > maxSize && bcp.index >= 0 {
+			retained[bcp.index] = false
+		}
+	}
+}
+
+// backupTime returns the time the backup was completed or created if the completion time is unknown.
+func backupTime(bcp api.PerconaXtraDBClusterBackup) time.Time {
+	if bcp.Status.CompletedAt != nil {
+		return bcp.Status.CompletedAt.Time
+	}
+	return bcp.CreationTimestamp.Time
+}
@@ -0,0 +1,203 @@
+package backup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+)
+
+func TestBackupsToDelete(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	newBackup := func(name string, completed time.Time, state api.PXCBackupState) api.PerconaXtraDBClusterBackup {
+		bcp := api.PerconaXtraDBClusterBackup{}
+		bcp.Name = name
+		bcp.CreationTimestamp = metav1.NewTime(completed.Add(-time.Hour))
+		bcp.Status.State = state
+		bcp.Status.CompletedAt = &metav1.Time{Time: completed}
+		bcp.Status.Destination.SetS3Destination("bucket", name)
+		bcp.Status.Size = resource.NewQuantity(100, resource.BinarySI)
+		return bcp
+	}
+
+	// a backup every day at 10:00 for the last 60 days
+	var daily []api.PerconaXtraDBClusterBackup
+	for i := 0; i < 60; i++ {
+		completed := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC).AddDate(0, 0, -i)
+		daily = append(daily, newBackup(completed.Format(time.DateOnly), completed, api.BackupSucceeded))
+	}
+
+	names := func(backups []api.PerconaXtraDBClusterBackup) []string {
+		var ret []string
+		for _, bcp := range backups {
+			ret = append(ret, bcp.Name)
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name      string
+		retention api.PXCScheduledBackupRetention
+		backups   []api.PerconaXtraDBClusterBackup
+		others    []api.PerconaXtraDBClusterBackup
+		retained  []string
+	}{
+		{
+			name: "count",
+			retention: api.PXCScheduledBackupRetention{
+				Type:  api.PXCScheduledBackupRetentionCount,
+				Count: 3,
+			},
+			backups:  daily,
+			retained: []string{"2024-03-15", "2024-03-14", "2024-03-13"},
+		},
+		{
+			name: "gfs",
+			retention: api.PXCScheduledBackupRetention{
+				Type: api.PXCScheduledBackupRetentionGFS,
+				GFS: &api.PXCScheduledBackupGFSRetention{
+					Daily:   2,
+					Weekly:  3,
+					Monthly: 3,
+				},
+			},
+			backups: daily,
+			retained: []string{
+				// daily
+				"2024-03-15", "2024-03-14",
+				// weekly, the weeks start on Monday
+				"2024-03-10", "2024-03-03",
+				// monthly
+				"2024-02-29", "2024-01-31",
+			},
+		},
+		{
+			name: "age",
+			retention: api.PXCScheduledBackupRetention{
+				Type:   api.PXCScheduledBackupRetentionAge,
+				MaxAge: &metav1.Duration{Duration: 72 * time.Hour},
+			},
+			backups:  daily,
+			retained: []string{"2024-03-15", "2024-03-14", "2024-03-13"},
+		},
+		{
+			name: "age keeps the latest backup",
+			retention: api.PXCScheduledBackupRetention{
+				Type:   api.PXCScheduledBackupRetentionAge,
+				MaxAge: &metav1.Duration{Duration: time.Hour},
+			},
+			backups:  daily,
+			retained: []string{"2024-03-15"},
+		},
+		{
+			name: "count with size limit",
+			retention: api.PXCScheduledBackupRetention{
+				Type:         api.PXCScheduledBackupRetentionCount,
+				Count:        5,
+				MaxTotalSize: resource.NewQuantity(250, resource.BinarySI),
+			},
+			backups:  daily,
+			retained: []string{"2024-03-15", "2024-03-14"},
+		},
+		{
+			name: "size limit only",
+			retention: api.PXCScheduledBackupRetention{
+				Type:         api.PXCScheduledBackupRetentionCount,
+				MaxTotalSize: resource.NewQuantity(300, resource.BinarySI),
+			},
+			backups:  daily,
+			retained: []string{"2024-03-15", "2024-03-14", "2024-03-13"},
+		},
+		{
+			name: "size limit shared with other backups in the storage",
+			retention: api.PXCScheduledBackupRetention{
+				Type:         api.PXCScheduledBackupRetentionCount,
+				MaxTotalSize: resource.NewQuantity(300, resource.BinarySI),
+			},
+			backups: daily,
+			others: []api.PerconaXtraDBClusterBackup{
+				newBackup("manual", time.Date(2024, 3, 14, 12, 0, 0, 0, time.UTC), api.BackupSucceeded),
+				newBackup("failed", time.Date(2024, 3, 15, 11, 0, 0, 0, time.UTC), api.BackupFailed),
+			},
+			retained: []string{"2024-03-15", "2024-03-14"},
+		},
+		{
+			name: "other backups don't delete the latest backup",
+			retention: api.PXCScheduledBackupRetention{
+				Type:         api.PXCScheduledBackupRetentionCount,
+				MaxTotalSize: resource.NewQuantity(100, resource.BinarySI),
+			},
+			backups: daily,
+			others: []api.PerconaXtraDBClusterBackup{
+				newBackup("copy", time.Date(2024, 3, 15, 11, 0, 0, 0, time.UTC), api.BackupSucceeded),
+			},
+			retained: []string{"2024-03-15"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := make(map[string]struct{})
+			for _, bcp := range BackupsToDelete(tt.retention, tt.backups, tt.others, now) {
+				deleted[bcp.Name] = struct{}{}
+			}
+			var retained []string
+			for _, bcp := range tt.backups {
+				if _, ok := deleted[bcp.Name]; !ok {
+					retained = append(retained, bcp.Name)
+				}
+			}
+			assert.Equal(t, tt.retained, retained)
+		})
+	}
+
+	t.Run("oldest first", func(t *testing.T) {
+		toDelete := BackupsToDelete(api.PXCScheduledBackupRetention{
+			Type:  api.PXCScheduledBackupRetentionCount,
+			Count: 57,
+		}, daily, nil, now)
+		assert.Equal(t, []string{"2024-01-16", "2024-01-17", "2024-01-18"}, names(toDelete))
+	})
+
+	t.Run("count by creation time", func(t *testing.T) {
+		// the older backup completed after the newer one
+		long := newBackup("long", now.Add(-time.Hour), api.BackupSucceeded)
+		long.CreationTimestamp = metav1.NewTime(now.Add(-5 * time.Hour))
+		short := newBackup("short", now.Add(-2*time.Hour), api.BackupSucceeded)
+
+		toDelete := BackupsToDelete(api.PXCScheduledBackupRetention{
+			Type:  api.PXCScheduledBackupRetentionCount,
+			Count: 1,
+		}, []api.PerconaXtraDBClusterBackup{long, short}, nil, now)
+		assert.Equal(t, []string{"long"}, names(toDelete))
+	})
+
+	t.Run("incremental chains", func(t *testing.T) {
+		full := newBackup("full", now.Add(-4*time.Hour), api.BackupSucceeded)
+		incr1 := newBackup("incr1", now.Add(-3*time.Hour), api.BackupSucceeded)
+		incr1.Status.BaseChain = []api.PXCBackupDestination{full.Status.Destination}
+		incr2 := newBackup("incr2", now.Add(-2*time.Hour), api.BackupSucceeded)
+		incr2.Status.BaseChain = []api.PXCBackupDestination{full.Status.Destination, incr1.Status.Destination}
+		failed := newBackup("failed", now.Add(-time.Hour), api.BackupFailed)
+		failed.Status.BaseChain = []api.PXCBackupDestination{full.Status.Destination}
+		oldFull := newBackup("old-full", now.Add(-48*time.Hour), api.BackupSucceeded)
+
+		retention := api.PXCScheduledBackupRetention{
+			Type:  api.PXCScheduledBackupRetentionCount,
+			Count: 1,
+		}
+
+		toDelete := BackupsToDelete(retention, []api.PerconaXtraDBClusterBackup{oldFull, full, incr1, incr2, failed}, nil, now)
+		assert.Equal(t, []string{"old-full"}, names(toDelete))
+
+		running := newBackup("running", now, api.BackupRunning)
+		running.Status.BaseChain = []api.PXCBackupDestination{oldFull.Status.Destination}
+		toDelete = BackupsToDelete(retention, []api.PerconaXtraDBClusterBackup{oldFull, full, incr1, incr2, running}, nil, now)
+		assert.Empty(t, toDelete)
+	})
+}
@@ -0,0 +1,34 @@
+package backup
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/users"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/util"
+)
+
+// SetSource makes the backup job take the backup from the pod of the source cluster
+// instead of the node selected by the job itself.
+// The source cluster is a replica cluster if the backup is taken from the async replica.
+func SetSource(spec *batchv1.JobSpec, sourceCluster *api.PerconaXtraDBCluster, pod string) {
+	container := &spec.Template.Spec.Containers[0]
+	container.Env = util.MergeEnvLists(container.Env, []corev1.EnvVar{
+		{
+			Name:  "BACKUP_SOURCE_NODE",
+			Value: pod,
+		},
+		{
+			Name:  "PXC_SERVICE",
+			Value: sourceCluster.Name + "-pxc",
+		},
+		{
+			Name: "PXC_PASS",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: app.SecretKeySelector(sourceCluster.Spec.SecretsName, users.Xtrabackup),
+			},
+		},
+	})
+}
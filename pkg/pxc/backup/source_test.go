@@ -0,0 +1,59 @@
+package backup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/users"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/version"
+)
+
+func TestSetSource(t *testing.T) {
+	cluster := newLogicalTestCluster(t, version.Version())
+
+	cr := &pxcv1.PerconaXtraDBClusterBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-backup",
+			Namespace: "test-ns",
+		},
+		Spec: pxcv1.PXCBackupSpec{
+			PXCCluster:  "test-cluster",
+			StorageName: "test-storage",
+		},
+	}
+
+	bcp := New(cluster)
+	job := bcp.Job(cr, cluster)
+	spec, err := bcp.JobSpec(cr.Spec, cluster, job, "init-image")
+	require.NoError(t, err)
+
+	replica := cluster.DeepCopy()
+	replica.Name = "replica-cluster"
+	replica.Spec.SecretsName = "replica-secrets"
+
+	SetSource(&spec, replica, "replica-cluster-pxc-1")
+
+	env := spec.Template.Spec.Containers[0].Env
+	assert.Contains(t, env, corev1.EnvVar{Name: "BACKUP_SOURCE_NODE", Value: "replica-cluster-pxc-1"})
+	assert.Contains(t, env, corev1.EnvVar{Name: "PXC_SERVICE", Value: "replica-cluster-pxc"})
+	assert.Contains(t, env, corev1.EnvVar{
+		Name: "PXC_PASS",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: app.SecretKeySelector("replica-secrets", users.Xtrabackup),
+		},
+	})
+
+	// the envs of the cluster are replaced
+	names := map[string]int{}
+	for _, e := range env {
+		names[e.Name]++
+	}
+	assert.Equal(t, 1, names["PXC_SERVICE"])
+	assert.Equal(t, 1, names["PXC_PASS"])
+}
@@ -18,6 +18,10 @@ func NewStorage(_ context.Context, opts storage.Options) (storage.Storage, error
 		if opts.Container == "" {
 			return nil, errors.New("container name is empty")
 		}
+	case *storage.GCSOptions:
+		if opts.BucketName == "" {
+			return nil, errors.New("bucket name is empty")
+		}
 	}
 	return &Storage{}, nil
 }
@@ -38,6 +42,10 @@ func (c *Storage) ListObjects(_ context.Context, _ string) ([]string, error) {
 	return nil, nil
 }
 
+func (c *Storage) ObjectsSize(_ context.Context, _ string) (int64, error) {
+	return 0, nil
+}
+
 func (c *Storage) DeleteObject(_ context.Context, _ string) error {
 	return nil
 }
@@ -0,0 +1,421 @@
+package storage
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	gcsDefaultEndpoint = "https://storage.googleapis.com"
+	gcsDefaultTokenURL = "https://oauth2.googleapis.com/token"
+	gcsScopeReadWrite  = "https://www.googleapis.com/auth/devstorage.read_write"
+
+	gcsMetadataHost = "metadata.google.internal"
+)
+
+// GCS is a type for working with Google Cloud Storage using its JSON API
+type GCS struct {
+	client   *http.Client
+	endpoint string
+	bucket   string
+	prefix   string
+}
+
+// NewGCS returns new Storage for Google Cloud Storage.
+// If credentialsJSON is empty, the access token is taken from the metadata server (e.g. GKE workload identity).
+func NewGCS(ctx context.Context, credentialsJSON []byte, endpoint, bucket, prefix string, verifyTLS bool) (Storage, error) {
+	if bucket == "" {
+		return nil, errors.New("bucket name is not set")
+	}
+	if endpoint == "" {
+		endpoint = gcsDefaultEndpoint
+	}
+
+	transport := gcsTransport(verifyTLS)
+
+	ts, err := NewGCSTokenSource(ctx, credentialsJSON, verifyTLS)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GCS{
+		client: &http.Client{
+			Transport: &oauth2.Transport{
+				Source: ts,
+				Base:   transport,
+			},
+		},
+		endpoint: strings.TrimRight(endpoint, "/"),
+		bucket:   bucket,
+		prefix:   prefix,
+	}, nil
+}
+
+// NewGCSTokenSource returns the source of access tokens for Google Cloud Storage.
+// If credentialsJSON is empty, the tokens are taken from the metadata server (e.g. GKE workload identity).
+func NewGCSTokenSource(ctx context.Context, credentialsJSON []byte, verifyTLS bool) (oauth2.TokenSource, error) {
+	if len(credentialsJSON) == 0 {
+		return oauth2.ReuseTokenSource(nil, &gcsMetadataTokenSource{client: &http.Client{Timeout: 10 * time.Second}}), nil
+	}
+
+	cfg, err := gcsJWTConfig(credentialsJSON)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse credentials")
+	}
+
+	// token requests shouldn't be interrupted when the caller's context is canceled,
+	// tokens are reused by the client for the subsequent requests
+	tokenCtx := context.WithValue(context.WithoutCancel(ctx), oauth2.HTTPClient, &http.Client{Transport: gcsTransport(verifyTLS)})
+
+	return cfg.TokenSource(tokenCtx), nil
+}
+
+func gcsTransport(verifyTLS bool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: !verifyTLS,
+	}
+	return transport
+}
+
+// gcsJWTConfig parses service account key in JSON format
+func gcsJWTConfig(credentialsJSON []byte) (*jwt.Config, error) {
+	key := struct {
+		Type         string `json:"type"`
+		ClientEmail  string `json:"client_email"`
+		PrivateKey   string `json:"private_key"`
+		PrivateKeyID string `json:"private_key_id"`
+		TokenURI     string `json:"token_uri"`
+	}{}
+	if err := json.Unmarshal(credentialsJSON, &key); err != nil {
+		return nil, errors.Wrap(err, "unmarshal service account key")
+	}
+	if key.Type != "service_account" {
+		return nil, errors.Errorf("unsupported credentials type %q", key.Type)
+	}
+
+	tokenURL := key.TokenURI
+	if tokenURL == "" {
+		tokenURL = gcsDefaultTokenURL
+	}
+
+	return &jwt.Config{
+		Email:        key.ClientEmail,
+		PrivateKey:   []byte(key.PrivateKey),
+		PrivateKeyID: key.PrivateKeyID,
+		Scopes:       []string{gcsScopeReadWrite},
+		TokenURL:     tokenURL,
+	}, nil
+}
+
+// gcsMetadataTokenSource fetches access tokens of the default service account from the metadata server
+type gcsMetadataTokenSource struct {
+	client *http.Client
+}
+
+func (s *gcsMetadataTokenSource) Token() (*oauth2.Token, error) {
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == "" {
+		host = gcsMetadataHost
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "http://"+host+"/computeMetadata/v1/instance/service-accounts/default/token", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "new request")
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "get token from metadata server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, gcsResponseError(resp)
+	}
+
+	tok := struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		TokenType   string `json:"token_type"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return nil, errors.Wrap(err, "decode token")
+	}
+
+	return &oauth2.Token{
+		AccessToken: tok.AccessToken,
+		TokenType:   tok.TokenType,
+		Expiry:      time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second),
+	}, nil
+}
+
+func gcsResponseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return errors.Errorf("unexpected response status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
+
+func (g *GCS) objectURL(name string) string {
+	return fmt.Sprintf("%s/storage/v1/b/%s/o/%s", g.endpoint, url.PathEscape(g.bucket), url.PathEscape(name))
+}
+
+// GetObject return content by given object name
+func (g *GCS) GetObject(ctx context.Context, name string) (io.ReadCloser, error) {
+	objPath := path.Join(g.prefix, name)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.objectURL(objPath)+"?alt=media", nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "new request for object %s", objPath)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get object %s", objPath)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrObjectNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, errors.Wrapf(gcsResponseError(resp), "get object %s", objPath)
+	}
+}
+
+// PutObject puts new object to storage with given name and content
+func (g *GCS) PutObject(ctx context.Context, name string, data io.Reader, size int64) error {
+	objPath := path.Join(g.prefix, name)
+
+	u := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s", g.endpoint, url.PathEscape(g.bucket), url.QueryEscape(objPath))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, data)
+	if err != nil {
+		return errors.Wrapf(err, "new request for object %s", objPath)
+	}
+	if size > 0 {
+		req.ContentLength = size
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "put object %s", objPath)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrapf(gcsResponseError(resp), "put object %s", objPath)
+	}
+
+	return nil
+}
+
+func (g *GCS) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	list := []string{}
+	err := g.listObjects(ctx, prefix, func(name string, _ int64) {
+		list = append(list, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (g *GCS) ObjectsSize(ctx context.Context, prefix string) (int64, error) {
+	var size int64
+	err := g.listObjects(ctx, prefix, func(_ string, objSize int64) {
+		size += objSize
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// listObjects calls fn for each object with the prefix. The name passed to fn is relative to the storage prefix.
+func (g *GCS) listObjects(ctx context.Context, prefix string, fn func(name string, size int64)) error {
+	pageToken := ""
+	for {
+		q := url.Values{}
+		q.Set("prefix", g.prefix+prefix)
+		q.Set("fields", "items(name,size),nextPageToken")
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+
+		u := fmt.Sprintf("%s/storage/v1/b/%s/o?%s", g.endpoint, url.PathEscape(g.bucket), q.Encode())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return errors.Wrap(err, "new list request")
+		}
+
+		resp, err := g.client.Do(req)
+		if err != nil {
+			return errors.Wrapf(err, "list objects %s", prefix)
+		}
+
+		page := struct {
+			Items []struct {
+				Name string `json:"name"`
+				// GCS JSON API returns the size as a string
+				Size int64 `json:"size,string,omitempty"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}{}
+
+		if resp.StatusCode != http.StatusOK {
+			err = errors.Wrapf(gcsResponseError(resp), "list objects %s", prefix)
+		} else if derr := json.NewDecoder(resp.Body).Decode(&page); derr != nil {
+			err = errors.Wrapf(derr, "decode list of objects %s", prefix)
+		}
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		for _, item := range page.Items {
+			fn(strings.TrimPrefix(item.Name, g.prefix), item.Size)
+		}
+
+		if page.NextPageToken == "" {
+			return nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func (g *GCS) SetPrefix(prefix string) {
+	g.prefix = prefix
+}
+
+func (g *GCS) GetPrefix() string {
+	return g.prefix
+}
+
+func (g *GCS) DeleteObject(ctx context.Context, objectName string) error {
+	log := logf.FromContext(ctx).WithValues("bucket", g.bucket, "prefix", g.prefix)
+
+	objPath := path.Join(g.prefix, objectName)
+	log.V(1).Info("deleting object", "object", objPath)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, g.objectURL(objPath), nil)
+	if err != nil {
+		return errors.Wrapf(err, "new request for object %s", objPath)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove object %s", objectName)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+	case http.StatusNotFound:
+		return ErrObjectNotFound
+	default:
+		return errors.Wrapf(gcsResponseError(resp), "failed to remove object %s", objectName)
+	}
+
+	log.V(1).Info("object deleted", "object", objPath)
+
+	return nil
+}
+
+// GCSProxyHMACKey is passed to xbcloud as both HMAC keys when its requests go through the GCS proxy.
+// The proxy replaces the request signature with an access token, so the value is never checked.
+const GCSProxyHMACKey = "oauth2"
+
+// gcsSignatureHeaders are set by xbcloud to sign requests with HMAC keys
+var gcsSignatureHeaders = []string{
+	"Authorization",
+	"X-Amz-Date",
+	"X-Amz-Content-Sha256",
+	"X-Goog-Date",
+	"X-Goog-Content-Sha256",
+}
+
+// NewGCSProxy returns the handler which forwards xbcloud requests to the XML API of Google Cloud Storage
+// and authorizes them with access tokens instead of HMAC signatures.
+// xbcloud supports only HMAC keys, the proxy allows it to use service account keys and workload identity.
+// xbcloud should use the path-style URLs, which it does for the custom endpoint.
+func NewGCSProxy(ts oauth2.TokenSource, endpoint string, verifyTLS bool) (http.Handler, error) {
+	if endpoint == "" {
+		endpoint = gcsDefaultEndpoint
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse endpoint")
+	}
+
+	return &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(target)
+			for _, h := range gcsSignatureHeaders {
+				r.Out.Header.Del(h)
+			}
+		},
+		Transport: &oauth2.Transport{
+			Source: ts,
+			Base:   gcsTransport(verifyTLS),
+		},
+	}, nil
+}
+
+// StartGCSProxy starts the GCS proxy on a random local port and returns its URL.
+// The proxy is stopped when ctx is canceled.
+func StartGCSProxy(ctx context.Context, credentialsJSON []byte, endpoint string, verifyTLS bool) (string, error) {
+	ts, err := NewGCSTokenSource(ctx, credentialsJSON, verifyTLS)
+	if err != nil {
+		return "", errors.Wrap(err, "new token source")
+	}
+	handler, err := NewGCSProxy(ts, endpoint, verifyTLS)
+	if err != nil {
+		return "", errors.Wrap(err, "new proxy")
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", errors.Wrap(err, "listen")
+	}
+
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: time.Minute,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	go func() {
+		if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logf.FromContext(ctx).Error(err, "gcs proxy failed")
+		}
+	}()
+
+	return "http://" + l.Addr().String(), nil
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+// fakeGCSServer implements a subset of the GCS JSON API used by GCS storage
+type fakeGCSServer struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string][]byte
+}
+
+func (f *fakeGCSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	uploadPrefix := "/upload/storage/v1/b/" + f.bucket + "/o"
+	objectsPrefix := "/storage/v1/b/" + f.bucket + "/o"
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == uploadPrefix:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Query().Get("name")] = data
+		_, _ = w.Write([]byte("{}"))
+	case r.Method == http.MethodGet && r.URL.Path == objectsPrefix:
+		prefix := r.URL.Query().Get("prefix")
+		names := []string{}
+		for name := range f.objects {
+			if strings.HasPrefix(name, prefix) {
+				names = append(names, `{"name":"`+name+`","size":"`+strconv.Itoa(len(f.objects[name]))+`"}`)
+			}
+		}
+		sort.Strings(names)
+		_, _ = w.Write([]byte(`{"items":[` + strings.Join(names, ",") + `]}`))
+	case strings.HasPrefix(r.URL.Path, objectsPrefix+"/"):
+		name := strings.TrimPrefix(r.URL.Path, objectsPrefix+"/")
+		data, ok := f.objects[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write(data)
+		case http.MethodDelete:
+			delete(f.objects, name)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestGCS(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(&fakeGCSServer{bucket: "my-bucket", objects: map[string][]byte{}})
+	defer srv.Close()
+
+	s := &GCS{
+		client:   srv.Client(),
+		endpoint: srv.URL,
+		bucket:   "my-bucket",
+		prefix:   "prefix/",
+	}
+
+	for _, name := range []string{"backup/xtrabackup_info", "backup/ibdata1", "backup.sst_info/sst_info"} {
+		if err := s.PutObject(ctx, name, strings.NewReader(name), int64(len(name))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list, err := s.ListObjects(ctx, "backup/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"backup/ibdata1", "backup/xtrabackup_info"}
+	if !reflect.DeepEqual(list, expected) {
+		t.Fatalf("expected: %v, got: %v", expected, list)
+	}
+
+	size, err := s.ObjectsSize(ctx, "backup/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := int64(len("backup/xtrabackup_info") + len("backup/ibdata1")); size != expected {
+		t.Fatalf("expected size: %d, got: %d", expected, size)
+	}
+
+	r, err := s.GetObject(ctx, "backup/xtrabackup_info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "backup/xtrabackup_info" {
+		t.Fatalf("unexpected object content: %s", data)
+	}
+
+	if err := s.DeleteObject(ctx, "backup/xtrabackup_info"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteObject(ctx, "backup/xtrabackup_info"); !errors.Is(err, ErrObjectNotFound) {
+		t.Fatalf("expected ErrObjectNotFound, got: %v", err)
+	}
+	if _, err := s.GetObject(ctx, "backup/xtrabackup_info"); !errors.Is(err, ErrObjectNotFound) {
+		t.Fatalf("expected ErrObjectNotFound, got: %v", err)
+	}
+}
+
+func TestGCSProxy(t *testing.T) {
+	type request struct {
+		method, path, auth, date string
+		body                     string
+	}
+	var got []request
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, request{r.Method, r.URL.Path, r.Header.Get("Authorization"), r.Header.Get("X-Amz-Date"), string(body)})
+	}))
+	defer upstream.Close()
+
+	handler, err := NewGCSProxy(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token", TokenType: "Bearer"}), upstream.URL, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := httptest.NewServer(handler)
+	defer proxy.Close()
+
+	req, err := http.NewRequest(http.MethodPut, proxy.URL+"/my-bucket/backup/ibdata1.00000000000000000000", strings.NewReader("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "GOOG1 "+GCSProxyHMACKey+":signature")
+	req.Header.Set("X-Amz-Date", "20261017T000000Z")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	expected := []request{{http.MethodPut, "/my-bucket/backup/ibdata1.00000000000000000000", "Bearer token", "", "data"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
@@ -110,6 +110,34 @@ func (_m *Storage) ListObjects(ctx context.Context, prefix string) ([]string, er
 	return r0, r1
 }
 
+// ObjectsSize provides a mock function with given fields: ctx, prefix
+func (_m *Storage) ObjectsSize(ctx context.Context, prefix string) (int64, error) {
+	ret := _m.Called(ctx, prefix)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ObjectsSize")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, prefix)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, prefix)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PutObject provides a mock function with given fields: ctx, name, data, size
 func (_m *Storage) PutObject(ctx context.Context, name string, data io.Reader, size int64) error {
 	ret := _m.Called(ctx, name, data, size)
@@ -27,6 +27,8 @@ func GetOptions(ctx context.Context, cl client.Client, cluster *api.PerconaXtraD
 		return getS3Options(ctx, cl, cluster, stg.S3, stg.VerifyTLS)
 	case api.BackupStorageAzure:
 		return getAzureOptions(ctx, cl, cluster, stg.Azure)
+	case api.BackupStorageGCS:
+		return getGCSOptions(ctx, cl, cluster, stg.GCS, stg.VerifyTLS)
 	default:
 		return nil, errors.Errorf("unknown storage type %s", stg.Type)
 	}
@@ -38,6 +40,8 @@ func GetOptionsFromBackup(ctx context.Context, cl client.Client, cluster *api.Pe
 		return getS3OptionsFromBackup(ctx, cl, cluster, backup)
 	case backup.Status.Azure != nil:
 		return getAzureOptionsFromBackup(ctx, cl, backup)
+	case backup.Status.GCS != nil:
+		return getGCSOptionsFromBackup(ctx, cl, cluster, backup)
 	default:
 		return nil, errors.Errorf("unknown storage type %s", backup.Status.StorageType)
 	}
@@ -63,6 +67,12 @@ func GetOptionsFromBackupConfig(cfg *xbscapi.BackupConfig) (Options, error) {
 			Endpoint:       cfg.Azure.EndpointUrl,
 			Container:      cfg.Azure.ContainerName,
 		}, nil
+	case xbscapi.BackupStorageType_GCS:
+		return &GCSOptions{
+			Endpoint:   cfg.Gcs.EndpointUrl,
+			BucketName: cfg.Gcs.Bucket,
+			VerifyTLS:  cfg.VerifyTls,
+		}, nil
 	default:
 		return nil, errors.Errorf("unknown storage type %s", cfg.Type)
 	}
@@ -131,6 +141,88 @@ func getAzureOptionsFromBackup(ctx context.Context, cl client.Client, backup *ap
 	}, nil
 }
 
+func getGCSOptions(
+	ctx context.Context,
+	cl client.Client,
+	cluster *api.PerconaXtraDBCluster,
+	gcs *api.BackupStorageGCSSpec,
+	verifyTLS *bool,
+) (*GCSOptions, error) {
+	var credentialsJSON []byte
+	if gcs.CredentialsSecret != "" {
+		secret := new(corev1.Secret)
+		err := cl.Get(ctx, types.NamespacedName{
+			Name:      gcs.CredentialsSecret,
+			Namespace: cluster.Namespace,
+		}, secret)
+		if client.IgnoreNotFound(err) != nil {
+			return nil, errors.Wrap(err, "failed to get secret")
+		}
+		credentialsJSON = secret.Data["GCS_CREDENTIALS_JSON"]
+	}
+
+	bucket, prefix := gcs.BucketAndPrefix()
+	if bucket == "" {
+		return nil, errors.New("bucket name is not set")
+	}
+
+	verify := true
+	if verifyTLS != nil && !*verifyTLS {
+		verify = false
+	}
+
+	return &GCSOptions{
+		CredentialsJSON: credentialsJSON,
+		Endpoint:        gcs.EndpointURL,
+		BucketName:      bucket,
+		Prefix:          prefix,
+		VerifyTLS:       verify,
+	}, nil
+}
+
+func getGCSOptionsFromBackup(ctx context.Context, cl client.Client, cluster *api.PerconaXtraDBCluster, backup *api.PerconaXtraDBClusterBackup) (*GCSOptions, error) {
+	var credentialsJSON []byte
+	if backup.Status.GCS.CredentialsSecret != "" {
+		secret := new(corev1.Secret)
+		err := cl.Get(ctx, types.NamespacedName{
+			Name:      backup.Status.GCS.CredentialsSecret,
+			Namespace: backup.Namespace,
+		}, secret)
+		if client.IgnoreNotFound(err) != nil {
+			return nil, errors.Wrap(err, "failed to get secret")
+		}
+		credentialsJSON = secret.Data["GCS_CREDENTIALS_JSON"]
+	}
+
+	bucket, prefix := backup.Status.GCS.BucketAndPrefix()
+	if bucket == "" {
+		bucket, prefix = backup.Status.Destination.BucketAndPrefix()
+	}
+
+	if bucket == "" {
+		return nil, errors.New("bucket name is not set")
+	}
+
+	verifyTLS := true
+	if backup.Status.VerifyTLS != nil && !*backup.Status.VerifyTLS {
+		verifyTLS = false
+	}
+	if cluster != nil && cluster.Spec.Backup != nil && len(cluster.Spec.Backup.Storages) > 0 {
+		storage, ok := cluster.Spec.Backup.Storages[backup.Spec.StorageName]
+		if ok && storage.VerifyTLS != nil {
+			verifyTLS = *storage.VerifyTLS
+		}
+	}
+
+	return &GCSOptions{
+		CredentialsJSON: credentialsJSON,
+		Endpoint:        backup.Status.GCS.EndpointURL,
+		BucketName:      bucket,
+		Prefix:          prefix,
+		VerifyTLS:       verifyTLS,
+	}, nil
+}
+
 func getS3CABundle(ctx context.Context, cl client.Client, s3 *api.BackupStorageS3Spec, namespace string) ([]byte, error) {
 	selector := s3.CABundle
 	if selector == nil {
@@ -315,3 +407,17 @@ type AzureOptions struct {
 func (o *AzureOptions) Type() api.BackupStorageType {
 	return api.BackupStorageAzure
 }
+
+var _ = Options(new(GCSOptions))
+
+type GCSOptions struct {
+	CredentialsJSON []byte
+	Endpoint        string
+	BucketName      string
+	Prefix          string
+	VerifyTLS       bool
+}
+
+func (o *GCSOptions) Type() api.BackupStorageType {
+	return api.BackupStorageGCS
+}
@@ -349,6 +349,146 @@ func TestGetAzureOptions(t *testing.T) {
 	}
 }
 
+func TestGetGCSOptions(t *testing.T) {
+	ctx := context.Background()
+
+	const ns = "my-ns"
+
+	const storageName = "my-storage"
+	const secretName = "my-secret"
+	const credentialsJSON = `{"type":"service_account"}`
+
+	boolPtr := func(b bool) *bool { return &b }
+
+	tests := []struct {
+		name              string
+		destination       string
+		bucket            string
+		credentialsSecret string
+		credentialsJSON   string
+		endpoint          string
+		verifyTLS         *bool
+		storage           *api.BackupStorageSpec
+
+		expected    *GCSOptions
+		expectedErr string
+	}{
+		{
+			name:     "no secret",
+			bucket:   "my-bucket",
+			endpoint: "some-endpoint",
+			expected: &GCSOptions{
+				Endpoint:   "some-endpoint",
+				BucketName: "my-bucket",
+				VerifyTLS:  true,
+			},
+		},
+		{
+			name:              "with secret",
+			bucket:            "my-bucket",
+			credentialsSecret: secretName,
+			credentialsJSON:   credentialsJSON,
+			expected: &GCSOptions{
+				CredentialsJSON: []byte(credentialsJSON),
+				BucketName:      "my-bucket",
+				VerifyTLS:       true,
+			},
+		},
+		{
+			name:              "secret doesn't exist",
+			bucket:            "my-bucket",
+			credentialsSecret: secretName,
+			expected: &GCSOptions{
+				BucketName: "my-bucket",
+				VerifyTLS:  true,
+			},
+		},
+		{
+			name:   "bucket with prefix",
+			bucket: "my-bucket/prefix",
+			expected: &GCSOptions{
+				BucketName: "my-bucket",
+				Prefix:     "prefix/",
+				VerifyTLS:  true,
+			},
+		},
+		{
+			name:        "destination with bucket",
+			destination: "gs://invalid-bucket/prefix/backup-name",
+			bucket:      "my-bucket",
+			expected: &GCSOptions{
+				BucketName: "my-bucket",
+				VerifyTLS:  true,
+			},
+		},
+		{
+			name:        "destination with prefix",
+			destination: "gs://destination-bucket/prefix/backup-name",
+			expected: &GCSOptions{
+				BucketName: "destination-bucket",
+				Prefix:     "prefix/",
+				VerifyTLS:  true,
+			},
+		},
+		{
+			name:        "no destination",
+			expectedErr: "bucket name is not set",
+		},
+		{
+			name:      "verifyTLS in backup and cluster",
+			bucket:    "my-bucket",
+			verifyTLS: boolPtr(true),
+			storage: &api.BackupStorageSpec{
+				VerifyTLS: boolPtr(false),
+			},
+			expected: &GCSOptions{
+				BucketName: "my-bucket",
+				VerifyTLS:  false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backup := testBackup(ns, storageName, tt.destination, tt.verifyTLS, nil, nil)
+			backup.Status.GCS = &api.BackupStorageGCSSpec{
+				Bucket:            tt.bucket,
+				CredentialsSecret: tt.credentialsSecret,
+				EndpointURL:       tt.endpoint,
+			}
+
+			var cluster *api.PerconaXtraDBCluster
+			if tt.storage != nil {
+				cluster = &api.PerconaXtraDBCluster{
+					Spec: api.PerconaXtraDBClusterSpec{
+						Backup: &api.BackupSpec{
+							Storages: map[string]*api.BackupStorageSpec{
+								storageName: tt.storage,
+							},
+						},
+					},
+				}
+			}
+
+			objs := []runtime.Object{}
+			if tt.credentialsJSON != "" {
+				objs = append(objs, testSecret(ns, secretName, map[string][]byte{
+					"GCS_CREDENTIALS_JSON": []byte(tt.credentialsJSON),
+				}))
+			}
+			cl := fake.NewClientBuilder().WithRuntimeObjects(objs...).Build()
+
+			opts, err := getGCSOptionsFromBackup(ctx, cl, cluster, backup)
+			if err != nil && tt.expectedErr != err.Error() {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(opts, tt.expected) {
+				t.Fatalf("expected: %+v, got: %+v", tt.expected, opts)
+			}
+		})
+	}
+}
+
 func testSecret(ns string, name string, data map[string][]byte) *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -28,6 +28,8 @@ type Storage interface {
 	GetObject(ctx context.Context, objectName string) (io.ReadCloser, error)
 	PutObject(ctx context.Context, name string, data io.Reader, size int64) error
 	ListObjects(ctx context.Context, prefix string) ([]string, error)
+	// ObjectsSize returns the total size in bytes of the objects with the prefix.
+	ObjectsSize(ctx context.Context, prefix string) (int64, error)
 	DeleteObject(ctx context.Context, objectName string) error
 	SetPrefix(prefix string)
 	GetPrefix() string
@@ -49,6 +51,12 @@ func NewClient(ctx context.Context, opts Options) (Storage, error) {
 			return nil, errors.New("invalid options type")
 		}
 		return NewAzure(opts.StorageAccount, opts.AccessKey, opts.Endpoint, opts.Container, opts.Prefix, opts.BlockSize, opts.Concurrency)
+	case api.BackupStorageGCS:
+		opts, ok := opts.(*GCSOptions)
+		if !ok {
+			return nil, errors.New("invalid options type")
+		}
+		return NewGCS(ctx, opts.CredentialsJSON, opts.Endpoint, opts.BucketName, opts.Prefix, opts.VerifyTLS)
 	}
 	return nil, errors.New("invalid storage type")
 }
@@ -198,6 +206,32 @@ func (s *S3) ListObjects(ctx context.Context, prefix string) ([]string, error) {
 	return list, nil
 }
 
+func (s *S3) ObjectsSize(ctx context.Context, prefix string) (int64, error) {
+	opts := minio.ListObjectsOptions{
+		UseV1:     true,
+		Recursive: true,
+		Prefix:    s.prefix + prefix,
+	}
+
+	var size int64
+	var err error
+	for object := range s.client.ListObjects(ctx, s.bucketName, opts) {
+		// the channel must be drained, see ListObjects
+		if err != nil {
+			continue
+		}
+		if object.Err != nil {
+			err = errors.Wrapf(object.Err, "list object %s", object.Key)
+		}
+		size += object.Size
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
 func (s *S3) SetPrefix(prefix string) {
 	s.prefix = prefix
 }
@@ -310,6 +344,28 @@ func (a *Azure) ListObjects(ctx context.Context, prefix string) ([]string, error
 	return blobs, nil
 }
 
+func (a *Azure) ObjectsSize(ctx context.Context, prefix string) (int64, error) {
+	listPrefix := path.Join(a.prefix, prefix)
+	pg := a.client.NewListBlobsFlatPager(a.container, &container.ListBlobsFlatOptions{
+		Prefix: &listPrefix,
+	})
+	var size int64
+	for pg.More() {
+		resp, err := pg.NextPage(ctx)
+		if err != nil {
+			return 0, errors.Wrapf(err, "next page: %s", prefix)
+		}
+		if resp.Segment != nil {
+			for _, item := range resp.Segment.BlobItems {
+				if item != nil && item.Properties != nil && item.Properties.ContentLength != nil {
+					size += *item.Properties.ContentLength
+				}
+			}
+		}
+	}
+	return size, nil
+}
+
 func (a *Azure) SetPrefix(prefix string) {
 	a.prefix = prefix
 }
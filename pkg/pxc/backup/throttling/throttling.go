@@ -0,0 +1,101 @@
+package throttling
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+)
+
+const (
+	EnvUploadRateLimit   = "UPLOAD_RATE_LIMIT"
+	EnvDownloadRateLimit = "DOWNLOAD_RATE_LIMIT"
+	EnvParallel          = "XBCLOUD_PARALLEL"
+	EnvThrottle          = "XB_THROTTLE"
+)
+
+// Config is the throttling configuration passed to backup, restore and binlog collector containers.
+type Config struct {
+	// UploadRateLimit is in bytes per second.
+	UploadRateLimit int64 `env:"UPLOAD_RATE_LIMIT"`
+	// DownloadRateLimit is in bytes per second.
+	DownloadRateLimit int64 `env:"DOWNLOAD_RATE_LIMIT"`
+	Parallel          int   `env:"XBCLOUD_PARALLEL"`
+	Throttle          int   `env:"XB_THROTTLE"`
+}
+
+// Envs returns env variables with the throttling configuration of the storage.
+func Envs(spec *api.BackupThrottlingSpec) []corev1.EnvVar {
+	if spec == nil {
+		return nil
+	}
+
+	var envs []corev1.EnvVar
+	if spec.UploadRateLimit != nil && spec.UploadRateLimit.Value() > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  EnvUploadRateLimit,
+			Value: strconv.FormatInt(spec.UploadRateLimit.Value(), 10),
+		})
+	}
+	if spec.DownloadRateLimit != nil && spec.DownloadRateLimit.Value() > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  EnvDownloadRateLimit,
+			Value: strconv.FormatInt(spec.DownloadRateLimit.Value(), 10),
+		})
+	}
+	if spec.Parallel > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  EnvParallel,
+			Value: strconv.Itoa(int(spec.Parallel)),
+		})
+	}
+	if spec.ThrottleIOPS > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  EnvThrottle,
+			Value: strconv.Itoa(int(spec.ThrottleIOPS)),
+		})
+	}
+
+	return envs
+}
+
+// maxBurst is the maximal number of bytes read at once by the rate limited reader.
+const maxBurst = 256 * 1024
+
+type reader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *rate.Limiter
+}
+
+// NewReader returns a reader which reads from r not faster than bytesPerSecond.
+// The reader is not limited if bytesPerSecond is not positive.
+func NewReader(ctx context.Context, r io.Reader, bytesPerSecond int64) io.Reader {
+	if bytesPerSecond <= 0 {
+		return r
+	}
+
+	burst := int(min(bytesPerSecond, maxBurst))
+	return &reader{
+		ctx:     ctx,
+		r:       r,
+		limiter: rate.NewLimiter(rate.Limit(bytesPerSecond), burst),
+	}
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if len(p) > r.limiter.Burst() {
+		p = p[:r.limiter.Burst()]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if werr := r.limiter.WaitN(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,54 @@
+package throttling
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+)
+
+func TestEnvs(t *testing.T) {
+	assert.Empty(t, Envs(nil))
+	assert.Empty(t, Envs(&api.BackupThrottlingSpec{}))
+
+	upload := resource.MustParse("10Mi")
+	download := resource.MustParse("50M")
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: EnvUploadRateLimit, Value: "10485760"},
+		{Name: EnvDownloadRateLimit, Value: "50000000"},
+		{Name: EnvParallel, Value: "4"},
+		{Name: EnvThrottle, Value: "200"},
+	}, Envs(&api.BackupThrottlingSpec{
+		UploadRateLimit:   &upload,
+		DownloadRateLimit: &download,
+		Parallel:          4,
+		ThrottleIOPS:      200,
+	}))
+}
+
+func TestNewReader(t *testing.T) {
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("0123456789"), 30*1024)
+
+	r := bytes.NewReader(data)
+	assert.Same(t, r, NewReader(ctx, r, 0))
+
+	// the first burst is read immediately, the rest of the data is limited
+	start := time.Now()
+	out, err := io.ReadAll(NewReader(ctx, bytes.NewReader(data), int64(len(data))))
+	assert.NoError(t, err)
+	assert.Equal(t, data, out)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = io.ReadAll(NewReader(ctx, bytes.NewReader(data), 1024))
+	assert.ErrorIs(t, err, context.Canceled)
+}
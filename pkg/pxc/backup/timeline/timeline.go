@@ -0,0 +1,246 @@
+package timeline
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	BinlogPrefix        = "binlog_"
+	BinlogGTIDSetSuffix = "-gtid-set"
+)
+
+// Binlog is a binlog uploaded to the PITR storage by the binlog collector.
+type Binlog struct {
+	Name string `json:"name"`
+	// FirstEventTime is the time of the first event in the binlog.
+	FirstEventTime time.Time `json:"firstEventTime"`
+	// LastEventTime is the time of the last event in the binlog. The time isn't stored
+	// in the storage, so it's the time of the first event of the next binlog
+	// or the latest uploaded event for the last binlog.
+	LastEventTime time.Time `json:"lastEventTime"`
+	GTIDSet       string    `json:"gtidSet"`
+}
+
+// Window is a period of time the cluster can be restored to.
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Gap is a period of time the cluster can't be restored to because of the missing transactions.
+type Gap struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// After is the last binlog before the gap.
+	After string `json:"after"`
+	// Before is the first binlog after the gap.
+	Before string `json:"before"`
+	// MissingGTIDSet is the set of the transactions which aren't found in the binlogs.
+	MissingGTIDSet string `json:"missingGTIDSet"`
+}
+
+// Timeline describes the periods of time the cluster can be restored to from the binlogs in the storage.
+type Timeline struct {
+	Windows []Window `json:"windows"`
+	Gaps    []Gap    `json:"gaps,omitempty"`
+	// GapDetected is the GTID set the binlog collector couldn't find in the binlogs of the cluster.
+	// It's reported until the operator marks the latest backup as not ready for PITR.
+	GapDetected string `json:"gapDetected,omitempty"`
+}
+
+// Contains checks if the cluster can be restored to the time.
+func (t *Timeline) Contains(tm time.Time) bool {
+	for _, w := range t.Windows {
+		if !tm.Before(w.Start) && !tm.After(w.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseBinlogName returns the time of the first event from the name of the binlog.
+// Binlogs are named binlog_<first event unix timestamp>_<binlog number>_<gtid set md5>.
+func ParseBinlogName(name string) (time.Time, error) {
+	parts := strings.Split(strings.TrimPrefix(name, BinlogPrefix), "_")
+	if len(parts) != 3 {
+		return time.Time{}, errors.Errorf("unexpected binlog name %s", name)
+	}
+	ts, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "parse timestamp of binlog %s", name)
+	}
+	return time.Unix(ts, 0), nil
+}
+
+// New returns the timeline of the binlogs ordered by the time of their first event.
+// The last event time of the binlogs is set from the first event of the next binlog
+// and the latest uploaded event. A gap is reported if the transactions of a binlog
+// don't follow the transactions of the previous binlogs.
+func New(binlogs []Binlog, latest time.Time) *Timeline {
+	t := &Timeline{Windows: []Window{}}
+	if len(binlogs) == 0 {
+		return t
+	}
+
+	for i := range binlogs {
+		switch {
+		case i < len(binlogs)-1:
+			binlogs[i].LastEventTime = binlogs[i+1].FirstEventTime
+		case latest.After(binlogs[i].FirstEventTime):
+			binlogs[i].LastEventTime = latest
+		default:
+			binlogs[i].LastEventTime = binlogs[i].FirstEventTime
+		}
+	}
+
+	window := Window{Start: binlogs[0].FirstEventTime}
+	seen := make(gtidSet)
+	for i, b := range binlogs {
+		set := parseGTIDSet(b.GTIDSet)
+		if i > 0 {
+			if missing := seen.missingBefore(set); missing != "" {
+				window.End = binlogs[i-1].LastEventTime
+				t.Windows = append(t.Windows, window)
+				t.Gaps = append(t.Gaps, Gap{
+					Start:          binlogs[i-1].LastEventTime,
+					End:            b.FirstEventTime,
+					After:          binlogs[i-1].Name,
+					Before:         b.Name,
+					MissingGTIDSet: missing,
+				})
+				window = Window{Start: b.FirstEventTime}
+			}
+		}
+		seen.add(set)
+	}
+	window.End = binlogs[len(binlogs)-1].LastEventTime
+	t.Windows = append(t.Windows, window)
+
+	return t
+}
+
+type interval struct {
+	start, end int64
+}
+
+// gtidSet is the set of transactions by the source UUID.
+type gtidSet map[string][]interval
+
+// parseGTIDSet parses the GTID set in the format of MySQL: uuid:1-5:7,uuid:3.
+// Invalid intervals are ignored.
+func parseGTIDSet(s string) gtidSet {
+	set := make(gtidSet)
+	for _, item := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) < 2 {
+			continue
+		}
+		uuid := strings.ToLower(parts[0])
+		for _, p := range parts[1:] {
+			from, to, found := strings.Cut(p, "-")
+			start, err := strconv.ParseInt(from, 10, 64)
+			if err != nil {
+				continue
+			}
+			end := start
+			if found {
+				end, err = strconv.ParseInt(to, 10, 64)
+				if err != nil {
+					continue
+				}
+			}
+			set[uuid] = append(set[uuid], interval{start, end})
+		}
+	}
+	return set
+}
+
+func (s gtidSet) add(o gtidSet) {
+	for uuid, intervals := range o {
+		s[uuid] = append(s[uuid], intervals...)
+	}
+}
+
+func (s gtidSet) max(uuid string) int64 {
+	var m int64
+	for _, i := range s[uuid] {
+		if i.end > m {
+			m = i.end
+		}
+	}
+	return m
+}
+
+func (s gtidSet) min(uuid string) int64 {
+	var m int64
+	for n, i := range s[uuid] {
+		if n == 0 || i.start < m {
+			m = i.start
+		}
+	}
+	return m
+}
+
+// missingBefore returns the GTID set of the transactions between the transactions of the set
+// and the transactions of the next set. Sources which aren't in the set are ignored,
+// since their transactions can start from any number.
+func (s gtidSet) missingBefore(next gtidSet) string {
+	uuids := make([]string, 0, len(next))
+	for uuid := range next {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+
+	var missing []string
+	for _, uuid := range uuids {
+		if _, ok := s[uuid]; !ok {
+			continue
+		}
+		last, first := s.max(uuid), next.min(uuid)
+		switch {
+		case first == last+2:
+			missing = append(missing, uuid+":"+strconv.FormatInt(last+1, 10))
+		case first > last+2:
+			missing = append(missing, uuid+":"+strconv.FormatInt(last+1, 10)+"-"+strconv.FormatInt(first-1, 10))
+		}
+	}
+	return strings.Join(missing, ",")
+}
+
+// CollectorStatus is the state of the binlog collection reported by the binlog collector.
+type CollectorStatus struct {
+	// Host is the PXC host the binlogs are collected from.
+	Host string `json:"host"`
+	// LastUploadedGTIDSet is the GTID set of the last uploaded binlog.
+	LastUploadedGTIDSet string `json:"lastUploadedGTIDSet"`
+	// LastUploadedEventTime is the time of the newest uploaded event.
+	LastUploadedEventTime time.Time `json:"lastUploadedEventTime"`
+	LastUploadTime        time.Time `json:"lastUploadTime"`
+	// SourceEventTime is the time of the newest event on the host seen by the collector.
+	SourceEventTime time.Time `json:"sourceEventTime"`
+}
+
+// Lag returns how far the uploaded events are behind the events seen on the host.
+func (s *CollectorStatus) Lag() time.Duration {
+	if s.SourceEventTime.IsZero() || s.LastUploadedEventTime.IsZero() || !s.SourceEventTime.After(s.LastUploadedEventTime) {
+		return 0
+	}
+	return s.SourceEventTime.Sub(s.LastUploadedEventTime)
+}
+
+// HasTransactionsAfter checks if the executed GTID set contains transactions
+// after the transactions of the uploaded set. Sources which aren't in the uploaded set are ignored.
+func HasTransactionsAfter(executed, uploaded string) bool {
+	e, u := parseGTIDSet(executed), parseGTIDSet(uploaded)
+	for uuid := range u {
+		if e.max(uuid) > u.max(uuid) {
+			return true
+		}
+	}
+	return false
+}
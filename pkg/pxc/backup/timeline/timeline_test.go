@@ -0,0 +1,75 @@
+package timeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	const uuid = "3e11fa47-71ca-11e1-9e33-c80aa9429562"
+
+	binlogs := []Binlog{
+		{Name: "binlog_1704096000_000011_9f8e", FirstEventTime: time.Unix(1704096000, 0), GTIDSet: uuid + ":1-100"},
+		{Name: "binlog_1704103200_000012_0a1b", FirstEventTime: time.Unix(1704103200, 0), GTIDSet: uuid + ":101-200"},
+		// transactions 201-250 are missing
+		{Name: "binlog_1704110400_000013_5c6d", FirstEventTime: time.Unix(1704110400, 0), GTIDSet: uuid + ":251-300,\nfd0a6e4f-6d3b-11ee-8bfa-0242ac120002:1-5"},
+		{Name: "binlog_1704117600_000014_7e8f", FirstEventTime: time.Unix(1704117600, 0), GTIDSet: uuid + ":301-310:312,fd0a6e4f-6d3b-11ee-8bfa-0242ac120002:7"},
+	}
+
+	tl := New(binlogs, time.Unix(1704120000, 0))
+
+	assert.Equal(t, []Window{
+		{Start: time.Unix(1704096000, 0), End: time.Unix(1704110400, 0)},
+		{Start: time.Unix(1704110400, 0), End: time.Unix(1704117600, 0)},
+		{Start: time.Unix(1704117600, 0), End: time.Unix(1704120000, 0)},
+	}, tl.Windows)
+	assert.Equal(t, []Gap{
+		{
+			Start:          time.Unix(1704110400, 0),
+			End:            time.Unix(1704110400, 0),
+			After:          "binlog_1704103200_000012_0a1b",
+			Before:         "binlog_1704110400_000013_5c6d",
+			MissingGTIDSet: uuid + ":201-250",
+		},
+		{
+			Start:          time.Unix(1704117600, 0),
+			End:            time.Unix(1704117600, 0),
+			After:          "binlog_1704110400_000013_5c6d",
+			Before:         "binlog_1704117600_000014_7e8f",
+			MissingGTIDSet: "fd0a6e4f-6d3b-11ee-8bfa-0242ac120002:6",
+		},
+	}, tl.Gaps)
+	assert.Equal(t, time.Unix(1704103200, 0), binlogs[0].LastEventTime)
+	assert.Equal(t, time.Unix(1704120000, 0), binlogs[3].LastEventTime)
+
+	assert.True(t, tl.Contains(time.Unix(1704100000, 0)))
+	assert.True(t, tl.Contains(time.Unix(1704120000, 0)))
+	assert.False(t, tl.Contains(time.Unix(1704090000, 0)))
+	assert.False(t, tl.Contains(time.Unix(1704120001, 0)))
+}
+
+func TestNewWithoutUploads(t *testing.T) {
+	tl := New(nil, time.Time{})
+	assert.Empty(t, tl.Windows)
+	assert.NotNil(t, tl.Windows)
+
+	// the latest uploaded event is unknown after the restart of the collector
+	binlogs := []Binlog{
+		{Name: "binlog_1704096000_000011_9f8e", FirstEventTime: time.Unix(1704096000, 0), GTIDSet: "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-100"},
+	}
+	tl = New(binlogs, time.Time{})
+	assert.Equal(t, []Window{{Start: time.Unix(1704096000, 0), End: time.Unix(1704096000, 0)}}, tl.Windows)
+	assert.Empty(t, tl.Gaps)
+}
+
+func TestHasTransactionsAfter(t *testing.T) {
+	uploaded := "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-200"
+
+	assert.False(t, HasTransactionsAfter("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-200", uploaded))
+	assert.True(t, HasTransactionsAfter("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-205", uploaded))
+	// transactions of other sources aren't collected from the cluster
+	assert.False(t, HasTransactionsAfter("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-200,4e11fa47-71ca-11e1-9e33-c80aa9429562:1-10", uploaded))
+	assert.False(t, HasTransactionsAfter("3e11fa47-71ca-11e1-9e33-c80aa9429562:1-200", ""))
+}
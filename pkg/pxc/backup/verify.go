@@ -0,0 +1,231 @@
+package backup
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	api "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/features"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/naming"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app/statefulset"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/util"
+)
+
+// VerifyJob creates a Kubernetes Job that verifies the backup is restorable.
+//
+// The backup is downloaded and prepared by recovery-cloud.sh into a scratch volume
+// in an init container. Then mysqld is started against the restored data
+// and the checks configured in the backup verification spec are run.
+func VerifyJob(
+	ctx context.Context,
+	cr *api.PerconaXtraDBClusterBackup,
+	cluster *api.PerconaXtraDBCluster,
+	initImage string,
+	scheme *runtime.Scheme,
+) (*batchv1.Job, error) {
+	switch cr.Status.GetStorageType(cluster) {
+	case api.BackupStorageAzure:
+		if cr.Status.Azure == nil {
+			return nil, errors.New("nil azure backup status storage")
+		}
+	case api.BackupStorageS3:
+		if cr.Status.S3 == nil {
+			return nil, errors.New("nil s3 backup status storage")
+		}
+	case api.BackupStorageGCS:
+		if cr.Status.GCS == nil {
+			return nil, errors.New("nil gcs backup status storage")
+		}
+	default:
+		return nil, errors.Errorf("verification is not supported for %s storage", cr.Status.GetStorageType(cluster))
+	}
+
+	verification := cr.Spec.Verification
+	jobName := naming.BackupVerifyJobName(cr.Name)
+
+	volumes := []corev1.Volume{
+		{
+			Name: app.DataVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		{
+			Name: app.BinVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+		app.GetSecretVolumes("vault-keyring-secret", cr.Status.VaultSecretName, true),
+	}
+	commonMounts := []corev1.VolumeMount{
+		{
+			Name:      app.BinVolumeName,
+			MountPath: app.BinVolumeMountPath,
+		},
+		{
+			Name:      statefulset.VaultSecretVolumeName,
+			MountPath: statefulset.VaultSecretMountPath,
+		},
+	}
+	restoreMounts := append([]corev1.VolumeMount{
+		{
+			Name:      app.DataVolumeName,
+			MountPath: "/datadir",
+		},
+	}, commonMounts...)
+	verifyMounts := append([]corev1.VolumeMount{
+		{
+			Name:      app.DataVolumeName,
+			MountPath: "/var/lib/mysql",
+		},
+	}, commonMounts...)
+
+	// add ca bundle (this is used by the aws-cli to verify the connection to S3)
+	if cr.Status.S3 != nil && cr.Status.S3.CABundle != nil {
+		appendCABundleSecretVolume(&volumes, &restoreMounts, cr.Status.S3.CABundle)
+	}
+
+	restoreEnvs, err := verifyRestoreEnvs(ctx, cr, cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "restore envs")
+	}
+
+	job := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: cr.Namespace,
+			Labels:    naming.LabelsBackupJob(cr, cluster, jobName),
+		},
+		Spec: batchv1.JobSpec{
+			TTLSecondsAfterFinished: cluster.Spec.Backup.TTLSecondsAfterFinished,
+			ActiveDeadlineSeconds:   verification.ActiveDeadlineSeconds,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: cluster.Spec.PXC.Annotations,
+					Labels:      naming.LabelsBackupJob(cr, cluster, jobName),
+				},
+				Spec: corev1.PodSpec{
+					ImagePullSecrets: cluster.Spec.Backup.ImagePullSecrets,
+					SecurityContext:  cluster.Spec.PXC.PodSecurityContext,
+					InitContainers: []corev1.Container{
+						statefulset.BackupInitContainer(cluster, initImage, cluster.Spec.PXC.ContainerSecurityContext),
+						{
+							Name:            "xtrabackup",
+							Image:           cluster.Spec.Backup.Image,
+							ImagePullPolicy: cluster.Spec.Backup.ImagePullPolicy,
+							Command:         []string{"/opt/percona/backup/recovery-cloud.sh"},
+							SecurityContext: cluster.Spec.PXC.ContainerSecurityContext,
+							VolumeMounts:    restoreMounts,
+							Env:             restoreEnvs,
+							Resources:       verification.Resources,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            "mysqld",
+							Image:           cluster.Spec.PXC.Image,
+							ImagePullPolicy: cluster.Spec.PXC.ImagePullPolicy,
+							Command:         []string{"/opt/percona/backup/verify-backup.sh"},
+							SecurityContext: cluster.Spec.PXC.ContainerSecurityContext,
+							VolumeMounts:    verifyMounts,
+							Env: []corev1.EnvVar{
+								{
+									Name:  "VERIFY_CHECK_TABLES_LIMIT",
+									Value: strconv.Itoa(int(verification.GetCheckTablesLimit())),
+								},
+								{
+									Name:  "VERIFY_QUERIES",
+									Value: verifyQueries(verification.Queries),
+								},
+							},
+							Resources: verification.Resources,
+						},
+					},
+					RestartPolicy:             corev1.RestartPolicyNever,
+					Volumes:                   volumes,
+					NodeSelector:              cluster.Spec.PXC.NodeSelector,
+					Affinity:                  cluster.Spec.PXC.Affinity.Advanced,
+					TopologySpreadConstraints: pxc.PodTopologySpreadConstraints(cluster.Spec.PXC.TopologySpreadConstraints, cluster.Spec.PXC.Labels),
+					Tolerations:               cluster.Spec.PXC.Tolerations,
+					SchedulerName:             cluster.Spec.PXC.SchedulerName,
+					PriorityClassName:         cluster.Spec.PXC.PriorityClassName,
+					ServiceAccountName:        cluster.Spec.PXC.ServiceAccountName,
+					RuntimeClassName:          cluster.Spec.PXC.RuntimeClassName,
+				},
+			},
+			BackoffLimit: ptr.To(int32(2)),
+		},
+	}
+
+	if err := controllerutil.SetControllerReference(cr, job, scheme); err != nil {
+		return nil, errors.Wrap(err, "set controller reference")
+	}
+	for i := range job.OwnerReferences {
+		job.OwnerReferences[i].BlockOwnerDeletion = nil
+	}
+	return job, nil
+}
+
+func verifyRestoreEnvs(ctx context.Context, cr *api.PerconaXtraDBClusterBackup, cluster *api.PerconaXtraDBCluster) ([]corev1.EnvVar, error) {
+	verifyTLS := true
+	if cr.Status.VerifyTLS != nil {
+		verifyTLS = *cr.Status.VerifyTLS
+	}
+	envs := []corev1.EnvVar{
+		{
+			Name:  "VERIFY_TLS",
+			Value: strconv.FormatBool(verifyTLS),
+		},
+		{
+			Name:  "XB_USE_MEMORY",
+			Value: xbMemoryUse(cr.Spec.Verification.Resources),
+		},
+	}
+
+	if features.Enabled(ctx, features.XtrabackupSidecar) {
+		envs = append(envs, corev1.EnvVar{
+			Name:  "XTRABACKUP_ENABLED",
+			Value: "true",
+		})
+	}
+
+	storageEnvs, err := storageEnvs(nil, cr, cluster, cr.Status.Destination, false)
+	if err != nil {
+		return nil, err
+	}
+	envs = append(envs, storageEnvs...)
+
+	return util.MergeEnvLists(
+		envs,
+		cr.Spec.ContainerOptions.GetEnvVar(cluster, cr.Spec.StorageName),
+	), nil
+}
+
+// verifyQueries joins the queries into a script which can be passed to mysql client.
+func verifyQueries(queries []string) string {
+	statements := make([]string, 0, len(queries))
+	for _, q := range queries {
+		q = strings.TrimSuffix(strings.TrimSpace(q), ";")
+		if q == "" {
+			continue
+		}
+		statements = append(statements, q+";")
+	}
+	return strings.Join(statements, "\n")
+}
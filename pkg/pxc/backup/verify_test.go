@@ -0,0 +1,162 @@
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sversion "k8s.io/apimachinery/pkg/version"
+	"k8s.io/utils/ptr"
+
+	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/test"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/version"
+)
+
+func TestVerifyJob(t *testing.T) {
+	ctx := context.Background()
+
+	s3 := &pxcv1.BackupStorageS3Spec{
+		Bucket:            "operator-testing",
+		Region:            "us-west-1",
+		CredentialsSecret: "test-secret",
+	}
+	cluster := pxcv1.PerconaXtraDBCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-ns",
+		},
+		Spec: pxcv1.PerconaXtraDBClusterSpec{
+			CRVersion: version.Version(),
+			Backup: &pxcv1.BackupSpec{
+				Image: "percona/percona-xtrabackup:8.0",
+				Storages: map[string]*pxcv1.BackupStorageSpec{
+					"test-storage": {
+						Type: pxcv1.BackupStorageS3,
+						S3:   s3,
+					},
+				},
+			},
+			PXC: &pxcv1.PXCSpec{
+				PodSpec: &pxcv1.PodSpec{
+					Size:     3,
+					Image:    "percona/percona-xtradb-cluster:8.0",
+					Affinity: &pxcv1.PodAffinity{},
+					VolumeSpec: &pxcv1.VolumeSpec{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimSpec{
+							Resources: corev1.VolumeResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceStorage: resource.MustParse("1Gi"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	sv := &version.ServerVersion{
+		Platform: version.PlatformKubernetes,
+		Info:     k8sversion.Info{},
+	}
+
+	err := cluster.CheckNSetDefaults(sv, log)
+	assert.NoError(t, err)
+
+	newBackup := func() *pxcv1.PerconaXtraDBClusterBackup {
+		bcp := &pxcv1.PerconaXtraDBClusterBackup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-backup",
+				Namespace: "test-ns",
+			},
+			Spec: pxcv1.PXCBackupSpec{
+				PXCCluster:  "test-cluster",
+				StorageName: "test-storage",
+				Verification: &pxcv1.PXCBackupVerification{
+					Enabled:               true,
+					Queries:               []string{"SELECT COUNT(*) FROM db.t1;", " SELECT 1 "},
+					ActiveDeadlineSeconds: ptr.To(int64(3600)),
+				},
+			},
+			Status: pxcv1.PXCBackupStatus{
+				State:           pxcv1.BackupSucceeded,
+				StorageName:     "test-storage",
+				S3:              s3,
+				VaultSecretName: "test-cluster-vault",
+			},
+		}
+		bcp.Status.Destination.SetS3Destination("operator-testing", "test-cluster-2024-01-01-00:00:00-full")
+		return bcp
+	}
+
+	initImage := "perconalab/percona-xtradb-cluster-operator:main"
+	cl := test.BuildFakeClient()
+
+	t.Run("s3 backup", func(t *testing.T) {
+		job, err := VerifyJob(ctx, newBackup(), &cluster, initImage, cl.Scheme())
+		assert.NoError(t, err)
+
+		assert.Equal(t, "xb-verify-test-backup", job.Name)
+		assert.Equal(t, ptr.To(int64(3600)), job.Spec.ActiveDeadlineSeconds)
+
+		initContainers := job.Spec.Template.Spec.InitContainers
+		assert.Len(t, initContainers, 2)
+		assert.Equal(t, "percona/percona-xtrabackup:8.0", initContainers[1].Image)
+		assert.Equal(t, []string{"/opt/percona/backup/recovery-cloud.sh"}, initContainers[1].Command)
+		assert.Contains(t, initContainers[1].Env, corev1.EnvVar{
+			Name:  "S3_BUCKET_URL",
+			Value: "operator-testing/test-cluster-2024-01-01-00:00:00-full",
+		})
+
+		container := job.Spec.Template.Spec.Containers[0]
+		assert.Equal(t, "percona/percona-xtradb-cluster:8.0", container.Image)
+		assert.Equal(t, []string{"/opt/percona/backup/verify-backup.sh"}, container.Command)
+		assert.ElementsMatch(t, []corev1.EnvVar{
+			{
+				Name:  "VERIFY_CHECK_TABLES_LIMIT",
+				Value: "10",
+			},
+			{
+				Name:  "VERIFY_QUERIES",
+				Value: "SELECT COUNT(*) FROM db.t1;\nSELECT 1;",
+			},
+		}, container.Env)
+		assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{
+			Name:      "datadir",
+			MountPath: "/var/lib/mysql",
+		})
+	})
+
+	t.Run("encrypted backup", func(t *testing.T) {
+		bcp := newBackup()
+		bcp.Status.Encryption = &pxcv1.BackupEncryptionSpec{
+			Algorithm: pxcv1.BackupEncryptionAES256,
+			VaultPath: "backup/test-cluster",
+		}
+
+		job, err := VerifyJob(ctx, bcp, &cluster, initImage, cl.Scheme())
+		assert.NoError(t, err)
+
+		initContainer := job.Spec.Template.Spec.InitContainers[1]
+		assert.Contains(t, initContainer.Env, corev1.EnvVar{Name: "ENCRYPTION_ALGORITHM", Value: "AES256"})
+		assert.Contains(t, initContainer.Env, corev1.EnvVar{Name: "ENCRYPTION_VAULT_PATH", Value: "backup/test-cluster"})
+		assert.Contains(t, initContainer.VolumeMounts, corev1.VolumeMount{
+			Name:      "vault-keyring-secret",
+			MountPath: "/etc/mysql/vault-keyring-secret",
+		})
+	})
+
+	t.Run("filesystem backup", func(t *testing.T) {
+		bcp := newBackup()
+		bcp.Status.StorageType = pxcv1.BackupStorageFilesystem
+		bcp.Status.S3 = nil
+		bcp.Status.PVC = &corev1.PersistentVolumeClaimSpec{}
+
+		_, err := VerifyJob(ctx, bcp, &cluster, initImage, cl.Scheme())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,244 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ProxySQLQueryRule is a row of mysql_query_rules, nil fields are NULL.
+type ProxySQLQueryRule struct {
+	RuleID               int
+	Active               bool
+	Username             *string
+	SchemaName           *string
+	FlagIn               int
+	MatchDigest          *string
+	MatchPattern         *string
+	NegateMatchPattern   bool
+	FlagOut              *int
+	DestinationHostgroup *int
+	CacheTTL             *int
+	MirrorHostgroup      *int
+	Multiplex            *int
+	Apply                bool
+	Comment              *string
+}
+
+const proxySQLQueryRuleColumns = "rule_id, active, username, schemaname, flagIN, match_digest, match_pattern, " +
+	"negate_match_pattern, flagOUT, destination_hostgroup, cache_ttl, mirror_hostgroup, multiplex, apply, comment"
+
+// ProxySQLUser is the options of the user in mysql_users, nil options aren't changed.
+type ProxySQLUser struct {
+	Username              string
+	DefaultHostgroup      *int
+	TransactionPersistent *bool
+	FastForward           *bool
+	MaxConnections        *int
+	Active                *bool
+}
+
+// ProxySQLQueryRules returns the runtime query rules with the IDs ordered by the ID.
+func (p *Database) ProxySQLQueryRules(ctx context.Context, ids []int) ([]ProxySQLQueryRule, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	rows, err := p.db.QueryContext(ctx, "SELECT "+proxySQLQueryRuleColumns+" FROM runtime_mysql_query_rules WHERE rule_id IN ("+placeholders(len(ids))+") ORDER BY rule_id", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "select query rules")
+	}
+	defer rows.Close()
+
+	var rules []ProxySQLQueryRule
+	for rows.Next() {
+		var (
+			rule                                              ProxySQLQueryRule
+			active, negateMatchPattern, apply                 int
+			username, schemaName, matchDigest, matchPattern   sql.NullString
+			flagOut, destination, cacheTTL, mirror, multiplex sql.NullInt64
+			comment                                           sql.NullString
+		)
+		err := rows.Scan(&rule.RuleID, &active, &username, &schemaName, &rule.FlagIn, &matchDigest, &matchPattern,
+			&negateMatchPattern, &flagOut, &destination, &cacheTTL, &mirror, &multiplex, &apply, &comment)
+		if err != nil {
+			return nil, errors.Wrap(err, "scan query rule")
+		}
+
+		rule.Active = active == 1
+		rule.NegateMatchPattern = negateMatchPattern == 1
+		rule.Apply = apply == 1
+		rule.Username = nullString(username)
+		rule.SchemaName = nullString(schemaName)
+		rule.MatchDigest = nullString(matchDigest)
+		rule.MatchPattern = nullString(matchPattern)
+		rule.FlagOut = nullInt(flagOut)
+		rule.DestinationHostgroup = nullInt(destination)
+		rule.CacheTTL = nullInt(cacheTTL)
+		rule.MirrorHostgroup = nullInt(mirror)
+		rule.Multiplex = nullInt(multiplex)
+		rule.Comment = nullString(comment)
+
+		rules = append(rules, rule)
+	}
+
+	return rules, errors.Wrap(rows.Err(), "read query rules")
+}
+
+// SetProxySQLQueryRules replaces the query rules with the IDs by the rules,
+// loads them to runtime and saves them to disk.
+func (p *Database) SetProxySQLQueryRules(ctx context.Context, ids []int, rules []ProxySQLQueryRule) error {
+	if len(ids) > 0 {
+		args := make([]any, 0, len(ids))
+		for _, id := range ids {
+			args = append(args, id)
+		}
+		if _, err := p.db.ExecContext(ctx, "DELETE FROM mysql_query_rules WHERE rule_id IN ("+placeholders(len(ids))+")", args...); err != nil {
+			return errors.Wrap(err, "delete query rules")
+		}
+	}
+
+	for _, rule := range rules {
+		_, err := p.db.ExecContext(ctx, "INSERT INTO mysql_query_rules ("+proxySQLQueryRuleColumns+") VALUES ("+placeholders(15)+")",
+			rule.RuleID, boolToInt(rule.Active), rule.Username, rule.SchemaName, rule.FlagIn, rule.MatchDigest, rule.MatchPattern,
+			boolToInt(rule.NegateMatchPattern), rule.FlagOut, rule.DestinationHostgroup, rule.CacheTTL, rule.MirrorHostgroup,
+			rule.Multiplex, boolToInt(rule.Apply), rule.Comment)
+		if err != nil {
+			return errors.Wrapf(err, "insert query rule %d", rule.RuleID)
+		}
+	}
+
+	if _, err := p.db.ExecContext(ctx, "LOAD MYSQL QUERY RULES TO RUNTIME"); err != nil {
+		return errors.Wrap(err, "load query rules to runtime")
+	}
+	if _, err := p.db.ExecContext(ctx, "SAVE MYSQL QUERY RULES TO DISK"); err != nil {
+		return errors.Wrap(err, "save query rules to disk")
+	}
+
+	return nil
+}
+
+// ProxySQLUsers returns the runtime options of the frontend users.
+// The users which aren't found in ProxySQL aren't returned.
+func (p *Database) ProxySQLUsers(ctx context.Context, usernames []string) ([]ProxySQLUser, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(usernames))
+	for _, name := range usernames {
+		args = append(args, name)
+	}
+
+	rows, err := p.db.QueryContext(ctx, "SELECT username, default_hostgroup, transaction_persistent, fast_forward, max_connections, active "+
+		"FROM runtime_mysql_users WHERE frontend=1 AND username IN ("+placeholders(len(usernames))+")", args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "select users")
+	}
+	defer rows.Close()
+
+	var users []ProxySQLUser
+	for rows.Next() {
+		var (
+			user                                       ProxySQLUser
+			hostgroup, maxConnections                  int
+			transactionPersistent, fastForward, active int
+		)
+		if err := rows.Scan(&user.Username, &hostgroup, &transactionPersistent, &fastForward, &maxConnections, &active); err != nil {
+			return nil, errors.Wrap(err, "scan user")
+		}
+
+		user.DefaultHostgroup = &hostgroup
+		user.MaxConnections = &maxConnections
+		user.TransactionPersistent = intToBool(transactionPersistent)
+		user.FastForward = intToBool(fastForward)
+		user.Active = intToBool(active)
+
+		users = append(users, user)
+	}
+
+	return users, errors.Wrap(rows.Err(), "read users")
+}
+
+// UpdateProxySQLUsers sets the options of the users, loads them to runtime and saves them to disk.
+func (p *Database) UpdateProxySQLUsers(ctx context.Context, users []ProxySQLUser) error {
+	for _, user := range users {
+		var columns []string
+		var args []any
+		if user.DefaultHostgroup != nil {
+			columns = append(columns, "default_hostgroup=?")
+			args = append(args, *user.DefaultHostgroup)
+		}
+		if user.TransactionPersistent != nil {
+			columns = append(columns, "transaction_persistent=?")
+			args = append(args, boolToInt(*user.TransactionPersistent))
+		}
+		if user.FastForward != nil {
+			columns = append(columns, "fast_forward=?")
+			args = append(args, boolToInt(*user.FastForward))
+		}
+		if user.MaxConnections != nil {
+			columns = append(columns, "max_connections=?")
+			args = append(args, *user.MaxConnections)
+		}
+		if user.Active != nil {
+			columns = append(columns, "active=?")
+			args = append(args, boolToInt(*user.Active))
+		}
+		if len(columns) == 0 {
+			continue
+		}
+
+		args = append(args, user.Username)
+		if _, err := p.db.ExecContext(ctx, "UPDATE mysql_users SET "+strings.Join(columns, ", ")+" WHERE username=?", args...); err != nil {
+			return errors.Wrapf(err, "update user %s", user.Username)
+		}
+	}
+
+	if _, err := p.db.ExecContext(ctx, "LOAD MYSQL USERS TO RUNTIME"); err != nil {
+		return errors.Wrap(err, "load users to runtime")
+	}
+	if _, err := p.db.ExecContext(ctx, "SAVE MYSQL USERS TO DISK"); err != nil {
+		return errors.Wrap(err, "save users to disk")
+	}
+
+	return nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func intToBool(i int) *bool {
+	b := i == 1
+	return &b
+}
+
+func nullString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func nullInt(i sql.NullInt64) *int {
+	if !i.Valid {
+		return nil
+	}
+	v := int(i.Int64)
+	return &v
+}
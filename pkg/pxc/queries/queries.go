@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/pkg/errors"
@@ -30,6 +31,12 @@ const (
 // https://github.com/percona/percona-docker/blob/pxc-operator-1.3.0/proxysql/dockerdir/etc/proxysql-admin.cnf#L23
 const writerID = 11
 
+// configWriterID is the writer hostgroup pxc_scheduler_handler keeps the configured weights in
+const configWriterID = 8000 + writerID
+
+// preferredWriterWeight is the writer weight of the preferred writer set by proxysql_add_pxc_nodes.sh
+const preferredWriterWeight = 1000000
+
 type Database struct {
 	db *sql.DB
 }
@@ -368,6 +375,32 @@ func (p *Database) PrimaryHost() (string, error) {
 	return host, nil
 }
 
+// SetProxySQLWriter makes the host the preferred writer node of ProxySQL.
+// pxc_scheduler_handler moves the writer to the node with the highest weight
+// in the writer hostgroups, the previous preferred writer becomes the next candidate.
+func (p *Database) SetProxySQLWriter(ctx context.Context, host string) error {
+	_, err := p.db.ExecContext(ctx, "UPDATE mysql_servers SET weight=? WHERE hostgroup_id IN (?, ?) AND hostname NOT LIKE ? AND weight=?",
+		preferredWriterWeight-1, writerID, configWriterID, host+"%", preferredWriterWeight)
+	if err != nil {
+		return errors.Wrap(err, "update weight of previous writer")
+	}
+
+	_, err = p.db.ExecContext(ctx, "UPDATE mysql_servers SET weight=? WHERE hostgroup_id IN (?, ?) AND hostname LIKE ?",
+		preferredWriterWeight, writerID, configWriterID, host+"%")
+	if err != nil {
+		return errors.Wrap(err, "update weight of writer")
+	}
+
+	if _, err := p.db.ExecContext(ctx, "LOAD MYSQL SERVERS TO RUNTIME"); err != nil {
+		return errors.Wrap(err, "load mysql servers to runtime")
+	}
+	if _, err := p.db.ExecContext(ctx, "SAVE MYSQL SERVERS TO DISK"); err != nil {
+		return errors.Wrap(err, "save mysql servers to disk")
+	}
+
+	return nil
+}
+
 func (p *Database) NonPrimaryHostsProxySQL() ([]string, error) {
 	rows, err := p.db.Query("SELECT DISTINCT hostname FROM runtime_mysql_servers WHERE hostgroup_id != ? AND status = 'ONLINE' AND hostname NOT IN (SELECT hostname FROM runtime_mysql_servers WHERE hostgroup_id = ? AND status = 'ONLINE');", writerID, writerID)
 	if err != nil {
@@ -417,6 +450,62 @@ func (p *Database) WsrepLocalStateComment() (string, error) {
 	return value, nil
 }
 
+// WsrepLocalRecvQueue returns the current length of the receive queue of the node.
+// Nodes with long receive queues are the ones which trigger the flow control.
+func (p *Database) WsrepLocalRecvQueue(ctx context.Context) (int, error) {
+	var variableName string
+	var value int
+
+	err := p.db.QueryRowContext(ctx, "SHOW GLOBAL STATUS LIKE 'wsrep_local_recv_queue'").Scan(&variableName, &value)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("variable was not found")
+		}
+		return 0, err
+	}
+
+	return value, nil
+}
+
+// SetWsrepDesync enables or disables the desync of the node,
+// desynced nodes don't participate in the flow control of the cluster.
+func (p *Database) SetWsrepDesync(ctx context.Context, desync bool) error {
+	value := "OFF"
+	if desync {
+		value = "ON"
+	}
+	_, err := p.db.ExecContext(ctx, "SET GLOBAL wsrep_desync="+value)
+	return errors.Wrap(err, "set global wsrep_desync to "+value)
+}
+
+// SetMaintMode sets pxc_maint_mode of the node. HAProxy and ProxySQL
+// don't route the traffic to the nodes in the MAINTENANCE mode.
+func (p *Database) SetMaintMode(ctx context.Context, mode string) error {
+	_, err := p.db.ExecContext(ctx, "SET GLOBAL pxc_maint_mode=?", mode)
+	return errors.Wrap(err, "set global pxc_maint_mode to "+mode)
+}
+
+// ActiveClientConnections returns the number of the client connections of the node
+// which run a query or have an open transaction. The connections of the excluded users
+// and the threads of the server are not counted.
+func (p *Database) ActiveClientConnections(ctx context.Context, excludeUsers []string) (int, error) {
+	args := []any{"system user", "event_scheduler"}
+	for _, user := range excludeUsers {
+		args = append(args, user)
+	}
+
+	var count int
+	err := p.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM information_schema.PROCESSLIST "+
+		"WHERE ID != CONNECTION_ID() AND COMMAND NOT IN ('Daemon', 'Binlog Dump', 'Binlog Dump GTID') "+
+		"AND (COMMAND != 'Sleep' OR ID IN (SELECT trx_mysql_thread_id FROM information_schema.INNODB_TRX)) "+
+		"AND USER NOT IN ("+placeholders(len(args))+")", args...).Scan(&count)
+	if err != nil {
+		return 0, errors.Wrap(err, "select client connections")
+	}
+
+	return count, nil
+}
+
 func (p *Database) Version() (string, error) {
 	var version string
 
@@ -446,6 +535,144 @@ func (p *Database) ReadVariable(variable string) (string, error) {
 	return value, nil
 }
 
+// TableExists checks if the table exists in the database.
+func (p *Database) TableExists(ctx context.Context, database, table string) (bool, error) {
+	var count int
+	err := p.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?",
+		database, table).Scan(&count)
+	if err != nil {
+		return false, errors.Wrapf(err, "check table %s.%s", database, table)
+	}
+
+	return count > 0, nil
+}
+
+// CreateTable creates the table in the database using the CREATE TABLE statement.
+// The database is created if it doesn't exist.
+func (p *Database) CreateTable(ctx context.Context, database, createStatement string) error {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "get connection")
+	}
+	defer conn.Close()
+
+	for _, q := range []string{
+		"CREATE DATABASE IF NOT EXISTS " + quoteIdentifier(database),
+		"USE " + quoteIdentifier(database),
+		"SET SESSION foreign_key_checks = 0",
+		createStatement,
+	} {
+		if _, err := conn.ExecContext(ctx, q); err != nil {
+			return errors.Wrapf(err, "create table in %s", database)
+		}
+	}
+
+	return nil
+}
+
+// DropTable drops the table if it exists.
+func (p *Database) DropTable(ctx context.Context, database, table string) error {
+	_, err := p.db.ExecContext(ctx, "DROP TABLE IF EXISTS "+quoteIdentifier(database)+"."+quoteIdentifier(table))
+	if err != nil {
+		return errors.Wrapf(err, "drop table %s.%s", database, table)
+	}
+
+	return nil
+}
+
+// ImportTablespace replaces the tablespace of the table on the node with the files
+// copied into the database directory by copyFiles. The statements aren't replicated,
+// so the tablespace should be imported on each node of the cluster.
+//
+// DISCARD and IMPORT TABLESPACE are not allowed in the ENFORCING and MASTER
+// pxc_strict_mode. The variable has only the global scope, so the node is switched
+// to PERMISSIVE for the time of the import and the previous mode is restored before
+// the function returns, even if the import fails.
+func (p *Database) ImportTablespace(ctx context.Context, database, table string, copyFiles func() error) (err error) {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "get connection")
+	}
+	defer conn.Close()
+
+	var strictMode string
+	if err := conn.QueryRowContext(ctx, "SELECT @@GLOBAL.pxc_strict_mode").Scan(&strictMode); err != nil {
+		return errors.Wrap(err, "get pxc_strict_mode")
+	}
+	if strictMode == "ENFORCING" || strictMode == "MASTER" {
+		if _, err := conn.ExecContext(ctx, "SET GLOBAL pxc_strict_mode = 'PERMISSIVE'"); err != nil {
+			return errors.Wrap(err, "set pxc_strict_mode")
+		}
+		defer func() {
+			// the mode is restored even if ctx is canceled
+			rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+			defer cancel()
+			if _, rerr := conn.ExecContext(rctx, "SET GLOBAL pxc_strict_mode = ?", strictMode); rerr != nil && err == nil {
+				err = errors.Wrapf(rerr, "restore pxc_strict_mode %s", strictMode)
+			}
+		}()
+	}
+
+	name := quoteIdentifier(database) + "." + quoteIdentifier(table)
+	for _, q := range []string{
+		"SET SESSION wsrep_on = OFF",
+		"SET SESSION foreign_key_checks = 0",
+		"ALTER TABLE " + name + " DISCARD TABLESPACE",
+	} {
+		if _, err := conn.ExecContext(ctx, q); err != nil {
+			return errors.Wrapf(err, "discard tablespace of %s.%s", database, table)
+		}
+	}
+
+	if err := copyFiles(); err != nil {
+		return errors.Wrap(err, "copy files")
+	}
+
+	if _, err := conn.ExecContext(ctx, "ALTER TABLE "+name+" IMPORT TABLESPACE"); err != nil {
+		return errors.Wrapf(err, "import tablespace of %s.%s", database, table)
+	}
+
+	return nil
+}
+
+// QueryValue returns the first column of the first row returned by the query.
+// NULL is returned as an empty string.
+func (p *Database) QueryValue(ctx context.Context, query string) (string, error) {
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", err
+		}
+		return "", ErrNotFound
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", errors.Wrap(err, "get columns")
+	}
+	values := make([]any, len(columns))
+	var value sql.NullString
+	values[0] = &value
+	for i := 1; i < len(values); i++ {
+		values[i] = new(sql.RawBytes)
+	}
+	if err := rows.Scan(values...); err != nil {
+		return "", errors.Wrap(err, "scan")
+	}
+
+	return value.String, nil
+}
+
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
 func (p *Database) Close() error {
 	return p.db.Close()
 }
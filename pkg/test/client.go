@@ -16,7 +16,10 @@ func BuildFakeClient(objs ...runtime.Object) client.Client {
 	types := []runtime.Object{
 		new(pxcv1.PerconaXtraDBClusterRestore),
 		new(pxcv1.PerconaXtraDBClusterRestoreList),
+		new(pxcv1.PerconaXtraDBClusterRestoreDrill),
+		new(pxcv1.PerconaXtraDBClusterRestoreDrillList),
 		new(pxcv1.PerconaXtraDBClusterBackup),
+		new(pxcv1.PerconaXtraDBClusterBackupList),
 		new(pxcv1.PerconaXtraDBCluster),
 	}
 
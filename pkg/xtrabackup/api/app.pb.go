@@ -379,6 +379,9 @@ type BackupConfig struct {
 	S3               *S3Config              `protobuf:"bytes,5,opt,name=s3,proto3,oneof" json:"s3,omitempty"`
 	Gcs              *GCSConfig             `protobuf:"bytes,6,opt,name=gcs,proto3,oneof" json:"gcs,omitempty"`
 	Azure            *AzureConfig           `protobuf:"bytes,7,opt,name=azure,proto3,oneof" json:"azure,omitempty"`
+	IncrementalLsn   uint64                 `protobuf:"varint,8,opt,name=incremental_lsn,json=incrementalLsn,proto3" json:"incremental_lsn,omitempty"`
+	Encryption       *EncryptionConfig      `protobuf:"bytes,9,opt,name=encryption,proto3,oneof" json:"encryption,omitempty"`
+	Throttling       *ThrottlingConfig      `protobuf:"bytes,10,opt,name=throttling,proto3,oneof" json:"throttling,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -462,6 +465,27 @@ func (x *BackupConfig) GetAzure() *AzureConfig {
 	return nil
 }
 
+func (x *BackupConfig) GetIncrementalLsn() uint64 {
+	if x != nil {
+		return x.IncrementalLsn
+	}
+	return 0
+}
+
+func (x *BackupConfig) GetEncryption() *EncryptionConfig {
+	if x != nil {
+		return x.Encryption
+	}
+	return nil
+}
+
+func (x *BackupConfig) GetThrottling() *ThrottlingConfig {
+	if x != nil {
+		return x.Throttling
+	}
+	return nil
+}
+
 type S3Config struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Bucket         string                 `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
@@ -714,6 +738,118 @@ func (x *AzureConfig) GetAccessKey() string {
 	return ""
 }
 
+type EncryptionConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Algorithm     string                 `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EncryptionConfig) Reset() {
+	*x = EncryptionConfig{}
+	mi := &file_app_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EncryptionConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptionConfig) ProtoMessage() {}
+
+func (x *EncryptionConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_app_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptionConfig.ProtoReflect.Descriptor instead.
+func (*EncryptionConfig) Descriptor() ([]byte, []int) {
+	return file_app_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EncryptionConfig) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *EncryptionConfig) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type ThrottlingConfig struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Parallel        uint32                 `protobuf:"varint,1,opt,name=parallel,proto3" json:"parallel,omitempty"`
+	Throttle        uint32                 `protobuf:"varint,2,opt,name=throttle,proto3" json:"throttle,omitempty"`
+	UploadRateLimit int64                  `protobuf:"varint,3,opt,name=upload_rate_limit,json=uploadRateLimit,proto3" json:"upload_rate_limit,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ThrottlingConfig) Reset() {
+	*x = ThrottlingConfig{}
+	mi := &file_app_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThrottlingConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThrottlingConfig) ProtoMessage() {}
+
+func (x *ThrottlingConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_app_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThrottlingConfig.ProtoReflect.Descriptor instead.
+func (*ThrottlingConfig) Descriptor() ([]byte, []int) {
+	return file_app_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ThrottlingConfig) GetParallel() uint32 {
+	if x != nil {
+		return x.Parallel
+	}
+	return 0
+}
+
+func (x *ThrottlingConfig) GetThrottle() uint32 {
+	if x != nil {
+		return x.Throttle
+	}
+	return 0
+}
+
+func (x *ThrottlingConfig) GetUploadRateLimit() int64 {
+	if x != nil {
+		return x.UploadRateLimit
+	}
+	return 0
+}
+
 type EnvVar struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -724,7 +860,7 @@ type EnvVar struct {
 
 func (x *EnvVar) Reset() {
 	*x = EnvVar{}
-	mi := &file_app_proto_msgTypes[11]
+	mi := &file_app_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -736,7 +872,7 @@ func (x *EnvVar) String() string {
 func (*EnvVar) ProtoMessage() {}
 
 func (x *EnvVar) ProtoReflect() protoreflect.Message {
-	mi := &file_app_proto_msgTypes[11]
+	mi := &file_app_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -749,7 +885,7 @@ func (x *EnvVar) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EnvVar.ProtoReflect.Descriptor instead.
 func (*EnvVar) Descriptor() ([]byte, []int) {
-	return file_app_proto_rawDescGZIP(), []int{11}
+	return file_app_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *EnvVar) GetKey() string {
@@ -777,7 +913,7 @@ type BackupContainerArgs struct {
 
 func (x *BackupContainerArgs) Reset() {
 	*x = BackupContainerArgs{}
-	mi := &file_app_proto_msgTypes[12]
+	mi := &file_app_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -789,7 +925,7 @@ func (x *BackupContainerArgs) String() string {
 func (*BackupContainerArgs) ProtoMessage() {}
 
 func (x *BackupContainerArgs) ProtoReflect() protoreflect.Message {
-	mi := &file_app_proto_msgTypes[12]
+	mi := &file_app_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -802,7 +938,7 @@ func (x *BackupContainerArgs) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BackupContainerArgs.ProtoReflect.Descriptor instead.
 func (*BackupContainerArgs) Descriptor() ([]byte, []int) {
-	return file_app_proto_rawDescGZIP(), []int{12}
+	return file_app_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *BackupContainerArgs) GetXtrabackup() []string {
@@ -836,7 +972,7 @@ type ContainerOptions struct {
 
 func (x *ContainerOptions) Reset() {
 	*x = ContainerOptions{}
-	mi := &file_app_proto_msgTypes[13]
+	mi := &file_app_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -848,7 +984,7 @@ func (x *ContainerOptions) String() string {
 func (*ContainerOptions) ProtoMessage() {}
 
 func (x *ContainerOptions) ProtoReflect() protoreflect.Message {
-	mi := &file_app_proto_msgTypes[13]
+	mi := &file_app_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -861,7 +997,7 @@ func (x *ContainerOptions) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ContainerOptions.ProtoReflect.Descriptor instead.
 func (*ContainerOptions) Descriptor() ([]byte, []int) {
-	return file_app_proto_rawDescGZIP(), []int{13}
+	return file_app_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *ContainerOptions) GetEnv() []*EnvVar {
@@ -898,7 +1034,7 @@ const file_app_proto_rawDesc = "" +
 	"\vbackup_name\x18\x01 \x01(\tR\n" +
 	"backupName\x126\n" +
 	"\rbackup_config\x18\x02 \x01(\v2\x11.api.BackupConfigR\fbackupConfig\"\x16\n" +
-	"\x14DeleteBackupResponse\"\xd0\x02\n" +
+	"\x14DeleteBackupResponse\"\x8f\x04\n" +
 	"\fBackupConfig\x12 \n" +
 	"\vdestination\x18\x01 \x01(\tR\vdestination\x12*\n" +
 	"\x04type\x18\x02 \x01(\x0e2\x16.api.BackupStorageTypeR\x04type\x12\x1d\n" +
@@ -907,10 +1043,20 @@ const file_app_proto_rawDesc = "" +
 	"\x11container_options\x18\x04 \x01(\v2\x15.api.ContainerOptionsR\x10containerOptions\x12\"\n" +
 	"\x02s3\x18\x05 \x01(\v2\r.api.S3ConfigH\x00R\x02s3\x88\x01\x01\x12%\n" +
 	"\x03gcs\x18\x06 \x01(\v2\x0e.api.GCSConfigH\x01R\x03gcs\x88\x01\x01\x12+\n" +
-	"\x05azure\x18\a \x01(\v2\x10.api.AzureConfigH\x02R\x05azure\x88\x01\x01B\x05\n" +
+	"\x05azure\x18\a \x01(\v2\x10.api.AzureConfigH\x02R\x05azure\x88\x01\x01\x12'\n" +
+	"\x0fincremental_lsn\x18\b \x01(\x04R\x0eincrementalLsn\x12:\n" +
+	"\n" +
+	"encryption\x18\t \x01(\v2\x15.api.EncryptionConfigH\x03R\n" +
+	"encryption\x88\x01\x01\x12:\n" +
+	"\n" +
+	"throttling\x18\n" +
+	" \x01(\v2\x15.api.ThrottlingConfigH\x04R\n" +
+	"throttling\x88\x01\x01B\x05\n" +
 	"\x03_s3B\x06\n" +
 	"\x04_gcsB\b\n" +
-	"\x06_azure\"\x8f\x02\n" +
+	"\x06_azureB\r\n" +
+	"\v_encryptionB\r\n" +
+	"\v_throttling\"\x8f\x02\n" +
 	"\bS3Config\x12\x16\n" +
 	"\x06bucket\x18\x01 \x01(\tR\x06bucket\x12\x16\n" +
 	"\x06region\x18\x02 \x01(\tR\x06region\x12!\n" +
@@ -936,7 +1082,14 @@ const file_app_proto_rawDesc = "" +
 	"\rstorage_class\x18\x03 \x01(\tR\fstorageClass\x12'\n" +
 	"\x0fstorage_account\x18\x04 \x01(\tR\x0estorageAccount\x12\x1d\n" +
 	"\n" +
-	"access_key\x18\x05 \x01(\tR\taccessKey\"0\n" +
+	"access_key\x18\x05 \x01(\tR\taccessKey\"B\n" +
+	"\x10EncryptionConfig\x12\x1c\n" +
+	"\talgorithm\x18\x01 \x01(\tR\talgorithm\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\"v\n" +
+	"\x10ThrottlingConfig\x12\x1a\n" +
+	"\bparallel\x18\x01 \x01(\rR\bparallel\x12\x1a\n" +
+	"\bthrottle\x18\x02 \x01(\rR\bthrottle\x12*\n" +
+	"\x11upload_rate_limit\x18\x03 \x01(\x03R\x0fuploadRateLimit\"0\n" +
 	"\x06EnvVar\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value\"k\n" +
@@ -972,7 +1125,7 @@ func file_app_proto_rawDescGZIP() []byte {
 }
 
 var file_app_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_app_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_app_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_app_proto_goTypes = []any{
 	(BackupStorageType)(0),                // 0: api.BackupStorageType
 	(*GetLogsRequest)(nil),                // 1: api.GetLogsRequest
@@ -986,33 +1139,37 @@ var file_app_proto_goTypes = []any{
 	(*S3Config)(nil),                      // 9: api.S3Config
 	(*GCSConfig)(nil),                     // 10: api.GCSConfig
 	(*AzureConfig)(nil),                   // 11: api.AzureConfig
-	(*EnvVar)(nil),                        // 12: api.EnvVar
-	(*BackupContainerArgs)(nil),           // 13: api.BackupContainerArgs
-	(*ContainerOptions)(nil),              // 14: api.ContainerOptions
+	(*EncryptionConfig)(nil),              // 12: api.EncryptionConfig
+	(*ThrottlingConfig)(nil),              // 13: api.ThrottlingConfig
+	(*EnvVar)(nil),                        // 14: api.EnvVar
+	(*BackupContainerArgs)(nil),           // 15: api.BackupContainerArgs
+	(*ContainerOptions)(nil),              // 16: api.ContainerOptions
 }
 var file_app_proto_depIdxs = []int32{
 	8,  // 0: api.CreateBackupRequest.backup_config:type_name -> api.BackupConfig
 	8,  // 1: api.DeleteBackupRequest.backup_config:type_name -> api.BackupConfig
 	0,  // 2: api.BackupConfig.type:type_name -> api.BackupStorageType
-	14, // 3: api.BackupConfig.container_options:type_name -> api.ContainerOptions
+	16, // 3: api.BackupConfig.container_options:type_name -> api.ContainerOptions
 	9,  // 4: api.BackupConfig.s3:type_name -> api.S3Config
 	10, // 5: api.BackupConfig.gcs:type_name -> api.GCSConfig
 	11, // 6: api.BackupConfig.azure:type_name -> api.AzureConfig
-	12, // 7: api.ContainerOptions.env:type_name -> api.EnvVar
-	13, // 8: api.ContainerOptions.args:type_name -> api.BackupContainerArgs
-	3,  // 9: api.XtrabackupService.GetCurrentBackupConfig:input_type -> api.GetCurrentBackupConfigRequest
-	4,  // 10: api.XtrabackupService.CreateBackup:input_type -> api.CreateBackupRequest
-	6,  // 11: api.XtrabackupService.DeleteBackup:input_type -> api.DeleteBackupRequest
-	1,  // 12: api.XtrabackupService.GetLogs:input_type -> api.GetLogsRequest
-	8,  // 13: api.XtrabackupService.GetCurrentBackupConfig:output_type -> api.BackupConfig
-	5,  // 14: api.XtrabackupService.CreateBackup:output_type -> api.CreateBackupResponse
-	7,  // 15: api.XtrabackupService.DeleteBackup:output_type -> api.DeleteBackupResponse
-	2,  // 16: api.XtrabackupService.GetLogs:output_type -> api.LogChunk
-	13, // [13:17] is the sub-list for method output_type
-	9,  // [9:13] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	12, // 7: api.BackupConfig.encryption:type_name -> api.EncryptionConfig
+	13, // 8: api.BackupConfig.throttling:type_name -> api.ThrottlingConfig
+	14, // 9: api.ContainerOptions.env:type_name -> api.EnvVar
+	15, // 10: api.ContainerOptions.args:type_name -> api.BackupContainerArgs
+	3,  // 11: api.XtrabackupService.GetCurrentBackupConfig:input_type -> api.GetCurrentBackupConfigRequest
+	4,  // 12: api.XtrabackupService.CreateBackup:input_type -> api.CreateBackupRequest
+	6,  // 13: api.XtrabackupService.DeleteBackup:input_type -> api.DeleteBackupRequest
+	1,  // 14: api.XtrabackupService.GetLogs:input_type -> api.GetLogsRequest
+	8,  // 15: api.XtrabackupService.GetCurrentBackupConfig:output_type -> api.BackupConfig
+	5,  // 16: api.XtrabackupService.CreateBackup:output_type -> api.CreateBackupResponse
+	7,  // 17: api.XtrabackupService.DeleteBackup:output_type -> api.DeleteBackupResponse
+	2,  // 18: api.XtrabackupService.GetLogs:output_type -> api.LogChunk
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_app_proto_init() }
@@ -1027,7 +1184,7 @@ func file_app_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_app_proto_rawDesc), len(file_app_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   14,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -13,6 +13,8 @@ import (
 const (
 	xtrabackupCmd = "xtrabackup"
 	xbcloudCmd    = "xbcloud"
+
+	defaultXbcloudParallel = 10
 )
 
 type XBCloudAction string
@@ -22,14 +24,16 @@ const (
 	XBCloudActionDelete XBCloudAction = "delete"
 )
 
-// NewXtrabackupCmd creates a new xtrabackup command
+// NewXtrabackupCmd creates a new xtrabackup command.
+// encryptKeyFile is the file with the key from the encryption config, see WriteEncryptKeyFile.
 func (cfg *BackupConfig) NewXtrabackupCmd(
 	ctx context.Context,
 	user,
 	password string,
 	mysqlVersion *goversion.Version,
-	withTablespaceEncryption bool) *exec.Cmd {
-	cmd := exec.CommandContext(ctx, xtrabackupCmd, cfg.xtrabackupArgs(user, password, mysqlVersion, withTablespaceEncryption)...)
+	withTablespaceEncryption bool,
+	encryptKeyFile string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, xtrabackupCmd, cfg.xtrabackupArgs(user, password, mysqlVersion, withTablespaceEncryption, encryptKeyFile)...)
 	cmd.Env = cfg.envs()
 	return cmd
 }
@@ -43,7 +47,11 @@ func (cfg *BackupConfig) NewXbcloudCmd(ctx context.Context, action XBCloudAction
 }
 
 func (cfg *BackupConfig) xbcloudArgs(action XBCloudAction) []string {
-	args := []string{string(action), "--parallel=10", "--curl-retriable-errors=7"}
+	parallel := uint32(defaultXbcloudParallel)
+	if p := cfg.GetThrottling().GetParallel(); p > 0 {
+		parallel = p
+	}
+	args := []string{string(action), fmt.Sprintf("--parallel=%d", parallel), "--curl-retriable-errors=7"}
 
 	if !cfg.VerifyTls {
 		args = append(args, "--insecure")
@@ -68,6 +76,9 @@ func (cfg *BackupConfig) xbcloudArgs(action XBCloudAction) []string {
 		if len(cfg.Gcs.EndpointUrl) > 0 {
 			args = append(args, fmt.Sprintf("--google-endpoint=%s", cfg.Gcs.EndpointUrl))
 		}
+		if len(cfg.Gcs.StorageClass) > 0 {
+			args = append(args, fmt.Sprintf("--google-storage-class=%s", cfg.Gcs.StorageClass))
+		}
 	case BackupStorageType_S3:
 		args = append(
 			args,
@@ -109,7 +120,7 @@ func (cfg *BackupConfig) xbcloudArgs(action XBCloudAction) []string {
 	return args
 }
 
-func (cfg *BackupConfig) xtrabackupArgs(user, pass string, mysqlVersion *goversion.Version, withTablespaceEncryption bool) []string {
+func (cfg *BackupConfig) xtrabackupArgs(user, pass string, mysqlVersion *goversion.Version, withTablespaceEncryption bool, encryptKeyFile string) []string {
 	args := []string{
 		"--backup",
 		"--stream=xbstream",
@@ -129,12 +140,44 @@ func (cfg *BackupConfig) xtrabackupArgs(user, pass string, mysqlVersion *goversi
 		}
 		args = append(args, vaultConfigFlag)
 	}
+	if cfg.GetIncrementalLsn() > 0 {
+		args = append(args, fmt.Sprintf("--incremental-lsn=%d", cfg.GetIncrementalLsn()))
+	}
+	if throttle := cfg.GetThrottling().GetThrottle(); throttle > 0 {
+		args = append(args, fmt.Sprintf("--throttle=%d", throttle))
+	}
+	if enc := cfg.GetEncryption(); enc != nil && len(enc.Key) > 0 && encryptKeyFile != "" {
+		args = append(args, fmt.Sprintf("--encrypt=%s", enc.Algorithm), fmt.Sprintf("--encrypt-key-file=%s", encryptKeyFile))
+	}
 	if cfg != nil && cfg.ContainerOptions != nil && cfg.ContainerOptions.Args != nil {
 		args = append(args, cfg.ContainerOptions.Args.Xtrabackup...)
 	}
 	return args
 }
 
+// WriteEncryptKeyFile writes the key from the encryption config into a new file in dir,
+// so the key isn't passed to xtrabackup in its arguments. It returns an empty path if
+// encryption isn't configured. The caller should remove the file.
+func (cfg *BackupConfig) WriteEncryptKeyFile(dir string) (string, error) {
+	enc := cfg.GetEncryption()
+	if enc == nil || len(enc.Key) == 0 {
+		return "", nil
+	}
+
+	f, err := os.CreateTemp(dir, "encryption-key-")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() //nolint:errcheck
+
+	if _, err := f.WriteString(enc.Key); err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
 func (cfg *BackupConfig) envs() []string {
 	envs := os.Environ()
 	if cfg.ContainerOptions != nil {
@@ -44,13 +44,85 @@ func TestNewXtrabackupCmd(t *testing.T) {
 				"--parallel=4",
 			},
 		},
+		{
+			backupConfig: &BackupConfig{
+				Destination:    "s3://bucket/backup-incr",
+				Type:           BackupStorageType_S3,
+				VerifyTls:      true,
+				IncrementalLsn: 25147368,
+			},
+			expectedArgs: []string{
+				"xtrabackup",
+				"--backup",
+				"--stream=xbstream",
+				"--safe-slave-backup",
+				"--slave-info",
+				"--target-dir=/backup/",
+				"--socket=/tmp/mysql.sock",
+				"--user=root",
+				"--password=password123",
+				"--incremental-lsn=25147368",
+			},
+		},
+		{
+			backupConfig: &BackupConfig{
+				Destination: "s3://bucket/backup",
+				Type:        BackupStorageType_S3,
+				VerifyTls:   true,
+				Encryption: &EncryptionConfig{
+					Algorithm: "AES256",
+					Key:       "Ue1ocVcrTd3qJQ7CA7Qo5JNXwz9FyPTx",
+				},
+				ContainerOptions: &ContainerOptions{
+					Args: &BackupContainerArgs{
+						Xtrabackup: []string{"--compress"},
+					},
+				},
+			},
+			expectedArgs: []string{
+				"xtrabackup",
+				"--backup",
+				"--stream=xbstream",
+				"--safe-slave-backup",
+				"--slave-info",
+				"--target-dir=/backup/",
+				"--socket=/tmp/mysql.sock",
+				"--user=root",
+				"--password=password123",
+				"--encrypt=AES256",
+				"--encrypt-key-file=/tmp/encryption-key-1",
+				"--compress",
+			},
+		},
+		{
+			backupConfig: &BackupConfig{
+				Destination: "s3://bucket/backup",
+				Type:        BackupStorageType_S3,
+				VerifyTls:   true,
+				Throttling: &ThrottlingConfig{
+					Throttle: 100,
+				},
+			},
+			expectedArgs: []string{
+				"xtrabackup",
+				"--backup",
+				"--stream=xbstream",
+				"--safe-slave-backup",
+				"--slave-info",
+				"--target-dir=/backup/",
+				"--socket=/tmp/mysql.sock",
+				"--user=root",
+				"--password=password123",
+				"--throttle=100",
+			},
+		},
 	}
 
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
 			cmd := tc.backupConfig.NewXtrabackupCmd(
 				context.Background(),
-				"root", "password123", goversion.Must(goversion.NewVersion("8.0.0")), false)
+				"root", "password123", goversion.Must(goversion.NewVersion("8.0.0")), false, "/tmp/encryption-key-1")
 			assert.Equal(t, tc.expectedArgs, cmd.Args)
 		})
 	}
@@ -126,6 +198,39 @@ func TestNewXbcloudCmd(t *testing.T) {
 			},
 			expectedEnv: []string{},
 		},
+		{
+			name: "S3 storage with throttling",
+			backupConfig: &BackupConfig{
+				Destination: "s3://bucket/backup-name",
+				Type:        BackupStorageType_S3,
+				VerifyTls:   true,
+				S3: &S3Config{
+					Bucket:    "test-bucket",
+					Region:    "us-west-2",
+					AccessKey: "access-key-123",
+					SecretKey: "secret-key-456",
+				},
+				Throttling: &ThrottlingConfig{
+					Parallel:        2,
+					UploadRateLimit: 10485760,
+				},
+			},
+			action: XBCloudActionPut,
+			expectedArgs: []string{
+				"xbcloud",
+				"put",
+				"--parallel=2",
+				"--curl-retriable-errors=7",
+				"--md5",
+				"--storage=s3",
+				"--s3-bucket=test-bucket",
+				"--s3-region=us-west-2",
+				"--s3-access-key=access-key-123",
+				"--s3-secret-key=secret-key-456",
+				"s3://bucket/backup-name",
+			},
+			expectedEnv: []string{},
+		},
 		{
 			name: "S3 storage with session token",
 			backupConfig: &BackupConfig{
@@ -2,11 +2,14 @@ package xtrabackup
 
 import (
 	"fmt"
+	"strconv"
 
 	pxcv1 "github.com/percona/percona-xtradb-cluster-operator/pkg/apis/pxc/v1"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app/statefulset"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/encryption"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/throttling"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -39,6 +42,15 @@ func JobSpec(
 	)
 	spec := backup.Spec
 	storage := cluster.Spec.Backup.Storages[backup.Spec.StorageName]
+
+	// the encryption key is fetched from Vault by the backup container
+	if storage.Encryption.IsEnabled() && storage.Encryption.VaultPath != "" {
+		volumes = append(volumes, app.GetSecretVolumes(statefulset.VaultSecretVolumeName, backup.Status.VaultSecretName, true))
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      statefulset.VaultSecretVolumeName,
+			MountPath: statefulset.VaultSecretMountPath,
+		})
+	}
 	var initContainers []corev1.Container
 	initContainers = append(initContainers, statefulset.BackupInitContainer(cluster, initImage, storage.ContainerSecurityContext))
 
@@ -116,5 +128,16 @@ func xtrabackupJobEnvVars(
 			Value: backup.Name,
 		},
 	}
+	envs = append(envs, encryption.Envs("", storage.Encryption)...)
+	envs = append(envs, throttling.Envs(storage.Throttling)...)
+	if backup.Status.IsIncremental() {
+		if backup.Status.LSN == nil {
+			return nil, fmt.Errorf("incremental backup %s has no base LSN", backup.Name)
+		}
+		envs = append(envs, corev1.EnvVar{
+			Name:  "INCREMENTAL_LSN",
+			Value: strconv.FormatInt(backup.Status.LSN.From, 10),
+		})
+	}
 	return envs, nil
 }
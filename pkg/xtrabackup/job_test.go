@@ -217,3 +217,82 @@ func TestJobSpec(t *testing.T) {
 	assert.Equal(t, string(pxcv1.BackupStorageS3), envMap["STORAGE_TYPE"])
 	assert.Equal(t, "true", envMap["VERIFY_TLS"])
 }
+
+func TestJobSpecEncryption(t *testing.T) {
+	storageName := "s3-storage"
+	newCluster := func(enc *pxcv1.BackupEncryptionSpec) *pxcv1.PerconaXtraDBCluster {
+		return &pxcv1.PerconaXtraDBCluster{
+			Spec: pxcv1.PerconaXtraDBClusterSpec{
+				Backup: &pxcv1.BackupSpec{
+					Image: "percona/percona-xtradb-cluster-operator:backup-image",
+					Storages: map[string]*pxcv1.BackupStorageSpec{
+						storageName: {
+							Type: pxcv1.BackupStorageS3,
+							S3: &pxcv1.BackupStorageS3Spec{
+								Bucket:            "test-bucket",
+								CredentialsSecret: "s3-credentials",
+							},
+							Encryption: enc,
+						},
+					},
+				},
+				InitContainer: pxcv1.InitContainerSpec{
+					Resources: &corev1.ResourceRequirements{},
+				},
+			},
+		}
+	}
+	backup := &pxcv1.PerconaXtraDBClusterBackup{
+		Spec: pxcv1.PXCBackupSpec{
+			StorageName: storageName,
+		},
+		Status: pxcv1.PXCBackupStatus{
+			VaultSecretName: "cluster-vault",
+		},
+	}
+
+	t.Run("key secret", func(t *testing.T) {
+		cluster := newCluster(&pxcv1.BackupEncryptionSpec{
+			KeySecret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "backup-key"},
+				Key:                  "key",
+			},
+		})
+
+		jobSpec, err := JobSpec(backup, cluster, &batchv1.Job{}, "init-image", "cluster-pxc-0.cluster-pxc")
+		assert.NoError(t, err)
+
+		container := jobSpec.Template.Spec.Containers[0]
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "ENCRYPTION_ALGORITHM", Value: "AES256"})
+		assert.Contains(t, container.Env, corev1.EnvVar{
+			Name: "ENCRYPTION_KEY",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "backup-key"},
+					Key:                  "key",
+				},
+			},
+		})
+		assert.Len(t, jobSpec.Template.Spec.Volumes, 1)
+	})
+
+	t.Run("vault", func(t *testing.T) {
+		cluster := newCluster(&pxcv1.BackupEncryptionSpec{
+			Algorithm: pxcv1.BackupEncryptionAES128,
+			VaultPath: "backup/key",
+		})
+
+		jobSpec, err := JobSpec(backup, cluster, &batchv1.Job{}, "init-image", "cluster-pxc-0.cluster-pxc")
+		assert.NoError(t, err)
+
+		container := jobSpec.Template.Spec.Containers[0]
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "ENCRYPTION_ALGORITHM", Value: "AES128"})
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "ENCRYPTION_VAULT_PATH", Value: "backup/key"})
+		assert.Len(t, jobSpec.Template.Spec.Volumes, 2)
+		assert.Equal(t, "cluster-vault", jobSpec.Template.Spec.Volumes[1].Secret.SecretName)
+		assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{
+			Name:      "vault-keyring-secret",
+			MountPath: "/etc/mysql/vault-keyring-secret",
+		})
+	})
+}
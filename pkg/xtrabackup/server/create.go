@@ -11,12 +11,14 @@ import (
 
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/app"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/storage"
+	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/backup/throttling"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/pxc/users"
 	"github.com/percona/percona-xtradb-cluster-operator/pkg/xtrabackup/api"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func (s *appServer) CreateBackup(req *api.CreateBackupRequest, stream api.XtrabackupService_CreateBackupServer) error {
@@ -54,10 +56,19 @@ func (s *appServer) CreateBackup(req *api.CreateBackupRequest, stream api.Xtraba
 		return errors.Wrap(err, "get backup user password")
 	}
 
+	encryptKeyFile, err := req.BackupConfig.WriteEncryptKeyFile("")
+	if err != nil {
+		logger.Error(err, "failed to write encryption key file")
+		return errors.Wrap(err, "write encryption key file")
+	}
+	if encryptKeyFile != "" {
+		defer os.Remove(encryptKeyFile) //nolint:errcheck
+	}
+
 	g, gCtx := errgroup.WithContext(ctx)
 
 	xtrabackup := req.BackupConfig.NewXtrabackupCmd(
-		gCtx, backupUser, backupPass, s.mysqlVersion, s.tableSpaceEncryptionEnabled)
+		gCtx, backupUser, backupPass, s.mysqlVersion, s.tableSpaceEncryptionEnabled, encryptKeyFile)
 	xbOut, err := xtrabackup.StdoutPipe()
 	if err != nil {
 		logger.Error(err, "xtrabackup stdout pipe failed")
@@ -80,7 +91,13 @@ func (s *appServer) CreateBackup(req *api.CreateBackupRequest, stream api.Xtraba
 	defer backupLog.Close() //nolint:errcheck
 	logWriter := io.MultiWriter(backupLog, os.Stderr)
 
-	xbcloud := req.BackupConfig.NewXbcloudCmd(gCtx, api.XBCloudActionPut, xbOut)
+	xbcloudCfg, err := xbcloudConfig(gCtx, req.BackupConfig)
+	if err != nil {
+		logger.Error(err, "failed to prepare xbcloud config")
+		return errors.Wrap(err, "prepare xbcloud config")
+	}
+	xbcloud := xbcloudCfg.NewXbcloudCmd(gCtx, api.XBCloudActionPut,
+		throttling.NewReader(gCtx, xbOut, req.BackupConfig.GetThrottling().GetUploadRateLimit()))
 	xbcloudErr, err := xbcloud.StderrPipe()
 	if err != nil {
 		logger.Error(err, "xbcloud stderr pipe failed")
@@ -220,7 +237,14 @@ func (s *appServer) deleteBackup(ctx context.Context, cfg *api.BackupConfig, bac
 		logWriter = io.MultiWriter(backupLog, os.Stderr)
 	}
 
-	xbcloud := cfg.NewXbcloudCmd(ctx, api.XBCloudActionDelete, nil)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	xbcloudCfg, err := xbcloudConfig(ctx, cfg)
+	if err != nil {
+		return errors.Wrap(err, "prepare xbcloud config")
+	}
+	xbcloud := xbcloudCfg.NewXbcloudCmd(ctx, api.XBCloudActionDelete, nil)
 	xbcloudErr, err := xbcloud.StderrPipe()
 	if err != nil {
 		return errors.Wrap(err, "xbcloud stderr pipe failed")
@@ -247,8 +271,29 @@ func (s *appServer) deleteBackup(ctx context.Context, cfg *api.BackupConfig, bac
 
 }
 
+// xbcloudConfig returns the config for xbcloud commands.
+// xbcloud supports only HMAC keys for Google Cloud Storage, so without them its requests
+// go through a local proxy, which authorizes them with the pod's service account
+// (e.g. GKE workload identity). The proxy is stopped when ctx is canceled.
+func xbcloudConfig(ctx context.Context, cfg *api.BackupConfig) (*api.BackupConfig, error) {
+	if cfg.Type != api.BackupStorageType_GCS || cfg.Gcs == nil || (cfg.Gcs.AccessKey != "" && cfg.Gcs.SecretKey != "") {
+		return cfg, nil
+	}
+
+	url, err := storage.StartGCSProxy(ctx, nil, cfg.Gcs.EndpointUrl, cfg.VerifyTls)
+	if err != nil {
+		return nil, errors.Wrap(err, "start gcs proxy")
+	}
+
+	c := proto.Clone(cfg).(*api.BackupConfig)
+	c.Gcs.EndpointUrl = url
+	c.Gcs.AccessKey = storage.GCSProxyHMACKey
+	c.Gcs.SecretKey = storage.GCSProxyHMACKey
+	return c, nil
+}
+
 func sanitizeCmd(cmd *exec.Cmd) string {
-	sensitiveFlags := regexp.MustCompile("--password=(.*)|--.*-access-key=(.*)|--.*secret-key=(.*)")
+	sensitiveFlags := regexp.MustCompile("--password=(.*)|--.*-access-key=(.*)|--.*secret-key=(.*)|--encrypt-key=(.*)")
 	c := []string{cmd.Path}
 
 	for _, arg := range cmd.Args[1:] {